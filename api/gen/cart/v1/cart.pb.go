@@ -21,6 +21,107 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type CartChangeOp int32
+
+const (
+	CartChangeOp_CART_CHANGE_OP_UNSPECIFIED CartChangeOp = 0
+	CartChangeOp_CART_CHANGE_OP_ADD         CartChangeOp = 1 // add item.quantity units, creating the line if needed
+	CartChangeOp_CART_CHANGE_OP_SET         CartChangeOp = 2 // set the quantity of an existing line
+	CartChangeOp_CART_CHANGE_OP_REMOVE      CartChangeOp = 3 // drop the line; item.quantity is ignored
+)
+
+// Enum value maps for CartChangeOp.
+var (
+	CartChangeOp_name = map[int32]string{
+		0: "CART_CHANGE_OP_UNSPECIFIED",
+		1: "CART_CHANGE_OP_ADD",
+		2: "CART_CHANGE_OP_SET",
+		3: "CART_CHANGE_OP_REMOVE",
+	}
+	CartChangeOp_value = map[string]int32{
+		"CART_CHANGE_OP_UNSPECIFIED": 0,
+		"CART_CHANGE_OP_ADD":         1,
+		"CART_CHANGE_OP_SET":         2,
+		"CART_CHANGE_OP_REMOVE":      3,
+	}
+)
+
+func (x CartChangeOp) Enum() *CartChangeOp {
+	p := new(CartChangeOp)
+	*p = x
+	return p
+}
+
+func (x CartChangeOp) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CartChangeOp) Descriptor() protoreflect.EnumDescriptor {
+	return file_cart_v1_cart_proto_enumTypes[0].Descriptor()
+}
+
+func (CartChangeOp) Type() protoreflect.EnumType {
+	return &file_cart_v1_cart_proto_enumTypes[0]
+}
+
+func (x CartChangeOp) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CartChangeOp.Descriptor instead.
+func (CartChangeOp) EnumDescriptor() ([]byte, []int) {
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{0}
+}
+
+type MergeStrategy int32
+
+const (
+	MergeStrategy_MERGE_STRATEGY_UNSPECIFIED MergeStrategy = 0 // same as SUM
+	MergeStrategy_MERGE_STRATEGY_SUM         MergeStrategy = 1 // a line in both carts gets both quantities
+	MergeStrategy_MERGE_STRATEGY_LATEST      MergeStrategy = 2 // a line in both carts keeps the most recently updated quantity
+)
+
+// Enum value maps for MergeStrategy.
+var (
+	MergeStrategy_name = map[int32]string{
+		0: "MERGE_STRATEGY_UNSPECIFIED",
+		1: "MERGE_STRATEGY_SUM",
+		2: "MERGE_STRATEGY_LATEST",
+	}
+	MergeStrategy_value = map[string]int32{
+		"MERGE_STRATEGY_UNSPECIFIED": 0,
+		"MERGE_STRATEGY_SUM":         1,
+		"MERGE_STRATEGY_LATEST":      2,
+	}
+)
+
+func (x MergeStrategy) Enum() *MergeStrategy {
+	p := new(MergeStrategy)
+	*p = x
+	return p
+}
+
+func (x MergeStrategy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MergeStrategy) Descriptor() protoreflect.EnumDescriptor {
+	return file_cart_v1_cart_proto_enumTypes[1].Descriptor()
+}
+
+func (MergeStrategy) Type() protoreflect.EnumType {
+	return &file_cart_v1_cart_proto_enumTypes[1]
+}
+
+func (x MergeStrategy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MergeStrategy.Descriptor instead.
+func (MergeStrategy) EnumDescriptor() ([]byte, []int) {
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{1}
+}
+
 type Cart struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -29,6 +130,10 @@ type Cart struct {
 	Items         []*CartItem            `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
 	CreatedAtUnix int64                  `protobuf:"varint,4,opt,name=created_at_unix,json=createdAtUnix,proto3" json:"created_at_unix,omitempty"`
 	UpdatedAtUnix int64                  `protobuf:"varint,5,opt,name=updated_at_unix,json=updatedAtUnix,proto3" json:"updated_at_unix,omitempty"`
+	Version       int64                  `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"` // bumped on every write; send it back as expected_version
+	// Lines that changed since they were added. Only GetCart, GetOrCreateCart
+	// and GetGuestCart check for them.
+	Notices       []*CartNotice `protobuf:"bytes,8,rep,name=notices,proto3" json:"notices,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -105,17 +210,87 @@ func (x *Cart) GetUpdatedAtUnix() int64 {
 	return 0
 }
 
-type CartItem struct {
+func (x *Cart) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Cart) GetNotices() []*CartNotice {
+	if x != nil {
+		return x.Notices
+	}
+	return nil
+}
+
+type Money struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_cart_v1_cart_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_v1_cart_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Money) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type CartItem struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity  int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	VariantId string                 `protobuf:"bytes,3,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"` // empty for products without variants
+	// Catalog price when the line was last added to; unset for older lines.
+	// Ignored in requests.
+	UnitPrice     *Money `protobuf:"bytes,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CartItem) Reset() {
 	*x = CartItem{}
-	mi := &file_cart_v1_cart_proto_msgTypes[1]
+	mi := &file_cart_v1_cart_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -127,7 +302,7 @@ func (x *CartItem) String() string {
 func (*CartItem) ProtoMessage() {}
 
 func (x *CartItem) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_v1_cart_proto_msgTypes[1]
+	mi := &file_cart_v1_cart_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -140,7 +315,7 @@ func (x *CartItem) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CartItem.ProtoReflect.Descriptor instead.
 func (*CartItem) Descriptor() ([]byte, []int) {
-	return file_cart_v1_cart_proto_rawDescGZIP(), []int{1}
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CartItem) GetProductId() string {
@@ -157,6 +332,106 @@ func (x *CartItem) GetQuantity() int32 {
 	return 0
 }
 
+func (x *CartItem) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *CartItem) GetUnitPrice() *Money {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return nil
+}
+
+type CartNotice struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId string                 `protobuf:"bytes,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	// PRICE_INCREASED, PRICE_DECREASED, PRODUCT_ARCHIVED, PRODUCT_UNAVAILABLE
+	// or OUT_OF_STOCK
+	Kind          string `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	OldPrice      *Money `protobuf:"bytes,4,opt,name=old_price,json=oldPrice,proto3" json:"old_price,omitempty"` // price notices only
+	NewPrice      *Money `protobuf:"bytes,5,opt,name=new_price,json=newPrice,proto3" json:"new_price,omitempty"` // price notices only
+	Available     int32  `protobuf:"varint,6,opt,name=available,proto3" json:"available,omitempty"`              // OUT_OF_STOCK only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CartNotice) Reset() {
+	*x = CartNotice{}
+	mi := &file_cart_v1_cart_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartNotice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartNotice) ProtoMessage() {}
+
+func (x *CartNotice) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_v1_cart_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartNotice.ProtoReflect.Descriptor instead.
+func (*CartNotice) Descriptor() ([]byte, []int) {
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CartNotice) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CartNotice) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *CartNotice) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *CartNotice) GetOldPrice() *Money {
+	if x != nil {
+		return x.OldPrice
+	}
+	return nil
+}
+
+func (x *CartNotice) GetNewPrice() *Money {
+	if x != nil {
+		return x.NewPrice
+	}
+	return nil
+}
+
+func (x *CartNotice) GetAvailable() int32 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
 type UserId struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -166,7 +441,7 @@ type UserId struct {
 
 func (x *UserId) Reset() {
 	*x = UserId{}
-	mi := &file_cart_v1_cart_proto_msgTypes[2]
+	mi := &file_cart_v1_cart_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -178,7 +453,7 @@ func (x *UserId) String() string {
 func (*UserId) ProtoMessage() {}
 
 func (x *UserId) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_v1_cart_proto_msgTypes[2]
+	mi := &file_cart_v1_cart_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -191,7 +466,7 @@ func (x *UserId) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserId.ProtoReflect.Descriptor instead.
 func (*UserId) Descriptor() ([]byte, []int) {
-	return file_cart_v1_cart_proto_rawDescGZIP(), []int{2}
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *UserId) GetId() string {
@@ -202,15 +477,16 @@ func (x *UserId) GetId() string {
 }
 
 type CartId struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *CartId) Reset() {
 	*x = CartId{}
-	mi := &file_cart_v1_cart_proto_msgTypes[3]
+	mi := &file_cart_v1_cart_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -222,7 +498,7 @@ func (x *CartId) String() string {
 func (*CartId) ProtoMessage() {}
 
 func (x *CartId) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_v1_cart_proto_msgTypes[3]
+	mi := &file_cart_v1_cart_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -235,7 +511,7 @@ func (x *CartId) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CartId.ProtoReflect.Descriptor instead.
 func (*CartId) Descriptor() ([]byte, []int) {
-	return file_cart_v1_cart_proto_rawDescGZIP(), []int{3}
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CartId) GetId() string {
@@ -245,17 +521,31 @@ func (x *CartId) GetId() string {
 	return ""
 }
 
+func (x *CartId) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+// Item requests address the user's cart, or a guest cart when guest_token
+// is set instead of user_id.
+//
+// Mutating requests fail with ABORTED when expected_version is set and no
+// longer matches the cart's version; 0 skips the check.
 type UpdateCartItemRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Item          *CartItem              `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Item            *CartItem              `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	GuestToken      string                 `protobuf:"bytes,3,opt,name=guest_token,json=guestToken,proto3" json:"guest_token,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *UpdateCartItemRequest) Reset() {
 	*x = UpdateCartItemRequest{}
-	mi := &file_cart_v1_cart_proto_msgTypes[4]
+	mi := &file_cart_v1_cart_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -267,7 +557,7 @@ func (x *UpdateCartItemRequest) String() string {
 func (*UpdateCartItemRequest) ProtoMessage() {}
 
 func (x *UpdateCartItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_v1_cart_proto_msgTypes[4]
+	mi := &file_cart_v1_cart_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -280,7 +570,7 @@ func (x *UpdateCartItemRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateCartItemRequest.ProtoReflect.Descriptor instead.
 func (*UpdateCartItemRequest) Descriptor() ([]byte, []int) {
-	return file_cart_v1_cart_proto_rawDescGZIP(), []int{4}
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateCartItemRequest) GetUserId() string {
@@ -297,17 +587,34 @@ func (x *UpdateCartItemRequest) GetItem() *CartItem {
 	return nil
 }
 
+func (x *UpdateCartItemRequest) GetGuestToken() string {
+	if x != nil {
+		return x.GuestToken
+	}
+	return ""
+}
+
+func (x *UpdateCartItemRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 type RemoveCartItemRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId       string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId       string                 `protobuf:"bytes,3,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	GuestToken      string                 `protobuf:"bytes,4,opt,name=guest_token,json=guestToken,proto3" json:"guest_token,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *RemoveCartItemRequest) Reset() {
 	*x = RemoveCartItemRequest{}
-	mi := &file_cart_v1_cart_proto_msgTypes[5]
+	mi := &file_cart_v1_cart_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -319,7 +626,7 @@ func (x *RemoveCartItemRequest) String() string {
 func (*RemoveCartItemRequest) ProtoMessage() {}
 
 func (x *RemoveCartItemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_v1_cart_proto_msgTypes[5]
+	mi := &file_cart_v1_cart_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -332,7 +639,7 @@ func (x *RemoveCartItemRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveCartItemRequest.ProtoReflect.Descriptor instead.
 func (*RemoveCartItemRequest) Descriptor() ([]byte, []int) {
-	return file_cart_v1_cart_proto_rawDescGZIP(), []int{5}
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *RemoveCartItemRequest) GetUserId() string {
@@ -349,43 +656,453 @@ func (x *RemoveCartItemRequest) GetProductId() string {
 	return ""
 }
 
+func (x *RemoveCartItemRequest) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *RemoveCartItemRequest) GetGuestToken() string {
+	if x != nil {
+		return x.GuestToken
+	}
+	return ""
+}
+
+func (x *RemoveCartItemRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type CartChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Op            CartChangeOp           `protobuf:"varint,1,opt,name=op,proto3,enum=cart.v1.CartChangeOp" json:"op,omitempty"`
+	Item          *CartItem              `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CartChange) Reset() {
+	*x = CartChange{}
+	mi := &file_cart_v1_cart_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartChange) ProtoMessage() {}
+
+func (x *CartChange) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_v1_cart_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartChange.ProtoReflect.Descriptor instead.
+func (*CartChange) Descriptor() ([]byte, []int) {
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CartChange) GetOp() CartChangeOp {
+	if x != nil {
+		return x.Op
+	}
+	return CartChangeOp_CART_CHANGE_OP_UNSPECIFIED
+}
+
+func (x *CartChange) GetItem() *CartItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+// ApplyChanges applies the changes in order in one transaction and returns
+// the resulting cart; if any change fails, none of them are applied.
+type ApplyCartChangesRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Changes         []*CartChange          `protobuf:"bytes,2,rep,name=changes,proto3" json:"changes,omitempty"` // at most 100
+	GuestToken      string                 `protobuf:"bytes,3,opt,name=guest_token,json=guestToken,proto3" json:"guest_token,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ApplyCartChangesRequest) Reset() {
+	*x = ApplyCartChangesRequest{}
+	mi := &file_cart_v1_cart_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyCartChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyCartChangesRequest) ProtoMessage() {}
+
+func (x *ApplyCartChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_v1_cart_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyCartChangesRequest.ProtoReflect.Descriptor instead.
+func (*ApplyCartChangesRequest) Descriptor() ([]byte, []int) {
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ApplyCartChangesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ApplyCartChangesRequest) GetChanges() []*CartChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *ApplyCartChangesRequest) GetGuestToken() string {
+	if x != nil {
+		return x.GuestToken
+	}
+	return ""
+}
+
+func (x *ApplyCartChangesRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type GuestToken struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GuestToken) Reset() {
+	*x = GuestToken{}
+	mi := &file_cart_v1_cart_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GuestToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GuestToken) ProtoMessage() {}
+
+func (x *GuestToken) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_v1_cart_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GuestToken.ProtoReflect.Descriptor instead.
+func (*GuestToken) Descriptor() ([]byte, []int) {
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GuestToken) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type CreateGuestCartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateGuestCartRequest) Reset() {
+	*x = CreateGuestCartRequest{}
+	mi := &file_cart_v1_cart_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateGuestCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGuestCartRequest) ProtoMessage() {}
+
+func (x *CreateGuestCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_v1_cart_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGuestCartRequest.ProtoReflect.Descriptor instead.
+func (*CreateGuestCartRequest) Descriptor() ([]byte, []int) {
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{11}
+}
+
+type GuestCart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // opaque; the only way to reach the cart, keep it in the session
+	Cart          *Cart                  `protobuf:"bytes,2,opt,name=cart,proto3" json:"cart,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GuestCart) Reset() {
+	*x = GuestCart{}
+	mi := &file_cart_v1_cart_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GuestCart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GuestCart) ProtoMessage() {}
+
+func (x *GuestCart) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_v1_cart_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GuestCart.ProtoReflect.Descriptor instead.
+func (*GuestCart) Descriptor() ([]byte, []int) {
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GuestCart) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *GuestCart) GetCart() *Cart {
+	if x != nil {
+		return x.Cart
+	}
+	return nil
+}
+
+// MergeCart folds the guest cart into the user's ACTIVE cart (created if
+// missing) and closes the guest cart. NOT_FOUND once the guest cart has been
+// merged.
+type MergeCartRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	GuestToken      string                 `protobuf:"bytes,1,opt,name=guest_token,json=guestToken,proto3" json:"guest_token,omitempty"`
+	UserId          string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Strategy        MergeStrategy          `protobuf:"varint,3,opt,name=strategy,proto3,enum=cart.v1.MergeStrategy" json:"strategy,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"` // of the user's cart
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MergeCartRequest) Reset() {
+	*x = MergeCartRequest{}
+	mi := &file_cart_v1_cart_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeCartRequest) ProtoMessage() {}
+
+func (x *MergeCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_v1_cart_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeCartRequest.ProtoReflect.Descriptor instead.
+func (*MergeCartRequest) Descriptor() ([]byte, []int) {
+	return file_cart_v1_cart_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MergeCartRequest) GetGuestToken() string {
+	if x != nil {
+		return x.GuestToken
+	}
+	return ""
+}
+
+func (x *MergeCartRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *MergeCartRequest) GetStrategy() MergeStrategy {
+	if x != nil {
+		return x.Strategy
+	}
+	return MergeStrategy_MERGE_STRATEGY_UNSPECIFIED
+}
+
+func (x *MergeCartRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 var File_cart_v1_cart_proto protoreflect.FileDescriptor
 
 const file_cart_v1_cart_proto_rawDesc = "" +
 	"\n" +
-	"\x12cart/v1/cart.proto\x12\acart.v1\"\xc0\x01\n" +
+	"\x12cart/v1/cart.proto\x12\acart.v1\"\x89\x02\n" +
 	"\x04Cart\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
 	"\x06status\x18\x06 \x01(\tR\x06status\x12'\n" +
 	"\x05items\x18\x03 \x03(\v2\x11.cart.v1.CartItemR\x05items\x12&\n" +
 	"\x0fcreated_at_unix\x18\x04 \x01(\x03R\rcreatedAtUnix\x12&\n" +
-	"\x0fupdated_at_unix\x18\x05 \x01(\x03R\rupdatedAtUnix\"E\n" +
+	"\x0fupdated_at_unix\x18\x05 \x01(\x03R\rupdatedAtUnix\x12\x18\n" +
+	"\aversion\x18\a \x01(\x03R\aversion\x12-\n" +
+	"\anotices\x18\b \x03(\v2\x13.cart.v1.CartNoticeR\anotices\";\n" +
+	"\x05Money\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\"\x93\x01\n" +
 	"\bCartItem\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x02 \x01(\x05R\bquantity\"\x18\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\tR\tvariantId\x12-\n" +
+	"\n" +
+	"unit_price\x18\x04 \x01(\v2\x0e.cart.v1.MoneyR\tunitPrice\"\xd6\x01\n" +
+	"\n" +
+	"CartNotice\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\tR\tvariantId\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12+\n" +
+	"\told_price\x18\x04 \x01(\v2\x0e.cart.v1.MoneyR\boldPrice\x12+\n" +
+	"\tnew_price\x18\x05 \x01(\v2\x0e.cart.v1.MoneyR\bnewPrice\x12\x1c\n" +
+	"\tavailable\x18\x06 \x01(\x05R\tavailable\"\x18\n" +
 	"\x06UserId\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x18\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"C\n" +
 	"\x06CartId\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"W\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"\xa3\x01\n" +
 	"\x15UpdateCartItemRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
-	"\x04item\x18\x02 \x01(\v2\x11.cart.v1.CartItemR\x04item\"O\n" +
+	"\x04item\x18\x02 \x01(\v2\x11.cart.v1.CartItemR\x04item\x12\x1f\n" +
+	"\vguest_token\x18\x03 \x01(\tR\n" +
+	"guestToken\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x03R\x0fexpectedVersion\"\xba\x01\n" +
 	"\x15RemoveCartItemRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
 	"\n" +
-	"product_id\x18\x02 \x01(\tR\tproductId2\xfd\x02\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\tR\tvariantId\x12\x1f\n" +
+	"\vguest_token\x18\x04 \x01(\tR\n" +
+	"guestToken\x12)\n" +
+	"\x10expected_version\x18\x05 \x01(\x03R\x0fexpectedVersion\"Z\n" +
+	"\n" +
+	"CartChange\x12%\n" +
+	"\x02op\x18\x01 \x01(\x0e2\x15.cart.v1.CartChangeOpR\x02op\x12%\n" +
+	"\x04item\x18\x02 \x01(\v2\x11.cart.v1.CartItemR\x04item\"\xad\x01\n" +
+	"\x17ApplyCartChangesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12-\n" +
+	"\achanges\x18\x02 \x03(\v2\x13.cart.v1.CartChangeR\achanges\x12\x1f\n" +
+	"\vguest_token\x18\x03 \x01(\tR\n" +
+	"guestToken\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x03R\x0fexpectedVersion\"\"\n" +
+	"\n" +
+	"GuestToken\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"\x18\n" +
+	"\x16CreateGuestCartRequest\"D\n" +
+	"\tGuestCart\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12!\n" +
+	"\x04cart\x18\x02 \x01(\v2\r.cart.v1.CartR\x04cart\"\xab\x01\n" +
+	"\x10MergeCartRequest\x12\x1f\n" +
+	"\vguest_token\x18\x01 \x01(\tR\n" +
+	"guestToken\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x122\n" +
+	"\bstrategy\x18\x03 \x01(\x0e2\x16.cart.v1.MergeStrategyR\bstrategy\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x03R\x0fexpectedVersion*y\n" +
+	"\fCartChangeOp\x12\x1e\n" +
+	"\x1aCART_CHANGE_OP_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12CART_CHANGE_OP_ADD\x10\x01\x12\x16\n" +
+	"\x12CART_CHANGE_OP_SET\x10\x02\x12\x19\n" +
+	"\x15CART_CHANGE_OP_REMOVE\x10\x03*b\n" +
+	"\rMergeStrategy\x12\x1e\n" +
+	"\x1aMERGE_STRATEGY_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12MERGE_STRATEGY_SUM\x10\x01\x12\x19\n" +
+	"\x15MERGE_STRATEGY_LATEST\x10\x022\xf1\x04\n" +
 	"\vCartService\x12)\n" +
 	"\aGetCart\x12\x0f.cart.v1.UserId\x1a\r.cart.v1.Cart\x128\n" +
 	"\aAddItem\x12\x1e.cart.v1.UpdateCartItemRequest\x1a\r.cart.v1.Cart\x12@\n" +
 	"\x0fSetItemQuantity\x12\x1e.cart.v1.UpdateCartItemRequest\x1a\r.cart.v1.Cart\x12;\n" +
 	"\n" +
 	"RemoveItem\x12\x1e.cart.v1.RemoveCartItemRequest\x1a\r.cart.v1.Cart\x12+\n" +
-	"\tClearCart\x12\x0f.cart.v1.CartId\x1a\r.cart.v1.Cart\x12*\n" +
+	"\tClearCart\x12\x0f.cart.v1.CartId\x1a\r.cart.v1.Cart\x12?\n" +
+	"\fApplyChanges\x12 .cart.v1.ApplyCartChangesRequest\x1a\r.cart.v1.Cart\x12*\n" +
 	"\n" +
 	"CreateCart\x12\r.cart.v1.Cart\x1a\r.cart.v1.Cart\x121\n" +
-	"\x0fGetOrCreateCart\x12\x0f.cart.v1.UserId\x1a\r.cart.v1.CartB;Z9github.com/dwikikusuma/shoping-llm/api/gen/cart/v1;cartv1b\x06proto3"
+	"\x0fGetOrCreateCart\x12\x0f.cart.v1.UserId\x1a\r.cart.v1.Cart\x12F\n" +
+	"\x0fCreateGuestCart\x12\x1f.cart.v1.CreateGuestCartRequest\x1a\x12.cart.v1.GuestCart\x122\n" +
+	"\fGetGuestCart\x12\x13.cart.v1.GuestToken\x1a\r.cart.v1.Cart\x125\n" +
+	"\tMergeCart\x12\x19.cart.v1.MergeCartRequest\x1a\r.cart.v1.CartB;Z9github.com/dwikikusuma/shoping-llm/api/gen/cart/v1;cartv1b\x06proto3"
 
 var (
 	file_cart_v1_cart_proto_rawDescOnce sync.Once
@@ -399,37 +1116,65 @@ func file_cart_v1_cart_proto_rawDescGZIP() []byte {
 	return file_cart_v1_cart_proto_rawDescData
 }
 
-var file_cart_v1_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_cart_v1_cart_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_cart_v1_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_cart_v1_cart_proto_goTypes = []any{
-	(*Cart)(nil),                  // 0: cart.v1.Cart
-	(*CartItem)(nil),              // 1: cart.v1.CartItem
-	(*UserId)(nil),                // 2: cart.v1.UserId
-	(*CartId)(nil),                // 3: cart.v1.CartId
-	(*UpdateCartItemRequest)(nil), // 4: cart.v1.UpdateCartItemRequest
-	(*RemoveCartItemRequest)(nil), // 5: cart.v1.RemoveCartItemRequest
+	(CartChangeOp)(0),               // 0: cart.v1.CartChangeOp
+	(MergeStrategy)(0),              // 1: cart.v1.MergeStrategy
+	(*Cart)(nil),                    // 2: cart.v1.Cart
+	(*Money)(nil),                   // 3: cart.v1.Money
+	(*CartItem)(nil),                // 4: cart.v1.CartItem
+	(*CartNotice)(nil),              // 5: cart.v1.CartNotice
+	(*UserId)(nil),                  // 6: cart.v1.UserId
+	(*CartId)(nil),                  // 7: cart.v1.CartId
+	(*UpdateCartItemRequest)(nil),   // 8: cart.v1.UpdateCartItemRequest
+	(*RemoveCartItemRequest)(nil),   // 9: cart.v1.RemoveCartItemRequest
+	(*CartChange)(nil),              // 10: cart.v1.CartChange
+	(*ApplyCartChangesRequest)(nil), // 11: cart.v1.ApplyCartChangesRequest
+	(*GuestToken)(nil),              // 12: cart.v1.GuestToken
+	(*CreateGuestCartRequest)(nil),  // 13: cart.v1.CreateGuestCartRequest
+	(*GuestCart)(nil),               // 14: cart.v1.GuestCart
+	(*MergeCartRequest)(nil),        // 15: cart.v1.MergeCartRequest
 }
 var file_cart_v1_cart_proto_depIdxs = []int32{
-	1, // 0: cart.v1.Cart.items:type_name -> cart.v1.CartItem
-	1, // 1: cart.v1.UpdateCartItemRequest.item:type_name -> cart.v1.CartItem
-	2, // 2: cart.v1.CartService.GetCart:input_type -> cart.v1.UserId
-	4, // 3: cart.v1.CartService.AddItem:input_type -> cart.v1.UpdateCartItemRequest
-	4, // 4: cart.v1.CartService.SetItemQuantity:input_type -> cart.v1.UpdateCartItemRequest
-	5, // 5: cart.v1.CartService.RemoveItem:input_type -> cart.v1.RemoveCartItemRequest
-	3, // 6: cart.v1.CartService.ClearCart:input_type -> cart.v1.CartId
-	0, // 7: cart.v1.CartService.CreateCart:input_type -> cart.v1.Cart
-	2, // 8: cart.v1.CartService.GetOrCreateCart:input_type -> cart.v1.UserId
-	0, // 9: cart.v1.CartService.GetCart:output_type -> cart.v1.Cart
-	0, // 10: cart.v1.CartService.AddItem:output_type -> cart.v1.Cart
-	0, // 11: cart.v1.CartService.SetItemQuantity:output_type -> cart.v1.Cart
-	0, // 12: cart.v1.CartService.RemoveItem:output_type -> cart.v1.Cart
-	0, // 13: cart.v1.CartService.ClearCart:output_type -> cart.v1.Cart
-	0, // 14: cart.v1.CartService.CreateCart:output_type -> cart.v1.Cart
-	0, // 15: cart.v1.CartService.GetOrCreateCart:output_type -> cart.v1.Cart
-	9, // [9:16] is the sub-list for method output_type
-	2, // [2:9] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	4,  // 0: cart.v1.Cart.items:type_name -> cart.v1.CartItem
+	5,  // 1: cart.v1.Cart.notices:type_name -> cart.v1.CartNotice
+	3,  // 2: cart.v1.CartItem.unit_price:type_name -> cart.v1.Money
+	3,  // 3: cart.v1.CartNotice.old_price:type_name -> cart.v1.Money
+	3,  // 4: cart.v1.CartNotice.new_price:type_name -> cart.v1.Money
+	4,  // 5: cart.v1.UpdateCartItemRequest.item:type_name -> cart.v1.CartItem
+	0,  // 6: cart.v1.CartChange.op:type_name -> cart.v1.CartChangeOp
+	4,  // 7: cart.v1.CartChange.item:type_name -> cart.v1.CartItem
+	10, // 8: cart.v1.ApplyCartChangesRequest.changes:type_name -> cart.v1.CartChange
+	2,  // 9: cart.v1.GuestCart.cart:type_name -> cart.v1.Cart
+	1,  // 10: cart.v1.MergeCartRequest.strategy:type_name -> cart.v1.MergeStrategy
+	6,  // 11: cart.v1.CartService.GetCart:input_type -> cart.v1.UserId
+	8,  // 12: cart.v1.CartService.AddItem:input_type -> cart.v1.UpdateCartItemRequest
+	8,  // 13: cart.v1.CartService.SetItemQuantity:input_type -> cart.v1.UpdateCartItemRequest
+	9,  // 14: cart.v1.CartService.RemoveItem:input_type -> cart.v1.RemoveCartItemRequest
+	7,  // 15: cart.v1.CartService.ClearCart:input_type -> cart.v1.CartId
+	11, // 16: cart.v1.CartService.ApplyChanges:input_type -> cart.v1.ApplyCartChangesRequest
+	2,  // 17: cart.v1.CartService.CreateCart:input_type -> cart.v1.Cart
+	6,  // 18: cart.v1.CartService.GetOrCreateCart:input_type -> cart.v1.UserId
+	13, // 19: cart.v1.CartService.CreateGuestCart:input_type -> cart.v1.CreateGuestCartRequest
+	12, // 20: cart.v1.CartService.GetGuestCart:input_type -> cart.v1.GuestToken
+	15, // 21: cart.v1.CartService.MergeCart:input_type -> cart.v1.MergeCartRequest
+	2,  // 22: cart.v1.CartService.GetCart:output_type -> cart.v1.Cart
+	2,  // 23: cart.v1.CartService.AddItem:output_type -> cart.v1.Cart
+	2,  // 24: cart.v1.CartService.SetItemQuantity:output_type -> cart.v1.Cart
+	2,  // 25: cart.v1.CartService.RemoveItem:output_type -> cart.v1.Cart
+	2,  // 26: cart.v1.CartService.ClearCart:output_type -> cart.v1.Cart
+	2,  // 27: cart.v1.CartService.ApplyChanges:output_type -> cart.v1.Cart
+	2,  // 28: cart.v1.CartService.CreateCart:output_type -> cart.v1.Cart
+	2,  // 29: cart.v1.CartService.GetOrCreateCart:output_type -> cart.v1.Cart
+	14, // 30: cart.v1.CartService.CreateGuestCart:output_type -> cart.v1.GuestCart
+	2,  // 31: cart.v1.CartService.GetGuestCart:output_type -> cart.v1.Cart
+	2,  // 32: cart.v1.CartService.MergeCart:output_type -> cart.v1.Cart
+	22, // [22:33] is the sub-list for method output_type
+	11, // [11:22] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_cart_v1_cart_proto_init() }
@@ -442,13 +1187,14 @@ func file_cart_v1_cart_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cart_v1_cart_proto_rawDesc), len(file_cart_v1_cart_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      2,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_cart_v1_cart_proto_goTypes,
 		DependencyIndexes: file_cart_v1_cart_proto_depIdxs,
+		EnumInfos:         file_cart_v1_cart_proto_enumTypes,
 		MessageInfos:      file_cart_v1_cart_proto_msgTypes,
 	}.Build()
 	File_cart_v1_cart_proto = out.File
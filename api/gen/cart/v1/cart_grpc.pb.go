@@ -24,8 +24,12 @@ const (
 	CartService_SetItemQuantity_FullMethodName = "/cart.v1.CartService/SetItemQuantity"
 	CartService_RemoveItem_FullMethodName      = "/cart.v1.CartService/RemoveItem"
 	CartService_ClearCart_FullMethodName       = "/cart.v1.CartService/ClearCart"
+	CartService_ApplyChanges_FullMethodName    = "/cart.v1.CartService/ApplyChanges"
 	CartService_CreateCart_FullMethodName      = "/cart.v1.CartService/CreateCart"
 	CartService_GetOrCreateCart_FullMethodName = "/cart.v1.CartService/GetOrCreateCart"
+	CartService_CreateGuestCart_FullMethodName = "/cart.v1.CartService/CreateGuestCart"
+	CartService_GetGuestCart_FullMethodName    = "/cart.v1.CartService/GetGuestCart"
+	CartService_MergeCart_FullMethodName       = "/cart.v1.CartService/MergeCart"
 )
 
 // CartServiceClient is the client API for CartService service.
@@ -37,8 +41,12 @@ type CartServiceClient interface {
 	SetItemQuantity(ctx context.Context, in *UpdateCartItemRequest, opts ...grpc.CallOption) (*Cart, error)
 	RemoveItem(ctx context.Context, in *RemoveCartItemRequest, opts ...grpc.CallOption) (*Cart, error)
 	ClearCart(ctx context.Context, in *CartId, opts ...grpc.CallOption) (*Cart, error)
+	ApplyChanges(ctx context.Context, in *ApplyCartChangesRequest, opts ...grpc.CallOption) (*Cart, error)
 	CreateCart(ctx context.Context, in *Cart, opts ...grpc.CallOption) (*Cart, error)
 	GetOrCreateCart(ctx context.Context, in *UserId, opts ...grpc.CallOption) (*Cart, error)
+	CreateGuestCart(ctx context.Context, in *CreateGuestCartRequest, opts ...grpc.CallOption) (*GuestCart, error)
+	GetGuestCart(ctx context.Context, in *GuestToken, opts ...grpc.CallOption) (*Cart, error)
+	MergeCart(ctx context.Context, in *MergeCartRequest, opts ...grpc.CallOption) (*Cart, error)
 }
 
 type cartServiceClient struct {
@@ -99,6 +107,16 @@ func (c *cartServiceClient) ClearCart(ctx context.Context, in *CartId, opts ...g
 	return out, nil
 }
 
+func (c *cartServiceClient) ApplyChanges(ctx context.Context, in *ApplyCartChangesRequest, opts ...grpc.CallOption) (*Cart, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Cart)
+	err := c.cc.Invoke(ctx, CartService_ApplyChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cartServiceClient) CreateCart(ctx context.Context, in *Cart, opts ...grpc.CallOption) (*Cart, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Cart)
@@ -119,6 +137,36 @@ func (c *cartServiceClient) GetOrCreateCart(ctx context.Context, in *UserId, opt
 	return out, nil
 }
 
+func (c *cartServiceClient) CreateGuestCart(ctx context.Context, in *CreateGuestCartRequest, opts ...grpc.CallOption) (*GuestCart, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GuestCart)
+	err := c.cc.Invoke(ctx, CartService_CreateGuestCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) GetGuestCart(ctx context.Context, in *GuestToken, opts ...grpc.CallOption) (*Cart, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Cart)
+	err := c.cc.Invoke(ctx, CartService_GetGuestCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) MergeCart(ctx context.Context, in *MergeCartRequest, opts ...grpc.CallOption) (*Cart, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Cart)
+	err := c.cc.Invoke(ctx, CartService_MergeCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CartServiceServer is the server API for CartService service.
 // All implementations must embed UnimplementedCartServiceServer
 // for forward compatibility.
@@ -128,8 +176,12 @@ type CartServiceServer interface {
 	SetItemQuantity(context.Context, *UpdateCartItemRequest) (*Cart, error)
 	RemoveItem(context.Context, *RemoveCartItemRequest) (*Cart, error)
 	ClearCart(context.Context, *CartId) (*Cart, error)
+	ApplyChanges(context.Context, *ApplyCartChangesRequest) (*Cart, error)
 	CreateCart(context.Context, *Cart) (*Cart, error)
 	GetOrCreateCart(context.Context, *UserId) (*Cart, error)
+	CreateGuestCart(context.Context, *CreateGuestCartRequest) (*GuestCart, error)
+	GetGuestCart(context.Context, *GuestToken) (*Cart, error)
+	MergeCart(context.Context, *MergeCartRequest) (*Cart, error)
 	mustEmbedUnimplementedCartServiceServer()
 }
 
@@ -155,12 +207,24 @@ func (UnimplementedCartServiceServer) RemoveItem(context.Context, *RemoveCartIte
 func (UnimplementedCartServiceServer) ClearCart(context.Context, *CartId) (*Cart, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ClearCart not implemented")
 }
+func (UnimplementedCartServiceServer) ApplyChanges(context.Context, *ApplyCartChangesRequest) (*Cart, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyChanges not implemented")
+}
 func (UnimplementedCartServiceServer) CreateCart(context.Context, *Cart) (*Cart, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateCart not implemented")
 }
 func (UnimplementedCartServiceServer) GetOrCreateCart(context.Context, *UserId) (*Cart, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetOrCreateCart not implemented")
 }
+func (UnimplementedCartServiceServer) CreateGuestCart(context.Context, *CreateGuestCartRequest) (*GuestCart, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGuestCart not implemented")
+}
+func (UnimplementedCartServiceServer) GetGuestCart(context.Context, *GuestToken) (*Cart, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGuestCart not implemented")
+}
+func (UnimplementedCartServiceServer) MergeCart(context.Context, *MergeCartRequest) (*Cart, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeCart not implemented")
+}
 func (UnimplementedCartServiceServer) mustEmbedUnimplementedCartServiceServer() {}
 func (UnimplementedCartServiceServer) testEmbeddedByValue()                     {}
 
@@ -272,6 +336,24 @@ func _CartService_ClearCart_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CartService_ApplyChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyCartChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).ApplyChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_ApplyChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).ApplyChanges(ctx, req.(*ApplyCartChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CartService_CreateCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Cart)
 	if err := dec(in); err != nil {
@@ -308,6 +390,60 @@ func _CartService_GetOrCreateCart_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CartService_CreateGuestCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGuestCartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).CreateGuestCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_CreateGuestCart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).CreateGuestCart(ctx, req.(*CreateGuestCartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_GetGuestCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GuestToken)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).GetGuestCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_GetGuestCart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).GetGuestCart(ctx, req.(*GuestToken))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_MergeCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeCartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).MergeCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_MergeCart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).MergeCart(ctx, req.(*MergeCartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CartService_ServiceDesc is the grpc.ServiceDesc for CartService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -335,6 +471,10 @@ var CartService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ClearCart",
 			Handler:    _CartService_ClearCart_Handler,
 		},
+		{
+			MethodName: "ApplyChanges",
+			Handler:    _CartService_ApplyChanges_Handler,
+		},
 		{
 			MethodName: "CreateCart",
 			Handler:    _CartService_CreateCart_Handler,
@@ -343,6 +483,18 @@ var CartService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetOrCreateCart",
 			Handler:    _CartService_GetOrCreateCart_Handler,
 		},
+		{
+			MethodName: "CreateGuestCart",
+			Handler:    _CartService_CreateGuestCart_Handler,
+		},
+		{
+			MethodName: "GetGuestCart",
+			Handler:    _CartService_GetGuestCart_Handler,
+		},
+		{
+			MethodName: "MergeCart",
+			Handler:    _CartService_MergeCart_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "cart/v1/cart.proto",
@@ -74,14 +74,17 @@ func (x *Money) GetAmount() int64 {
 }
 
 type QuoteLine struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	UnitPrice     *Money                 `protobuf:"bytes,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
-	LineTotal     *Money                 `protobuf:"bytes,5,opt,name=line_total,json=lineTotal,proto3" json:"line_total,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ProductId        string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Name             string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity         int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitPrice        *Money                 `protobuf:"bytes,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	LineTotal        *Money                 `protobuf:"bytes,5,opt,name=line_total,json=lineTotal,proto3" json:"line_total,omitempty"`
+	VariantId        string                 `protobuf:"bytes,6,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"` // empty for products without variants
+	Sku              string                 `protobuf:"bytes,7,opt,name=sku,proto3" json:"sku,omitempty"`
+	DisplayLineTotal *Money                 `protobuf:"bytes,8,opt,name=display_line_total,json=displayLineTotal,proto3" json:"display_line_total,omitempty"` // line_total in the currency of the quote total
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *QuoteLine) Reset() {
@@ -149,9 +152,39 @@ func (x *QuoteLine) GetLineTotal() *Money {
 	return nil
 }
 
+func (x *QuoteLine) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *QuoteLine) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *QuoteLine) GetDisplayLineTotal() *Money {
+	if x != nil {
+		return x.DisplayLineTotal
+	}
+	return nil
+}
+
 type QuoteRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// ISO-4217 code to show the total in. Empty keeps the catalog currency,
+	// which requires every line to share one.
+	DisplayCurrency string `protobuf:"bytes,2,opt,name=display_currency,json=displayCurrency,proto3" json:"display_currency,omitempty"`
+	// Promotion codes to apply on top of the automatic promotions. Codes
+	// that don't apply come back in rejected_codes.
+	CouponCodes []string `protobuf:"bytes,3,rep,name=coupon_codes,json=couponCodes,proto3" json:"coupon_codes,omitempty"`
+	// ISO 3166-1 alpha-2 code of the shipping destination. Empty prices
+	// shipping to the default zone.
+	Country       string `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -193,17 +226,346 @@ func (x *QuoteRequest) GetUserId() string {
 	return ""
 }
 
-type QuoteResponse struct {
+func (x *QuoteRequest) GetDisplayCurrency() string {
+	if x != nil {
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+func (x *QuoteRequest) GetCouponCodes() []string {
+	if x != nil {
+		return x.CouponCodes
+	}
+	return nil
+}
+
+func (x *QuoteRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+// ShippingOption is a way the cart can ship, with its fee and delivery
+// estimate.
+type ShippingOption struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"` // e.g. STANDARD or EXPRESS
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Fee           *Money                 `protobuf:"bytes,3,opt,name=fee,proto3" json:"fee,omitempty"`                         // in the currency of the quote total; zero with free_shipping
+	MinDays       int32                  `protobuf:"varint,4,opt,name=min_days,json=minDays,proto3" json:"min_days,omitempty"` // business days until delivery
+	MaxDays       int32                  `protobuf:"varint,5,opt,name=max_days,json=maxDays,proto3" json:"max_days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShippingOption) Reset() {
+	*x = ShippingOption{}
+	mi := &file_checkout_v1_checkout_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingOption) ProtoMessage() {}
+
+func (x *ShippingOption) ProtoReflect() protoreflect.Message {
+	mi := &file_checkout_v1_checkout_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingOption.ProtoReflect.Descriptor instead.
+func (*ShippingOption) Descriptor() ([]byte, []int) {
+	return file_checkout_v1_checkout_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ShippingOption) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ShippingOption) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ShippingOption) GetFee() *Money {
+	if x != nil {
+		return x.Fee
+	}
+	return nil
+}
+
+func (x *ShippingOption) GetMinDays() int32 {
+	if x != nil {
+		return x.MinDays
+	}
+	return 0
+}
+
+func (x *ShippingOption) GetMaxDays() int32 {
+	if x != nil {
+		return x.MaxDays
+	}
+	return 0
+}
+
+// Discount is a promotion applied to the quote.
+type Discount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PromotionId   string                 `protobuf:"bytes,1,opt,name=promotion_id,json=promotionId,proto3" json:"promotion_id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"` // empty for automatic promotions
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Amount        *Money                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`                                    // in the catalog currency; zero for free shipping
+	DisplayAmount *Money                 `protobuf:"bytes,5,opt,name=display_amount,json=displayAmount,proto3" json:"display_amount,omitempty"` // amount in the currency of the quote total
+	FreeShipping  bool                   `protobuf:"varint,6,opt,name=free_shipping,json=freeShipping,proto3" json:"free_shipping,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Discount) Reset() {
+	*x = Discount{}
+	mi := &file_checkout_v1_checkout_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Discount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discount) ProtoMessage() {}
+
+func (x *Discount) ProtoReflect() protoreflect.Message {
+	mi := &file_checkout_v1_checkout_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discount.ProtoReflect.Descriptor instead.
+func (*Discount) Descriptor() ([]byte, []int) {
+	return file_checkout_v1_checkout_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Discount) GetPromotionId() string {
+	if x != nil {
+		return x.PromotionId
+	}
+	return ""
+}
+
+func (x *Discount) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Discount) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Discount) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Discount) GetDisplayAmount() *Money {
+	if x != nil {
+		return x.DisplayAmount
+	}
+	return nil
+}
+
+func (x *Discount) GetFreeShipping() bool {
+	if x != nil {
+		return x.FreeShipping
+	}
+	return false
+}
+
+type RejectedCode struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Lines         []*QuoteLine           `protobuf:"bytes,1,rep,name=lines,proto3" json:"lines,omitempty"`
-	Total         *Money                 `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // e.g. "has expired"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectedCode) Reset() {
+	*x = RejectedCode{}
+	mi := &file_checkout_v1_checkout_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectedCode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectedCode) ProtoMessage() {}
+
+func (x *RejectedCode) ProtoReflect() protoreflect.Message {
+	mi := &file_checkout_v1_checkout_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectedCode.ProtoReflect.Descriptor instead.
+func (*RejectedCode) Descriptor() ([]byte, []int) {
+	return file_checkout_v1_checkout_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RejectedCode) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *RejectedCode) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Notice warns about a cart line that changed since it was added.
+type Notice struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId string                 `protobuf:"bytes,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	// PRICE_INCREASED, PRICE_DECREASED, PRODUCT_ARCHIVED, PRODUCT_UNAVAILABLE
+	// or OUT_OF_STOCK
+	Kind          string `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	OldPrice      *Money `protobuf:"bytes,4,opt,name=old_price,json=oldPrice,proto3" json:"old_price,omitempty"` // price notices only
+	NewPrice      *Money `protobuf:"bytes,5,opt,name=new_price,json=newPrice,proto3" json:"new_price,omitempty"` // price notices only
+	Available     int32  `protobuf:"varint,6,opt,name=available,proto3" json:"available,omitempty"`              // OUT_OF_STOCK only
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *Notice) Reset() {
+	*x = Notice{}
+	mi := &file_checkout_v1_checkout_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Notice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Notice) ProtoMessage() {}
+
+func (x *Notice) ProtoReflect() protoreflect.Message {
+	mi := &file_checkout_v1_checkout_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Notice.ProtoReflect.Descriptor instead.
+func (*Notice) Descriptor() ([]byte, []int) {
+	return file_checkout_v1_checkout_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Notice) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Notice) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+func (x *Notice) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Notice) GetOldPrice() *Money {
+	if x != nil {
+		return x.OldPrice
+	}
+	return nil
+}
+
+func (x *Notice) GetNewPrice() *Money {
+	if x != nil {
+		return x.NewPrice
+	}
+	return nil
+}
+
+func (x *Notice) GetAvailable() int32 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+type QuoteResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Lines           []*QuoteLine           `protobuf:"bytes,1,rep,name=lines,proto3" json:"lines,omitempty"`
+	Total           *Money                 `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"` // subtotal less the discounts
+	Notices         []*Notice              `protobuf:"bytes,3,rep,name=notices,proto3" json:"notices,omitempty"`
+	Subtotal        *Money                 `protobuf:"bytes,4,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	Discounts       []*Discount            `protobuf:"bytes,5,rep,name=discounts,proto3" json:"discounts,omitempty"`
+	RejectedCodes   []*RejectedCode        `protobuf:"bytes,6,rep,name=rejected_codes,json=rejectedCodes,proto3" json:"rejected_codes,omitempty"`
+	FreeShipping    bool                   `protobuf:"varint,7,opt,name=free_shipping,json=freeShipping,proto3" json:"free_shipping,omitempty"`         // a discount waives the shipping fee
+	ShippingOptions []*ShippingOption      `protobuf:"bytes,8,rep,name=shipping_options,json=shippingOptions,proto3" json:"shipping_options,omitempty"` // cheapest first
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
 func (x *QuoteResponse) Reset() {
 	*x = QuoteResponse{}
-	mi := &file_checkout_v1_checkout_proto_msgTypes[3]
+	mi := &file_checkout_v1_checkout_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -215,7 +577,7 @@ func (x *QuoteResponse) String() string {
 func (*QuoteResponse) ProtoMessage() {}
 
 func (x *QuoteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_checkout_v1_checkout_proto_msgTypes[3]
+	mi := &file_checkout_v1_checkout_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -228,7 +590,7 @@ func (x *QuoteResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QuoteResponse.ProtoReflect.Descriptor instead.
 func (*QuoteResponse) Descriptor() ([]byte, []int) {
-	return file_checkout_v1_checkout_proto_rawDescGZIP(), []int{3}
+	return file_checkout_v1_checkout_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *QuoteResponse) GetLines() []*QuoteLine {
@@ -245,6 +607,227 @@ func (x *QuoteResponse) GetTotal() *Money {
 	return nil
 }
 
+func (x *QuoteResponse) GetNotices() []*Notice {
+	if x != nil {
+		return x.Notices
+	}
+	return nil
+}
+
+func (x *QuoteResponse) GetSubtotal() *Money {
+	if x != nil {
+		return x.Subtotal
+	}
+	return nil
+}
+
+func (x *QuoteResponse) GetDiscounts() []*Discount {
+	if x != nil {
+		return x.Discounts
+	}
+	return nil
+}
+
+func (x *QuoteResponse) GetRejectedCodes() []*RejectedCode {
+	if x != nil {
+		return x.RejectedCodes
+	}
+	return nil
+}
+
+func (x *QuoteResponse) GetFreeShipping() bool {
+	if x != nil {
+		return x.FreeShipping
+	}
+	return false
+}
+
+func (x *QuoteResponse) GetShippingOptions() []*ShippingOption {
+	if x != nil {
+		return x.ShippingOptions
+	}
+	return nil
+}
+
+type PlaceOrderRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// One of the quote's shipping_options; STANDARD by default.
+	ShippingOption string `protobuf:"bytes,2,opt,name=shipping_option,json=shippingOption,proto3" json:"shipping_option,omitempty"`
+	// Every code must apply, otherwise the order fails with
+	// FAILED_PRECONDITION; quote first to see which do.
+	CouponCodes   []string `protobuf:"bytes,3,rep,name=coupon_codes,json=couponCodes,proto3" json:"coupon_codes,omitempty"`
+	Country       string   `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"` // required; ISO 3166-1 alpha-2 code of the destination
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceOrderRequest) Reset() {
+	*x = PlaceOrderRequest{}
+	mi := &file_checkout_v1_checkout_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceOrderRequest) ProtoMessage() {}
+
+func (x *PlaceOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_checkout_v1_checkout_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceOrderRequest.ProtoReflect.Descriptor instead.
+func (*PlaceOrderRequest) Descriptor() ([]byte, []int) {
+	return file_checkout_v1_checkout_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PlaceOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetShippingOption() string {
+	if x != nil {
+		return x.ShippingOption
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetCouponCodes() []string {
+	if x != nil {
+		return x.CouponCodes
+	}
+	return nil
+}
+
+func (x *PlaceOrderRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+type PlaceOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Lines         []*QuoteLine           `protobuf:"bytes,3,rep,name=lines,proto3" json:"lines,omitempty"`
+	ShippingFee   *Money                 `protobuf:"bytes,4,opt,name=shipping_fee,json=shippingFee,proto3" json:"shipping_fee,omitempty"`
+	Total         *Money                 `protobuf:"bytes,5,opt,name=total,proto3" json:"total,omitempty"`
+	CreatedAtUnix int64                  `protobuf:"varint,6,opt,name=created_at_unix,json=createdAtUnix,proto3" json:"created_at_unix,omitempty"`
+	Discounts     []*Discount            `protobuf:"bytes,7,rep,name=discounts,proto3" json:"discounts,omitempty"`
+	Discount      *Money                 `protobuf:"bytes,8,opt,name=discount,proto3" json:"discount,omitempty"` // all discounts together; total is net of it
+	Shipping      *ShippingOption        `protobuf:"bytes,9,opt,name=shipping,proto3" json:"shipping,omitempty"` // the chosen option; its fee is shipping_fee
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlaceOrderResponse) Reset() {
+	*x = PlaceOrderResponse{}
+	mi := &file_checkout_v1_checkout_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceOrderResponse) ProtoMessage() {}
+
+func (x *PlaceOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_checkout_v1_checkout_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceOrderResponse.ProtoReflect.Descriptor instead.
+func (*PlaceOrderResponse) Descriptor() ([]byte, []int) {
+	return file_checkout_v1_checkout_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PlaceOrderResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PlaceOrderResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PlaceOrderResponse) GetLines() []*QuoteLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *PlaceOrderResponse) GetShippingFee() *Money {
+	if x != nil {
+		return x.ShippingFee
+	}
+	return nil
+}
+
+func (x *PlaceOrderResponse) GetTotal() *Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *PlaceOrderResponse) GetCreatedAtUnix() int64 {
+	if x != nil {
+		return x.CreatedAtUnix
+	}
+	return 0
+}
+
+func (x *PlaceOrderResponse) GetDiscounts() []*Discount {
+	if x != nil {
+		return x.Discounts
+	}
+	return nil
+}
+
+func (x *PlaceOrderResponse) GetDiscount() *Money {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+func (x *PlaceOrderResponse) GetShipping() *ShippingOption {
+	if x != nil {
+		return x.Shipping
+	}
+	return nil
+}
+
 var File_checkout_v1_checkout_proto protoreflect.FileDescriptor
 
 const file_checkout_v1_checkout_proto_rawDesc = "" +
@@ -252,7 +835,7 @@ const file_checkout_v1_checkout_proto_rawDesc = "" +
 	"\x1acheckout/v1/checkout.proto\x12\vcheckout.v1\";\n" +
 	"\x05Money\x12\x1a\n" +
 	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x16\n" +
-	"\x06amount\x18\x02 \x01(\x03R\x06amount\"\xc0\x01\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\"\xb3\x02\n" +
 	"\tQuoteLine\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12\x12\n" +
@@ -261,14 +844,69 @@ const file_checkout_v1_checkout_proto_rawDesc = "" +
 	"\n" +
 	"unit_price\x18\x04 \x01(\v2\x12.checkout.v1.MoneyR\tunitPrice\x121\n" +
 	"\n" +
-	"line_total\x18\x05 \x01(\v2\x12.checkout.v1.MoneyR\tlineTotal\"'\n" +
+	"line_total\x18\x05 \x01(\v2\x12.checkout.v1.MoneyR\tlineTotal\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x06 \x01(\tR\tvariantId\x12\x10\n" +
+	"\x03sku\x18\a \x01(\tR\x03sku\x12@\n" +
+	"\x12display_line_total\x18\b \x01(\v2\x12.checkout.v1.MoneyR\x10displayLineTotal\"\x8f\x01\n" +
 	"\fQuoteRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"g\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12)\n" +
+	"\x10display_currency\x18\x02 \x01(\tR\x0fdisplayCurrency\x12!\n" +
+	"\fcoupon_codes\x18\x03 \x03(\tR\vcouponCodes\x12\x18\n" +
+	"\acountry\x18\x04 \x01(\tR\acountry\"\x94\x01\n" +
+	"\x0eShippingOption\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12$\n" +
+	"\x03fee\x18\x03 \x01(\v2\x12.checkout.v1.MoneyR\x03fee\x12\x19\n" +
+	"\bmin_days\x18\x04 \x01(\x05R\aminDays\x12\x19\n" +
+	"\bmax_days\x18\x05 \x01(\x05R\amaxDays\"\xe1\x01\n" +
+	"\bDiscount\x12!\n" +
+	"\fpromotion_id\x18\x01 \x01(\tR\vpromotionId\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12*\n" +
+	"\x06amount\x18\x04 \x01(\v2\x12.checkout.v1.MoneyR\x06amount\x129\n" +
+	"\x0edisplay_amount\x18\x05 \x01(\v2\x12.checkout.v1.MoneyR\rdisplayAmount\x12#\n" +
+	"\rfree_shipping\x18\x06 \x01(\bR\ffreeShipping\":\n" +
+	"\fRejectedCode\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xda\x01\n" +
+	"\x06Notice\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\tR\tvariantId\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12/\n" +
+	"\told_price\x18\x04 \x01(\v2\x12.checkout.v1.MoneyR\boldPrice\x12/\n" +
+	"\tnew_price\x18\x05 \x01(\v2\x12.checkout.v1.MoneyR\bnewPrice\x12\x1c\n" +
+	"\tavailable\x18\x06 \x01(\x05R\tavailable\"\xaa\x03\n" +
 	"\rQuoteResponse\x12,\n" +
 	"\x05lines\x18\x01 \x03(\v2\x16.checkout.v1.QuoteLineR\x05lines\x12(\n" +
-	"\x05total\x18\x02 \x01(\v2\x12.checkout.v1.MoneyR\x05total2Q\n" +
+	"\x05total\x18\x02 \x01(\v2\x12.checkout.v1.MoneyR\x05total\x12-\n" +
+	"\anotices\x18\x03 \x03(\v2\x13.checkout.v1.NoticeR\anotices\x12.\n" +
+	"\bsubtotal\x18\x04 \x01(\v2\x12.checkout.v1.MoneyR\bsubtotal\x123\n" +
+	"\tdiscounts\x18\x05 \x03(\v2\x15.checkout.v1.DiscountR\tdiscounts\x12@\n" +
+	"\x0erejected_codes\x18\x06 \x03(\v2\x19.checkout.v1.RejectedCodeR\rrejectedCodes\x12#\n" +
+	"\rfree_shipping\x18\a \x01(\bR\ffreeShipping\x12F\n" +
+	"\x10shipping_options\x18\b \x03(\v2\x1b.checkout.v1.ShippingOptionR\x0fshippingOptions\"\x92\x01\n" +
+	"\x11PlaceOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12'\n" +
+	"\x0fshipping_option\x18\x02 \x01(\tR\x0eshippingOption\x12!\n" +
+	"\fcoupon_codes\x18\x03 \x03(\tR\vcouponCodes\x12\x18\n" +
+	"\acountry\x18\x04 \x01(\tR\acountry\"\x9c\x03\n" +
+	"\x12PlaceOrderResponse\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12,\n" +
+	"\x05lines\x18\x03 \x03(\v2\x16.checkout.v1.QuoteLineR\x05lines\x125\n" +
+	"\fshipping_fee\x18\x04 \x01(\v2\x12.checkout.v1.MoneyR\vshippingFee\x12(\n" +
+	"\x05total\x18\x05 \x01(\v2\x12.checkout.v1.MoneyR\x05total\x12&\n" +
+	"\x0fcreated_at_unix\x18\x06 \x01(\x03R\rcreatedAtUnix\x123\n" +
+	"\tdiscounts\x18\a \x03(\v2\x15.checkout.v1.DiscountR\tdiscounts\x12.\n" +
+	"\bdiscount\x18\b \x01(\v2\x12.checkout.v1.MoneyR\bdiscount\x127\n" +
+	"\bshipping\x18\t \x01(\v2\x1b.checkout.v1.ShippingOptionR\bshipping2\xa0\x01\n" +
 	"\x0fCheckoutService\x12>\n" +
-	"\x05Quote\x12\x19.checkout.v1.QuoteRequest\x1a\x1a.checkout.v1.QuoteResponseBCZAgithub.com/dwikikusuma/shoping-llm/api/gen/checkout/v1;checkoutv1b\x06proto3"
+	"\x05Quote\x12\x19.checkout.v1.QuoteRequest\x1a\x1a.checkout.v1.QuoteResponse\x12M\n" +
+	"\n" +
+	"PlaceOrder\x12\x1e.checkout.v1.PlaceOrderRequest\x1a\x1f.checkout.v1.PlaceOrderResponseBCZAgithub.com/dwikikusuma/shoping-llm/api/gen/checkout/v1;checkoutv1b\x06proto3"
 
 var (
 	file_checkout_v1_checkout_proto_rawDescOnce sync.Once
@@ -282,25 +920,50 @@ func file_checkout_v1_checkout_proto_rawDescGZIP() []byte {
 	return file_checkout_v1_checkout_proto_rawDescData
 }
 
-var file_checkout_v1_checkout_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_checkout_v1_checkout_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_checkout_v1_checkout_proto_goTypes = []any{
-	(*Money)(nil),         // 0: checkout.v1.Money
-	(*QuoteLine)(nil),     // 1: checkout.v1.QuoteLine
-	(*QuoteRequest)(nil),  // 2: checkout.v1.QuoteRequest
-	(*QuoteResponse)(nil), // 3: checkout.v1.QuoteResponse
+	(*Money)(nil),              // 0: checkout.v1.Money
+	(*QuoteLine)(nil),          // 1: checkout.v1.QuoteLine
+	(*QuoteRequest)(nil),       // 2: checkout.v1.QuoteRequest
+	(*ShippingOption)(nil),     // 3: checkout.v1.ShippingOption
+	(*Discount)(nil),           // 4: checkout.v1.Discount
+	(*RejectedCode)(nil),       // 5: checkout.v1.RejectedCode
+	(*Notice)(nil),             // 6: checkout.v1.Notice
+	(*QuoteResponse)(nil),      // 7: checkout.v1.QuoteResponse
+	(*PlaceOrderRequest)(nil),  // 8: checkout.v1.PlaceOrderRequest
+	(*PlaceOrderResponse)(nil), // 9: checkout.v1.PlaceOrderResponse
 }
 var file_checkout_v1_checkout_proto_depIdxs = []int32{
-	0, // 0: checkout.v1.QuoteLine.unit_price:type_name -> checkout.v1.Money
-	0, // 1: checkout.v1.QuoteLine.line_total:type_name -> checkout.v1.Money
-	1, // 2: checkout.v1.QuoteResponse.lines:type_name -> checkout.v1.QuoteLine
-	0, // 3: checkout.v1.QuoteResponse.total:type_name -> checkout.v1.Money
-	2, // 4: checkout.v1.CheckoutService.Quote:input_type -> checkout.v1.QuoteRequest
-	3, // 5: checkout.v1.CheckoutService.Quote:output_type -> checkout.v1.QuoteResponse
-	5, // [5:6] is the sub-list for method output_type
-	4, // [4:5] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	0,  // 0: checkout.v1.QuoteLine.unit_price:type_name -> checkout.v1.Money
+	0,  // 1: checkout.v1.QuoteLine.line_total:type_name -> checkout.v1.Money
+	0,  // 2: checkout.v1.QuoteLine.display_line_total:type_name -> checkout.v1.Money
+	0,  // 3: checkout.v1.ShippingOption.fee:type_name -> checkout.v1.Money
+	0,  // 4: checkout.v1.Discount.amount:type_name -> checkout.v1.Money
+	0,  // 5: checkout.v1.Discount.display_amount:type_name -> checkout.v1.Money
+	0,  // 6: checkout.v1.Notice.old_price:type_name -> checkout.v1.Money
+	0,  // 7: checkout.v1.Notice.new_price:type_name -> checkout.v1.Money
+	1,  // 8: checkout.v1.QuoteResponse.lines:type_name -> checkout.v1.QuoteLine
+	0,  // 9: checkout.v1.QuoteResponse.total:type_name -> checkout.v1.Money
+	6,  // 10: checkout.v1.QuoteResponse.notices:type_name -> checkout.v1.Notice
+	0,  // 11: checkout.v1.QuoteResponse.subtotal:type_name -> checkout.v1.Money
+	4,  // 12: checkout.v1.QuoteResponse.discounts:type_name -> checkout.v1.Discount
+	5,  // 13: checkout.v1.QuoteResponse.rejected_codes:type_name -> checkout.v1.RejectedCode
+	3,  // 14: checkout.v1.QuoteResponse.shipping_options:type_name -> checkout.v1.ShippingOption
+	1,  // 15: checkout.v1.PlaceOrderResponse.lines:type_name -> checkout.v1.QuoteLine
+	0,  // 16: checkout.v1.PlaceOrderResponse.shipping_fee:type_name -> checkout.v1.Money
+	0,  // 17: checkout.v1.PlaceOrderResponse.total:type_name -> checkout.v1.Money
+	4,  // 18: checkout.v1.PlaceOrderResponse.discounts:type_name -> checkout.v1.Discount
+	0,  // 19: checkout.v1.PlaceOrderResponse.discount:type_name -> checkout.v1.Money
+	3,  // 20: checkout.v1.PlaceOrderResponse.shipping:type_name -> checkout.v1.ShippingOption
+	2,  // 21: checkout.v1.CheckoutService.Quote:input_type -> checkout.v1.QuoteRequest
+	8,  // 22: checkout.v1.CheckoutService.PlaceOrder:input_type -> checkout.v1.PlaceOrderRequest
+	7,  // 23: checkout.v1.CheckoutService.Quote:output_type -> checkout.v1.QuoteResponse
+	9,  // 24: checkout.v1.CheckoutService.PlaceOrder:output_type -> checkout.v1.PlaceOrderResponse
+	23, // [23:25] is the sub-list for method output_type
+	21, // [21:23] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_checkout_v1_checkout_proto_init() }
@@ -314,7 +977,7 @@ func file_checkout_v1_checkout_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_checkout_v1_checkout_proto_rawDesc), len(file_checkout_v1_checkout_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,528 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v3.21.12
+// source: promotion/v1/promotion.proto
+
+package promotionv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Money) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+// A promotion applies to every cart that qualifies when it has no code, and
+// to carts whose shopper entered the code otherwise. Stackable promotions
+// combine with each other; any other promotion only applies on its own.
+type Promotion struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code           string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"` // empty for automatic promotions
+	Name           string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Kind           string                 `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`                                                 // PERCENT_OFF, FIXED_OFF, BUY_X_GET_Y or FREE_SHIPPING
+	PercentOff     int32                  `protobuf:"varint,5,opt,name=percent_off,json=percentOff,proto3" json:"percent_off,omitempty"`                  // PERCENT_OFF: 1 to 100
+	AmountOff      *Money                 `protobuf:"bytes,6,opt,name=amount_off,json=amountOff,proto3" json:"amount_off,omitempty"`                      // FIXED_OFF
+	ProductId      string                 `protobuf:"bytes,7,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`                      // BUY_X_GET_Y; optional for PERCENT_OFF
+	BuyQuantity    int32                  `protobuf:"varint,8,opt,name=buy_quantity,json=buyQuantity,proto3" json:"buy_quantity,omitempty"`               // BUY_X_GET_Y
+	GetQuantity    int32                  `protobuf:"varint,9,opt,name=get_quantity,json=getQuantity,proto3" json:"get_quantity,omitempty"`               // BUY_X_GET_Y: free units of every buy+get
+	MinSpend       *Money                 `protobuf:"bytes,10,opt,name=min_spend,json=minSpend,proto3" json:"min_spend,omitempty"`                        // cart subtotal needed to qualify
+	StartsAtUnix   int64                  `protobuf:"varint,11,opt,name=starts_at_unix,json=startsAtUnix,proto3" json:"starts_at_unix,omitempty"`         // 0: already started
+	EndsAtUnix     int64                  `protobuf:"varint,12,opt,name=ends_at_unix,json=endsAtUnix,proto3" json:"ends_at_unix,omitempty"`               // 0: never ends
+	MaxUses        int32                  `protobuf:"varint,13,opt,name=max_uses,json=maxUses,proto3" json:"max_uses,omitempty"`                          // orders in total; 0 is unlimited
+	MaxUsesPerUser int32                  `protobuf:"varint,14,opt,name=max_uses_per_user,json=maxUsesPerUser,proto3" json:"max_uses_per_user,omitempty"` // orders per user; 0 is unlimited
+	Stackable      bool                   `protobuf:"varint,15,opt,name=stackable,proto3" json:"stackable,omitempty"`
+	Active         bool                   `protobuf:"varint,16,opt,name=active,proto3" json:"active,omitempty"`
+	CreatedAtUnix  int64                  `protobuf:"varint,17,opt,name=created_at_unix,json=createdAtUnix,proto3" json:"created_at_unix,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Promotion) Reset() {
+	*x = Promotion{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Promotion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Promotion) ProtoMessage() {}
+
+func (x *Promotion) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Promotion.ProtoReflect.Descriptor instead.
+func (*Promotion) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Promotion) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Promotion) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Promotion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Promotion) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Promotion) GetPercentOff() int32 {
+	if x != nil {
+		return x.PercentOff
+	}
+	return 0
+}
+
+func (x *Promotion) GetAmountOff() *Money {
+	if x != nil {
+		return x.AmountOff
+	}
+	return nil
+}
+
+func (x *Promotion) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Promotion) GetBuyQuantity() int32 {
+	if x != nil {
+		return x.BuyQuantity
+	}
+	return 0
+}
+
+func (x *Promotion) GetGetQuantity() int32 {
+	if x != nil {
+		return x.GetQuantity
+	}
+	return 0
+}
+
+func (x *Promotion) GetMinSpend() *Money {
+	if x != nil {
+		return x.MinSpend
+	}
+	return nil
+}
+
+func (x *Promotion) GetStartsAtUnix() int64 {
+	if x != nil {
+		return x.StartsAtUnix
+	}
+	return 0
+}
+
+func (x *Promotion) GetEndsAtUnix() int64 {
+	if x != nil {
+		return x.EndsAtUnix
+	}
+	return 0
+}
+
+func (x *Promotion) GetMaxUses() int32 {
+	if x != nil {
+		return x.MaxUses
+	}
+	return 0
+}
+
+func (x *Promotion) GetMaxUsesPerUser() int32 {
+	if x != nil {
+		return x.MaxUsesPerUser
+	}
+	return 0
+}
+
+func (x *Promotion) GetStackable() bool {
+	if x != nil {
+		return x.Stackable
+	}
+	return false
+}
+
+func (x *Promotion) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Promotion) GetCreatedAtUnix() int64 {
+	if x != nil {
+		return x.CreatedAtUnix
+	}
+	return 0
+}
+
+// Admin only, like the rest of this service. Codes are case-insensitive and
+// stored upper case.
+type CreatePromotionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Promotion     *Promotion             `protobuf:"bytes,1,opt,name=promotion,proto3" json:"promotion,omitempty"` // id, active and created_at_unix are ignored
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePromotionRequest) Reset() {
+	*x = CreatePromotionRequest{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePromotionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePromotionRequest) ProtoMessage() {}
+
+func (x *CreatePromotionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePromotionRequest.ProtoReflect.Descriptor instead.
+func (*CreatePromotionRequest) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreatePromotionRequest) GetPromotion() *Promotion {
+	if x != nil {
+		return x.Promotion
+	}
+	return nil
+}
+
+type ListPromotionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPromotionsRequest) Reset() {
+	*x = ListPromotionsRequest{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPromotionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPromotionsRequest) ProtoMessage() {}
+
+func (x *ListPromotionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPromotionsRequest.ProtoReflect.Descriptor instead.
+func (*ListPromotionsRequest) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{3}
+}
+
+type ListPromotionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Promotions    []*Promotion           `protobuf:"bytes,1,rep,name=promotions,proto3" json:"promotions,omitempty"` // newest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPromotionsResponse) Reset() {
+	*x = ListPromotionsResponse{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPromotionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPromotionsResponse) ProtoMessage() {}
+
+func (x *ListPromotionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPromotionsResponse.ProtoReflect.Descriptor instead.
+func (*ListPromotionsResponse) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListPromotionsResponse) GetPromotions() []*Promotion {
+	if x != nil {
+		return x.Promotions
+	}
+	return nil
+}
+
+// Deactivating stops the promotion from applying to new quotes and orders.
+type DeactivatePromotionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivatePromotionRequest) Reset() {
+	*x = DeactivatePromotionRequest{}
+	mi := &file_promotion_v1_promotion_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivatePromotionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivatePromotionRequest) ProtoMessage() {}
+
+func (x *DeactivatePromotionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_promotion_v1_promotion_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivatePromotionRequest.ProtoReflect.Descriptor instead.
+func (*DeactivatePromotionRequest) Descriptor() ([]byte, []int) {
+	return file_promotion_v1_promotion_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeactivatePromotionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_promotion_v1_promotion_proto protoreflect.FileDescriptor
+
+const file_promotion_v1_promotion_proto_rawDesc = "" +
+	"\n" +
+	"\x1cpromotion/v1/promotion.proto\x12\fpromotion.v1\";\n" +
+	"\x05Money\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\"\xaf\x04\n" +
+	"\tPromotion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12\x1f\n" +
+	"\vpercent_off\x18\x05 \x01(\x05R\n" +
+	"percentOff\x122\n" +
+	"\n" +
+	"amount_off\x18\x06 \x01(\v2\x13.promotion.v1.MoneyR\tamountOff\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\a \x01(\tR\tproductId\x12!\n" +
+	"\fbuy_quantity\x18\b \x01(\x05R\vbuyQuantity\x12!\n" +
+	"\fget_quantity\x18\t \x01(\x05R\vgetQuantity\x120\n" +
+	"\tmin_spend\x18\n" +
+	" \x01(\v2\x13.promotion.v1.MoneyR\bminSpend\x12$\n" +
+	"\x0estarts_at_unix\x18\v \x01(\x03R\fstartsAtUnix\x12 \n" +
+	"\fends_at_unix\x18\f \x01(\x03R\n" +
+	"endsAtUnix\x12\x19\n" +
+	"\bmax_uses\x18\r \x01(\x05R\amaxUses\x12)\n" +
+	"\x11max_uses_per_user\x18\x0e \x01(\x05R\x0emaxUsesPerUser\x12\x1c\n" +
+	"\tstackable\x18\x0f \x01(\bR\tstackable\x12\x16\n" +
+	"\x06active\x18\x10 \x01(\bR\x06active\x12&\n" +
+	"\x0fcreated_at_unix\x18\x11 \x01(\x03R\rcreatedAtUnix\"O\n" +
+	"\x16CreatePromotionRequest\x125\n" +
+	"\tpromotion\x18\x01 \x01(\v2\x17.promotion.v1.PromotionR\tpromotion\"\x17\n" +
+	"\x15ListPromotionsRequest\"Q\n" +
+	"\x16ListPromotionsResponse\x127\n" +
+	"\n" +
+	"promotions\x18\x01 \x03(\v2\x17.promotion.v1.PromotionR\n" +
+	"promotions\",\n" +
+	"\x1aDeactivatePromotionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id2\x9b\x02\n" +
+	"\x10PromotionService\x12P\n" +
+	"\x0fCreatePromotion\x12$.promotion.v1.CreatePromotionRequest\x1a\x17.promotion.v1.Promotion\x12[\n" +
+	"\x0eListPromotions\x12#.promotion.v1.ListPromotionsRequest\x1a$.promotion.v1.ListPromotionsResponse\x12X\n" +
+	"\x13DeactivatePromotion\x12(.promotion.v1.DeactivatePromotionRequest\x1a\x17.promotion.v1.PromotionBEZCgithub.com/dwikikusuma/shoping-llm/api/gen/promotion/v1;promotionv1b\x06proto3"
+
+var (
+	file_promotion_v1_promotion_proto_rawDescOnce sync.Once
+	file_promotion_v1_promotion_proto_rawDescData []byte
+)
+
+func file_promotion_v1_promotion_proto_rawDescGZIP() []byte {
+	file_promotion_v1_promotion_proto_rawDescOnce.Do(func() {
+		file_promotion_v1_promotion_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_promotion_v1_promotion_proto_rawDesc), len(file_promotion_v1_promotion_proto_rawDesc)))
+	})
+	return file_promotion_v1_promotion_proto_rawDescData
+}
+
+var file_promotion_v1_promotion_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_promotion_v1_promotion_proto_goTypes = []any{
+	(*Money)(nil),                      // 0: promotion.v1.Money
+	(*Promotion)(nil),                  // 1: promotion.v1.Promotion
+	(*CreatePromotionRequest)(nil),     // 2: promotion.v1.CreatePromotionRequest
+	(*ListPromotionsRequest)(nil),      // 3: promotion.v1.ListPromotionsRequest
+	(*ListPromotionsResponse)(nil),     // 4: promotion.v1.ListPromotionsResponse
+	(*DeactivatePromotionRequest)(nil), // 5: promotion.v1.DeactivatePromotionRequest
+}
+var file_promotion_v1_promotion_proto_depIdxs = []int32{
+	0, // 0: promotion.v1.Promotion.amount_off:type_name -> promotion.v1.Money
+	0, // 1: promotion.v1.Promotion.min_spend:type_name -> promotion.v1.Money
+	1, // 2: promotion.v1.CreatePromotionRequest.promotion:type_name -> promotion.v1.Promotion
+	1, // 3: promotion.v1.ListPromotionsResponse.promotions:type_name -> promotion.v1.Promotion
+	2, // 4: promotion.v1.PromotionService.CreatePromotion:input_type -> promotion.v1.CreatePromotionRequest
+	3, // 5: promotion.v1.PromotionService.ListPromotions:input_type -> promotion.v1.ListPromotionsRequest
+	5, // 6: promotion.v1.PromotionService.DeactivatePromotion:input_type -> promotion.v1.DeactivatePromotionRequest
+	1, // 7: promotion.v1.PromotionService.CreatePromotion:output_type -> promotion.v1.Promotion
+	4, // 8: promotion.v1.PromotionService.ListPromotions:output_type -> promotion.v1.ListPromotionsResponse
+	1, // 9: promotion.v1.PromotionService.DeactivatePromotion:output_type -> promotion.v1.Promotion
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_promotion_v1_promotion_proto_init() }
+func file_promotion_v1_promotion_proto_init() {
+	if File_promotion_v1_promotion_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_promotion_v1_promotion_proto_rawDesc), len(file_promotion_v1_promotion_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_promotion_v1_promotion_proto_goTypes,
+		DependencyIndexes: file_promotion_v1_promotion_proto_depIdxs,
+		MessageInfos:      file_promotion_v1_promotion_proto_msgTypes,
+	}.Build()
+	File_promotion_v1_promotion_proto = out.File
+	file_promotion_v1_promotion_proto_goTypes = nil
+	file_promotion_v1_promotion_proto_depIdxs = nil
+}
@@ -0,0 +1,173 @@
+// Command catalog-import loads products from a CSV or JSONL file through the
+// ImportProducts RPC, or dumps the catalog with ExportProducts.
+//
+//	catalog-import [-dry-run] [-format csv|jsonl] products.csv
+//	catalog-import -export [-format csv|jsonl] [-o products.csv]
+//
+// The server address comes from CATALOG_GRPC_ADDR. An import is all or
+// nothing; failed rows are printed and the command exits non-zero.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	catalogv1 "github.com/dwikikusuma/shoping-llm/api/gen/catalog/v1"
+	"github.com/dwikikusuma/shoping-llm/pkg/config"
+	"github.com/dwikikusuma/shoping-llm/pkg/shutdown"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// chunkSize is how much of the file goes into one ImportProducts message.
+const chunkSize = 64 << 10
+
+func main() {
+	var (
+		dryRun = flag.Bool("dry-run", false, "validate and preview the import without writing")
+		export = flag.Bool("export", false, "export the catalog instead of importing")
+		format = flag.String("format", "", "csv or jsonl (default: from the file extension, csv for exports)")
+		out    = flag.String("o", "", "export destination (default: stdout)")
+	)
+	flag.Parse()
+
+	if err := run(*export, *dryRun, *format, *out, flag.Arg(0)); err != nil {
+		fmt.Fprintln(os.Stderr, "catalog-import:", err)
+		os.Exit(1)
+	}
+}
+
+func run(export, dryRun bool, format, out, in string) error {
+	cfg := config.Load()
+
+	ctx, cancel := shutdown.WithSignals(context.Background())
+	defer cancel()
+
+	conn, err := grpc.NewClient(cfg.CatalogGRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := catalogv1.NewCatalogServiceClient(conn)
+
+	if export {
+		if format == "" {
+			format = formatOf(out)
+		}
+		return exportProducts(ctx, client, format, out)
+	}
+
+	if in == "" {
+		return errors.New("missing input file")
+	}
+	if format == "" {
+		format = formatOf(in)
+	}
+	return importProducts(ctx, client, format, in, dryRun)
+}
+
+func importProducts(ctx context.Context, client catalogv1.CatalogServiceClient, format, path string, dryRun bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stream, err := client.ImportProducts(ctx)
+	if err != nil {
+		return err
+	}
+
+	first := true
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 || first {
+			req := &catalogv1.ImportProductsRequest{Data: buf[:n]}
+			if first {
+				req.Format = format
+				req.DryRun = dryRun
+				first = false
+			}
+			if sendErr := stream.Send(req); sendErr != nil {
+				// the server gave up early; CloseAndRecv has the reason
+				break
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	for _, row := range resp.GetRows() {
+		if row.GetAction() == "failed" {
+			fmt.Fprintf(os.Stderr, "line %d (sku %q): %s\n", row.GetLine(), row.GetSku(), row.GetError())
+		}
+	}
+	result := "applied"
+	switch {
+	case dryRun:
+		result = "dry run, nothing applied"
+	case !resp.GetApplied():
+		result = "nothing applied"
+	}
+	fmt.Printf("%d created, %d updated, %d failed (%s)\n", resp.GetCreated(), resp.GetUpdated(), resp.GetFailed(), result)
+
+	if resp.GetFailed() > 0 {
+		return fmt.Errorf("%d rows failed, nothing was imported", resp.GetFailed())
+	}
+	return nil
+}
+
+func exportProducts(ctx context.Context, client catalogv1.CatalogServiceClient, format, path string) error {
+	w := io.Writer(os.Stdout)
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	stream, err := client.ExportProducts(ctx, &catalogv1.ExportProductsRequest{Format: format})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(resp.GetData()); err != nil {
+			return err
+		}
+	}
+}
+
+// formatOf guesses the format from a file name, defaulting to csv.
+func formatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson":
+		return "jsonl"
+	default:
+		return "csv"
+	}
+}
@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"strconv"
 	"sync"
@@ -14,28 +16,64 @@ import (
 	cartv1 "github.com/dwikikusuma/shoping-llm/api/gen/cart/v1"
 	catalogv1 "github.com/dwikikusuma/shoping-llm/api/gen/catalog/v1"
 	checkoutv1 "github.com/dwikikusuma/shoping-llm/api/gen/checkout/v1"
+	inventoryv1 "github.com/dwikikusuma/shoping-llm/api/gen/inventory/v1"
 	orderv1 "github.com/dwikikusuma/shoping-llm/api/gen/order/v1"
+	promotionv1 "github.com/dwikikusuma/shoping-llm/api/gen/promotion/v1"
+	reviewv1 "github.com/dwikikusuma/shoping-llm/api/gen/review/v1"
+	wishlistv1 "github.com/dwikikusuma/shoping-llm/api/gen/wishlist/v1"
 
 	cartapp "github.com/dwikikusuma/shoping-llm/internal/cart/app"
 	cartgrpc "github.com/dwikikusuma/shoping-llm/internal/cart/grpc"
+	cartadapter "github.com/dwikikusuma/shoping-llm/internal/cart/infra/adapter"
+	cartevents "github.com/dwikikusuma/shoping-llm/internal/cart/infra/events"
 	cartpg "github.com/dwikikusuma/shoping-llm/internal/cart/infra/postgres"
 
 	catalogapp "github.com/dwikikusuma/shoping-llm/internal/catalog/app"
 	cgrpc "github.com/dwikikusuma/shoping-llm/internal/catalog/grpc"
+	catalogcache "github.com/dwikikusuma/shoping-llm/internal/catalog/infra/cache"
 	cpg "github.com/dwikikusuma/shoping-llm/internal/catalog/infra/postgres"
 
 	checkoutapp "github.com/dwikikusuma/shoping-llm/internal/checkout/app"
 	checkoutgrpc "github.com/dwikikusuma/shoping-llm/internal/checkout/grpc"
 	checkoutadapter "github.com/dwikikusuma/shoping-llm/internal/checkout/infra/adapter"
 
+	idemapp "github.com/dwikikusuma/shoping-llm/internal/idempotency/app"
+	idemgrpc "github.com/dwikikusuma/shoping-llm/internal/idempotency/grpc"
+	idempg "github.com/dwikikusuma/shoping-llm/internal/idempotency/infra/postgres"
+
+	inventoryapp "github.com/dwikikusuma/shoping-llm/internal/inventory/app"
+	inventorygrpc "github.com/dwikikusuma/shoping-llm/internal/inventory/grpc"
+	inventorypg "github.com/dwikikusuma/shoping-llm/internal/inventory/infra/postgres"
+
 	orderapp "github.com/dwikikusuma/shoping-llm/internal/order/app"
 	ordergrpc "github.com/dwikikusuma/shoping-llm/internal/order/grpc"
+	orderadapter "github.com/dwikikusuma/shoping-llm/internal/order/infra/adapter"
 	orderpg "github.com/dwikikusuma/shoping-llm/internal/order/infra/postgres"
 
+	promotionapp "github.com/dwikikusuma/shoping-llm/internal/promotion/app"
+	promotiongrpc "github.com/dwikikusuma/shoping-llm/internal/promotion/grpc"
+	promotionpg "github.com/dwikikusuma/shoping-llm/internal/promotion/infra/postgres"
+
+	reviewapp "github.com/dwikikusuma/shoping-llm/internal/review/app"
+	reviewgrpc "github.com/dwikikusuma/shoping-llm/internal/review/grpc"
+	reviewadapter "github.com/dwikikusuma/shoping-llm/internal/review/infra/adapter"
+	reviewpg "github.com/dwikikusuma/shoping-llm/internal/review/infra/postgres"
+
+	shippingapp "github.com/dwikikusuma/shoping-llm/internal/shipping/app"
+	shippingtable "github.com/dwikikusuma/shoping-llm/internal/shipping/infra/table"
+
+	wishlistapp "github.com/dwikikusuma/shoping-llm/internal/wishlist/app"
+	wishlistgrpc "github.com/dwikikusuma/shoping-llm/internal/wishlist/grpc"
+	wishlistadapter "github.com/dwikikusuma/shoping-llm/internal/wishlist/infra/adapter"
+	wishlistpg "github.com/dwikikusuma/shoping-llm/internal/wishlist/infra/postgres"
+
 	"github.com/dwikikusuma/shoping-llm/pkg/config"
 	"github.com/dwikikusuma/shoping-llm/pkg/logger"
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
+	"github.com/dwikikusuma/shoping-llm/pkg/pagination"
 	"github.com/dwikikusuma/shoping-llm/pkg/postgres"
 	"github.com/dwikikusuma/shoping-llm/pkg/shutdown"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
 )
 
@@ -49,22 +87,91 @@ func main() {
 	db := mustDB(log)
 	defer db.Close()
 
+	cursors := pagination.NewCodec([]byte(cfg.CursorSecret))
+	if cfg.CursorSecret == "" {
+		log.Warn("CURSOR_SECRET is not set; pagination cursors will not survive a restart")
+	}
+
+	rdb := newRedis(log)
+
 	// Catalog
-	catalogRepo := cpg.NewProductRepo(db)
-	catalogSvc := catalogapp.NewService(catalogRepo)
+	productCache := catalogcache.NewProductRepo(
+		cpg.NewProductRepo(db, cursors),
+		getenvInt("PRODUCT_CACHE_SIZE", 10000),
+		time.Duration(getenvInt("PRODUCT_CACHE_TTL_SECONDS", 60))*time.Second,
+		rdb,
+	)
+	priceRepo := catalogcache.NewPriceRepo(cpg.NewPriceRepo(db), productCache)
+	catalogSvc := catalogapp.NewService(productCache, cpg.NewCategoryRepo(db), cpg.NewVariantRepo(db), priceRepo)
+
+	txManager := postgres.NewTxManager(db)
+
+	// Inventory
+	reservationTTL := time.Duration(getenvInt("INVENTORY_RESERVATION_TTL_MINUTES", 30)) * time.Minute
+	inventorySvc := inventoryapp.NewService(inventorypg.NewInventoryRepo(db), txManager, reservationTTL)
 
 	// Cart
 	cartRepo := cartpg.NewCartRepo(db)
-	cartSvc := cartapp.NewService(cartRepo)
+	var cartEvents cartapp.EventPublisher = cartevents.NewLogPublisher(log)
+	if rdb != nil {
+		cartEvents = cartevents.NewRedisPublisher(rdb)
+	}
+	cartSvc := cartapp.NewService(
+		cartRepo,
+		cartadapter.NewCatalogServiceReader(catalogSvc),
+		cartadapter.NewInventoryServiceReader(inventorySvc),
+		txManager,
+		cartEvents,
+		cartapp.Limits{
+			MaxLineQuantity: int32(getenvInt("CART_MAX_LINE_QUANTITY", 99)),
+			MaxLines:        getenvInt("CART_MAX_LINES", 50),
+		},
+	)
+
+	// Shipping
+	fxRates := mustRates(log, cfg.FXRatesFile)
+	shippingSvc := shippingapp.NewService(mustShippingRates(log, cfg.ShippingRatesFile), fxRates)
+
+	// Order
+	orderRepo := orderpg.NewOrderRepo(db, cursors)
+	ordersvc := orderapp.NewService(
+		orderRepo,
+		orderadapter.NewInventoryReserver(inventorySvc),
+		orderadapter.NewShippingServicePricer(catalogSvc, shippingSvc),
+		txManager,
+	)
+
+	// Reviews
+	reviewSvc := reviewapp.NewService(
+		reviewpg.NewReviewRepo(db, cursors),
+		reviewadapter.NewOrderPurchaseChecker(ordersvc),
+		reviewadapter.NewCatalogRatingWriter(catalogSvc),
+		txManager,
+	)
+
+	// Promotions
+	promotionSvc := promotionapp.NewService(promotionpg.NewPromotionRepo(db), txManager)
+
+	// Wishlist
+	wishlistSvc := wishlistapp.NewService(
+		wishlistpg.NewWishlistRepo(db),
+		wishlistadapter.NewCatalogServiceReader(catalogSvc),
+		wishlistadapter.NewCartServiceAdder(cartSvc),
+		txManager,
+	)
 
 	// Checkout (adapters)
 	cartReader := checkoutadapter.NewCartServiceReader(cartSvc)
 	catalogReader := checkoutadapter.NewCatalogServiceReader(catalogSvc)
-	checkoutSvc := checkoutapp.NewService(cartReader, catalogReader, 10)
+	cartWriter := checkoutadapter.NewCartServiceWriter(cartSvc)
+	orderWriter := checkoutadapter.NewOrderServiceWriter(ordersvc)
+	shipping := checkoutadapter.NewShippingServiceRates(shippingSvc)
+	promotions := checkoutadapter.NewPromotionServiceApplier(promotionSvc)
+	checkoutSvc := checkoutapp.NewService(cartReader, catalogReader, cartWriter, orderWriter, shipping, promotions, fxRates, txManager)
 
-	// Order
-	orderRepo := orderpg.NewOrderRepo(db)
-	ordersvc := orderapp.NewService(orderRepo)
+	// Idempotency
+	idemTTL := time.Duration(getenvInt("IDEMPOTENCY_TTL_HOURS", 24)) * time.Hour
+	idemSvc := idemapp.NewService(idempg.NewKeyRepo(db), txManager, idemTTL)
 
 	addr := fmt.Sprintf(":%d", cfg.GRPCPort)
 	lis, err := net.Listen("tcp", addr)
@@ -73,13 +180,124 @@ func main() {
 		os.Exit(1)
 	}
 
-	grpcServer := grpc.NewServer()
-	catalogv1.RegisterCatalogServiceServer(grpcServer, cgrpc.NewServer(catalogSvc))
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			idemgrpc.UnaryServerInterceptor(idemSvc,
+				orderv1.OrderService_CreateOrder_FullMethodName,
+				checkoutv1.CheckoutService_PlaceOrder_FullMethodName,
+			),
+		),
+	)
+	catalogv1.RegisterCatalogServiceServer(grpcServer, cgrpc.NewServer(catalogSvc, cfg.AdminToken))
 	cartv1.RegisterCartServiceServer(grpcServer, cartgrpc.NewServer(cartSvc))
 	checkoutv1.RegisterCheckoutServiceServer(grpcServer, checkoutgrpc.NewServer(checkoutSvc))
 	orderv1.RegisterOrderServiceServer(grpcServer, ordergrpc.NewServer(ordersvc))
+	inventoryv1.RegisterInventoryServiceServer(grpcServer, inventorygrpc.NewServer(inventorySvc))
+	reviewv1.RegisterReviewServiceServer(grpcServer, reviewgrpc.NewServer(reviewSvc, cfg.AdminToken))
+	wishlistv1.RegisterWishlistServiceServer(grpcServer, wishlistgrpc.NewServer(wishlistSvc))
+	promotionv1.RegisterPromotionServiceServer(grpcServer, promotiongrpc.NewServer(promotionSvc, cfg.AdminToken))
 
 	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runEvery(ctx, time.Hour, func(ctx context.Context) {
+			n, err := idemSvc.PurgeExpired(ctx)
+			if err != nil {
+				log.Error("purge idempotency keys failed", slog.Any("err", err))
+				return
+			}
+			log.Debug("purged idempotency keys", slog.Int64("count", n))
+		})
+	}()
+
+	// Scheduled prices take over from the stored product prices as their
+	// periods start and end.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runEvery(ctx, time.Duration(getenvInt("PRICE_SYNC_INTERVAL_SECONDS", 60))*time.Second, func(ctx context.Context) {
+			n, err := catalogSvc.SyncPrices(ctx)
+			if err != nil {
+				log.Error("sync product prices failed", slog.Any("err", err))
+				return
+			}
+			if n > 0 {
+				log.Info("product prices changed", slog.Int64("count", n))
+			}
+		})
+	}()
+
+	// Carts nobody touched for a while are abandoned, which marketing hears
+	// about, and deleted once the retention period is over.
+	cartIdle := time.Duration(getenvInt("CART_ABANDON_AFTER_DAYS", 7)) * 24 * time.Hour
+	cartRetention := time.Duration(getenvInt("CART_ABANDONED_RETENTION_DAYS", 90)) * 24 * time.Hour
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runEvery(ctx, time.Hour, func(ctx context.Context) {
+			n, err := cartSvc.AbandonInactive(ctx, cartIdle)
+			if err != nil {
+				log.Error("abandon inactive carts failed", slog.Any("err", err))
+			}
+			if n > 0 {
+				log.Info("abandoned inactive carts", slog.Int("count", n))
+			}
+
+			purged, err := cartSvc.PurgeAbandoned(ctx, cartRetention)
+			if err != nil {
+				log.Error("purge abandoned carts failed", slog.Any("err", err))
+				return
+			}
+			log.Debug("purged abandoned carts", slog.Int64("count", purged))
+		})
+	}()
+
+	// Other instances publish the products they changed.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		productCache.Listen(ctx)
+	}()
+
+	metricsMux := http.NewServeMux()
+	metricsMux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		productCache.WriteMetrics(w)
+	})
+	metricsSrv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", getenvInt("METRICS_PORT", 9091)),
+		Handler:           metricsMux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		log.Info("metrics starting", slog.String("addr", metricsSrv.Addr))
+		if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("metrics serve error", slog.Any("err", err))
+		}
+	}()
+
+	// Expired reservations give their stock back; the orders holding them are
+	// cancelled so they can no longer be paid.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runEvery(ctx, time.Minute, func(ctx context.Context) {
+			refs, err := inventorySvc.ExpireReservations(ctx)
+			if err != nil {
+				log.Error("expire stock reservations failed", slog.Any("err", err))
+				return
+			}
+			for _, orderID := range refs {
+				if _, err := ordersvc.CancelOrder(ctx, orderID, "system", "stock reservation expired"); err != nil {
+					log.Warn("cancel expired order failed", slog.String("order_id", orderID), slog.Any("err", err))
+				}
+			}
+		})
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -96,6 +314,10 @@ func main() {
 	stopCtx, stopCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer stopCancel()
 
+	if err := metricsSrv.Shutdown(stopCtx); err != nil {
+		log.Warn("metrics shutdown failed", slog.Any("err", err))
+	}
+
 	stopped := make(chan struct{})
 	go func() {
 		grpcServer.GracefulStop()
@@ -113,6 +335,20 @@ func main() {
 	log.Info("bye")
 }
 
+// runEvery calls fn every interval until ctx is cancelled.
+func runEvery(ctx context.Context, interval time.Duration, fn func(ctx context.Context)) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			fn(ctx)
+		}
+	}
+}
+
 func mustDB(log *slog.Logger) *sql.DB {
 	cfg := postgres.Config{
 		Host: getenv("POSTGRES_HOST", "localhost"),
@@ -129,6 +365,59 @@ func mustDB(log *slog.Logger) *sql.DB {
 	return db
 }
 
+// newRedis connects the shared product cache tier, which also carries cart
+// events. Without REDIS_ADDR each instance only caches locally and cart
+// events are only logged.
+func newRedis(log *slog.Logger) redis.UniversalClient {
+	addr := getenv("REDIS_ADDR", "")
+	if addr == "" {
+		log.Info("REDIS_ADDR is not set; the product cache is local to this instance")
+		return nil
+	}
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: getenv("REDIS_PASSWORD", ""),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		// Cache reads fall back to Postgres until Redis is reachable.
+		log.Warn("redis ping failed", slog.Any("err", err), slog.String("addr", addr))
+	}
+	return rdb
+}
+
+// mustRates loads the exchange rates for display-currency quotes. Without a
+// file, quotes are only available in the catalog currency.
+func mustRates(log *slog.Logger, path string) money.RateProvider {
+	if path == "" {
+		log.Warn("FX_RATES_FILE is not set; quotes can't be converted to a display currency")
+		return nil
+	}
+	rates, err := money.LoadStaticRates(path)
+	if err != nil {
+		log.Error("load exchange rates failed", slog.Any("err", err), slog.String("path", path))
+		os.Exit(1)
+	}
+	return rates
+}
+
+// mustShippingRates loads the shipping rate table. Without a file, orders
+// ship anywhere for the flat fees of table.Default.
+func mustShippingRates(log *slog.Logger, path string) shippingapp.RateProvider {
+	if path == "" {
+		log.Warn("SHIPPING_RATES_FILE is not set; using flat shipping rates")
+		return shippingtable.Default()
+	}
+	rates, err := shippingtable.Load(path)
+	if err != nil {
+		log.Error("load shipping rates failed", slog.Any("err", err), slog.String("path", path))
+		os.Exit(1)
+	}
+	return rates
+}
+
 func getenv(key, def string) string {
 	v := os.Getenv(key)
 	if v == "" {
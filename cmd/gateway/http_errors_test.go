@@ -26,6 +26,46 @@ func TestHTTPStatusFromGRPC(t *testing.T) {
 		}
 	})
 
+	t.Run("Unauthenticated -> 401", func(t *testing.T) {
+		err := status.Error(codes.Unauthenticated, "missing admin token")
+		gotStatus, gotCode, _ := httpStatusFromGRPC(err)
+		if gotStatus != http.StatusUnauthorized || gotCode != "UNAUTHENTICATED" {
+			t.Fatalf("got (%d,%s)", gotStatus, gotCode)
+		}
+	})
+
+	t.Run("PermissionDenied -> 403", func(t *testing.T) {
+		err := status.Error(codes.PermissionDenied, "invalid admin token")
+		gotStatus, gotCode, _ := httpStatusFromGRPC(err)
+		if gotStatus != http.StatusForbidden || gotCode != "PERMISSION_DENIED" {
+			t.Fatalf("got (%d,%s)", gotStatus, gotCode)
+		}
+	})
+
+	t.Run("AlreadyExists -> 409", func(t *testing.T) {
+		err := status.Error(codes.AlreadyExists, "idempotency key reused")
+		gotStatus, gotCode, _ := httpStatusFromGRPC(err)
+		if gotStatus != http.StatusConflict || gotCode != "ALREADY_EXISTS" {
+			t.Fatalf("got (%d,%s)", gotStatus, gotCode)
+		}
+	})
+
+	t.Run("FailedPrecondition -> 409", func(t *testing.T) {
+		err := status.Error(codes.FailedPrecondition, "illegal transition")
+		gotStatus, gotCode, _ := httpStatusFromGRPC(err)
+		if gotStatus != http.StatusConflict || gotCode != "FAILED_PRECONDITION" {
+			t.Fatalf("got (%d,%s)", gotStatus, gotCode)
+		}
+	})
+
+	t.Run("Aborted -> 409", func(t *testing.T) {
+		err := status.Error(codes.Aborted, "concurrent update")
+		gotStatus, gotCode, _ := httpStatusFromGRPC(err)
+		if gotStatus != http.StatusConflict || gotCode != "ABORTED" {
+			t.Fatalf("got (%d,%s)", gotStatus, gotCode)
+		}
+	})
+
 	t.Run("Unavailable -> 503", func(t *testing.T) {
 		err := status.Error(codes.Unavailable, "down")
 		gotStatus, gotCode, _ := httpStatusFromGRPC(err)
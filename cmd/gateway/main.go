@@ -17,6 +17,11 @@ import (
 	cartv1 "github.com/dwikikusuma/shoping-llm/api/gen/cart/v1"
 	catalogv1 "github.com/dwikikusuma/shoping-llm/api/gen/catalog/v1"
 	checkoutv1 "github.com/dwikikusuma/shoping-llm/api/gen/checkout/v1"
+	inventoryv1 "github.com/dwikikusuma/shoping-llm/api/gen/inventory/v1"
+	orderv1 "github.com/dwikikusuma/shoping-llm/api/gen/order/v1"
+	promotionv1 "github.com/dwikikusuma/shoping-llm/api/gen/promotion/v1"
+	reviewv1 "github.com/dwikikusuma/shoping-llm/api/gen/review/v1"
+	wishlistv1 "github.com/dwikikusuma/shoping-llm/api/gen/wishlist/v1"
 
 	"github.com/dwikikusuma/shoping-llm/pkg/config"
 	"github.com/dwikikusuma/shoping-llm/pkg/logger"
@@ -24,14 +29,21 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 type server struct {
-	log      *slog.Logger
-	catalog  catalogv1.CatalogServiceClient
-	cart     cartv1.CartServiceClient
-	checkout checkoutv1.CheckoutServiceClient
+	log       *slog.Logger
+	catalog   catalogv1.CatalogServiceClient
+	cart      cartv1.CartServiceClient
+	checkout  checkoutv1.CheckoutServiceClient
+	order     orderv1.OrderServiceClient
+	inventory inventoryv1.InventoryServiceClient
+	promotion promotionv1.PromotionServiceClient
+	review    reviewv1.ReviewServiceClient
+	wishlist  wishlistv1.WishlistServiceClient
 }
 
 func main() {
@@ -49,10 +61,15 @@ func main() {
 	defer conn.Close()
 
 	s := &server{
-		log:      log,
-		catalog:  catalogv1.NewCatalogServiceClient(conn),
-		cart:     cartv1.NewCartServiceClient(conn),
-		checkout: checkoutv1.NewCheckoutServiceClient(conn),
+		log:       log,
+		catalog:   catalogv1.NewCatalogServiceClient(conn),
+		cart:      cartv1.NewCartServiceClient(conn),
+		checkout:  checkoutv1.NewCheckoutServiceClient(conn),
+		order:     orderv1.NewOrderServiceClient(conn),
+		inventory: inventoryv1.NewInventoryServiceClient(conn),
+		promotion: promotionv1.NewPromotionServiceClient(conn),
+		review:    reviewv1.NewReviewServiceClient(conn),
+		wishlist:  wishlistv1.NewWishlistServiceClient(conn),
 	}
 
 	mux := http.NewServeMux()
@@ -63,10 +80,34 @@ func main() {
 	// Catalog
 	mux.HandleFunc("/v1/products", s.productsHandler)
 	mux.HandleFunc("/v1/products/", s.productByIDHandler)
+	mux.HandleFunc("/v1/categories", s.categoriesHandler)
+	mux.HandleFunc("/v1/categories/", s.categoryByIDHandler)
+	mux.HandleFunc("/v1/variants/", s.variantByIDHandler)
 
 	// Cart + Checkout
 	mux.HandleFunc("/v1/cart/", s.cartHandler)
+	mux.HandleFunc("/v1/guest-cart", s.guestCartHandler)
+	mux.HandleFunc("/v1/guest-cart/", s.guestCartHandler)
 	mux.HandleFunc("/v1/checkout/quote/", s.quoteHandler)
+	mux.HandleFunc("/v1/checkout/place-order/", s.placeOrderHandler)
+
+	// Orders
+	mux.HandleFunc("/v1/orders", s.ordersHandler)
+	mux.HandleFunc("/v1/orders/", s.orderByIDHandler)
+
+	// Inventory
+	mux.HandleFunc("/v1/inventory/", s.inventoryHandler)
+
+	// Promotions
+	mux.HandleFunc("/v1/promotions", s.promotionsHandler)
+	mux.HandleFunc("/v1/promotions/", s.promotionByIDHandler)
+
+	// Reviews
+	mux.HandleFunc("/v1/reviews", s.reviewsHandler)
+	mux.HandleFunc("/v1/reviews/", s.reviewByIDHandler)
+
+	// Wishlist
+	mux.HandleFunc("/v1/wishlist/", s.wishlistHandler)
 
 	addr := fmt.Sprintf(":%d", cfg.HTTPPort)
 	httpServer := &http.Server{
@@ -136,6 +177,7 @@ type createProductReq struct {
 		Currency string `json:"currency"`
 		Amount   int64  `json:"amount"`
 	} `json:"price"`
+	WeightGrams int32 `json:"weight_grams"`
 }
 
 type productResp struct {
@@ -146,13 +188,21 @@ type productResp struct {
 		Currency string `json:"currency"`
 		Amount   int64  `json:"amount"`
 	} `json:"price"`
-	CreatedAtUnix int64 `json:"created_at_unix"`
-	UpdatedAtUnix int64 `json:"updated_at_unix"`
+	CreatedAtUnix  int64            `json:"created_at_unix"`
+	UpdatedAtUnix  int64            `json:"updated_at_unix"`
+	Version        int64            `json:"version"`
+	ArchivedAtUnix int64            `json:"archived_at_unix,omitempty"`
+	CategoryIDs    []string         `json:"category_ids,omitempty"`
+	Options        []optionAxisHTTP `json:"options,omitempty"`
+	RatingAvg      float64          `json:"rating_avg"`
+	RatingCount    int32            `json:"rating_count"`
+	WeightGrams    int32            `json:"weight_grams"`
 }
 
 type listProductsResp struct {
 	Products   []productResp `json:"products"`
 	NextCursor string        `json:"next_cursor"`
+	TotalCount int64         `json:"total_count"`
 }
 
 func (s *server) productsHandler(w http.ResponseWriter, r *http.Request) {
@@ -166,18 +216,52 @@ func (s *server) productsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Routes:
+// GET    /v1/products/{id}
+// PATCH  /v1/products/{id}          only the fields present in the body change
+// DELETE /v1/products/{id}          admin only (X-Admin-Token)
+// POST   /v1/products/{id}/archive
+// PUT    /v1/products/{id}/categories  body: {"category_ids": [...]}
+// PUT    /v1/products/{id}/options     body: {"options": [{"name": "size", "values": [...]}]}
+// GET    /v1/products/{id}/variants
+// POST   /v1/products/{id}/variants
+// GET    /v1/products/{id}/reviews     ?status=&sort=&limit=&cursor=
+// POST   /v1/products/{id}/reviews     body: {"user_id": "...", "rating": 5, "title": "...", "body": "..."}
 func (s *server) productByIDHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	id := strings.TrimPrefix(r.URL.Path, "/v1/products/")
-	id = strings.TrimSpace(id)
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/products/"), "/")
+	parts := strings.Split(path, "/")
+	id := strings.TrimSpace(parts[0])
 	if id == "" {
 		writeErr(w, "missing id", http.StatusBadRequest)
 		return
 	}
-	s.getProductHTTP(w, r, id)
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.getProductHTTP(w, r, id)
+	case len(parts) == 1 && r.Method == http.MethodPatch:
+		s.updateProductHTTP(w, r, id)
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		s.deleteProductHTTP(w, r, id)
+	case len(parts) == 2 && parts[1] == "archive" && r.Method == http.MethodPost:
+		s.archiveProductHTTP(w, r, id)
+	case len(parts) == 2 && parts[1] == "categories" && r.Method == http.MethodPut:
+		s.setProductCategoriesHTTP(w, r, id)
+	case len(parts) == 2 && parts[1] == "options" && r.Method == http.MethodPut:
+		s.setProductOptionsHTTP(w, r, id)
+	case len(parts) == 2 && parts[1] == "variants" && r.Method == http.MethodGet:
+		s.listVariantsHTTP(w, r, id)
+	case len(parts) == 2 && parts[1] == "variants" && r.Method == http.MethodPost:
+		s.createVariantHTTP(w, r, id)
+	case len(parts) == 2 && parts[1] == "reviews" && r.Method == http.MethodGet:
+		s.listReviewsHTTP(w, r, id)
+	case len(parts) == 2 && parts[1] == "reviews" && r.Method == http.MethodPost:
+		s.createReviewHTTP(w, r, id)
+	case len(parts) == 1 || (len(parts) == 2 && (parts[1] == "archive" || parts[1] == "categories" || parts[1] == "options" || parts[1] == "variants" || parts[1] == "reviews")):
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		writeErr(w, "not found", http.StatusNotFound)
+	}
 }
 
 func (s *server) createProductHTTP(w http.ResponseWriter, r *http.Request) {
@@ -197,6 +281,7 @@ func (s *server) createProductHTTP(w http.ResponseWriter, r *http.Request) {
 			Currency: body.Price.Currency,
 			Amount:   body.Price.Amount,
 		},
+		WeightGrams: body.WeightGrams,
 	})
 	if err != nil {
 		s.log.Error("create product failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())))
@@ -222,9 +307,106 @@ func (s *server) getProductHTTP(w http.ResponseWriter, r *http.Request, id strin
 	writeJSON(w, http.StatusOK, toHTTPProduct(resp.Product))
 }
 
+type updateProductReq struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Price       *struct {
+		Currency string `json:"currency"`
+		Amount   int64  `json:"amount"`
+	} `json:"price"`
+	WeightGrams     *int32 `json:"weight_grams"`
+	ExpectedVersion int64  `json:"expected_version"`
+}
+
+func (s *server) updateProductHTTP(w http.ResponseWriter, r *http.Request, id string) {
+	var body updateProductReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	req := &catalogv1.UpdateProductRequest{
+		Id:              id,
+		Product:         &catalogv1.Product{},
+		UpdateMask:      &fieldmaskpb.FieldMask{},
+		ExpectedVersion: body.ExpectedVersion,
+	}
+	if body.Name != nil {
+		req.Product.Name = *body.Name
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "name")
+	}
+	if body.Description != nil {
+		req.Product.Description = *body.Description
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "description")
+	}
+	if body.Price != nil {
+		req.Product.Price = &catalogv1.Money{Currency: body.Price.Currency, Amount: body.Price.Amount}
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "price")
+	}
+	if body.WeightGrams != nil {
+		req.Product.WeightGrams = *body.WeightGrams
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "weight_grams")
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.catalog.UpdateProduct(ctx, req)
+	if err != nil {
+		s.log.Error("update product failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", id))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPProduct(resp.Product))
+}
+
+func (s *server) archiveProductHTTP(w http.ResponseWriter, r *http.Request, id string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.catalog.ArchiveProduct(ctx, &catalogv1.ArchiveProductRequest{Id: id})
+	if err != nil {
+		s.log.Error("archive product failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", id))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPProduct(resp.Product))
+}
+
+func (s *server) deleteProductHTTP(w http.ResponseWriter, r *http.Request, id string) {
+	ctx, cancel := context.WithTimeout(withAdminToken(r), 3*time.Second)
+	defer cancel()
+
+	_, err := s.catalog.DeleteProduct(ctx, &catalogv1.DeleteProductRequest{Id: id})
+	if err != nil {
+		s.log.Error("delete product failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", id))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (s *server) listProductsHTTP(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query().Get("query")
 	cursor := r.URL.Query().Get("cursor")
+	categoryID := r.URL.Query().Get("category_id")
+
+	var minPrice, maxPrice int64
+	for name, dst := range map[string]*int64{"min_price": &minPrice, "max_price": &maxPrice} {
+		v := strings.TrimSpace(r.URL.Query().Get(name))
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeErr(w, "invalid "+name, http.StatusBadRequest)
+			return
+		}
+		*dst = n
+	}
 
 	limit := 20
 	if v := strings.TrimSpace(r.URL.Query().Get("limit")); v != "" {
@@ -243,9 +425,14 @@ func (s *server) listProductsHTTP(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	resp, err := s.catalog.ListProducts(ctx, &catalogv1.ListProductsRequest{
-		Query:  q,
-		Limit:  int32(limit),
-		Cursor: cursor,
+		Query:      q,
+		Limit:      int32(limit),
+		Cursor:     cursor,
+		CategoryId: categoryID,
+		Currency:   r.URL.Query().Get("currency"),
+		MinPrice:   minPrice,
+		MaxPrice:   maxPrice,
+		Sort:       r.URL.Query().Get("sort"),
 	})
 	if err != nil {
 		s.log.Error("list products failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())))
@@ -257,6 +444,7 @@ func (s *server) listProductsHTTP(w http.ResponseWriter, r *http.Request) {
 	out := listProductsResp{
 		Products:   make([]productResp, 0, len(resp.Products)),
 		NextCursor: resp.NextCursor,
+		TotalCount: resp.TotalCount,
 	}
 	for _, p := range resp.Products {
 		out.Products = append(out.Products, toHTTPProduct(p))
@@ -273,268 +461,1974 @@ func toHTTPProduct(p *catalogv1.Product) productResp {
 	out.Price.Amount = p.GetPrice().GetAmount()
 	out.CreatedAtUnix = p.GetCreatedAtUnix()
 	out.UpdatedAtUnix = p.GetUpdatedAtUnix()
+	out.Version = p.GetVersion()
+	out.ArchivedAtUnix = p.GetArchivedAtUnix()
+	out.CategoryIDs = p.GetCategoryIds()
+	out.Options = toHTTPOptions(p.GetOptions())
+	out.RatingAvg = p.GetRatingAvg()
+	out.RatingCount = p.GetRatingCount()
+	out.WeightGrams = p.GetWeightGrams()
 	return out
 }
 
+type setProductCategoriesReq struct {
+	CategoryIDs []string `json:"category_ids"`
+}
+
+func (s *server) setProductCategoriesHTTP(w http.ResponseWriter, r *http.Request, id string) {
+	var body setProductCategoriesReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.catalog.SetProductCategories(ctx, &catalogv1.SetProductCategoriesRequest{
+		ProductId:   id,
+		CategoryIds: body.CategoryIDs,
+	})
+	if err != nil {
+		s.log.Error("set product categories failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", id))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, setProductCategoriesReq{CategoryIDs: resp.GetCategoryIds()})
+}
+
 /* =========================
-   Cart HTTP
+   Variants HTTP
    ========================= */
 
-type cartItemHTTP struct {
-	ProductID string `json:"product_id"`
-	Quantity  int32  `json:"quantity"`
+type optionAxisHTTP struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
 }
 
-type cartHTTP struct {
-	ID        string         `json:"id"`
-	UserID    string         `json:"user_id"`
-	Status    string         `json:"status"`
-	Items     []cartItemHTTP `json:"items"`
-	CreatedAt int64          `json:"created_at_unix"`
-	UpdatedAt int64          `json:"updated_at_unix"`
+type setProductOptionsReq struct {
+	Options []optionAxisHTTP `json:"options"`
 }
 
-type addItemReq struct {
-	ProductID string `json:"product_id"`
-	Quantity  int32  `json:"quantity"`
+type variantHTTP struct {
+	ID        string            `json:"id"`
+	ProductID string            `json:"product_id"`
+	SKU       string            `json:"sku"`
+	Options   map[string]string `json:"options"`
+	Price     struct {
+		Currency string `json:"currency"`
+		Amount   int64  `json:"amount"`
+	} `json:"price"`
+	CreatedAtUnix int64 `json:"created_at_unix"`
+	UpdatedAtUnix int64 `json:"updated_at_unix"`
 }
 
-type setQtyReq struct {
-	Quantity int32 `json:"quantity"`
+type listVariantsResp struct {
+	Variants []variantHTTP `json:"variants"`
 }
 
-// Routes:
-// GET    /v1/cart/{user_id}
-// POST   /v1/cart/{user_id}/items
-// PUT    /v1/cart/{user_id}/items/{product_id}
-// DELETE /v1/cart/{user_id}/items/{product_id}
-// DELETE /v1/cart/{user_id}/items
-func (s *server) cartHandler(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/v1/cart/")
-	path = strings.Trim(path, "/")
-	parts := []string{}
-	if path != "" {
-		parts = strings.Split(path, "/")
-	}
-
-	// Need at least user_id
-	if len(parts) < 1 || strings.TrimSpace(parts[0]) == "" {
-		writeErr(w, "missing user_id", http.StatusBadRequest)
-		return
-	}
-	userID := parts[0]
-
-	// /v1/cart/{user_id}
-	if len(parts) == 1 {
-		if r.Method != http.MethodGet {
-			writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		s.getOrCreateCartHTTP(w, r, userID)
-		return
-	}
+type variantReq struct {
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options"`
+	Price   struct {
+		Currency string `json:"currency"` // optional: the product's currency
+		Amount   int64  `json:"amount"`
+	} `json:"price"`
+}
 
-	// /v1/cart/{user_id}/items
-	if len(parts) == 2 && parts[1] == "items" {
-		switch r.Method {
-		case http.MethodPost:
-			s.addItemHTTP(w, r, userID)
-		case http.MethodDelete:
-			s.clearCartHTTP(w, r, userID)
-		default:
-			writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
-		}
+func (s *server) setProductOptionsHTTP(w http.ResponseWriter, r *http.Request, id string) {
+	var body setProductOptionsReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
 		return
 	}
 
-	// /v1/cart/{user_id}/items/{product_id}
-	if len(parts) == 3 && parts[1] == "items" {
-		productID := parts[2]
-		switch r.Method {
-		case http.MethodPut:
-			s.setItemQtyHTTP(w, r, userID, productID)
-		case http.MethodDelete:
-			s.removeItemHTTP(w, r, userID, productID)
-		default:
-			writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
-		}
-		return
+	options := make([]*catalogv1.OptionAxis, 0, len(body.Options))
+	for _, axis := range body.Options {
+		options = append(options, &catalogv1.OptionAxis{Name: axis.Name, Values: axis.Values})
 	}
 
-	writeErr(w, "not found", http.StatusNotFound)
-}
-
-func (s *server) getOrCreateCartHTTP(w http.ResponseWriter, r *http.Request, userID string) {
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	resp, err := s.cart.GetOrCreateCart(ctx, &cartv1.UserId{Id: userID})
+	resp, err := s.catalog.SetProductOptions(ctx, &catalogv1.SetProductOptionsRequest{
+		ProductId: id,
+		Options:   options,
+	})
 	if err != nil {
-		s.log.Error("get or create cart failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		s.log.Error("set product options failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", id))
 		httpCode, code, msg := httpStatusFromGRPC(err)
 		writeAPIError(w, httpCode, code, msg)
 		return
 	}
-
-	writeJSON(w, http.StatusOK, toHTTPCart(resp))
+	writeJSON(w, http.StatusOK, setProductOptionsReq{Options: toHTTPOptions(resp.GetOptions())})
 }
 
-func (s *server) addItemHTTP(w http.ResponseWriter, r *http.Request, userID string) {
-	var body addItemReq
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		writeErr(w, "invalid json", http.StatusBadRequest)
-		return
-	}
-	if strings.TrimSpace(body.ProductID) == "" {
-		writeErr(w, "missing product_id", http.StatusBadRequest)
-		return
-	}
-	if body.Quantity <= 0 {
-		writeErr(w, "quantity must be > 0", http.StatusBadRequest)
-		return
-	}
-
+func (s *server) listVariantsHTTP(w http.ResponseWriter, r *http.Request, productID string) {
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	resp, err := s.cart.AddItem(ctx, &cartv1.UpdateCartItemRequest{
-		UserId: userID,
-		Item: &cartv1.CartItem{
-			ProductId: body.ProductID,
-			Quantity:  body.Quantity,
-		},
-	})
+	resp, err := s.catalog.ListVariants(ctx, &catalogv1.ListVariantsRequest{ProductId: productID})
 	if err != nil {
-		s.log.Error("add item failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		s.log.Error("list variants failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", productID))
 		httpCode, code, msg := httpStatusFromGRPC(err)
 		writeAPIError(w, httpCode, code, msg)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, toHTTPCart(resp))
+	out := listVariantsResp{Variants: make([]variantHTTP, 0, len(resp.GetVariants()))}
+	for _, v := range resp.GetVariants() {
+		out.Variants = append(out.Variants, toHTTPVariant(v))
+	}
+	writeJSON(w, http.StatusOK, out)
 }
 
-func (s *server) setItemQtyHTTP(w http.ResponseWriter, r *http.Request, userID, productID string) {
-	var body setQtyReq
+func (s *server) createVariantHTTP(w http.ResponseWriter, r *http.Request, productID string) {
+	var body variantReq
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		writeErr(w, "invalid json", http.StatusBadRequest)
 		return
 	}
-	if body.Quantity <= 0 {
-		writeErr(w, "quantity must be > 0", http.StatusBadRequest)
-		return
-	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	resp, err := s.cart.SetItemQuantity(ctx, &cartv1.UpdateCartItemRequest{
-		UserId: userID,
-		Item: &cartv1.CartItem{
-			ProductId: productID,
-			Quantity:  body.Quantity,
-		},
+	resp, err := s.catalog.CreateVariant(ctx, &catalogv1.CreateVariantRequest{
+		ProductId: productID,
+		Sku:       body.SKU,
+		Options:   body.Options,
+		Price:     &catalogv1.Money{Currency: body.Price.Currency, Amount: body.Price.Amount},
 	})
 	if err != nil {
-		s.log.Error("set item quantity failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		s.log.Error("create variant failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", productID))
 		httpCode, code, msg := httpStatusFromGRPC(err)
 		writeAPIError(w, httpCode, code, msg)
 		return
 	}
-
-	writeJSON(w, http.StatusOK, toHTTPCart(resp))
+	writeJSON(w, http.StatusCreated, toHTTPVariant(resp.GetVariant()))
 }
 
-func (s *server) removeItemHTTP(w http.ResponseWriter, r *http.Request, userID, productID string) {
-	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
-	defer cancel()
-
-	resp, err := s.cart.RemoveItem(ctx, &cartv1.RemoveCartItemRequest{
-		UserId:    userID,
-		ProductId: productID,
-	})
-	if err != nil {
-		s.log.Error("remove item failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
-		httpCode, code, msg := httpStatusFromGRPC(err)
-		writeAPIError(w, httpCode, code, msg)
+// GET    /v1/variants/{id}
+// PUT    /v1/variants/{id}  (replaces sku, options and price)
+// DELETE /v1/variants/{id}
+func (s *server) variantByIDHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/variants/"), "/")
+	if id == "" || strings.Contains(id, "/") {
+		writeErr(w, "not found", http.StatusNotFound)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, toHTTPCart(resp))
-}
-
-func (s *server) clearCartHTTP(w http.ResponseWriter, r *http.Request, userID string) {
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	// NOTE: your current Cart gRPC ClearCart expects CartId but uses it like a user_id in server code.
-	resp, err := s.cart.ClearCart(ctx, &cartv1.CartId{Id: userID})
+	var (
+		variant *catalogv1.Variant
+		err     error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		var resp *catalogv1.GetVariantResponse
+		resp, err = s.catalog.GetVariant(ctx, &catalogv1.GetVariantRequest{Id: id})
+		variant = resp.GetVariant()
+	case http.MethodPut:
+		var body variantReq
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeErr(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+		var resp *catalogv1.UpdateVariantResponse
+		resp, err = s.catalog.UpdateVariant(ctx, &catalogv1.UpdateVariantRequest{
+			Id:      id,
+			Sku:     body.SKU,
+			Options: body.Options,
+			Price:   &catalogv1.Money{Currency: body.Price.Currency, Amount: body.Price.Amount},
+		})
+		variant = resp.GetVariant()
+	case http.MethodDelete:
+		_, err = s.catalog.DeleteVariant(ctx, &catalogv1.DeleteVariantRequest{Id: id})
+	default:
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 	if err != nil {
-		s.log.Error("clear cart failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		s.log.Error("variant request failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", id))
 		httpCode, code, msg := httpStatusFromGRPC(err)
 		writeAPIError(w, httpCode, code, msg)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, toHTTPCart(resp))
+	if variant == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPVariant(variant))
 }
 
-func toHTTPCart(c *cartv1.Cart) cartHTTP {
-	out := cartHTTP{
-		ID:        c.GetId(),
-		UserID:    c.GetUserId(),
-		Status:    c.GetStatus(),
-		CreatedAt: c.GetCreatedAtUnix(),
-		UpdatedAt: c.GetUpdatedAtUnix(),
-		Items:     make([]cartItemHTTP, 0, len(c.GetItems())),
+func toHTTPOptions(axes []*catalogv1.OptionAxis) []optionAxisHTTP {
+	if len(axes) == 0 {
+		return nil
 	}
-	for _, it := range c.GetItems() {
-		out.Items = append(out.Items, cartItemHTTP{
-			ProductID: it.GetProductId(),
-			Quantity:  it.GetQuantity(),
-		})
+	out := make([]optionAxisHTTP, 0, len(axes))
+	for _, axis := range axes {
+		out = append(out, optionAxisHTTP{Name: axis.GetName(), Values: axis.GetValues()})
 	}
 	return out
 }
 
+func toHTTPVariant(v *catalogv1.Variant) variantHTTP {
+	var out variantHTTP
+	out.ID = v.GetId()
+	out.ProductID = v.GetProductId()
+	out.SKU = v.GetSku()
+	out.Options = v.GetOptions()
+	out.Price.Currency = v.GetPrice().GetCurrency()
+	out.Price.Amount = v.GetPrice().GetAmount()
+	out.CreatedAtUnix = v.GetCreatedAtUnix()
+	out.UpdatedAtUnix = v.GetUpdatedAtUnix()
+	return out
+}
+
 /* =========================
-   Checkout Quote HTTP
+   Categories HTTP
    ========================= */
 
-// GET /v1/checkout/quote/{user_id}
-func (s *server) quoteHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+type categoryHTTP struct {
+	ID            string `json:"id"`
+	ParentID      string `json:"parent_id,omitempty"`
+	Name          string `json:"name"`
+	Slug          string `json:"slug"`
+	CreatedAtUnix int64  `json:"created_at_unix"`
+	UpdatedAtUnix int64  `json:"updated_at_unix"`
+}
 
-	userID := strings.TrimPrefix(r.URL.Path, "/v1/checkout/quote/")
-	userID = strings.Trim(userID, "/")
-	userID = strings.TrimSpace(userID)
-	if userID == "" {
-		writeErr(w, "missing user_id", http.StatusBadRequest)
-		return
-	}
+type listCategoriesResp struct {
+	Categories []categoryHTTP `json:"categories"`
+}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+type categoryReq struct {
+	ParentID string `json:"parent_id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+}
+
+// GET  /v1/categories  (flat list; rebuild the tree from parent_id)
+// POST /v1/categories
+func (s *server) categoriesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		resp, err := s.catalog.ListCategories(ctx, &catalogv1.ListCategoriesRequest{})
+		if err != nil {
+			s.log.Error("list categories failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())))
+			httpCode, code, msg := httpStatusFromGRPC(err)
+			writeAPIError(w, httpCode, code, msg)
+			return
+		}
+		out := listCategoriesResp{Categories: make([]categoryHTTP, 0, len(resp.GetCategories()))}
+		for _, c := range resp.GetCategories() {
+			out.Categories = append(out.Categories, toHTTPCategory(c))
+		}
+		writeJSON(w, http.StatusOK, out)
+	case http.MethodPost:
+		var body categoryReq
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeErr(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+		resp, err := s.catalog.CreateCategory(ctx, &catalogv1.CreateCategoryRequest{
+			ParentId: body.ParentID,
+			Name:     body.Name,
+			Slug:     body.Slug,
+		})
+		if err != nil {
+			s.log.Error("create category failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())))
+			httpCode, code, msg := httpStatusFromGRPC(err)
+			writeAPIError(w, httpCode, code, msg)
+			return
+		}
+		writeJSON(w, http.StatusCreated, toHTTPCategory(resp.GetCategory()))
+	default:
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GET    /v1/categories/{id}
+// PUT    /v1/categories/{id}  (rename or move; empty parent_id moves to the root)
+// DELETE /v1/categories/{id}
+func (s *server) categoryByIDHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/categories/"), "/")
+	if id == "" || strings.Contains(id, "/") {
+		writeErr(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	var (
+		category *catalogv1.Category
+		err      error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		var resp *catalogv1.GetCategoryResponse
+		resp, err = s.catalog.GetCategory(ctx, &catalogv1.GetCategoryRequest{Id: id})
+		category = resp.GetCategory()
+	case http.MethodPut:
+		var body categoryReq
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeErr(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+		var resp *catalogv1.UpdateCategoryResponse
+		resp, err = s.catalog.UpdateCategory(ctx, &catalogv1.UpdateCategoryRequest{
+			Id:       id,
+			ParentId: body.ParentID,
+			Name:     body.Name,
+			Slug:     body.Slug,
+		})
+		category = resp.GetCategory()
+	case http.MethodDelete:
+		_, err = s.catalog.DeleteCategory(ctx, &catalogv1.DeleteCategoryRequest{Id: id})
+	default:
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		s.log.Error("category request failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", id))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+
+	if category == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPCategory(category))
+}
+
+func toHTTPCategory(c *catalogv1.Category) categoryHTTP {
+	return categoryHTTP{
+		ID:            c.GetId(),
+		ParentID:      c.GetParentId(),
+		Name:          c.GetName(),
+		Slug:          c.GetSlug(),
+		CreatedAtUnix: c.GetCreatedAtUnix(),
+		UpdatedAtUnix: c.GetUpdatedAtUnix(),
+	}
+}
+
+/* =========================
+   Cart HTTP
+   ========================= */
+
+type moneyHTTP struct {
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
+}
+
+type cartItemHTTP struct {
+	ProductID string     `json:"product_id"`
+	VariantID string     `json:"variant_id,omitempty"`
+	Quantity  int32      `json:"quantity"`
+	UnitPrice *moneyHTTP `json:"unit_price,omitempty"` // price when added
+}
+
+type cartNoticeHTTP struct {
+	ProductID string     `json:"product_id"`
+	VariantID string     `json:"variant_id,omitempty"`
+	Kind      string     `json:"kind"`
+	OldPrice  *moneyHTTP `json:"old_price,omitempty"`
+	NewPrice  *moneyHTTP `json:"new_price,omitempty"`
+	Available int32      `json:"available,omitempty"`
+}
+
+type cartHTTP struct {
+	ID        string           `json:"id"`
+	UserID    string           `json:"user_id"`
+	Status    string           `json:"status"`
+	Items     []cartItemHTTP   `json:"items"`
+	CreatedAt int64            `json:"created_at_unix"`
+	UpdatedAt int64            `json:"updated_at_unix"`
+	Version   int64            `json:"version"`
+	Notices   []cartNoticeHTTP `json:"notices,omitempty"`
+}
+
+type addItemReq struct {
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id"` // required for products with variants
+	Quantity  int32  `json:"quantity"`
+}
+
+type setQtyReq struct {
+	Quantity int32 `json:"quantity"`
+}
+
+type cartChangeHTTP struct {
+	Op        string `json:"op"` // "add", "set" or "remove"
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id"`
+	Quantity  int32  `json:"quantity"` // ignored for "remove"
+}
+
+type applyChangesReq struct {
+	Changes []cartChangeHTTP `json:"changes"`
+}
+
+type guestCartHTTP struct {
+	Token string   `json:"token"`
+	Cart  cartHTTP `json:"cart"`
+}
+
+type mergeCartReq struct {
+	GuestToken string `json:"guest_token"`
+	Strategy   string `json:"strategy"` // "sum" (default) or "latest"
+}
+
+// cartOwner is who a cart item request acts for: a user, or a guest session
+// when guestToken is set.
+type cartOwner struct {
+	userID     string
+	guestToken string
+}
+
+// Routes (responses carry the cart version as an ETag; send it back in
+// If-Match on writes to get 412 instead of overwriting a newer cart):
+// GET    /v1/cart/{user_id}
+// POST   /v1/cart/{user_id}/items
+// PATCH  /v1/cart/{user_id}/items      body: {"changes": [{"op": "set", "product_id": "...", "quantity": 2}, ...]}
+// PUT    /v1/cart/{user_id}/items/{product_id}?variant_id=...
+// DELETE /v1/cart/{user_id}/items/{product_id}?variant_id=...
+// DELETE /v1/cart/{user_id}/items
+// POST   /v1/cart/{user_id}/merge      body: {"guest_token": "...", "strategy": "sum"}
+func (s *server) cartHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v1/cart/")
+	path = strings.Trim(path, "/")
+	parts := []string{}
+	if path != "" {
+		parts = strings.Split(path, "/")
+	}
+
+	// Need at least user_id
+	if len(parts) < 1 || strings.TrimSpace(parts[0]) == "" {
+		writeErr(w, "missing user_id", http.StatusBadRequest)
+		return
+	}
+	userID := parts[0]
+
+	// /v1/cart/{user_id}
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.getOrCreateCartHTTP(w, r, userID)
+		return
+	}
+
+	// /v1/cart/{user_id}/items
+	if len(parts) == 2 && parts[1] == "items" {
+		switch r.Method {
+		case http.MethodPost:
+			s.addItemHTTP(w, r, cartOwner{userID: userID})
+		case http.MethodPatch:
+			s.applyChangesHTTP(w, r, cartOwner{userID: userID})
+		case http.MethodDelete:
+			s.clearCartHTTP(w, r, userID)
+		default:
+			writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// /v1/cart/{user_id}/items/{product_id}
+	if len(parts) == 3 && parts[1] == "items" {
+		productID := parts[2]
+		switch r.Method {
+		case http.MethodPut:
+			s.setItemQtyHTTP(w, r, cartOwner{userID: userID}, productID)
+		case http.MethodDelete:
+			s.removeItemHTTP(w, r, cartOwner{userID: userID}, productID)
+		default:
+			writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// /v1/cart/{user_id}/merge
+	if len(parts) == 2 && parts[1] == "merge" {
+		if r.Method != http.MethodPost {
+			writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.mergeCartHTTP(w, r, userID)
+		return
+	}
+
+	writeErr(w, "not found", http.StatusNotFound)
+}
+
+// Routes (the guest cart is addressed by the X-Guest-Token header returned
+// on creation, so the token stays out of URLs and access logs):
+// POST   /v1/guest-cart
+// GET    /v1/guest-cart
+// POST   /v1/guest-cart/items
+// PATCH  /v1/guest-cart/items
+// PUT    /v1/guest-cart/items/{product_id}?variant_id=...
+// DELETE /v1/guest-cart/items/{product_id}?variant_id=...
+func (s *server) guestCartHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/guest-cart"), "/")
+	parts := []string{}
+	if path != "" {
+		parts = strings.Split(path, "/")
+	}
+
+	if len(parts) == 0 && r.Method == http.MethodPost {
+		s.createGuestCartHTTP(w, r)
+		return
+	}
+
+	token := strings.TrimSpace(r.Header.Get("X-Guest-Token"))
+	if token == "" {
+		writeErr(w, "missing X-Guest-Token", http.StatusBadRequest)
+		return
+	}
+	owner := cartOwner{guestToken: token}
+
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		s.getGuestCartHTTP(w, r, token)
+	case len(parts) == 1 && parts[0] == "items" && r.Method == http.MethodPost:
+		s.addItemHTTP(w, r, owner)
+	case len(parts) == 1 && parts[0] == "items" && r.Method == http.MethodPatch:
+		s.applyChangesHTTP(w, r, owner)
+	case len(parts) == 2 && parts[0] == "items" && r.Method == http.MethodPut:
+		s.setItemQtyHTTP(w, r, owner, parts[1])
+	case len(parts) == 2 && parts[0] == "items" && r.Method == http.MethodDelete:
+		s.removeItemHTTP(w, r, owner, parts[1])
+	case len(parts) == 0 || (len(parts) <= 2 && parts[0] == "items"):
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		writeErr(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (s *server) getOrCreateCartHTTP(w http.ResponseWriter, r *http.Request, userID string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.cart.GetOrCreateCart(ctx, &cartv1.UserId{Id: userID})
+	if err != nil {
+		s.log.Error("get or create cart failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+
+	writeCart(w, http.StatusOK, resp)
+}
+
+func (s *server) addItemHTTP(w http.ResponseWriter, r *http.Request, owner cartOwner) {
+	var body addItemReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(body.ProductID) == "" {
+		writeErr(w, "missing product_id", http.StatusBadRequest)
+		return
+	}
+	if body.Quantity <= 0 {
+		writeErr(w, "quantity must be > 0", http.StatusBadRequest)
+		return
+	}
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		writeErr(w, "invalid If-Match", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.cart.AddItem(ctx, &cartv1.UpdateCartItemRequest{
+		UserId:          owner.userID,
+		GuestToken:      owner.guestToken,
+		ExpectedVersion: version,
+		Item: &cartv1.CartItem{
+			ProductId: body.ProductID,
+			VariantId: body.VariantID,
+			Quantity:  body.Quantity,
+		},
+	})
+	if err != nil {
+		s.log.Error("add item failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", owner.userID))
+		writeCartError(w, err)
+		return
+	}
+
+	writeCart(w, http.StatusOK, resp)
+}
+
+func (s *server) setItemQtyHTTP(w http.ResponseWriter, r *http.Request, owner cartOwner, productID string) {
+	var body setQtyReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if body.Quantity <= 0 {
+		writeErr(w, "quantity must be > 0", http.StatusBadRequest)
+		return
+	}
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		writeErr(w, "invalid If-Match", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.cart.SetItemQuantity(ctx, &cartv1.UpdateCartItemRequest{
+		UserId:          owner.userID,
+		GuestToken:      owner.guestToken,
+		ExpectedVersion: version,
+		Item: &cartv1.CartItem{
+			ProductId: productID,
+			VariantId: r.URL.Query().Get("variant_id"),
+			Quantity:  body.Quantity,
+		},
+	})
+	if err != nil {
+		s.log.Error("set item quantity failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", owner.userID))
+		writeCartError(w, err)
+		return
+	}
+
+	writeCart(w, http.StatusOK, resp)
+}
+
+func (s *server) removeItemHTTP(w http.ResponseWriter, r *http.Request, owner cartOwner, productID string) {
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		writeErr(w, "invalid If-Match", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.cart.RemoveItem(ctx, &cartv1.RemoveCartItemRequest{
+		UserId:          owner.userID,
+		GuestToken:      owner.guestToken,
+		ProductId:       productID,
+		VariantId:       r.URL.Query().Get("variant_id"),
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		s.log.Error("remove item failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", owner.userID))
+		writeCartError(w, err)
+		return
+	}
+
+	writeCart(w, http.StatusOK, resp)
+}
+
+func (s *server) applyChangesHTTP(w http.ResponseWriter, r *http.Request, owner cartOwner) {
+	var body applyChangesReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if len(body.Changes) == 0 {
+		writeErr(w, "missing changes", http.StatusBadRequest)
+		return
+	}
+
+	changes := make([]*cartv1.CartChange, 0, len(body.Changes))
+	for _, c := range body.Changes {
+		var op cartv1.CartChangeOp
+		switch strings.ToLower(strings.TrimSpace(c.Op)) {
+		case "add":
+			op = cartv1.CartChangeOp_CART_CHANGE_OP_ADD
+		case "set":
+			op = cartv1.CartChangeOp_CART_CHANGE_OP_SET
+		case "remove":
+			op = cartv1.CartChangeOp_CART_CHANGE_OP_REMOVE
+		default:
+			writeErr(w, "op must be add, set or remove", http.StatusBadRequest)
+			return
+		}
+		changes = append(changes, &cartv1.CartChange{
+			Op: op,
+			Item: &cartv1.CartItem{
+				ProductId: c.ProductID,
+				VariantId: c.VariantID,
+				Quantity:  c.Quantity,
+			},
+		})
+	}
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		writeErr(w, "invalid If-Match", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.cart.ApplyChanges(ctx, &cartv1.ApplyCartChangesRequest{
+		UserId:          owner.userID,
+		GuestToken:      owner.guestToken,
+		Changes:         changes,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		s.log.Error("apply cart changes failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", owner.userID))
+		writeCartError(w, err)
+		return
+	}
+
+	writeCart(w, http.StatusOK, resp)
+}
+
+func (s *server) clearCartHTTP(w http.ResponseWriter, r *http.Request, userID string) {
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		writeErr(w, "invalid If-Match", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	// NOTE: your current Cart gRPC ClearCart expects CartId but uses it like a user_id in server code.
+	resp, err := s.cart.ClearCart(ctx, &cartv1.CartId{Id: userID, ExpectedVersion: version})
+	if err != nil {
+		s.log.Error("clear cart failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		writeCartError(w, err)
+		return
+	}
+
+	writeCart(w, http.StatusOK, resp)
+}
+
+func (s *server) createGuestCartHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.cart.CreateGuestCart(ctx, &cartv1.CreateGuestCartRequest{})
+	if err != nil {
+		s.log.Error("create guest cart failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(resp.Cart))
+	writeJSON(w, http.StatusCreated, guestCartHTTP{Token: resp.Token, Cart: toHTTPCart(resp.Cart)})
+}
+
+func (s *server) getGuestCartHTTP(w http.ResponseWriter, r *http.Request, token string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.cart.GetGuestCart(ctx, &cartv1.GuestToken{Token: token})
+	if err != nil {
+		s.log.Error("get guest cart failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+
+	writeCart(w, http.StatusOK, resp)
+}
+
+func (s *server) mergeCartHTTP(w http.ResponseWriter, r *http.Request, userID string) {
+	var body mergeCartReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(body.GuestToken) == "" {
+		writeErr(w, "missing guest_token", http.StatusBadRequest)
+		return
+	}
+
+	var strategy cartv1.MergeStrategy
+	switch strings.ToLower(strings.TrimSpace(body.Strategy)) {
+	case "", "sum":
+		strategy = cartv1.MergeStrategy_MERGE_STRATEGY_SUM
+	case "latest":
+		strategy = cartv1.MergeStrategy_MERGE_STRATEGY_LATEST
+	default:
+		writeErr(w, "strategy must be sum or latest", http.StatusBadRequest)
+		return
+	}
+
+	version, ok := ifMatchVersion(r)
+	if !ok {
+		writeErr(w, "invalid If-Match", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.cart.MergeCart(ctx, &cartv1.MergeCartRequest{
+		GuestToken:      body.GuestToken,
+		UserId:          userID,
+		Strategy:        strategy,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		s.log.Error("merge cart failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		writeCartError(w, err)
+		return
+	}
+
+	writeCart(w, http.StatusOK, resp)
+}
+
+func toHTTPCart(c *cartv1.Cart) cartHTTP {
+	out := cartHTTP{
+		ID:        c.GetId(),
+		UserID:    c.GetUserId(),
+		Status:    c.GetStatus(),
+		CreatedAt: c.GetCreatedAtUnix(),
+		UpdatedAt: c.GetUpdatedAtUnix(),
+		Version:   c.GetVersion(),
+		Items:     make([]cartItemHTTP, 0, len(c.GetItems())),
+	}
+	for _, it := range c.GetItems() {
+		out.Items = append(out.Items, cartItemHTTP{
+			ProductID: it.GetProductId(),
+			VariantID: it.GetVariantId(),
+			Quantity:  it.GetQuantity(),
+			UnitPrice: toHTTPMoney(it.GetUnitPrice()),
+		})
+	}
+	for _, n := range c.GetNotices() {
+		out.Notices = append(out.Notices, cartNoticeHTTP{
+			ProductID: n.GetProductId(),
+			VariantID: n.GetVariantId(),
+			Kind:      n.GetKind(),
+			OldPrice:  toHTTPMoney(n.GetOldPrice()),
+			NewPrice:  toHTTPMoney(n.GetNewPrice()),
+			Available: n.GetAvailable(),
+		})
+	}
+	return out
+}
+
+func toHTTPMoney(m *cartv1.Money) *moneyHTTP {
+	if m == nil {
+		return nil
+	}
+	return &moneyHTTP{Currency: m.GetCurrency(), Amount: m.GetAmount()}
+}
+
+func writeCart(w http.ResponseWriter, status int, c *cartv1.Cart) {
+	w.Header().Set("ETag", cartETag(c))
+	writeJSON(w, status, toHTTPCart(c))
+}
+
+func cartETag(c *cartv1.Cart) string {
+	return strconv.Quote(strconv.FormatInt(c.GetVersion(), 10))
+}
+
+// ifMatchVersion reads the cart version from If-Match. A missing header or
+// "*" gives 0, which skips the version check.
+func ifMatchVersion(r *http.Request) (int64, bool) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "" || v == "*" {
+		return 0, true
+	}
+	v = strings.TrimPrefix(v, "W/")
+	unquoted, err := strconv.Unquote(v)
+	if err != nil {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// writeCartError is httpStatusFromGRPC for cart writes: the cart service only
+// answers ABORTED when If-Match is stale, which HTTP calls 412.
+func writeCartError(w http.ResponseWriter, err error) {
+	if status.Code(err) == codes.Aborted {
+		writeAPIError(w, http.StatusPreconditionFailed, "PRECONDITION_FAILED", status.Convert(err).Message())
+		return
+	}
+	httpCode, code, msg := httpStatusFromGRPC(err)
+	writeAPIError(w, httpCode, code, msg)
+}
+
+/* =========================
+   Wishlist HTTP
+   ========================= */
+
+type wishlistItemHTTP struct {
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id,omitempty"`
+	AddedAt   int64  `json:"added_at_unix"`
+}
+
+type wishlistHTTP struct {
+	UserID string             `json:"user_id"`
+	Items  []wishlistItemHTTP `json:"items"`
+}
+
+type addWishlistItemReq struct {
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id"`
+}
+
+type moveToCartReq struct {
+	Quantity int32 `json:"quantity"` // default 1
+}
+
+// Routes:
+// GET    /v1/wishlist/{user_id}
+// POST   /v1/wishlist/{user_id}/items                                  body: {"product_id": "...", "variant_id": "..."}
+// DELETE /v1/wishlist/{user_id}/items/{product_id}?variant_id=...
+// POST   /v1/wishlist/{user_id}/items/{product_id}/move-to-cart?variant_id=...   body: {"quantity": 1}
+func (s *server) wishlistHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/wishlist/"), "/")
+	parts := strings.Split(path, "/")
+	userID := strings.TrimSpace(parts[0])
+	if userID == "" {
+		writeErr(w, "missing user_id", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.getWishlistHTTP(w, r, userID)
+	case len(parts) == 2 && parts[1] == "items" && r.Method == http.MethodPost:
+		s.addWishlistItemHTTP(w, r, userID)
+	case len(parts) == 3 && parts[1] == "items" && r.Method == http.MethodDelete:
+		s.removeWishlistItemHTTP(w, r, userID, parts[2])
+	case len(parts) == 4 && parts[1] == "items" && parts[3] == "move-to-cart" && r.Method == http.MethodPost:
+		s.moveToCartHTTP(w, r, userID, parts[2])
+	case len(parts) == 1 || (len(parts) <= 3 && parts[1] == "items") || (len(parts) == 4 && parts[1] == "items" && parts[3] == "move-to-cart"):
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		writeErr(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (s *server) getWishlistHTTP(w http.ResponseWriter, r *http.Request, userID string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.wishlist.GetWishlist(ctx, &wishlistv1.GetWishlistRequest{UserId: userID})
+	if err != nil {
+		s.log.Error("get wishlist failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPWishlist(resp))
+}
+
+func (s *server) addWishlistItemHTTP(w http.ResponseWriter, r *http.Request, userID string) {
+	var body addWishlistItemReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(body.ProductID) == "" {
+		writeErr(w, "missing product_id", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.wishlist.AddItem(ctx, &wishlistv1.AddWishlistItemRequest{
+		UserId:    userID,
+		ProductId: body.ProductID,
+		VariantId: body.VariantID,
+	})
+	if err != nil {
+		s.log.Error("add wishlist item failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPWishlist(resp))
+}
+
+func (s *server) removeWishlistItemHTTP(w http.ResponseWriter, r *http.Request, userID, productID string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.wishlist.RemoveItem(ctx, &wishlistv1.RemoveWishlistItemRequest{
+		UserId:    userID,
+		ProductId: productID,
+		VariantId: r.URL.Query().Get("variant_id"),
+	})
+	if err != nil {
+		s.log.Error("remove wishlist item failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPWishlist(resp))
+}
+
+func (s *server) moveToCartHTTP(w http.ResponseWriter, r *http.Request, userID, productID string) {
+	var body moveToCartReq
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeErr(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+	}
+	if body.Quantity < 0 {
+		writeErr(w, "quantity must be > 0", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.wishlist.MoveToCart(ctx, &wishlistv1.MoveToCartRequest{
+		UserId:    userID,
+		ProductId: productID,
+		VariantId: r.URL.Query().Get("variant_id"),
+		Quantity:  body.Quantity,
+	})
+	if err != nil {
+		s.log.Error("move to cart failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPWishlist(resp))
+}
+
+func toHTTPWishlist(wl *wishlistv1.Wishlist) wishlistHTTP {
+	out := wishlistHTTP{
+		UserID: wl.GetUserId(),
+		Items:  make([]wishlistItemHTTP, 0, len(wl.GetItems())),
+	}
+	for _, it := range wl.GetItems() {
+		out.Items = append(out.Items, wishlistItemHTTP{
+			ProductID: it.GetProductId(),
+			VariantID: it.GetVariantId(),
+			AddedAt:   it.GetAddedAtUnix(),
+		})
+	}
+	return out
+}
+
+/* =========================
+   Checkout Quote HTTP
+   ========================= */
+
+// GET /v1/checkout/quote/{user_id}?currency=USD&code=SUMMER10&code=FREESHIP&country=ID
+func (s *server) quoteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := strings.TrimPrefix(r.URL.Path, "/v1/checkout/quote/")
+	userID = strings.Trim(userID, "/")
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		writeErr(w, "missing user_id", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.checkout.Quote(ctx, &checkoutv1.QuoteRequest{
+		UserId:          userID,
+		DisplayCurrency: r.URL.Query().Get("currency"),
+		CouponCodes:     r.URL.Query()["code"],
+		Country:         r.URL.Query().Get("country"),
+	})
+	if err != nil {
+		s.log.Error("quote failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+
+	// resp already JSON-friendly, but we’ll map explicitly.
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type placeOrderReq struct {
+	ShippingOption string   `json:"shipping_option"`
+	Country        string   `json:"country"`
+	CouponCodes    []string `json:"coupon_codes"`
+}
+
+// POST /v1/checkout/place-order/{user_id}
+func (s *server) placeOrderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID := strings.TrimPrefix(r.URL.Path, "/v1/checkout/place-order/")
+	userID = strings.TrimSpace(strings.Trim(userID, "/"))
+	if userID == "" {
+		writeErr(w, "missing user_id", http.StatusBadRequest)
+		return
+	}
+
+	var body placeOrderReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(withIdempotencyKey(r), 5*time.Second)
+	defer cancel()
+
+	var header metadata.MD
+	resp, err := s.checkout.PlaceOrder(ctx, &checkoutv1.PlaceOrderRequest{
+		UserId:         userID,
+		ShippingOption: body.ShippingOption,
+		Country:        body.Country,
+		CouponCodes:    body.CouponCodes,
+	}, grpc.Header(&header))
+	if err != nil {
+		s.log.Error("place order failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+
+	copyReplayedHeader(w, header)
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+/* =========================
+   Orders HTTP
+   ========================= */
+
+type orderItemHTTP struct {
+	ID              string `json:"id"`
+	ProductID       string `json:"product_id"`
+	VariantID       string `json:"variant_id,omitempty"`
+	SKU             string `json:"sku,omitempty"`
+	Name            string `json:"name"`
+	UnitAmount      int64  `json:"unit_amount"`
+	Quantity        int32  `json:"quantity"`
+	LineTotalAmount int64  `json:"line_total_amount"`
+}
+
+type statusChangeHTTP struct {
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Actor      string `json:"actor"`
+	Reason     string `json:"reason"`
+	CreatedAt  int64  `json:"created_at_unix"`
+}
+
+type orderHTTP struct {
+	ID             string             `json:"id"`
+	UserID         string             `json:"user_id"`
+	Status         string             `json:"status"`
+	Currency       string             `json:"currency"`
+	SubtotalAmount int64              `json:"subtotal_amount"`
+	ShippingAmount int64              `json:"shipping_amount"`
+	DiscountAmount int64              `json:"discount_amount"`
+	TotalAmount    int64              `json:"total_amount"`
+	ShippingOption string             `json:"shipping_option,omitempty"`
+	Country        string             `json:"country,omitempty"`
+	Items          []orderItemHTTP    `json:"items,omitempty"`
+	StatusHistory  []statusChangeHTTP `json:"status_history,omitempty"`
+	CreatedAt      int64              `json:"created_at_unix"`
+	UpdatedAt      int64              `json:"updated_at_unix"`
+}
+
+type listOrdersResp struct {
+	Orders     []orderHTTP `json:"orders"`
+	NextCursor string      `json:"next_cursor"`
+}
+
+type createOrderReq struct {
+	UserID         string `json:"user_id"`
+	Currency       string `json:"currency"`
+	ShippingOption string `json:"shipping_option"`
+	Country        string `json:"country"`
+	Items          []struct {
+		ProductID  string `json:"product_id"`
+		VariantID  string `json:"variant_id"`
+		SKU        string `json:"sku"`
+		Name       string `json:"name"`
+		UnitAmount int64  `json:"unit_amount"`
+		Quantity   int32  `json:"quantity"`
+	} `json:"items"`
+}
+
+type createOrderResp struct {
+	OrderID     string `json:"order_id"`
+	Status      string `json:"status"`
+	TotalAmount int64  `json:"total_amount"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// GET  /v1/orders?user_id=...&status=...&limit=...&cursor=...
+// POST /v1/orders (supports the Idempotency-Key header)
+func (s *server) ordersHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listOrdersHTTP(w, r)
+	case http.MethodPost:
+		s.createOrderHTTP(w, r)
+	default:
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+type changeOrderStatusReq struct {
+	Actor  string `json:"actor"`
+	Reason string `json:"reason"`
+}
+
+// Routes:
+// GET  /v1/orders/{order_id}
+// POST /v1/orders/{order_id}/pay
+// POST /v1/orders/{order_id}/cancel
+// POST /v1/orders/{order_id}/fulfill
+func (s *server) orderByIDHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/orders/"), "/")
+	parts := strings.Split(path, "/")
+	id := strings.TrimSpace(parts[0])
+	if id == "" {
+		writeErr(w, "missing id", http.StatusBadRequest)
+		return
+	}
+
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.getOrderHTTP(w, r, id)
+		return
+	}
+
+	if len(parts) == 2 {
+		if r.Method != http.MethodPost {
+			writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		switch parts[1] {
+		case "pay":
+			s.changeOrderStatusHTTP(w, r, id, s.order.MarkPaid)
+		case "cancel":
+			s.changeOrderStatusHTTP(w, r, id, s.order.CancelOrder)
+		case "fulfill":
+			s.changeOrderStatusHTTP(w, r, id, s.order.FulfillOrder)
+		default:
+			writeErr(w, "not found", http.StatusNotFound)
+		}
+		return
+	}
+
+	writeErr(w, "not found", http.StatusNotFound)
+}
+
+func (s *server) getOrderHTTP(w http.ResponseWriter, r *http.Request, id string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.order.GetOrder(ctx, &orderv1.GetOrderRequest{OrderId: id})
+	if err != nil {
+		s.log.Error("get order failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", id))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPOrder(resp.Order))
+}
+
+type changeOrderStatusFn func(ctx context.Context, in *orderv1.ChangeOrderStatusRequest, opts ...grpc.CallOption) (*orderv1.ChangeOrderStatusResponse, error)
+
+func (s *server) changeOrderStatusHTTP(w http.ResponseWriter, r *http.Request, id string, change changeOrderStatusFn) {
+	var body changeOrderStatusReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(body.Actor) == "" {
+		writeErr(w, "missing actor", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := change(ctx, &orderv1.ChangeOrderStatusRequest{
+		OrderId: id,
+		Actor:   body.Actor,
+		Reason:  body.Reason,
+	})
+	if err != nil {
+		s.log.Error("change order status failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", id))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPOrder(resp.Order))
+}
+
+func (s *server) createOrderHTTP(w http.ResponseWriter, r *http.Request) {
+	var body createOrderReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	items := make([]*orderv1.OrderItemInput, 0, len(body.Items))
+	for _, it := range body.Items {
+		items = append(items, &orderv1.OrderItemInput{
+			ProductId:  it.ProductID,
+			VariantId:  it.VariantID,
+			Sku:        it.SKU,
+			Name:       it.Name,
+			UnitAmount: it.UnitAmount,
+			Quantity:   it.Quantity,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(withIdempotencyKey(r), 3*time.Second)
+	defer cancel()
+
+	var header metadata.MD
+	resp, err := s.order.CreateOrder(ctx, &orderv1.CreateOrderRequest{
+		UserId:         body.UserID,
+		Currency:       body.Currency,
+		ShippingOption: body.ShippingOption,
+		Country:        body.Country,
+		Items:          items,
+	}, grpc.Header(&header))
+	if err != nil {
+		s.log.Error("create order failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", body.UserID))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+
+	copyReplayedHeader(w, header)
+	writeJSON(w, http.StatusCreated, createOrderResp{
+		OrderID:     resp.GetOrderId(),
+		Status:      resp.GetStatus(),
+		TotalAmount: resp.GetTotalAmount(),
+		CreatedAt:   resp.GetCreatedAtUnix(),
+	})
+}
+
+func (s *server) listOrdersHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := strings.TrimSpace(r.URL.Query().Get("user_id"))
+	if userID == "" {
+		writeErr(w, "missing user_id", http.StatusBadRequest)
+		return
+	}
+
+	limit := 20
+	if v := strings.TrimSpace(r.URL.Query().Get("limit")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			limit = n
+		}
+	}
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	resp, err := s.checkout.Quote(ctx, &checkoutv1.QuoteRequest{UserId: userID})
+	resp, err := s.order.ListOrders(ctx, &orderv1.ListOrdersRequest{
+		UserId: userID,
+		Status: r.URL.Query().Get("status"),
+		Limit:  int32(limit),
+		Cursor: r.URL.Query().Get("cursor"),
+	})
 	if err != nil {
-		s.log.Error("quote failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
+		s.log.Error("list orders failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("user_id", userID))
 		httpCode, code, msg := httpStatusFromGRPC(err)
 		writeAPIError(w, httpCode, code, msg)
 		return
 	}
 
-	// resp already JSON-friendly, but we’ll map explicitly.
-	writeJSON(w, http.StatusOK, resp)
+	out := listOrdersResp{
+		Orders:     make([]orderHTTP, 0, len(resp.Orders)),
+		NextCursor: resp.NextCursor,
+	}
+	for _, o := range resp.Orders {
+		out.Orders = append(out.Orders, toHTTPOrder(o))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func toHTTPOrder(o *orderv1.Order) orderHTTP {
+	out := orderHTTP{
+		ID:             o.GetId(),
+		UserID:         o.GetUserId(),
+		Status:         o.GetStatus(),
+		Currency:       o.GetCurrency(),
+		SubtotalAmount: o.GetSubtotalAmount(),
+		ShippingAmount: o.GetShippingAmount(),
+		DiscountAmount: o.GetDiscountAmount(),
+		TotalAmount:    o.GetTotalAmount(),
+		ShippingOption: o.GetShippingOption(),
+		Country:        o.GetCountry(),
+		CreatedAt:      o.GetCreatedAtUnix(),
+		UpdatedAt:      o.GetUpdatedAtUnix(),
+	}
+	for _, it := range o.GetItems() {
+		out.Items = append(out.Items, orderItemHTTP{
+			ID:              it.GetId(),
+			ProductID:       it.GetProductId(),
+			VariantID:       it.GetVariantId(),
+			SKU:             it.GetSku(),
+			Name:            it.GetName(),
+			UnitAmount:      it.GetUnitAmount(),
+			Quantity:        it.GetQuantity(),
+			LineTotalAmount: it.GetLineTotalAmount(),
+		})
+	}
+	for _, h := range o.GetStatusHistory() {
+		out.StatusHistory = append(out.StatusHistory, statusChangeHTTP{
+			FromStatus: h.GetFromStatus(),
+			ToStatus:   h.GetToStatus(),
+			Actor:      h.GetActor(),
+			Reason:     h.GetReason(),
+			CreatedAt:  h.GetCreatedAtUnix(),
+		})
+	}
+	return out
+}
+
+/* =========================
+   Inventory
+   ========================= */
+
+type stockHTTP struct {
+	ProductID string `json:"product_id"`
+	OnHand    int32  `json:"on_hand"`
+	Reserved  int32  `json:"reserved"`
+	Available int32  `json:"available"`
+	UpdatedAt int64  `json:"updated_at_unix"`
+}
+
+type setStockReq struct {
+	OnHand int32 `json:"on_hand"`
+}
+
+type adjustStockReq struct {
+	Delta int32 `json:"delta"`
+}
+
+// Routes:
+// GET  /v1/inventory/{product_id}
+// PUT  /v1/inventory/{product_id}         body: {"on_hand": 10}
+// POST /v1/inventory/{product_id}/adjust  body: {"delta": -2}
+func (s *server) inventoryHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/inventory/"), "/")
+	parts := strings.Split(path, "/")
+	productID := strings.TrimSpace(parts[0])
+	if productID == "" {
+		writeErr(w, "missing product_id", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	var (
+		stock *inventoryv1.Stock
+		err   error
+	)
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		var resp *inventoryv1.GetStockResponse
+		resp, err = s.inventory.GetStock(ctx, &inventoryv1.GetStockRequest{ProductId: productID})
+		stock = resp.GetStock()
+	case len(parts) == 1 && r.Method == http.MethodPut:
+		var body setStockReq
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeErr(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+		var resp *inventoryv1.SetStockResponse
+		resp, err = s.inventory.SetStock(ctx, &inventoryv1.SetStockRequest{ProductId: productID, OnHand: body.OnHand})
+		stock = resp.GetStock()
+	case len(parts) == 2 && parts[1] == "adjust" && r.Method == http.MethodPost:
+		var body adjustStockReq
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeErr(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+		var resp *inventoryv1.AdjustStockResponse
+		resp, err = s.inventory.AdjustStock(ctx, &inventoryv1.AdjustStockRequest{ProductId: productID, Delta: body.Delta})
+		stock = resp.GetStock()
+	case len(parts) == 1 || (len(parts) == 2 && parts[1] == "adjust"):
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	default:
+		writeErr(w, "not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		s.log.Error("inventory request failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("product_id", productID))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stockHTTP{
+		ProductID: stock.GetProductId(),
+		OnHand:    stock.GetOnHand(),
+		Reserved:  stock.GetReserved(),
+		Available: stock.GetAvailable(),
+		UpdatedAt: stock.GetUpdatedAtUnix(),
+	})
+}
+
+/* =========================
+   Promotions
+   ========================= */
+
+type promotionHTTP struct {
+	ID             string     `json:"id"`
+	Code           string     `json:"code,omitempty"`
+	Name           string     `json:"name"`
+	Kind           string     `json:"kind"`
+	PercentOff     int32      `json:"percent_off,omitempty"`
+	AmountOff      *moneyHTTP `json:"amount_off,omitempty"`
+	ProductID      string     `json:"product_id,omitempty"`
+	BuyQuantity    int32      `json:"buy_quantity,omitempty"`
+	GetQuantity    int32      `json:"get_quantity,omitempty"`
+	MinSpend       *moneyHTTP `json:"min_spend,omitempty"`
+	StartsAt       int64      `json:"starts_at_unix,omitempty"`
+	EndsAt         int64      `json:"ends_at_unix,omitempty"`
+	MaxUses        int32      `json:"max_uses"`
+	MaxUsesPerUser int32      `json:"max_uses_per_user"`
+	Stackable      bool       `json:"stackable"`
+	Active         bool       `json:"active"`
+	CreatedAt      int64      `json:"created_at_unix"`
+}
+
+type listPromotionsResp struct {
+	Promotions []promotionHTTP `json:"promotions"`
+}
+
+// Routes (admin only, X-Admin-Token):
+// GET  /v1/promotions
+// POST /v1/promotions   body: {"code": "SUMMER10", "name": "Summer sale", "kind": "PERCENT_OFF", "percent_off": 10, ...}
+func (s *server) promotionsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(withAdminToken(r), 3*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case http.MethodGet:
+		resp, err := s.promotion.ListPromotions(ctx, &promotionv1.ListPromotionsRequest{})
+		if err != nil {
+			s.log.Error("list promotions failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())))
+			httpCode, code, msg := httpStatusFromGRPC(err)
+			writeAPIError(w, httpCode, code, msg)
+			return
+		}
+		out := listPromotionsResp{Promotions: make([]promotionHTTP, 0, len(resp.GetPromotions()))}
+		for _, p := range resp.GetPromotions() {
+			out.Promotions = append(out.Promotions, toHTTPPromotion(p))
+		}
+		writeJSON(w, http.StatusOK, out)
+
+	case http.MethodPost:
+		var body promotionHTTP
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeErr(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+		p, err := s.promotion.CreatePromotion(ctx, &promotionv1.CreatePromotionRequest{Promotion: fromHTTPPromotion(body)})
+		if err != nil {
+			s.log.Error("create promotion failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())))
+			httpCode, code, msg := httpStatusFromGRPC(err)
+			writeAPIError(w, httpCode, code, msg)
+			return
+		}
+		writeJSON(w, http.StatusCreated, toHTTPPromotion(p))
+
+	default:
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// POST /v1/promotions/{id}/deactivate   admin only (X-Admin-Token)
+func (s *server) promotionByIDHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/promotions/"), "/")
+	parts := strings.Split(path, "/")
+	id := strings.TrimSpace(parts[0])
+	if id == "" {
+		writeErr(w, "missing id", http.StatusBadRequest)
+		return
+	}
+	if len(parts) != 2 || parts[1] != "deactivate" {
+		writeErr(w, "not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(withAdminToken(r), 3*time.Second)
+	defer cancel()
+
+	p, err := s.promotion.DeactivatePromotion(ctx, &promotionv1.DeactivatePromotionRequest{Id: id})
+	if err != nil {
+		s.log.Error("deactivate promotion failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("promotion_id", id))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPPromotion(p))
+}
+
+func toHTTPPromotion(p *promotionv1.Promotion) promotionHTTP {
+	out := promotionHTTP{
+		ID:             p.GetId(),
+		Code:           p.GetCode(),
+		Name:           p.GetName(),
+		Kind:           p.GetKind(),
+		PercentOff:     p.GetPercentOff(),
+		ProductID:      p.GetProductId(),
+		BuyQuantity:    p.GetBuyQuantity(),
+		GetQuantity:    p.GetGetQuantity(),
+		StartsAt:       p.GetStartsAtUnix(),
+		EndsAt:         p.GetEndsAtUnix(),
+		MaxUses:        p.GetMaxUses(),
+		MaxUsesPerUser: p.GetMaxUsesPerUser(),
+		Stackable:      p.GetStackable(),
+		Active:         p.GetActive(),
+		CreatedAt:      p.GetCreatedAtUnix(),
+	}
+	if m := p.GetAmountOff(); m != nil {
+		out.AmountOff = &moneyHTTP{Currency: m.GetCurrency(), Amount: m.GetAmount()}
+	}
+	if m := p.GetMinSpend(); m != nil {
+		out.MinSpend = &moneyHTTP{Currency: m.GetCurrency(), Amount: m.GetAmount()}
+	}
+	return out
+}
+
+func fromHTTPPromotion(p promotionHTTP) *promotionv1.Promotion {
+	out := &promotionv1.Promotion{
+		Code:           p.Code,
+		Name:           p.Name,
+		Kind:           p.Kind,
+		PercentOff:     p.PercentOff,
+		ProductId:      p.ProductID,
+		BuyQuantity:    p.BuyQuantity,
+		GetQuantity:    p.GetQuantity,
+		StartsAtUnix:   p.StartsAt,
+		EndsAtUnix:     p.EndsAt,
+		MaxUses:        p.MaxUses,
+		MaxUsesPerUser: p.MaxUsesPerUser,
+		Stackable:      p.Stackable,
+	}
+	if p.AmountOff != nil {
+		out.AmountOff = &promotionv1.Money{Currency: p.AmountOff.Currency, Amount: p.AmountOff.Amount}
+	}
+	if p.MinSpend != nil {
+		out.MinSpend = &promotionv1.Money{Currency: p.MinSpend.Currency, Amount: p.MinSpend.Amount}
+	}
+	return out
+}
+
+/* =========================
+   Reviews
+   ========================= */
+
+type reviewHTTP struct {
+	ID             string `json:"id"`
+	ProductID      string `json:"product_id"`
+	UserID         string `json:"user_id"`
+	Rating         int32  `json:"rating"`
+	Title          string `json:"title"`
+	Body           string `json:"body"`
+	Status         string `json:"status"`
+	ModerationNote string `json:"moderation_note,omitempty"`
+	HelpfulCount   int32  `json:"helpful_count"`
+	CreatedAt      int64  `json:"created_at_unix"`
+	UpdatedAt      int64  `json:"updated_at_unix"`
+}
+
+type listReviewsResp struct {
+	Reviews    []reviewHTTP `json:"reviews"`
+	NextCursor string       `json:"next_cursor"`
+}
+
+type writeReviewReq struct {
+	UserID string `json:"user_id"`
+	Rating int32  `json:"rating"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+type moderateReviewReq struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+type voteHelpfulReq struct {
+	UserID  string `json:"user_id"`
+	Helpful *bool  `json:"helpful"` // default true; false withdraws the vote
+}
+
+// GET /v1/reviews?status=PENDING&sort=&limit=&cursor=   reviews of all products;
+// statuses other than APPROVED are admin only (X-Admin-Token)
+func (s *server) reviewsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.listReviewsHTTP(w, r, "")
+}
+
+// Routes:
+// PATCH /v1/reviews/{id}            body: {"user_id": "...", "rating": 4, "title": "...", "body": "..."}
+// POST  /v1/reviews/{id}/moderate   admin only (X-Admin-Token); body: {"status": "APPROVED", "note": "..."}
+// POST  /v1/reviews/{id}/helpful    body: {"user_id": "...", "helpful": true}
+func (s *server) reviewByIDHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/reviews/"), "/")
+	parts := strings.Split(path, "/")
+	id := strings.TrimSpace(parts[0])
+	if id == "" {
+		writeErr(w, "missing id", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodPatch:
+		s.updateReviewHTTP(w, r, id)
+	case len(parts) == 2 && parts[1] == "moderate" && r.Method == http.MethodPost:
+		s.moderateReviewHTTP(w, r, id)
+	case len(parts) == 2 && parts[1] == "helpful" && r.Method == http.MethodPost:
+		s.voteHelpfulHTTP(w, r, id)
+	case len(parts) == 1 || (len(parts) == 2 && (parts[1] == "moderate" || parts[1] == "helpful")):
+		writeErr(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		writeErr(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (s *server) listReviewsHTTP(w http.ResponseWriter, r *http.Request, productID string) {
+	limit := 20
+	if v := strings.TrimSpace(r.URL.Query().Get("limit")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			limit = n
+		}
+	}
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	ctx, cancel := context.WithTimeout(withAdminToken(r), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.review.ListReviews(ctx, &reviewv1.ListReviewsRequest{
+		ProductId: productID,
+		Status:    r.URL.Query().Get("status"),
+		Sort:      r.URL.Query().Get("sort"),
+		Limit:     int32(limit),
+		Cursor:    r.URL.Query().Get("cursor"),
+	})
+	if err != nil {
+		s.log.Error("list reviews failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("product_id", productID))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+
+	out := listReviewsResp{
+		Reviews:    make([]reviewHTTP, 0, len(resp.Reviews)),
+		NextCursor: resp.NextCursor,
+	}
+	for _, rv := range resp.Reviews {
+		out.Reviews = append(out.Reviews, toHTTPReview(rv))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *server) createReviewHTTP(w http.ResponseWriter, r *http.Request, productID string) {
+	var body writeReviewReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.review.CreateReview(ctx, &reviewv1.CreateReviewRequest{
+		UserId:    body.UserID,
+		ProductId: productID,
+		Rating:    body.Rating,
+		Title:     body.Title,
+		Body:      body.Body,
+	})
+	if err != nil {
+		s.log.Error("create review failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("product_id", productID))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toHTTPReview(resp.Review))
+}
+
+func (s *server) updateReviewHTTP(w http.ResponseWriter, r *http.Request, id string) {
+	var body writeReviewReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.review.UpdateReview(ctx, &reviewv1.UpdateReviewRequest{
+		Id:     id,
+		UserId: body.UserID,
+		Rating: body.Rating,
+		Title:  body.Title,
+		Body:   body.Body,
+	})
+	if err != nil {
+		s.log.Error("update review failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", id))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPReview(resp.Review))
+}
+
+func (s *server) moderateReviewHTTP(w http.ResponseWriter, r *http.Request, id string) {
+	var body moderateReviewReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(withAdminToken(r), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.review.ModerateReview(ctx, &reviewv1.ModerateReviewRequest{
+		Id:     id,
+		Status: body.Status,
+		Note:   body.Note,
+	})
+	if err != nil {
+		s.log.Error("moderate review failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", id))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPReview(resp.Review))
+}
+
+func (s *server) voteHelpfulHTTP(w http.ResponseWriter, r *http.Request, id string) {
+	var body voteHelpfulReq
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErr(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	helpful := body.Helpful == nil || *body.Helpful
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	resp, err := s.review.VoteHelpful(ctx, &reviewv1.VoteHelpfulRequest{
+		ReviewId: id,
+		UserId:   body.UserID,
+		Helpful:  helpful,
+	})
+	if err != nil {
+		s.log.Error("vote review helpful failed", slog.Any("err", err), slog.String("rid", reqIDFrom(r.Context())), slog.String("id", id))
+		httpCode, code, msg := httpStatusFromGRPC(err)
+		writeAPIError(w, httpCode, code, msg)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHTTPReview(resp.Review))
+}
+
+func toHTTPReview(rv *reviewv1.Review) reviewHTTP {
+	return reviewHTTP{
+		ID:             rv.GetId(),
+		ProductID:      rv.GetProductId(),
+		UserID:         rv.GetUserId(),
+		Rating:         rv.GetRating(),
+		Title:          rv.GetTitle(),
+		Body:           rv.GetBody(),
+		Status:         rv.GetStatus(),
+		ModerationNote: rv.GetModerationNote(),
+		HelpfulCount:   rv.GetHelpfulCount(),
+		CreatedAt:      rv.GetCreatedAtUnix(),
+		UpdatedAt:      rv.GetUpdatedAtUnix(),
+	}
 }
 
 /* =========================
    Common HTTP utils
    ========================= */
 
+// withIdempotencyKey forwards the Idempotency-Key header to the backend as
+// gRPC metadata.
+func withIdempotencyKey(r *http.Request) context.Context {
+	key := strings.TrimSpace(r.Header.Get("Idempotency-Key"))
+	if key == "" {
+		return r.Context()
+	}
+	return metadata.AppendToOutgoingContext(r.Context(), "idempotency-key", key)
+}
+
+// withAdminToken forwards the X-Admin-Token header to the backend as gRPC
+// metadata. The backend decides whether the token is valid.
+func withAdminToken(r *http.Request) context.Context {
+	token := strings.TrimSpace(r.Header.Get("X-Admin-Token"))
+	if token == "" {
+		return r.Context()
+	}
+	return metadata.AppendToOutgoingContext(r.Context(), "admin-token", token)
+}
+
+// copyReplayedHeader tells the client the response was replayed from a
+// previous request with the same Idempotency-Key.
+func copyReplayedHeader(w http.ResponseWriter, header metadata.MD) {
+	if v := header.Get("idempotent-replayed"); len(v) > 0 {
+		w.Header().Set("Idempotent-Replayed", v[0])
+	}
+}
+
 func writeJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -573,6 +2467,16 @@ func httpStatusFromGRPC(err error) (int, string, string) {
 		return http.StatusBadRequest, "INVALID_ARGUMENT", st.Message()
 	case codes.NotFound:
 		return http.StatusNotFound, "NOT_FOUND", st.Message()
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized, "UNAUTHENTICATED", st.Message()
+	case codes.PermissionDenied:
+		return http.StatusForbidden, "PERMISSION_DENIED", st.Message()
+	case codes.AlreadyExists:
+		return http.StatusConflict, "ALREADY_EXISTS", st.Message()
+	case codes.FailedPrecondition:
+		return http.StatusConflict, "FAILED_PRECONDITION", st.Message()
+	case codes.Aborted:
+		return http.StatusConflict, "ABORTED", st.Message()
 	case codes.Unavailable, codes.DeadlineExceeded:
 		return http.StatusServiceUnavailable, "UNAVAILABLE", st.Message()
 	default:
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"time"
 
 	"github.com/dwikikusuma/shoping-llm/internal/cart/domain"
 )
@@ -11,7 +12,67 @@ type CartRepo interface {
 	Create(ctx context.Context, cart domain.Cart) (domain.Cart, error)
 	AddItem(ctx context.Context, item domain.CartItem, cartId string) error
 	ClearCart(ctx context.Context, cartId string) error
-	RemoveItem(ctx context.Context, cartID string, productID string) error
+	RemoveItem(ctx context.Context, cartID string, productID, variantID string) error
+	// SetItemQuantity fails with ErrLineNotFound when the cart has no line
+	// for item.
 	SetItemQuantity(ctx context.Context, cartID string, item domain.CartItem) error
 	GetOrCreate(ctx context.Context, userID string) (domain.Cart, error)
+	LockActive(ctx context.Context, userID string) (domain.Cart, error)
+	MarkCheckedOut(ctx context.Context, cartID string) error
+
+	CreateGuest(ctx context.Context, token string) (domain.Cart, error)
+	GetGuest(ctx context.Context, token string) (domain.Cart, error)
+	LockGuest(ctx context.Context, token string) (domain.Cart, error)
+	MarkMerged(ctx context.Context, cartID string) error
+	// LockByID returns the cart with its items and locks it until the
+	// surrounding transaction ends, whatever its status.
+	LockByID(ctx context.Context, cartID string) (domain.Cart, error)
+
+	// MarkAbandoned closes up to limit ACTIVE carts last updated before
+	// cutoff as ABANDONED and returns them with their items.
+	MarkAbandoned(ctx context.Context, cutoff time.Time, limit int) ([]domain.Cart, error)
+	DeleteAbandoned(ctx context.Context, abandonedBefore time.Time) (int64, error)
+}
+
+// EventPublisher tells other teams about cart lifecycle events.
+type EventPublisher interface {
+	// AbandonedCart is called inside the transaction that abandons cart, so
+	// an error leaves the cart ACTIVE for the next run. It may be called
+	// again for a cart it already reported.
+	AbandonedCart(ctx context.Context, cart domain.Cart) error
+}
+
+// Catalog tells the cart which products and variants it may hold. Both
+// lookups take many IDs at once and leave out the IDs that don't exist.
+type Catalog interface {
+	GetProducts(ctx context.Context, productIDs []string) (map[string]Product, error)
+	GetVariants(ctx context.Context, variantIDs []string) (map[string]Variant, error)
+}
+
+type Product struct {
+	ID       string
+	Archived bool
+	// HasVariants is set when the product can only be bought as one of its
+	// variants.
+	HasVariants bool
+	Price       domain.Money
+}
+
+type Variant struct {
+	ID        string
+	ProductID string
+	Price     domain.Money // replaces the product's price
+}
+
+// Stock tells the cart how many units can still be bought, keyed like the
+// inventory: by variant ID for a variant, by product ID otherwise (see
+// stockID). IDs the inventory doesn't track are left out; they can be bought
+// in any quantity.
+type Stock interface {
+	Available(ctx context.Context, ids []string) (map[string]int32, error)
+}
+
+// TxRunner runs fn inside a single database transaction carried by ctx.
+type TxRunner interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -2,17 +2,69 @@ package app
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/dwikikusuma/shoping-llm/internal/cart/domain"
 )
 
+var (
+	ErrCartNotActive   = errors.New("cart is not active")
+	ErrInvalidInput    = errors.New("invalid input")
+	ErrUnknownProduct  = errors.New("product does not exist")
+	ErrProductArchived = errors.New("product is no longer sold")
+	ErrInvalidVariant  = errors.New("cart line has no valid variant for its product")
+	ErrQuantityLimit   = errors.New("quantity exceeds the per-line limit")
+	ErrCartFull        = errors.New("cart has too many lines")
+	ErrVersionConflict = errors.New("cart was modified by someone else")
+	ErrOutOfStock      = errors.New("not enough stock for the quantity")
+	ErrLineNotFound    = errors.New("line not in cart")
+)
+
+// abandonBatchSize bounds how many carts one transaction abandons.
+const abandonBatchSize = 100
+
+// Limits bound what one cart may hold. Zero fields take the defaults.
+type Limits struct {
+	MaxLineQuantity int32 // units of one product or variant; default 99
+	MaxLines        int   // distinct lines; default 50
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.MaxLineQuantity <= 0 {
+		l.MaxLineQuantity = 99
+	}
+	if l.MaxLines <= 0 {
+		l.MaxLines = 50
+	}
+	return l
+}
+
 type Service struct {
-	repo CartRepo
+	repo    CartRepo
+	catalog Catalog
+	stock   Stock
+	tx      TxRunner
+	events  EventPublisher
+	limits  Limits
+	now     func() time.Time
 }
 
-func NewService(repo CartRepo) *Service {
+func NewService(repo CartRepo, catalog Catalog, stock Stock, tx TxRunner, events EventPublisher, limits Limits) *Service {
 	return &Service{
-		repo: repo,
+		repo:    repo,
+		catalog: catalog,
+		stock:   stock,
+		tx:      tx,
+		events:  events,
+		limits:  limits.withDefaults(),
+		now:     time.Now,
 	}
 }
 
@@ -27,18 +79,596 @@ func (s *Service) CreateCart(ctx context.Context, cart domain.Cart) (domain.Cart
 func (s *Service) GetOrCreate(ctx context.Context, userID string) (domain.Cart, error) {
 	return s.repo.GetOrCreate(ctx, userID)
 }
-func (s *Service) AddItemToCart(ctx context.Context, item domain.CartItem, cartId string) error {
-	return s.repo.AddItem(ctx, item, cartId)
+
+// AddItemToCart adds quantity units of the line to the cart, after checking
+// with the catalog that the line can be sold, with the inventory that the
+// product's lines don't ask for more than is in stock, and that the cart
+// stays within its limits.
+//
+// Every mutation takes the cart version the caller last saw and fails with
+// ErrVersionConflict if the cart has changed since, so two tabs editing the
+// same cart cannot silently overwrite each other. An expectedVersion of 0
+// skips the check.
+func (s *Service) AddItemToCart(ctx context.Context, item domain.CartItem, cartId string, expectedVersion int64) error {
+	checks, err := s.checkChange(ctx, []domain.CartItem{item})
+	if err != nil {
+		return err
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		cart, err := s.lockActive(ctx, cartId, expectedVersion)
+		if err != nil {
+			return err
+		}
+		return s.addLine(ctx, &cart, item, checks)
+	})
+}
+
+func (s *Service) ClearCart(ctx context.Context, cartId string, expectedVersion int64) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.lockActive(ctx, cartId, expectedVersion); err != nil {
+			return err
+		}
+		return s.repo.ClearCart(ctx, cartId)
+	})
+}
+
+// SetItemQuantity changes the quantity of a line already in the cart, with
+// the same checks as AddItemToCart.
+func (s *Service) SetItemQuantity(ctx context.Context, cartID string, item domain.CartItem, expectedVersion int64) error {
+	checks, err := s.checkChange(ctx, []domain.CartItem{item})
+	if err != nil {
+		return err
+	}
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		cart, err := s.lockActive(ctx, cartID, expectedVersion)
+		if err != nil {
+			return err
+		}
+		return s.setLine(ctx, &cart, item, checks)
+	})
+}
+
+func (s *Service) RemoveItemFromCart(ctx context.Context, cartID string, productID, variantID string, expectedVersion int64) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		cart, err := s.lockActive(ctx, cartID, expectedVersion)
+		if err != nil {
+			return err
+		}
+		return s.removeLine(ctx, &cart, domain.CartItem{ProductID: productID, VariantID: variantID})
+	})
+}
+
+// MaxBatchChanges caps the changes of one ApplyChanges call.
+const MaxBatchChanges = 100
+
+// ApplyChanges applies the changes to the cart in order, with the same
+// checks as the single-line mutations, and returns the resulting cart. It
+// runs in one transaction: if any change fails, none of them stick, and the
+// error names the failing change by its index.
+func (s *Service) ApplyChanges(ctx context.Context, cartID string, changes []domain.CartChange, expectedVersion int64) (domain.Cart, error) {
+	if len(changes) == 0 || len(changes) > MaxBatchChanges {
+		return domain.Cart{}, ErrInvalidInput
+	}
+	// The catalog and the inventory are asked about every line up front, so
+	// the cart isn't locked while they answer.
+	var items []domain.CartItem
+	for _, c := range changes {
+		if c.Op == domain.ChangeAdd || c.Op == domain.ChangeSet {
+			items = append(items, c.Item)
+		}
+	}
+	checks, err := s.checkChange(ctx, items)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	var out domain.Cart
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		cart, err := s.lockActive(ctx, cartID, expectedVersion)
+		if err != nil {
+			return err
+		}
+		for i, c := range changes {
+			switch c.Op {
+			case domain.ChangeAdd:
+				err = s.addLine(ctx, &cart, c.Item, checks)
+			case domain.ChangeSet:
+				err = s.setLine(ctx, &cart, c.Item, checks)
+			case domain.ChangeRemove:
+				err = s.removeLine(ctx, &cart, c.Item)
+			default:
+				err = ErrInvalidInput
+			}
+			if err != nil {
+				return fmt.Errorf("change %d: %w", i, err)
+			}
+		}
+
+		out, err = s.repo.LockByID(ctx, cartID)
+		return err
+	})
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	return out, nil
+}
+
+// LockActiveCart returns the user's ACTIVE cart and locks it until the
+// surrounding transaction ends, so concurrent checkouts of the same cart
+// are serialized.
+func (s *Service) LockActiveCart(ctx context.Context, userID string) (domain.Cart, error) {
+	return s.repo.LockActive(ctx, userID)
+}
+
+// MarkCheckedOut closes the ACTIVE cart so a fresh one can be started.
+func (s *Service) MarkCheckedOut(ctx context.Context, cartID string) error {
+	return s.repo.MarkCheckedOut(ctx, cartID)
+}
+
+// CreateGuestCart starts an empty cart for an anonymous session and returns
+// the opaque token that addresses it from now on.
+func (s *Service) CreateGuestCart(ctx context.Context) (string, domain.Cart, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", domain.Cart{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	cart, err := s.repo.CreateGuest(ctx, token)
+	if err != nil {
+		return "", domain.Cart{}, err
+	}
+	return token, cart, nil
+}
+
+func (s *Service) GetGuestCart(ctx context.Context, token string) (domain.Cart, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return domain.Cart{}, ErrInvalidInput
+	}
+	return s.repo.GetGuest(ctx, token)
+}
+
+// MergeCart folds the guest cart into the user's ACTIVE cart, creating that
+// cart if the user has none, and closes the guest cart as MERGED. Lines only
+// in the guest cart are copied; lines in both are combined by strategy.
+// Both carts are locked for the duration, so a guest cart is merged at most
+// once and the user still ends up with a single ACTIVE cart.
+//
+// Logging in must not fail because of what the guest picked, so guest lines
+// the catalog no longer sells are dropped, quantities are capped at the
+// per-line limit and new lines stop being added once the cart is full.
+// expectedVersion, if not 0, is checked against the user's cart.
+func (s *Service) MergeCart(ctx context.Context, token, userID string, strategy domain.MergeStrategy, expectedVersion int64) (domain.Cart, error) {
+	token, userID = strings.TrimSpace(token), strings.TrimSpace(userID)
+	if token == "" || userID == "" {
+		return domain.Cart{}, ErrInvalidInput
+	}
+	if strategy == "" {
+		strategy = domain.MergeSum
+	}
+	if !strategy.Valid() {
+		return domain.Cart{}, ErrInvalidInput
+	}
+	// The guest's lines are checked before the carts are locked; under the
+	// lock only lines added since need the catalog.
+	guest, err := s.repo.GetGuest(ctx, token)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	checks, err := s.checkLines(ctx, guest.Items)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	var merged domain.Cart
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		guest, err := s.repo.LockGuest(ctx, token)
+		if err != nil {
+			return err
+		}
+		var unchecked []domain.CartItem
+		for _, it := range guest.Items {
+			if _, ok := checks[lineKey(it)]; !ok {
+				unchecked = append(unchecked, it)
+			}
+		}
+		late, err := s.checkLines(ctx, unchecked)
+		if err != nil {
+			return err
+		}
+		maps.Copy(checks, late)
+
+		if _, err := s.repo.GetOrCreate(ctx, userID); err != nil {
+			return err
+		}
+		user, err := s.repo.LockActive(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if expectedVersion != 0 && user.Version != expectedVersion {
+			return ErrVersionConflict
+		}
+
+		lines := make(map[string]domain.CartItem, len(user.Items))
+		for _, it := range user.Items {
+			lines[lineKey(it)] = it
+		}
+		for _, it := range guest.Items {
+			check := checks[lineKey(it)]
+			if check.err != nil {
+				continue
+			}
+
+			existing, ok := lines[lineKey(it)]
+			switch {
+			case !ok && len(lines) >= s.limits.MaxLines:
+				continue
+			case !ok:
+				it.Quantity = min(it.Quantity, s.limits.MaxLineQuantity)
+				it.UnitPrice = check.price
+				lines[lineKey(it)] = it
+				err = s.repo.AddItem(ctx, it, user.ID)
+			default:
+				existing.Quantity = min(strategy.MergeQuantity(existing, it), s.limits.MaxLineQuantity)
+				err = s.repo.SetItemQuantity(ctx, user.ID, existing)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := s.repo.MarkMerged(ctx, guest.ID); err != nil {
+			return err
+		}
+		merged, err = s.repo.Get(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	return merged, nil
+}
+
+// AbandonInactive marks ACTIVE carts with no activity for idleFor as
+// ABANDONED and publishes an abandoned_cart event for every user cart that
+// still had items; empty carts and guest carts are closed silently. It works
+// in batches, each in its own transaction, and returns how many carts it
+// abandoned.
+func (s *Service) AbandonInactive(ctx context.Context, idleFor time.Duration) (int, error) {
+	if idleFor <= 0 {
+		return 0, ErrInvalidInput
+	}
+	cutoff := s.now().Add(-idleFor)
+
+	total := 0
+	for {
+		var n int
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			carts, err := s.repo.MarkAbandoned(ctx, cutoff, abandonBatchSize)
+			if err != nil {
+				return err
+			}
+			for _, cart := range carts {
+				if cart.UserID == "" || len(cart.Items) == 0 {
+					continue
+				}
+				if err := s.events.AbandonedCart(ctx, cart); err != nil {
+					return err
+				}
+			}
+			n = len(carts)
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n < abandonBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// PurgeAbandoned deletes carts that were abandoned more than retention ago.
+func (s *Service) PurgeAbandoned(ctx context.Context, retention time.Duration) (int64, error) {
+	if retention <= 0 {
+		return 0, ErrInvalidInput
+	}
+	return s.repo.DeleteAbandoned(ctx, s.now().Add(-retention))
+}
+
+// Notices compares the cart's lines with the catalog and stock as they are
+// now: a line whose price moved since it was added, that can no longer be
+// bought, or that asks for more than is in stock gets a notice. Stock is
+// kept per variant, or per product for products without variants (see
+// stockID); items the inventory doesn't track never run out. The catalog
+// and the inventory are each asked once for the whole cart.
+func (s *Service) Notices(ctx context.Context, cart domain.Cart) ([]domain.Notice, error) {
+	wanted := map[string]int32{} // stockID -> units across its lines
+	for _, it := range cart.Items {
+		wanted[stockID(it)] += it.Quantity
+	}
+	checks, err := s.checkLines(ctx, cart.Items)
+	if err != nil {
+		return nil, err
+	}
+	available := map[string]int32{}
+	if len(wanted) > 0 {
+		if available, err = s.stock.Available(ctx, slices.Collect(maps.Keys(wanted))); err != nil {
+			return nil, err
+		}
+	}
+
+	var notices []domain.Notice
+	for _, it := range cart.Items {
+		check := checks[lineKey(it)]
+		switch {
+		case errors.Is(check.err, ErrProductArchived):
+			notices = append(notices, lineNotice(it, domain.NoticeProductArchived))
+			continue
+		case errors.Is(check.err, ErrUnknownProduct), errors.Is(check.err, ErrInvalidVariant):
+			notices = append(notices, lineNotice(it, domain.NoticeProductUnavailable))
+			continue
+		case check.err != nil:
+			return nil, check.err
+		}
+
+		// Prices in different currencies can't be compared; neither can a
+		// line added before prices were recorded.
+		price := check.price
+		if old := it.UnitPrice; old.Currency == price.Currency && old.Amount != price.Amount {
+			n := lineNotice(it, domain.NoticePriceDecreased)
+			if price.Amount > old.Amount {
+				n.Kind = domain.NoticePriceIncreased
+			}
+			n.OldPrice, n.NewPrice = old, price
+			notices = append(notices, n)
+		}
+
+		if n, tracked := available[stockID(it)]; tracked && wanted[stockID(it)] > n {
+			out := lineNotice(it, domain.NoticeOutOfStock)
+			out.Available = max(n, 0)
+			notices = append(notices, out)
+		}
+	}
+	return notices, nil
+}
+
+func lineNotice(it domain.CartItem, kind domain.NoticeKind) domain.Notice {
+	return domain.Notice{ProductID: it.ProductID, VariantID: it.VariantID, Kind: kind}
+}
+
+// lockActive locks the cart and checks that it can still be changed and,
+// unless expectedVersion is 0, that it hasn't been changed since the caller
+// read it.
+func (s *Service) lockActive(ctx context.Context, cartID string, expectedVersion int64) (domain.Cart, error) {
+	cart, err := s.repo.LockByID(ctx, cartID)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	if cart.Status != domain.CartStatusActive {
+		return domain.Cart{}, ErrCartNotActive
+	}
+	if expectedVersion != 0 && cart.Version != expectedVersion {
+		return domain.Cart{}, ErrVersionConflict
+	}
+	return cart, nil
+}
+
+// lineCheck is the catalog's answer for one line: its unit price, or why
+// it can't be sold.
+type lineCheck struct {
+	price domain.Money
+	err   error
+}
+
+// checkLines asks the catalog whether the lines can be sold: the product
+// must exist and not be archived, and a line must name one of its variants
+// exactly when the product has variants. It looks up all the products
+// together and all the variants together, and returns each line's answer
+// keyed by lineKey. The error is only for failed lookups.
+func (s *Service) checkLines(ctx context.Context, items []domain.CartItem) (map[string]lineCheck, error) {
+	out := make(map[string]lineCheck, len(items))
+	var productIDs, variantIDs []string
+	for _, it := range items {
+		if strings.TrimSpace(it.ProductID) == "" {
+			out[lineKey(it)] = lineCheck{err: ErrInvalidInput}
+			continue
+		}
+		productIDs = append(productIDs, it.ProductID)
+		if it.VariantID != "" {
+			variantIDs = append(variantIDs, it.VariantID)
+		}
+	}
+	if len(productIDs) == 0 {
+		return out, nil
+	}
+
+	products, err := s.catalog.GetProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	variants := map[string]Variant{}
+	if len(variantIDs) > 0 {
+		if variants, err = s.catalog.GetVariants(ctx, variantIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, it := range items {
+		key := lineKey(it)
+		if _, ok := out[key]; ok {
+			continue
+		}
+		product, ok := products[it.ProductID]
+		switch {
+		case !ok:
+			out[key] = lineCheck{err: ErrUnknownProduct}
+		case product.Archived:
+			out[key] = lineCheck{err: ErrProductArchived}
+		case it.VariantID == "" && product.HasVariants:
+			out[key] = lineCheck{err: ErrInvalidVariant}
+		case it.VariantID == "":
+			out[key] = lineCheck{price: product.Price}
+		default:
+			variant, ok := variants[it.VariantID]
+			if !ok || variant.ProductID != product.ID {
+				out[key] = lineCheck{err: ErrInvalidVariant}
+				continue
+			}
+			out[key] = lineCheck{price: variant.Price}
+		}
+	}
+	return out, nil
 }
 
-func (s *Service) ClearCart(ctx context.Context, cartId string) error {
-	return s.repo.ClearCart(ctx, cartId)
+// changeChecks is what the catalog and the inventory said about the lines
+// of a change, asked before the cart is locked.
+type changeChecks struct {
+	lines     map[string]lineCheck // by lineKey
+	available map[string]int32     // stockID -> units; untracked items are left out
 }
 
-func (s *Service) SetItemQuantity(ctx context.Context, cartID string, item domain.CartItem) error {
-	return s.repo.SetItemQuantity(ctx, cartID, item)
+// checkChange is checkLines plus the stock of the lines' items.
+func (s *Service) checkChange(ctx context.Context, items []domain.CartItem) (changeChecks, error) {
+	lines, err := s.checkLines(ctx, items)
+	if err != nil {
+		return changeChecks{}, err
+	}
+	var ids []string
+	for _, it := range items {
+		if id := stockID(it); lines[lineKey(it)].err == nil && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	available := map[string]int32{}
+	if len(ids) > 0 {
+		if available, err = s.stock.Available(ctx, ids); err != nil {
+			return changeChecks{}, err
+		}
+	}
+	return changeChecks{lines: lines, available: available}, nil
+}
+
+// checkStock fails with ErrOutOfStock when the cart's lines stocked like
+// item would ask for more units than are in stock after changing by delta.
+// Lowering a quantity is always allowed, and items the inventory doesn't
+// track never run out.
+func (c changeChecks) checkStock(cart domain.Cart, item domain.CartItem, delta int32) error {
+	id := stockID(item)
+	n, tracked := c.available[id]
+	if !tracked || delta <= 0 {
+		return nil
+	}
+	units := delta
+	for _, it := range cart.Items {
+		if stockID(it) == id {
+			units += it.Quantity
+		}
+	}
+	if units > n {
+		return ErrOutOfStock
+	}
+	return nil
 }
 
-func (s *Service) RemoveItemFromCart(ctx context.Context, cartID string, productID string) error {
-	return s.repo.RemoveItem(ctx, cartID, productID)
+// addLine adds item to the locked cart and keeps cart in step with the
+// write, so a batch checks each change against the ones before it. checks
+// holds the catalog's and the inventory's answers for item.
+func (s *Service) addLine(ctx context.Context, cart *domain.Cart, item domain.CartItem, checks changeChecks) error {
+	if item.Quantity <= 0 {
+		return ErrInvalidInput
+	}
+	check := checks.lines[lineKey(item)]
+	if check.err != nil {
+		return check.err
+	}
+	price := check.price
+	item.UnitPrice = price
+
+	i := findLine(*cart, item)
+	quantity := item.Quantity
+	if i >= 0 {
+		quantity += cart.Items[i].Quantity
+	} else if len(cart.Items) >= s.limits.MaxLines {
+		return ErrCartFull
+	}
+	if quantity > s.limits.MaxLineQuantity {
+		return ErrQuantityLimit
+	}
+	if err := checks.checkStock(*cart, item, item.Quantity); err != nil {
+		return err
+	}
+	if err := s.repo.AddItem(ctx, item, cart.ID); err != nil {
+		return err
+	}
+
+	if i >= 0 {
+		cart.Items[i].Quantity = quantity
+		cart.Items[i].UnitPrice = price
+	} else {
+		cart.Items = append(cart.Items, item)
+	}
+	return nil
+}
+
+func (s *Service) setLine(ctx context.Context, cart *domain.Cart, item domain.CartItem, checks changeChecks) error {
+	if item.Quantity <= 0 {
+		return ErrInvalidInput
+	}
+	if item.Quantity > s.limits.MaxLineQuantity {
+		return ErrQuantityLimit
+	}
+	if err := checks.lines[lineKey(item)].err; err != nil {
+		return err
+	}
+	i := findLine(*cart, item)
+	if i < 0 {
+		return ErrLineNotFound
+	}
+	if err := checks.checkStock(*cart, item, item.Quantity-cart.Items[i].Quantity); err != nil {
+		return err
+	}
+	if err := s.repo.SetItemQuantity(ctx, cart.ID, item); err != nil {
+		return err
+	}
+
+	cart.Items[i].Quantity = item.Quantity
+	return nil
+}
+
+func (s *Service) removeLine(ctx context.Context, cart *domain.Cart, item domain.CartItem) error {
+	if err := s.repo.RemoveItem(ctx, cart.ID, item.ProductID, item.VariantID); err != nil {
+		return err
+	}
+
+	if i := findLine(*cart, item); i >= 0 {
+		cart.Items = append(cart.Items[:i], cart.Items[i+1:]...)
+	}
+	return nil
+}
+
+// stockID is the inventory key of item. Each variant is stocked on its own,
+// under the variant ID.
+func stockID(item domain.CartItem) string {
+	if item.VariantID != "" {
+		return item.VariantID
+	}
+	return item.ProductID
+}
+
+// lineKey identifies item's line within a cart.
+func lineKey(item domain.CartItem) string {
+	return item.ProductID + "/" + item.VariantID
+}
+
+// findLine returns the index of item's line in cart, or -1.
+func findLine(cart domain.Cart, item domain.CartItem) int {
+	for i, it := range cart.Items {
+		if it.ProductID == item.ProductID && it.VariantID == item.VariantID {
+			return i
+		}
+	}
+	return -1
 }
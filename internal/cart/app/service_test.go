@@ -0,0 +1,801 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/cart/domain"
+)
+
+// fakeRepo keeps carts in memory. Carts are keyed by ID; owner is the user
+// ID, or "guest:" + token for guest carts.
+type fakeRepo struct {
+	carts       map[string]*domain.Cart
+	owners      map[string]string    // cart ID -> owner
+	abandonedAt map[string]time.Time // cart ID -> when it was abandoned
+	nextID      int
+	now         time.Time
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		carts:       map[string]*domain.Cart{},
+		owners:      map[string]string{},
+		abandonedAt: map[string]time.Time{},
+		now:         time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (f *fakeRepo) active(owner string) (*domain.Cart, error) {
+	for id, o := range f.owners {
+		if o == owner && f.carts[id].Status == domain.CartStatusActive {
+			return f.carts[id], nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeRepo) create(owner, userID string) domain.Cart {
+	f.nextID++
+	c := &domain.Cart{ID: fmt.Sprintf("c%d", f.nextID), UserID: userID, Status: domain.CartStatusActive, UpdatedAt: f.now, Version: 1}
+	f.carts[c.ID] = c
+	f.owners[c.ID] = owner
+	return snapshot(c)
+}
+
+// snapshot copies c like a fresh read would, so callers can't change the
+// stored lines through it.
+func snapshot(c *domain.Cart) domain.Cart {
+	out := *c
+	out.Items = slices.Clone(c.Items)
+	return out
+}
+
+func (f *fakeRepo) Get(ctx context.Context, userID string) (domain.Cart, error) {
+	c, err := f.active(userID)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	return snapshot(c), nil
+}
+
+func (f *fakeRepo) Create(ctx context.Context, cart domain.Cart) (domain.Cart, error) {
+	return f.create(cart.UserID, cart.UserID), nil
+}
+
+// AddItem stamps each write one minute after the previous one.
+func (f *fakeRepo) AddItem(ctx context.Context, item domain.CartItem, cartID string) error {
+	f.now = f.now.Add(time.Minute)
+	c := f.carts[cartID]
+	c.UpdatedAt = f.now
+	c.Version++
+	for i, it := range c.Items {
+		if it.ProductID == item.ProductID && it.VariantID == item.VariantID {
+			c.Items[i].Quantity += item.Quantity
+			c.Items[i].UpdatedAt = f.now
+			if item.UnitPrice.Currency != "" {
+				c.Items[i].UnitPrice = item.UnitPrice
+			}
+			return nil
+		}
+	}
+	item.UpdatedAt = f.now
+	c.Items = append(c.Items, item)
+	return nil
+}
+
+func (f *fakeRepo) ClearCart(ctx context.Context, cartID string) error {
+	f.carts[cartID].Items = nil
+	f.carts[cartID].Version++
+	return nil
+}
+
+func (f *fakeRepo) RemoveItem(ctx context.Context, cartID string, productID, variantID string) error {
+	c := f.carts[cartID]
+	for i, it := range c.Items {
+		if it.ProductID == productID && it.VariantID == variantID {
+			c.Items = append(c.Items[:i], c.Items[i+1:]...)
+			break
+		}
+	}
+	c.Version++
+	return nil
+}
+
+func (f *fakeRepo) SetItemQuantity(ctx context.Context, cartID string, item domain.CartItem) error {
+	f.now = f.now.Add(time.Minute)
+	c := f.carts[cartID]
+	for i, it := range c.Items {
+		if it.ProductID == item.ProductID && it.VariantID == item.VariantID {
+			c.Items[i].Quantity = item.Quantity
+			c.Items[i].UpdatedAt = f.now
+			c.Version++
+			return nil
+		}
+	}
+	return ErrLineNotFound
+}
+
+func (f *fakeRepo) GetOrCreate(ctx context.Context, userID string) (domain.Cart, error) {
+	if c, err := f.active(userID); err == nil {
+		return snapshot(c), nil
+	}
+	return f.create(userID, userID), nil
+}
+
+func (f *fakeRepo) LockActive(ctx context.Context, userID string) (domain.Cart, error) {
+	return f.Get(ctx, userID)
+}
+
+func (f *fakeRepo) MarkCheckedOut(ctx context.Context, cartID string) error {
+	f.carts[cartID].Status = domain.CartStatusCheckedOut
+	return nil
+}
+
+func (f *fakeRepo) CreateGuest(ctx context.Context, token string) (domain.Cart, error) {
+	return f.create("guest:"+token, ""), nil
+}
+
+func (f *fakeRepo) GetGuest(ctx context.Context, token string) (domain.Cart, error) {
+	c, err := f.active("guest:" + token)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	return snapshot(c), nil
+}
+
+func (f *fakeRepo) LockGuest(ctx context.Context, token string) (domain.Cart, error) {
+	return f.GetGuest(ctx, token)
+}
+
+func (f *fakeRepo) MarkMerged(ctx context.Context, cartID string) error {
+	if f.carts[cartID].Status != domain.CartStatusActive {
+		return ErrCartNotActive
+	}
+	f.carts[cartID].Status = domain.CartStatusMerged
+	return nil
+}
+
+func (f *fakeRepo) LockByID(ctx context.Context, cartID string) (domain.Cart, error) {
+	c, ok := f.carts[cartID]
+	if !ok {
+		return domain.Cart{}, sql.ErrNoRows
+	}
+	return snapshot(c), nil
+}
+
+func (f *fakeRepo) MarkAbandoned(ctx context.Context, cutoff time.Time, limit int) ([]domain.Cart, error) {
+	var out []domain.Cart
+	for i := 1; i <= f.nextID && len(out) < limit; i++ {
+		c := f.carts[fmt.Sprintf("c%d", i)]
+		if c.Status == domain.CartStatusActive && c.UpdatedAt.Before(cutoff) {
+			c.Status = domain.CartStatusAbandoned
+			f.abandonedAt[c.ID] = f.now
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) DeleteAbandoned(ctx context.Context, abandonedBefore time.Time) (int64, error) {
+	var n int64
+	for id, at := range f.abandonedAt {
+		if at.Before(abandonedBefore) {
+			delete(f.carts, id)
+			delete(f.owners, id)
+			delete(f.abandonedAt, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// fakeTx puts the carts back when fn fails, like a rolled back transaction.
+type fakeTx struct {
+	repo *fakeRepo
+}
+
+func (t fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if t.repo == nil {
+		return fn(ctx)
+	}
+	before := map[string]domain.Cart{}
+	for id, c := range t.repo.carts {
+		before[id] = snapshot(c)
+	}
+	err := fn(ctx)
+	if err != nil {
+		for id, c := range before {
+			*t.repo.carts[id] = c
+		}
+	}
+	return err
+}
+
+// fakeCatalog sells p1 and p3 as they are, p2 only as variant v1, and no
+// longer sells the archived product "old". Everything costs 10.00 USD
+// unless prices says otherwise.
+type fakeCatalog struct {
+	prices  map[string]int64 // product or variant ID -> amount in cents
+	lookups *int             // GetProducts and GetVariants calls, when set
+}
+
+func (f fakeCatalog) price(id string) domain.Money {
+	if amount, ok := f.prices[id]; ok {
+		return domain.Money{Currency: "USD", Amount: amount}
+	}
+	return domain.Money{Currency: "USD", Amount: 1000}
+}
+
+func (f fakeCatalog) GetProducts(ctx context.Context, productIDs []string) (map[string]Product, error) {
+	if f.lookups != nil {
+		*f.lookups++
+	}
+	out := map[string]Product{}
+	for _, id := range productIDs {
+		switch id {
+		case "p1", "p3":
+			out[id] = Product{ID: id, Price: f.price(id)}
+		case "p2":
+			out[id] = Product{ID: id, HasVariants: true, Price: f.price(id)}
+		case "old":
+			out[id] = Product{ID: id, Archived: true, Price: f.price(id)}
+		}
+	}
+	return out, nil
+}
+
+func (f fakeCatalog) GetVariants(ctx context.Context, variantIDs []string) (map[string]Variant, error) {
+	if f.lookups != nil {
+		*f.lookups++
+	}
+	out := map[string]Variant{}
+	if slices.Contains(variantIDs, "v1") {
+		out["v1"] = Variant{ID: "v1", ProductID: "p2", Price: f.price("v1")}
+	}
+	return out, nil
+}
+
+// fakeStock has 100 of every product unless units says otherwise, and
+// doesn't track the products in untracked.
+type fakeStock struct {
+	units     map[string]int32
+	untracked []string
+}
+
+func (f fakeStock) Available(ctx context.Context, ids []string) (map[string]int32, error) {
+	out := map[string]int32{}
+	for _, id := range ids {
+		if slices.Contains(f.untracked, id) {
+			continue
+		}
+		if n, ok := f.units[id]; ok {
+			out[id] = n
+		} else {
+			out[id] = 100
+		}
+	}
+	return out, nil
+}
+
+type fakeEvents struct {
+	abandoned []string // cart IDs
+	err       error
+}
+
+func (f *fakeEvents) AbandonedCart(ctx context.Context, cart domain.Cart) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.abandoned = append(f.abandoned, cart.ID)
+	return nil
+}
+
+func quantities(c domain.Cart) map[string]int32 {
+	out := map[string]int32{}
+	for _, it := range c.Items {
+		out[it.ProductID+"/"+it.VariantID] = it.Quantity
+	}
+	return out
+}
+
+func TestMergeCart(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		name     string
+		strategy domain.MergeStrategy
+		want     map[string]int32
+	}{
+		{"sum by default", "", map[string]int32{"p1/": 3, "p2/v1": 1, "p3/": 4}},
+		{"latest wins", domain.MergeLatest, map[string]int32{"p1/": 2, "p2/v1": 1, "p3/": 4}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			svc := NewService(repo, fakeCatalog{}, fakeStock{}, fakeTx{}, &fakeEvents{}, Limits{})
+
+			user, _ := svc.GetOrCreate(ctx, "alice")
+			svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 1}, user.ID, 0)
+			svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p3", Quantity: 4}, user.ID, 0)
+
+			// The guest touched p1 after the user did.
+			token, guest, err := svc.CreateGuestCart(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 2}, guest.ID, 0)
+			svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p2", VariantID: "v1", Quantity: 1}, guest.ID, 0)
+
+			merged, err := svc.MergeCart(ctx, token, "alice", tc.strategy, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if merged.ID != user.ID {
+				t.Fatalf("expected the merge to keep the user's cart %s, got %s", user.ID, merged.ID)
+			}
+			got := quantities(merged)
+			if len(got) != len(tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+			for k, q := range tc.want {
+				if got[k] != q {
+					t.Fatalf("expected %v, got %v", tc.want, got)
+				}
+			}
+
+			if _, err := svc.GetGuestCart(ctx, token); !errors.Is(err, sql.ErrNoRows) {
+				t.Fatalf("expected the guest cart to be closed, got %v", err)
+			}
+			if _, err := svc.MergeCart(ctx, token, "alice", tc.strategy, 0); !errors.Is(err, sql.ErrNoRows) {
+				t.Fatalf("expected a second merge to find no guest cart, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMergeCartKeepsOlderUserLineOnLatest(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := NewService(repo, fakeCatalog{}, fakeStock{}, fakeTx{}, &fakeEvents{}, Limits{})
+
+	token, guest, _ := svc.CreateGuestCart(ctx)
+	svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 5}, guest.ID, 0)
+	user, _ := svc.GetOrCreate(ctx, "alice")
+	svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 1}, user.ID, 0)
+
+	merged, err := svc.MergeCart(ctx, token, "alice", domain.MergeLatest, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := quantities(merged)["p1/"]; got != 1 {
+		t.Fatalf("expected the user's newer line to win, got %d", got)
+	}
+}
+
+func TestMergeCartCreatesUserCart(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := NewService(repo, fakeCatalog{}, fakeStock{}, fakeTx{}, &fakeEvents{}, Limits{})
+
+	token, guest, _ := svc.CreateGuestCart(ctx)
+	svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 2}, guest.ID, 0)
+
+	merged, err := svc.MergeCart(ctx, token, "bob", domain.MergeSum, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.UserID != "bob" || quantities(merged)["p1/"] != 2 {
+		t.Fatalf("expected bob's new cart to hold the guest lines, got %+v", merged)
+	}
+
+	for _, tc := range []struct {
+		token, user string
+		strategy    domain.MergeStrategy
+	}{
+		{"", "bob", domain.MergeSum},
+		{token, " ", domain.MergeSum},
+		{token, "bob", "newest"},
+	} {
+		if _, err := svc.MergeCart(ctx, tc.token, tc.user, tc.strategy, 0); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput for %+v, got %v", tc, err)
+		}
+	}
+}
+
+func TestMergeCartSkipsLinesItCannotKeep(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := NewService(repo, fakeCatalog{}, fakeStock{}, fakeTx{}, &fakeEvents{}, Limits{MaxLineQuantity: 5, MaxLines: 2})
+
+	user, _ := svc.GetOrCreate(ctx, "alice")
+	svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 3}, user.ID, 0)
+
+	// Lines the guest added before the catalog changed, or the limits did.
+	token, guest, _ := svc.CreateGuestCart(ctx)
+	repo.AddItem(ctx, domain.CartItem{ProductID: "old", Quantity: 1}, guest.ID)
+	repo.AddItem(ctx, domain.CartItem{ProductID: "p1", Quantity: 4}, guest.ID)
+	repo.AddItem(ctx, domain.CartItem{ProductID: "p2", VariantID: "v1", Quantity: 9}, guest.ID)
+	repo.AddItem(ctx, domain.CartItem{ProductID: "p3", Quantity: 1}, guest.ID)
+
+	merged, err := svc.MergeCart(ctx, token, "alice", domain.MergeSum, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := quantities(merged)
+	want := map[string]int32{"p1/": 5, "p2/v1": 5}
+	if len(got) != len(want) || got["p1/"] != want["p1/"] || got["p2/v1"] != want["p2/v1"] {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestAddItemToCartChecksTheCatalog(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := NewService(repo, fakeCatalog{}, fakeStock{}, fakeTx{}, &fakeEvents{}, Limits{})
+	cart, _ := svc.GetOrCreate(ctx, "alice")
+
+	cases := []struct {
+		name string
+		item domain.CartItem
+		want error
+	}{
+		{"unknown product", domain.CartItem{ProductID: "nope", Quantity: 1}, ErrUnknownProduct},
+		{"archived product", domain.CartItem{ProductID: "old", Quantity: 1}, ErrProductArchived},
+		{"product sold only as variants", domain.CartItem{ProductID: "p2", Quantity: 1}, ErrInvalidVariant},
+		{"variant of another product", domain.CartItem{ProductID: "p1", VariantID: "v1", Quantity: 1}, ErrInvalidVariant},
+		{"unknown variant", domain.CartItem{ProductID: "p2", VariantID: "v9", Quantity: 1}, ErrInvalidVariant},
+		{"no quantity", domain.CartItem{ProductID: "p1"}, ErrInvalidInput},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := svc.AddItemToCart(ctx, tc.item, cart.ID, 0); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+	if c, _ := svc.GetCart(ctx, "alice"); len(c.Items) != 0 {
+		t.Fatalf("expected no line to be added, got %+v", c.Items)
+	}
+
+	if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p2", VariantID: "v1", Quantity: 1}, cart.ID, 0); err != nil {
+		t.Fatalf("expected a variant of p2 to be accepted, got %v", err)
+	}
+}
+
+func TestAddItemToCartEnforcesLimits(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := NewService(repo, fakeCatalog{}, fakeStock{}, fakeTx{}, &fakeEvents{}, Limits{MaxLineQuantity: 5, MaxLines: 2})
+	cart, _ := svc.GetOrCreate(ctx, "alice")
+
+	if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 3}, cart.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 3}, cart.ID, 0); !errors.Is(err, ErrQuantityLimit) {
+		t.Fatalf("expected 6 units of p1 to hit the limit, got %v", err)
+	}
+	if err := svc.SetItemQuantity(ctx, cart.ID, domain.CartItem{ProductID: "p1", Quantity: 6}, 0); !errors.Is(err, ErrQuantityLimit) {
+		t.Fatalf("expected setting 6 units of p1 to hit the limit, got %v", err)
+	}
+
+	if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p3", Quantity: 1}, cart.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p2", VariantID: "v1", Quantity: 1}, cart.ID, 0); !errors.Is(err, ErrCartFull) {
+		t.Fatalf("expected a third line to be refused, got %v", err)
+	}
+	if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 2}, cart.ID, 0); err != nil {
+		t.Fatalf("expected a full cart to still take more of an existing line, got %v", err)
+	}
+
+	svc.MarkCheckedOut(ctx, cart.ID)
+	if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 1}, cart.ID, 0); !errors.Is(err, ErrCartNotActive) {
+		t.Fatalf("expected a checked out cart to be refused, got %v", err)
+	}
+}
+
+func TestAddItemToCartChecksStock(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	stock := fakeStock{units: map[string]int32{"p1": 3}, untracked: []string{"p3"}}
+	svc := NewService(repo, fakeCatalog{}, stock, fakeTx{}, &fakeEvents{}, Limits{})
+	cart, _ := svc.GetOrCreate(ctx, "alice")
+
+	if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 2}, cart.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 2}, cart.ID, 0); !errors.Is(err, ErrOutOfStock) {
+		t.Fatalf("expected 4 units of p1 to be refused, got %v", err)
+	}
+	if err := svc.SetItemQuantity(ctx, cart.ID, domain.CartItem{ProductID: "p1", Quantity: 4}, 0); !errors.Is(err, ErrOutOfStock) {
+		t.Fatalf("expected setting 4 units of p1 to be refused, got %v", err)
+	}
+	if err := svc.SetItemQuantity(ctx, cart.ID, domain.CartItem{ProductID: "p1", Quantity: 3}, 0); err != nil {
+		t.Fatalf("expected all 3 units of p1 to fit, got %v", err)
+	}
+	if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p3", Quantity: 50}, cart.ID, 0); err != nil {
+		t.Fatalf("expected an untracked product to never run out, got %v", err)
+	}
+
+	// The stock ran out after the line was added; lowering it still works.
+	stock.units["p1"] = 1
+	if err := svc.SetItemQuantity(ctx, cart.ID, domain.CartItem{ProductID: "p1", Quantity: 2}, 0); err != nil {
+		t.Fatalf("expected lowering a quantity to be allowed, got %v", err)
+	}
+
+	t.Run("variants are stocked on their own", func(t *testing.T) {
+		stock.units["v1"] = 2
+		bob, _ := svc.GetOrCreate(ctx, "bob")
+		if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p2", VariantID: "v1", Quantity: 3}, bob.ID, 0); !errors.Is(err, ErrOutOfStock) {
+			t.Fatalf("expected 3 units of v1 to be refused, got %v", err)
+		}
+		if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p2", VariantID: "v1", Quantity: 2}, bob.ID, 0); err != nil {
+			t.Fatalf("expected both units of v1 to fit, got %v", err)
+		}
+		if err := svc.SetItemQuantity(ctx, bob.ID, domain.CartItem{ProductID: "p2", VariantID: "v1", Quantity: 3}, 0); !errors.Is(err, ErrOutOfStock) {
+			t.Fatalf("expected setting 3 units of v1 to be refused, got %v", err)
+		}
+	})
+}
+
+func TestStaleWritesAreRejected(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := NewService(repo, fakeCatalog{}, fakeStock{}, fakeTx{}, &fakeEvents{}, Limits{})
+	cart, _ := svc.GetOrCreate(ctx, "alice")
+
+	// Both tabs read version 1; the first write wins.
+	seen := cart.Version
+	if err := svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 1}, cart.ID, seen); err != nil {
+		t.Fatal(err)
+	}
+	p1, p3 := domain.CartItem{ProductID: "p1", Quantity: 2}, domain.CartItem{ProductID: "p3", Quantity: 1}
+	for name, write := range map[string]func() error{
+		"add":    func() error { return svc.AddItemToCart(ctx, p3, cart.ID, seen) },
+		"set":    func() error { return svc.SetItemQuantity(ctx, cart.ID, p1, seen) },
+		"remove": func() error { return svc.RemoveItemFromCart(ctx, cart.ID, "p1", "", seen) },
+		"clear":  func() error { return svc.ClearCart(ctx, cart.ID, seen) },
+	} {
+		if err := write(); !errors.Is(err, ErrVersionConflict) {
+			t.Fatalf("expected a stale %s to fail with ErrVersionConflict, got %v", name, err)
+		}
+	}
+
+	cart, _ = svc.GetCart(ctx, "alice")
+	if got := quantities(cart); len(got) != 1 || got["p1/"] != 1 {
+		t.Fatalf("expected the stale writes to leave the cart alone, got %v", got)
+	}
+	if err := svc.SetItemQuantity(ctx, cart.ID, p1, cart.Version); err != nil {
+		t.Fatalf("expected a write with the current version to succeed, got %v", err)
+	}
+	if err := svc.AddItemToCart(ctx, p3, cart.ID, 0); err != nil {
+		t.Fatalf("expected a write without a version to skip the check, got %v", err)
+	}
+}
+
+func TestApplyChanges(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	svc := NewService(repo, fakeCatalog{}, fakeStock{}, fakeTx{repo: repo}, &fakeEvents{}, Limits{MaxLines: 2})
+	cart, _ := svc.GetOrCreate(ctx, "alice")
+	svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 1}, cart.ID, 0)
+
+	// p2/v1 only fits because p3 and p1 are removed earlier in the batch.
+	got, err := svc.ApplyChanges(ctx, cart.ID, []domain.CartChange{
+		{Op: domain.ChangeSet, Item: domain.CartItem{ProductID: "p1", Quantity: 3}},
+		{Op: domain.ChangeAdd, Item: domain.CartItem{ProductID: "p3", Quantity: 2}},
+		{Op: domain.ChangeRemove, Item: domain.CartItem{ProductID: "p3"}},
+		{Op: domain.ChangeAdd, Item: domain.CartItem{ProductID: "p2", VariantID: "v1", Quantity: 1}},
+	}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := quantities(got); len(q) != 2 || q["p1/"] != 3 || q["p2/v1"] != 1 {
+		t.Fatalf("expected p1 x3 and p2/v1 x1, got %v", q)
+	}
+
+	t.Run("a failing change rolls back the whole batch", func(t *testing.T) {
+		_, err := svc.ApplyChanges(ctx, cart.ID, []domain.CartChange{
+			{Op: domain.ChangeRemove, Item: domain.CartItem{ProductID: "p1"}},
+			{Op: domain.ChangeAdd, Item: domain.CartItem{ProductID: "old", Quantity: 1}},
+		}, 0)
+		if !errors.Is(err, ErrProductArchived) || !strings.Contains(err.Error(), "change 1") {
+			t.Fatalf("expected change 1 to fail with ErrProductArchived, got %v", err)
+		}
+		after, _ := svc.GetCart(ctx, "alice")
+		if q := quantities(after); len(q) != 2 || q["p1/"] != 3 || after.Version != got.Version {
+			t.Fatalf("expected the cart to be left as it was, got %v at version %d", q, after.Version)
+		}
+	})
+
+	t.Run("asks the catalog once for the whole batch", func(t *testing.T) {
+		lookups := 0
+		svc := NewService(repo, fakeCatalog{lookups: &lookups}, fakeStock{}, fakeTx{repo: repo}, &fakeEvents{}, Limits{})
+		bob, _ := svc.GetOrCreate(ctx, "bob")
+
+		_, err := svc.ApplyChanges(ctx, bob.ID, []domain.CartChange{
+			{Op: domain.ChangeAdd, Item: domain.CartItem{ProductID: "p1", Quantity: 2}},
+			{Op: domain.ChangeAdd, Item: domain.CartItem{ProductID: "p3", Quantity: 1}},
+			{Op: domain.ChangeAdd, Item: domain.CartItem{ProductID: "p2", VariantID: "v1", Quantity: 2}},
+			{Op: domain.ChangeSet, Item: domain.CartItem{ProductID: "p1", Quantity: 1}},
+		}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lookups != 2 {
+			t.Fatalf("expected one product and one variant lookup, got %d lookups", lookups)
+		}
+	})
+
+	for _, tc := range []struct {
+		name    string
+		changes []domain.CartChange
+		version int64
+		want    error
+	}{
+		{"no changes", nil, 0, ErrInvalidInput},
+		{"unknown op", []domain.CartChange{{Op: "bump", Item: domain.CartItem{ProductID: "p1"}}}, 0, ErrInvalidInput},
+		{"set a line that isn't there", []domain.CartChange{{Op: domain.ChangeSet, Item: domain.CartItem{ProductID: "p3", Quantity: 1}}}, 0, ErrLineNotFound},
+		{"stale version", []domain.CartChange{{Op: domain.ChangeRemove, Item: domain.CartItem{ProductID: "p1"}}}, 2, ErrVersionConflict},
+	} {
+		if _, err := svc.ApplyChanges(ctx, cart.ID, tc.changes, tc.version); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestAbandonInactive(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	events := &fakeEvents{}
+	svc := NewService(repo, fakeCatalog{}, fakeStock{}, fakeTx{repo: repo}, events, Limits{})
+	svc.now = func() time.Time { return repo.now }
+
+	idle, _ := svc.GetOrCreate(ctx, "alice")
+	svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 1}, idle.ID, 0)
+	svc.GetOrCreate(ctx, "bob") // idle but empty
+	_, guest, _ := svc.CreateGuestCart(ctx)
+	svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 1}, guest.ID, 0)
+
+	repo.now = repo.now.Add(8 * 24 * time.Hour)
+	recent, _ := svc.GetOrCreate(ctx, "carol")
+	svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p2", Quantity: 1}, recent.ID, 0)
+
+	t.Run("a failed publish keeps the batch active", func(t *testing.T) {
+		events.err = errors.New("redis down")
+		if _, err := svc.AbandonInactive(ctx, 7*24*time.Hour); err == nil {
+			t.Fatal("expected the publish error")
+		}
+		events.err = nil
+		if repo.carts[idle.ID].Status != domain.CartStatusActive {
+			t.Fatalf("expected %s to stay ACTIVE, got %s", idle.ID, repo.carts[idle.ID].Status)
+		}
+	})
+
+	n, err := svc.AbandonInactive(ctx, 7*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("expected alice's, bob's and the guest cart to be abandoned, got %d", n)
+	}
+	if len(events.abandoned) != 1 || events.abandoned[0] != idle.ID {
+		t.Fatalf("expected one event for alice's cart, got %v", events.abandoned)
+	}
+	if repo.carts[recent.ID].Status != domain.CartStatusActive {
+		t.Fatalf("expected carol's recent cart to stay ACTIVE")
+	}
+	if c, _ := svc.GetOrCreate(ctx, "alice"); c.ID == idle.ID || len(c.Items) != 0 {
+		t.Fatalf("expected alice to start a fresh cart, got %+v", c)
+	}
+
+	repo.now = repo.now.Add(30 * 24 * time.Hour)
+	if purged, _ := svc.PurgeAbandoned(ctx, 90*24*time.Hour); purged != 0 {
+		t.Fatalf("expected nothing to purge within retention, got %d", purged)
+	}
+	repo.now = repo.now.Add(61 * 24 * time.Hour)
+	if purged, _ := svc.PurgeAbandoned(ctx, 90*24*time.Hour); purged != 3 {
+		t.Fatalf("expected the 3 abandoned carts to be purged, got %d", purged)
+	}
+}
+
+func TestNotices(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo()
+	catalog := fakeCatalog{prices: map[string]int64{}}
+	stock := fakeStock{units: map[string]int32{}}
+	svc := NewService(repo, catalog, stock, fakeTx{repo: repo}, &fakeEvents{}, Limits{})
+
+	cart, _ := svc.GetOrCreate(ctx, "alice")
+	svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 1}, cart.ID, 0)
+	svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p2", VariantID: "v1", Quantity: 1}, cart.ID, 0)
+	svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p3", Quantity: 5}, cart.ID, 0)
+
+	cart, _ = svc.GetOrCreate(ctx, "alice")
+	for _, it := range cart.Items {
+		if it.UnitPrice != (domain.Money{Currency: "USD", Amount: 1000}) {
+			t.Fatalf("expected each line to record the price it was added at, got %+v", it)
+		}
+	}
+	if notices, err := svc.Notices(ctx, cart); err != nil || len(notices) != 0 {
+		t.Fatalf("expected no notices while nothing changed, got %+v, %v", notices, err)
+	}
+
+	catalog.prices["p1"] = 1200
+	catalog.prices["v1"] = 800
+	stock.units["p3"] = 2
+	// Lines the catalog stopped selling since they were added.
+	repo.carts[cart.ID].Items = append(repo.carts[cart.ID].Items,
+		domain.CartItem{ProductID: "old", Quantity: 1},
+		domain.CartItem{ProductID: "gone", Quantity: 1},
+	)
+
+	cart, _ = svc.GetOrCreate(ctx, "alice")
+	notices, err := svc.Notices(ctx, cart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usd := func(amount int64) domain.Money { return domain.Money{Currency: "USD", Amount: amount} }
+	want := []domain.Notice{
+		{ProductID: "p1", Kind: domain.NoticePriceIncreased, OldPrice: usd(1000), NewPrice: usd(1200)},
+		{ProductID: "p2", VariantID: "v1", Kind: domain.NoticePriceDecreased, OldPrice: usd(1000), NewPrice: usd(800)},
+		{ProductID: "p3", Kind: domain.NoticeOutOfStock, Available: 2},
+		{ProductID: "old", Kind: domain.NoticeProductArchived},
+		{ProductID: "gone", Kind: domain.NoticeProductUnavailable},
+	}
+	if !slices.Equal(notices, want) {
+		t.Fatalf("expected notices\n%+v\ngot\n%+v", want, notices)
+	}
+
+	t.Run("adding to a line records the new price", func(t *testing.T) {
+		svc.AddItemToCart(ctx, domain.CartItem{ProductID: "p1", Quantity: 1}, cart.ID, 0)
+		cart, _ := svc.GetOrCreate(ctx, "alice")
+		notices, _ := svc.Notices(ctx, cart)
+		for _, n := range notices {
+			if n.ProductID == "p1" {
+				t.Fatalf("expected no notice for p1 after adding it again, got %+v", n)
+			}
+		}
+	})
+
+	t.Run("products the inventory doesn't track never run out", func(t *testing.T) {
+		lookups := 0
+		svc := NewService(repo, fakeCatalog{lookups: &lookups}, fakeStock{units: map[string]int32{"p3": 0}, untracked: []string{"p3"}}, fakeTx{repo: repo}, &fakeEvents{}, Limits{})
+		repo.carts[cart.ID].Items = []domain.CartItem{{ProductID: "p3", Quantity: 5}, {ProductID: "p2", VariantID: "v1", Quantity: 1}}
+		cart, _ := svc.GetOrCreate(ctx, "alice")
+
+		if notices, err := svc.Notices(ctx, cart); err != nil || len(notices) != 0 {
+			t.Fatalf("expected no notices, got %+v, %v", notices, err)
+		}
+		if lookups != 2 {
+			t.Fatalf("expected one product and one variant lookup, got %d lookups", lookups)
+		}
+	})
+
+	t.Run("variant lines are checked against their variant's stock", func(t *testing.T) {
+		stock.units["v1"] = 1
+		repo.carts[cart.ID].Items = []domain.CartItem{{ProductID: "p2", VariantID: "v1", Quantity: 3}}
+		cart, _ := svc.GetOrCreate(ctx, "alice")
+
+		notices, err := svc.Notices(ctx, cart)
+		want := []domain.Notice{{ProductID: "p2", VariantID: "v1", Kind: domain.NoticeOutOfStock, Available: 1}}
+		if err != nil || !slices.Equal(notices, want) {
+			t.Fatalf("expected %+v, got %+v, %v", want, notices, err)
+		}
+	})
+
+	t.Run("lines without a recorded price get no price notice", func(t *testing.T) {
+		repo.carts[cart.ID].Items = []domain.CartItem{{ProductID: "p1", Quantity: 1}}
+		cart, _ := svc.GetOrCreate(ctx, "alice")
+		if notices, _ := svc.Notices(ctx, cart); len(notices) != 0 {
+			t.Fatalf("expected no notices, got %+v", notices)
+		}
+	})
+}
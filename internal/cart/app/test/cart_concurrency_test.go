@@ -9,6 +9,7 @@ import (
 	"github.com/dwikikusuma/shoping-llm/internal/cart/app"
 	"github.com/dwikikusuma/shoping-llm/internal/cart/domain"
 	"github.com/dwikikusuma/shoping-llm/internal/cart/infra/postgres"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
 	"github.com/google/uuid"
 	"golang.org/x/sync/errgroup"
 )
@@ -28,7 +29,27 @@ func newTestService(t *testing.T) *app.Service {
 	t.Helper()
 	db := openTestDB(t)
 	repo := postgres.NewCartRepo(db)
-	return app.NewService(repo)
+	return app.NewService(repo, anyProduct{}, anyProduct{}, pg.NewTxManager(db), nil, app.Limits{MaxLineQuantity: 1000})
+}
+
+// anyProduct sells every product and tracks the stock of none, so the tests
+// need neither a catalog nor stock.
+type anyProduct struct{}
+
+func (anyProduct) GetProducts(ctx context.Context, productIDs []string) (map[string]app.Product, error) {
+	out := make(map[string]app.Product, len(productIDs))
+	for _, id := range productIDs {
+		out[id] = app.Product{ID: id}
+	}
+	return out, nil
+}
+
+func (anyProduct) GetVariants(ctx context.Context, variantIDs []string) (map[string]app.Variant, error) {
+	return map[string]app.Variant{}, nil
+}
+
+func (anyProduct) Available(ctx context.Context, productIDs []string) (map[string]int32, error) {
+	return map[string]int32{}, nil
 }
 
 func TestCart_ConcurrentGetOrCreate_SingleActiveCart(t *testing.T) {
@@ -82,7 +103,7 @@ func TestCart_ConcurrentAddItemIncrement(t *testing.T) {
 			return svc.AddItemToCart(ctx, domain.CartItem{
 				ProductID: productID,
 				Quantity:  1,
-			}, cart.ID)
+			}, cart.ID, 0)
 		})
 	}
 
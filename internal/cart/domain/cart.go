@@ -1,12 +1,34 @@
 package domain
 
-import "time"
+import (
+	"time"
 
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
+)
+
+const (
+	CartStatusActive     = "ACTIVE"
+	CartStatusCheckedOut = "CHECKED_OUT"
+	CartStatusMerged     = "MERGED"    // a guest cart folded into a user's cart
+	CartStatusAbandoned  = "ABANDONED" // no activity for too long
+)
+
+// CartItem is one line of the cart. Lines are keyed by product and variant,
+// so two sizes of the same shirt are two lines.
 type CartItem struct {
 	ProductID string
+	VariantID string // empty for products without variants
 	Quantity  int32
+	// UnitPrice is the catalog price when the line was last added to; zero
+	// for lines added before prices were recorded.
+	UnitPrice Money
+	UpdatedAt time.Time
 }
 
+// Money is an amount in the minor unit of its currency.
+type Money = money.Money
+
+// Cart belongs to a user, or to a guest session when UserID is empty.
 type Cart struct {
 	ID        string
 	UserID    string
@@ -14,4 +36,45 @@ type Cart struct {
 	Items     []CartItem
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Version   int64 // bumped on every write to the cart or its items
+}
+
+// MergeStrategy decides the quantity of a line that is in both the guest
+// cart and the user's cart when the two are merged.
+type MergeStrategy string
+
+const (
+	MergeSum    MergeStrategy = "sum"    // add the two quantities
+	MergeLatest MergeStrategy = "latest" // keep the most recently updated line
+)
+
+func (s MergeStrategy) Valid() bool {
+	return s == MergeSum || s == MergeLatest
+}
+
+// MergeQuantity returns the quantity the user's line should have once the
+// guest's copy of the same line is merged into it.
+func (s MergeStrategy) MergeQuantity(user, guest CartItem) int32 {
+	if s == MergeLatest {
+		if guest.UpdatedAt.After(user.UpdatedAt) {
+			return guest.Quantity
+		}
+		return user.Quantity
+	}
+	return user.Quantity + guest.Quantity
+}
+
+// ChangeOp is what a CartChange does to its line.
+type ChangeOp string
+
+const (
+	ChangeAdd    ChangeOp = "add"    // add Quantity units, creating the line if needed
+	ChangeSet    ChangeOp = "set"    // set the quantity of an existing line
+	ChangeRemove ChangeOp = "remove" // drop the line; Quantity is ignored
+)
+
+// CartChange is one step of a batch of line changes applied together.
+type CartChange struct {
+	Op   ChangeOp
+	Item CartItem
 }
@@ -0,0 +1,23 @@
+package domain
+
+// NoticeKind is why a cart line needs the shopper's attention.
+type NoticeKind string
+
+const (
+	NoticePriceIncreased     NoticeKind = "PRICE_INCREASED"
+	NoticePriceDecreased     NoticeKind = "PRICE_DECREASED"
+	NoticeProductArchived    NoticeKind = "PRODUCT_ARCHIVED"
+	NoticeProductUnavailable NoticeKind = "PRODUCT_UNAVAILABLE" // the product or variant no longer exists
+	NoticeOutOfStock         NoticeKind = "OUT_OF_STOCK"
+)
+
+// Notice tells the shopper that a line has changed since it was added, so
+// they can be warned before they pay.
+type Notice struct {
+	ProductID string
+	VariantID string
+	Kind      NoticeKind
+	OldPrice  Money // price notices only
+	NewPrice  Money // price notices only
+	Available int32 // OUT_OF_STOCK only: units that can still be bought
+}
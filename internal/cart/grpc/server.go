@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"log/slog"
 
 	"github.com/dwikikusuma/shoping-llm/api/gen/cart/v1"
 	"github.com/dwikikusuma/shoping-llm/internal/cart/app"
@@ -24,13 +25,10 @@ func NewServer(svc *app.Service) *Server {
 func (s *Server) GetCart(ctx context.Context, req *cartv1.UserId) (*cartv1.Cart, error) {
 	cart, err := s.svc.GetCart(ctx, req.Id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, status.Errorf(codes.NotFound, "cart not found: %v", err)
-		}
-		return nil, status.Errorf(codes.Internal, "error getting cart: %v", err)
+		return nil, mapErr("error getting cart", err)
 	}
 
-	return toProto(cart), nil
+	return s.withNotices(ctx, cart)
 }
 
 func (s *Server) CreateCart(ctx context.Context, req *cartv1.Cart) (*cartv1.Cart, error) {
@@ -38,6 +36,7 @@ func (s *Server) CreateCart(ctx context.Context, req *cartv1.Cart) (*cartv1.Cart
 	for _, item := range req.Items {
 		cartItems = append(cartItems, domain.CartItem{
 			ProductID: item.ProductId,
+			VariantID: item.VariantId,
 			Quantity:  item.Quantity,
 		})
 	}
@@ -50,7 +49,7 @@ func (s *Server) CreateCart(ctx context.Context, req *cartv1.Cart) (*cartv1.Cart
 
 	createdCart, err := s.svc.CreateCart(ctx, cart)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error creating cart: %v", err)
+		return nil, mapErr("error creating cart", err)
 	}
 
 	return toProto(createdCart), nil
@@ -59,30 +58,31 @@ func (s *Server) CreateCart(ctx context.Context, req *cartv1.Cart) (*cartv1.Cart
 func (s *Server) GetOrCreateCart(ctx context.Context, req *cartv1.UserId) (*cartv1.Cart, error) {
 	cart, err := s.svc.GetOrCreate(ctx, req.Id)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error getting or creating cart: %v", err)
+		return nil, mapErr("error getting or creating cart", err)
 	}
-	return toProto(cart), err
+	return s.withNotices(ctx, cart)
 }
 
 func (s *Server) AddItem(ctx context.Context, req *cartv1.UpdateCartItemRequest) (*cartv1.Cart, error) {
 	cartItem := domain.CartItem{
 		ProductID: req.Item.ProductId,
+		VariantID: req.Item.VariantId,
 		Quantity:  req.Item.Quantity,
 	}
 
-	cart, err := s.svc.GetOrCreate(ctx, req.UserId)
+	cart, err := s.cartFor(ctx, req.UserId, req.GuestToken, true)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error getting or creating cart: %v", err)
+		return nil, mapErr("error getting or creating cart", err)
 	}
 
-	err = s.svc.AddItemToCart(ctx, cartItem, cart.ID)
+	err = s.svc.AddItemToCart(ctx, cartItem, cart.ID, req.ExpectedVersion)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error adding item to cart: %v", err)
+		return nil, mapErr("error adding item to cart", err)
 	}
 
-	updatedCart, err := s.svc.GetCart(ctx, req.UserId)
+	updatedCart, err := s.cartFor(ctx, req.UserId, req.GuestToken, false)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error getting updated cart: %v", err)
+		return nil, mapErr("error getting updated cart", err)
 	}
 
 	return toProto(updatedCart), nil
@@ -91,73 +91,203 @@ func (s *Server) AddItem(ctx context.Context, req *cartv1.UpdateCartItemRequest)
 func (s *Server) ClearCart(ctx context.Context, req *cartv1.CartId) (*cartv1.Cart, error) {
 	cart, err := s.svc.GetCart(ctx, req.Id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, status.Errorf(codes.NotFound, "cart not found: %v", err)
-		}
-		return nil, status.Errorf(codes.Internal, "error getting cart: %v", err)
+		return nil, mapErr("error getting cart", err)
 	}
-	err = s.svc.ClearCart(ctx, cart.ID)
+	err = s.svc.ClearCart(ctx, cart.ID, req.ExpectedVersion)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error clearing cart: %v", err)
+		return nil, mapErr("error clearing cart", err)
 	}
 
 	clearedCart, err := s.svc.GetCart(ctx, req.Id)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error getting cleared cart: %v", err)
+		return nil, mapErr("error getting cleared cart", err)
 	}
 
 	return toProto(clearedCart), nil
 }
 
 func (s *Server) SetItemQuantity(ctx context.Context, req *cartv1.UpdateCartItemRequest) (*cartv1.Cart, error) {
-	cart, err := s.svc.GetCart(ctx, req.UserId)
+	cart, err := s.cartFor(ctx, req.UserId, req.GuestToken, false)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error getting cart: %v", err)
+		return nil, mapErr("error getting cart", err)
 	}
 
 	cartItem := domain.CartItem{
 		ProductID: req.Item.ProductId,
+		VariantID: req.Item.VariantId,
 		Quantity:  req.Item.Quantity,
 	}
 
-	err = s.svc.SetItemQuantity(ctx, cart.ID, cartItem)
+	err = s.svc.SetItemQuantity(ctx, cart.ID, cartItem, req.ExpectedVersion)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error setting item quantity: %v", err)
+		return nil, mapErr("error setting item quantity", err)
 	}
 
-	updatedCart, err := s.svc.GetCart(ctx, req.UserId)
+	updatedCart, err := s.cartFor(ctx, req.UserId, req.GuestToken, false)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error getting updated cart: %v", err)
+		return nil, mapErr("error getting updated cart", err)
 	}
 
 	return toProto(updatedCart), nil
 }
 
 func (s *Server) RemoveItem(ctx context.Context, req *cartv1.RemoveCartItemRequest) (*cartv1.Cart, error) {
-	cart, err := s.svc.GetCart(ctx, req.UserId)
+	cart, err := s.cartFor(ctx, req.UserId, req.GuestToken, false)
+	if err != nil {
+		return nil, mapErr("error getting cart", err)
+	}
+
+	err = s.svc.RemoveItemFromCart(ctx, cart.ID, req.ProductId, req.VariantId, req.ExpectedVersion)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error getting cart: %v", err)
+		return nil, mapErr("error removing item from cart", err)
 	}
 
-	err = s.svc.RemoveItemFromCart(ctx, cart.ID, req.ProductId)
+	updatedCart, err := s.cartFor(ctx, req.UserId, req.GuestToken, false)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error removing item from cart: %v", err)
+		return nil, mapErr("error getting updated cart", err)
+	}
+
+	return toProto(updatedCart), nil
+}
+
+func (s *Server) ApplyChanges(ctx context.Context, req *cartv1.ApplyCartChangesRequest) (*cartv1.Cart, error) {
+	changes := make([]domain.CartChange, 0, len(req.Changes))
+	for _, c := range req.Changes {
+		var op domain.ChangeOp
+		switch c.Op {
+		case cartv1.CartChangeOp_CART_CHANGE_OP_ADD:
+			op = domain.ChangeAdd
+		case cartv1.CartChangeOp_CART_CHANGE_OP_SET:
+			op = domain.ChangeSet
+		case cartv1.CartChangeOp_CART_CHANGE_OP_REMOVE:
+			op = domain.ChangeRemove
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "unknown change op %v", c.Op)
+		}
+		changes = append(changes, domain.CartChange{
+			Op: op,
+			Item: domain.CartItem{
+				ProductID: c.GetItem().GetProductId(),
+				VariantID: c.GetItem().GetVariantId(),
+				Quantity:  c.GetItem().GetQuantity(),
+			},
+		})
 	}
 
-	updatedCart, err := s.svc.GetCart(ctx, req.UserId)
+	cart, err := s.cartFor(ctx, req.UserId, req.GuestToken, true)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error getting updated cart: %v", err)
+		return nil, mapErr("error getting or creating cart", err)
 	}
 
+	updatedCart, err := s.svc.ApplyChanges(ctx, cart.ID, changes, req.ExpectedVersion)
+	if err != nil {
+		return nil, mapErr("error applying cart changes", err)
+	}
 	return toProto(updatedCart), nil
 }
 
+func (s *Server) CreateGuestCart(ctx context.Context, req *cartv1.CreateGuestCartRequest) (*cartv1.GuestCart, error) {
+	token, cart, err := s.svc.CreateGuestCart(ctx)
+	if err != nil {
+		return nil, mapErr("error creating guest cart", err)
+	}
+	return &cartv1.GuestCart{Token: token, Cart: toProto(cart)}, nil
+}
+
+func (s *Server) GetGuestCart(ctx context.Context, req *cartv1.GuestToken) (*cartv1.Cart, error) {
+	cart, err := s.svc.GetGuestCart(ctx, req.Token)
+	if err != nil {
+		return nil, mapErr("error getting guest cart", err)
+	}
+	return s.withNotices(ctx, cart)
+}
+
+func (s *Server) MergeCart(ctx context.Context, req *cartv1.MergeCartRequest) (*cartv1.Cart, error) {
+	var strategy domain.MergeStrategy
+	switch req.Strategy {
+	case cartv1.MergeStrategy_MERGE_STRATEGY_UNSPECIFIED, cartv1.MergeStrategy_MERGE_STRATEGY_SUM:
+		strategy = domain.MergeSum
+	case cartv1.MergeStrategy_MERGE_STRATEGY_LATEST:
+		strategy = domain.MergeLatest
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown merge strategy %v", req.Strategy)
+	}
+
+	cart, err := s.svc.MergeCart(ctx, req.GuestToken, req.UserId, strategy, req.ExpectedVersion)
+	if err != nil {
+		return nil, mapErr("error merging cart", err)
+	}
+	return toProto(cart), nil
+}
+
+// cartFor returns the cart a request addresses: the guest cart when
+// guestToken is set, otherwise the user's, created on demand if create is
+// set.
+func (s *Server) cartFor(ctx context.Context, userID, guestToken string, create bool) (domain.Cart, error) {
+	if guestToken != "" {
+		return s.svc.GetGuestCart(ctx, guestToken)
+	}
+	if create {
+		return s.svc.GetOrCreate(ctx, userID)
+	}
+	return s.svc.GetCart(ctx, userID)
+}
+
+func mapErr(msg string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(codes.NotFound, "cart not found: %v", err)
+	}
+	switch {
+	case errors.Is(err, app.ErrLineNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, app.ErrInvalidInput),
+		errors.Is(err, app.ErrUnknownProduct),
+		errors.Is(err, app.ErrInvalidVariant):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	case errors.Is(err, app.ErrCartNotActive),
+		errors.Is(err, app.ErrProductArchived),
+		errors.Is(err, app.ErrQuantityLimit),
+		errors.Is(err, app.ErrCartFull),
+		errors.Is(err, app.ErrOutOfStock):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	case errors.Is(err, app.ErrVersionConflict):
+		return status.Errorf(codes.Aborted, "%s: %v", msg, err)
+	}
+	return status.Errorf(codes.Internal, "%s: %v", msg, err)
+}
+
+// withNotices is toProto plus the cart's notices, for the read RPCs.
+// Notices are advisory, so when the catalog or the inventory can't be
+// reached the cart is returned without them rather than failing the read.
+func (s *Server) withNotices(ctx context.Context, cart domain.Cart) (*cartv1.Cart, error) {
+	pb := toProto(cart)
+	notices, err := s.svc.Notices(ctx, cart)
+	if err != nil {
+		slog.WarnContext(ctx, "cart notices unavailable", slog.String("cart_id", cart.ID), slog.Any("err", err))
+		return pb, nil
+	}
+
+	for _, n := range notices {
+		pb.Notices = append(pb.Notices, &cartv1.CartNotice{
+			ProductId: n.ProductID,
+			VariantId: n.VariantID,
+			Kind:      string(n.Kind),
+			OldPrice:  moneyToProto(n.OldPrice),
+			NewPrice:  moneyToProto(n.NewPrice),
+			Available: n.Available,
+		})
+	}
+	return pb, nil
+}
+
 func toProto(cart domain.Cart) *cartv1.Cart {
 	items := make([]*cartv1.CartItem, 0, len(cart.Items))
 	for _, item := range cart.Items {
 		items = append(items, &cartv1.CartItem{
 			ProductId: item.ProductID,
+			VariantId: item.VariantID,
 			Quantity:  item.Quantity,
+			UnitPrice: moneyToProto(item.UnitPrice),
 		})
 	}
 
@@ -168,5 +298,14 @@ func toProto(cart domain.Cart) *cartv1.Cart {
 		Items:         items,
 		CreatedAtUnix: cart.CreatedAt.Unix(),
 		UpdatedAtUnix: cart.UpdatedAt.Unix(),
+		Version:       cart.Version,
+	}
+}
+
+// moneyToProto leaves an unset price (no currency) unset.
+func moneyToProto(m domain.Money) *cartv1.Money {
+	if m.Currency == "" {
+		return nil
 	}
+	return &cartv1.Money{Currency: m.Currency, Amount: m.Amount}
 }
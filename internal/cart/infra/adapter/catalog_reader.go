@@ -0,0 +1,93 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	cartapp "github.com/dwikikusuma/shoping-llm/internal/cart/app"
+	cartdomain "github.com/dwikikusuma/shoping-llm/internal/cart/domain"
+	catalogapp "github.com/dwikikusuma/shoping-llm/internal/catalog/app"
+)
+
+type CatalogServiceReader struct {
+	svc *catalogapp.Service
+}
+
+func NewCatalogServiceReader(svc *catalogapp.Service) *CatalogServiceReader {
+	return &CatalogServiceReader{svc: svc}
+}
+
+func (r *CatalogServiceReader) GetProducts(ctx context.Context, productIDs []string) (map[string]cartapp.Product, error) {
+	// BatchGetProducts returns each product once, in request order, so with
+	// duplicates removed the i-th product answers the i-th ID.
+	productIDs = unique(productIDs)
+
+	out := make(map[string]cartapp.Product, len(productIDs))
+	for start := 0; start < len(productIDs); start += catalogapp.MaxBatchProducts {
+		ids := productIDs[start:min(start+catalogapp.MaxBatchProducts, len(productIDs))]
+		products, err := r.svc.BatchGetProducts(ctx, ids, time.Time{})
+		// The whole batch fails when a product is missing, so ask again
+		// without the missing ones.
+		var missing *catalogapp.MissingProductsError
+		if errors.As(err, &missing) {
+			ids = slices.DeleteFunc(slices.Clone(ids), func(id string) bool {
+				return slices.Contains(missing.IDs, id)
+			})
+			products, err = r.svc.BatchGetProducts(ctx, ids, time.Time{})
+		}
+		if errors.Is(err, catalogapp.ErrInvalidInput) {
+			return nil, cartapp.ErrInvalidInput
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for i, p := range products {
+			out[ids[i]] = cartapp.Product{
+				ID:          p.ID,
+				Archived:    p.Archived(),
+				HasVariants: p.HasVariants,
+				Price:       cartdomain.Money{Currency: p.Price.Currency, Amount: p.Price.Amount},
+			}
+		}
+	}
+	return out, nil
+}
+
+func (r *CatalogServiceReader) GetVariants(ctx context.Context, variantIDs []string) (map[string]cartapp.Variant, error) {
+	variantIDs = unique(variantIDs)
+
+	out := make(map[string]cartapp.Variant, len(variantIDs))
+	for start := 0; start < len(variantIDs); start += catalogapp.MaxBatchProducts {
+		variants, err := r.svc.BatchGetVariants(ctx, variantIDs[start:min(start+catalogapp.MaxBatchProducts, len(variantIDs))])
+		if errors.Is(err, catalogapp.ErrInvalidInput) {
+			return nil, cartapp.ErrInvalidInput
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for id, v := range variants {
+			out[id] = cartapp.Variant{
+				ID:        v.ID,
+				ProductID: v.ProductID,
+				Price:     cartdomain.Money{Currency: v.Price.Currency, Amount: v.Price.Amount},
+			}
+		}
+	}
+	return out, nil
+}
+
+func unique(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,28 @@
+package adapter
+
+import (
+	"context"
+
+	inventoryapp "github.com/dwikikusuma/shoping-llm/internal/inventory/app"
+)
+
+type InventoryServiceReader struct {
+	svc *inventoryapp.Service
+}
+
+func NewInventoryServiceReader(svc *inventoryapp.Service) *InventoryServiceReader {
+	return &InventoryServiceReader{svc: svc}
+}
+
+func (r *InventoryServiceReader) Available(ctx context.Context, ids []string) (map[string]int32, error) {
+	stock, err := r.svc.BatchGetStock(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]int32, len(stock))
+	for id, s := range stock {
+		out[id] = s.Available()
+	}
+	return out, nil
+}
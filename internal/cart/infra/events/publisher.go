@@ -0,0 +1,97 @@
+// Package events publishes cart lifecycle events for other teams.
+//
+// Events go to a Redis stream that consumers read with consumer groups, so
+// nothing is lost while they are down. Without Redis they are only logged.
+// Delivery is at least once: consumers should dedupe on cart_id.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/cart/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// Stream holds every cart event; the type field tells them apart.
+	Stream = "cart:events"
+
+	TypeAbandonedCart = "abandoned_cart"
+
+	// streamMaxLen caps the stream, approximately, so it can't grow without
+	// bound if no one consumes it.
+	streamMaxLen = 100000
+)
+
+type abandonedCartItem struct {
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id,omitempty"`
+	Quantity  int32  `json:"quantity"`
+}
+
+type abandonedCart struct {
+	CartID         string              `json:"cart_id"`
+	UserID         string              `json:"user_id"`
+	Items          []abandonedCartItem `json:"items"`
+	LastActivityAt time.Time           `json:"last_activity_at"`
+}
+
+func abandonedCartPayload(cart domain.Cart) ([]byte, error) {
+	ev := abandonedCart{
+		CartID:         cart.ID,
+		UserID:         cart.UserID,
+		Items:          make([]abandonedCartItem, 0, len(cart.Items)),
+		LastActivityAt: cart.UpdatedAt.UTC(),
+	}
+	for _, it := range cart.Items {
+		ev.Items = append(ev.Items, abandonedCartItem{
+			ProductID: it.ProductID,
+			VariantID: it.VariantID,
+			Quantity:  it.Quantity,
+		})
+	}
+	return json.Marshal(ev)
+}
+
+// RedisPublisher appends events to Stream.
+type RedisPublisher struct {
+	rdb redis.UniversalClient
+}
+
+func NewRedisPublisher(rdb redis.UniversalClient) *RedisPublisher {
+	return &RedisPublisher{rdb: rdb}
+}
+
+func (p *RedisPublisher) AbandonedCart(ctx context.Context, cart domain.Cart) error {
+	payload, err := abandonedCartPayload(cart)
+	if err != nil {
+		return err
+	}
+	return p.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: Stream,
+		MaxLen: streamMaxLen,
+		Approx: true,
+		Values: map[string]any{"type": TypeAbandonedCart, "payload": payload},
+	}).Err()
+}
+
+// LogPublisher logs events instead of publishing them, for development.
+type LogPublisher struct {
+	log *slog.Logger
+}
+
+func NewLogPublisher(log *slog.Logger) *LogPublisher {
+	return &LogPublisher{log: log}
+}
+
+func (p *LogPublisher) AbandonedCart(ctx context.Context, cart domain.Cart) error {
+	payload, err := abandonedCartPayload(cart)
+	if err != nil {
+		return err
+	}
+	p.log.Info("cart event", slog.String("type", TypeAbandonedCart), slog.String("payload", string(payload)))
+	return nil
+}
@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"strings"
+	"time"
 
+	"github.com/dwikikusuma/shoping-llm/internal/cart/app"
 	"github.com/dwikikusuma/shoping-llm/internal/cart/domain"
 	"github.com/dwikikusuma/shoping-llm/internal/cart/infra/postgres/cartgdb"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
 	"github.com/google/uuid"
 )
 
@@ -21,47 +24,72 @@ func NewCartRepo(db *sql.DB) *CartRepo {
 	}
 }
 
+// queries joins the caller's transaction when ctx carries one (see pg.TxManager).
+func (r *CartRepo) queries(ctx context.Context) *cartgdb.Queries {
+	if tx, ok := pg.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
 func (r *CartRepo) Get(ctx context.Context, userID string) (domain.Cart, error) {
-	userUUID, err := uuid.Parse(userID)
+	userUUID, err := parseUserID(userID)
 	if err != nil {
 		return domain.Cart{}, err
 	}
 
-	cart, err := r.q.GetActiveCartByUserID(ctx, userUUID)
+	cart, err := r.queries(ctx).GetActiveCartByUserID(ctx, userUUID)
 	if err != nil {
 		return domain.Cart{}, err
 	}
 
-	cartItem, err := r.q.ListCartItems(ctx, cart.ID)
+	return r.withItems(ctx, cart)
+}
+
+// withItems loads the lines of cart.
+func (r *CartRepo) withItems(ctx context.Context, cart cartgdb.Cart) (domain.Cart, error) {
+	cartItem, err := r.queries(ctx).ListCartItems(ctx, cart.ID)
 	if err != nil {
 		return domain.Cart{}, err
 	}
 
 	var items []domain.CartItem
 	for _, item := range cartItem {
-		items = append(items, domain.CartItem{
+		ci := domain.CartItem{
 			ProductID: item.ProductID.String(),
 			Quantity:  item.Quantity,
-		})
+			UpdatedAt: item.UpdatedAt,
+		}
+		if item.VariantID.Valid {
+			ci.VariantID = item.VariantID.UUID.String()
+		}
+		if item.UnitPriceAmount.Valid && item.Currency.Valid {
+			ci.UnitPrice = domain.Money{Currency: item.Currency.String, Amount: item.UnitPriceAmount.Int64}
+		}
+		items = append(items, ci)
 	}
 
-	return domain.Cart{
+	out := domain.Cart{
 		ID:        cart.ID.String(),
-		UserID:    cart.UserID.String(),
 		Status:    cart.Status,
 		Items:     items,
 		CreatedAt: cart.CreatedAt,
 		UpdatedAt: cart.UpdatedAt,
-	}, nil
+		Version:   cart.Version,
+	}
+	if cart.UserID.Valid {
+		out.UserID = cart.UserID.UUID.String()
+	}
+	return out, nil
 }
 
 func (r *CartRepo) Create(ctx context.Context, cart domain.Cart) (domain.Cart, error) {
-	userUUID, err := uuid.Parse(cart.UserID)
+	userUUID, err := parseUserID(cart.UserID)
 	if err != nil {
 		return domain.Cart{}, err
 	}
 
-	newCart, err := r.q.CreateActiveCart(ctx, userUUID)
+	newCart, err := r.queries(ctx).CreateActiveCart(ctx, userUUID)
 	if err != nil {
 		return domain.Cart{}, err
 	}
@@ -79,88 +107,113 @@ func (r *CartRepo) Create(ctx context.Context, cart domain.Cart) (domain.Cart, e
 }
 
 func (r *CartRepo) AddItem(ctx context.Context, item domain.CartItem, cartId string) error {
-	cartUUID, err := uuid.Parse(cartId)
+	cartUUID, err := parseUUID(cartId)
+	if err != nil {
+		return err
+	}
+
+	productUUID, err := parseUUID(item.ProductID)
 	if err != nil {
 		return err
 	}
 
-	productUUID, err := uuid.Parse(item.ProductID)
+	variantUUID, err := parseNullUUID(item.VariantID)
 	if err != nil {
 		return err
 	}
 
-	_, err = r.q.UpsertAddItemIncrement(ctx, cartgdb.UpsertAddItemIncrementParams{
+	params := cartgdb.UpsertAddItemIncrementParams{
 		CartID:    cartUUID,
 		ProductID: productUUID,
+		VariantID: variantUUID,
 		Quantity:  item.Quantity,
-	})
+	}
+	if item.UnitPrice.Currency != "" {
+		params.UnitPriceAmount = sql.NullInt64{Int64: item.UnitPrice.Amount, Valid: true}
+		params.Currency = sql.NullString{String: item.UnitPrice.Currency, Valid: true}
+	}
+	_, err = r.queries(ctx).UpsertAddItemIncrement(ctx, params)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return r.queries(ctx).TouchCartUpdatedAt(ctx, cartUUID)
 }
 
 func (r *CartRepo) ClearCart(ctx context.Context, cartId string) error {
-	cartUUID, err := uuid.Parse(cartId)
+	cartUUID, err := parseUUID(cartId)
 	if err != nil {
 		return err
 	}
 
-	err = r.q.ClearCart(ctx, cartUUID)
+	err = r.queries(ctx).ClearCart(ctx, cartUUID)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return r.queries(ctx).TouchCartUpdatedAt(ctx, cartUUID)
 }
 
-func (r *CartRepo) RemoveItem(ctx context.Context, cartID string, productID string) error {
-	cartUUID, err := uuid.Parse(cartID)
+func (r *CartRepo) RemoveItem(ctx context.Context, cartID string, productID, variantID string) error {
+	cartUUID, err := parseUUID(cartID)
 	if err != nil {
 		return err
 	}
 
-	productUUID, err := uuid.Parse(productID)
+	productUUID, err := parseUUID(productID)
 	if err != nil {
 		return err
 	}
 
-	err = r.q.RemoveItem(ctx, cartgdb.RemoveItemParams{
+	variantUUID, err := parseNullUUID(variantID)
+	if err != nil {
+		return err
+	}
+
+	err = r.queries(ctx).RemoveItem(ctx, cartgdb.RemoveItemParams{
 		CartID:    cartUUID,
 		ProductID: productUUID,
+		VariantID: variantUUID,
 	})
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return r.queries(ctx).TouchCartUpdatedAt(ctx, cartUUID)
 }
 
 func (r *CartRepo) SetItemQuantity(ctx context.Context, cartID string, item domain.CartItem) error {
-	cartUUID, err := uuid.Parse(cartID)
+	cartUUID, err := parseUUID(cartID)
+	if err != nil {
+		return err
+	}
+
+	productUUID, err := parseUUID(item.ProductID)
 	if err != nil {
 		return err
 	}
 
-	productUUID, err := uuid.Parse(item.ProductID)
+	variantUUID, err := parseNullUUID(item.VariantID)
 	if err != nil {
 		return err
 	}
 
-	_, err = r.q.SetItemQuantity(ctx, cartgdb.SetItemQuantityParams{
+	_, err = r.queries(ctx).SetItemQuantity(ctx, cartgdb.SetItemQuantityParams{
+		Quantity:  item.Quantity,
 		CartID:    cartUUID,
 		ProductID: productUUID,
-		Quantity:  item.Quantity,
+		VariantID: variantUUID,
 	})
-
+	if errors.Is(err, sql.ErrNoRows) {
+		return app.ErrLineNotFound
+	}
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return r.queries(ctx).TouchCartUpdatedAt(ctx, cartUUID)
 }
 
 func (r *CartRepo) GetOrCreate(ctx context.Context, userID string) (domain.Cart, error) {
@@ -173,31 +226,153 @@ func (r *CartRepo) GetOrCreate(ctx context.Context, userID string) (domain.Cart,
 		return domain.Cart{}, err
 	}
 
-	// 2) Not found => try create
-	userUUID, parseErr := uuid.Parse(userID)
+	// 2) Not found => create, unless someone else did concurrently
+	userUUID, parseErr := parseUserID(userID)
 	if parseErr != nil {
 		return domain.Cart{}, parseErr
 	}
 
-	_, createErr := r.q.CreateActiveCart(ctx, userUUID)
-	if createErr == nil {
-		return r.Get(ctx, userID)
+	if err := r.queries(ctx).EnsureActiveCart(ctx, userUUID); err != nil {
+		return domain.Cart{}, err
 	}
 
-	// 3) If someone else created concurrently => re-get
-	if isUniqueViolation(createErr) {
-		return r.Get(ctx, userID)
+	// 3) Re-get whichever cart won
+	return r.Get(ctx, userID)
+}
+
+func (r *CartRepo) LockActive(ctx context.Context, userID string) (domain.Cart, error) {
+	userUUID, err := parseUserID(userID)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	cart, err := r.queries(ctx).LockActiveCartByUserID(ctx, userUUID)
+	if err != nil {
+		return domain.Cart{}, err
 	}
 
-	return domain.Cart{}, createErr
+	return r.withItems(ctx, cart)
 }
 
-func isUniqueViolation(err error) bool {
-	if err == nil {
-		return false
+func (r *CartRepo) MarkCheckedOut(ctx context.Context, cartID string) error {
+	cartUUID, err := parseUUID(cartID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.queries(ctx).MarkCartCheckedOut(ctx, cartUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return app.ErrCartNotActive
+	}
+	return err
+}
+
+func (r *CartRepo) CreateGuest(ctx context.Context, token string) (domain.Cart, error) {
+	cart, err := r.queries(ctx).CreateGuestCart(ctx, token)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	return r.withItems(ctx, cart)
+}
+
+func (r *CartRepo) GetGuest(ctx context.Context, token string) (domain.Cart, error) {
+	cart, err := r.queries(ctx).GetActiveCartByGuestToken(ctx, token)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	return r.withItems(ctx, cart)
+}
+
+func (r *CartRepo) LockGuest(ctx context.Context, token string) (domain.Cart, error) {
+	cart, err := r.queries(ctx).LockActiveCartByGuestToken(ctx, token)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	return r.withItems(ctx, cart)
+}
+
+func (r *CartRepo) LockByID(ctx context.Context, cartID string) (domain.Cart, error) {
+	cartUUID, err := parseUUID(cartID)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	cart, err := r.queries(ctx).LockCartByID(ctx, cartUUID)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	return r.withItems(ctx, cart)
+}
+
+func (r *CartRepo) MarkMerged(ctx context.Context, cartID string) error {
+	cartUUID, err := parseUUID(cartID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.queries(ctx).MarkCartMerged(ctx, cartUUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return app.ErrCartNotActive
+	}
+	return err
+}
+
+func (r *CartRepo) MarkAbandoned(ctx context.Context, cutoff time.Time, limit int) ([]domain.Cart, error) {
+	rows, err := r.queries(ctx).AbandonInactiveCarts(ctx, cartgdb.AbandonInactiveCartsParams{
+		Cutoff:   cutoff,
+		MaxCarts: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	carts := make([]domain.Cart, 0, len(rows))
+	for _, row := range rows {
+		cart, err := r.withItems(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		carts = append(carts, cart)
+	}
+	return carts, nil
+}
+
+func (r *CartRepo) DeleteAbandoned(ctx context.Context, abandonedBefore time.Time) (int64, error) {
+	return r.queries(ctx).DeleteAbandonedCarts(ctx, sql.NullTime{Time: abandonedBefore, Valid: true})
+}
+
+// parseUUID reports a malformed ID as app.ErrInvalidInput.
+func parseUUID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return uuid.Nil, app.ErrInvalidInput
+	}
+	return id, nil
+}
+
+// parseUserID parses the owner of a user cart; user_id is NULL only for
+// guest carts.
+func parseUserID(s string) (uuid.NullUUID, error) {
+	id, err := parseUUID(s)
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}, nil
+}
+
+// parseNullUUID maps an empty ID to NULL.
+func parseNullUUID(s string) (uuid.NullUUID, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return uuid.NullUUID{}, nil
+	}
+	id, err := parseUUID(s)
+	if err != nil {
+		return uuid.NullUUID{}, err
 	}
-	msg := strings.ToLower(err.Error())
-	return strings.Contains(msg, "duplicate key") ||
-		strings.Contains(msg, "unique constraint") ||
-		strings.Contains(msg, "23505")
+	return uuid.NullUUID{UUID: id, Valid: true}, nil
 }
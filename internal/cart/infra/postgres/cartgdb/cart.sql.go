@@ -7,10 +7,63 @@ package cartgdb
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const abandonInactiveCarts = `-- name: AbandonInactiveCarts :many
+UPDATE carts SET status = 'ABANDONED', abandoned_at = now(), version = version + 1
+WHERE id IN (
+    SELECT id FROM carts
+    WHERE status = 'ACTIVE' AND updated_at < $1
+    ORDER BY updated_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+    RETURNING id, user_id, status, created_at, updated_at, guest_token, abandoned_at, version
+`
+
+type AbandonInactiveCartsParams struct {
+	Cutoff   time.Time `json:"cutoff"`
+	MaxCarts int32     `json:"max_carts"`
+}
+
+// Oldest first; SKIP LOCKED leaves carts that are being checked out or
+// merged right now to a later run.
+func (q *Queries) AbandonInactiveCarts(ctx context.Context, arg AbandonInactiveCartsParams) ([]Cart, error) {
+	rows, err := q.db.QueryContext(ctx, abandonInactiveCarts, arg.Cutoff, arg.MaxCarts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Cart
+	for rows.Next() {
+		var i Cart
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.GuestToken,
+			&i.AbandonedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const clearCart = `-- name: ClearCart :exec
 DELETE FROM cart_items
 WHERE cart_id = $1
@@ -24,10 +77,10 @@ func (q *Queries) ClearCart(ctx context.Context, cartID uuid.UUID) error {
 const createActiveCart = `-- name: CreateActiveCart :one
 INSERT INTO carts (user_id, status)
 VALUES ($1, 'ACTIVE')
-    RETURNING id, user_id, status, created_at, updated_at
+    RETURNING id, user_id, status, created_at, updated_at, guest_token, abandoned_at, version
 `
 
-func (q *Queries) CreateActiveCart(ctx context.Context, userID uuid.UUID) (Cart, error) {
+func (q *Queries) CreateActiveCart(ctx context.Context, userID uuid.NullUUID) (Cart, error) {
 	row := q.db.QueryRowContext(ctx, createActiveCart, userID)
 	var i Cart
 	err := row.Scan(
@@ -36,17 +89,91 @@ func (q *Queries) CreateActiveCart(ctx context.Context, userID uuid.UUID) (Cart,
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GuestToken,
+		&i.AbandonedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const createGuestCart = `-- name: CreateGuestCart :one
+INSERT INTO carts (guest_token, status)
+VALUES ($1::text, 'ACTIVE')
+    RETURNING id, user_id, status, created_at, updated_at, guest_token, abandoned_at, version
+`
+
+func (q *Queries) CreateGuestCart(ctx context.Context, guestToken string) (Cart, error) {
+	row := q.db.QueryRowContext(ctx, createGuestCart, guestToken)
+	var i Cart
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GuestToken,
+		&i.AbandonedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const deleteAbandonedCarts = `-- name: DeleteAbandonedCarts :execrows
+DELETE FROM carts
+WHERE status = 'ABANDONED' AND abandoned_at < $1
+`
+
+func (q *Queries) DeleteAbandonedCarts(ctx context.Context, abandonedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAbandonedCarts, abandonedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const ensureActiveCart = `-- name: EnsureActiveCart :exec
+INSERT INTO carts (user_id, status)
+VALUES ($1, 'ACTIVE')
+    ON CONFLICT (user_id) WHERE status = 'ACTIVE' DO NOTHING
+`
+
+// Creates the user's ACTIVE cart unless one exists. Unlike CreateActiveCart
+// it never fails on ux_carts_user_active, so it is safe inside a transaction
+// that two requests race on.
+func (q *Queries) EnsureActiveCart(ctx context.Context, userID uuid.NullUUID) error {
+	_, err := q.db.ExecContext(ctx, ensureActiveCart, userID)
+	return err
+}
+
+const getActiveCartByGuestToken = `-- name: GetActiveCartByGuestToken :one
+SELECT id, user_id, status, created_at, updated_at, guest_token, abandoned_at, version FROM carts
+WHERE guest_token = $1::text AND status = 'ACTIVE'
+    LIMIT 1
+`
+
+func (q *Queries) GetActiveCartByGuestToken(ctx context.Context, guestToken string) (Cart, error) {
+	row := q.db.QueryRowContext(ctx, getActiveCartByGuestToken, guestToken)
+	var i Cart
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GuestToken,
+		&i.AbandonedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getActiveCartByUserID = `-- name: GetActiveCartByUserID :one
-SELECT id, user_id, status, created_at, updated_at FROM carts
+SELECT id, user_id, status, created_at, updated_at, guest_token, abandoned_at, version FROM carts
 WHERE user_id = $1 AND status = 'ACTIVE'
     LIMIT 1
 `
 
-func (q *Queries) GetActiveCartByUserID(ctx context.Context, userID uuid.UUID) (Cart, error) {
+func (q *Queries) GetActiveCartByUserID(ctx context.Context, userID uuid.NullUUID) (Cart, error) {
 	row := q.db.QueryRowContext(ctx, getActiveCartByUserID, userID)
 	var i Cart
 	err := row.Scan(
@@ -55,12 +182,15 @@ func (q *Queries) GetActiveCartByUserID(ctx context.Context, userID uuid.UUID) (
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GuestToken,
+		&i.AbandonedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const listCartItems = `-- name: ListCartItems :many
-SELECT id, cart_id, product_id, quantity, created_at, updated_at FROM cart_items
+SELECT id, cart_id, product_id, quantity, created_at, updated_at, variant_id, unit_price_amount, currency FROM cart_items
 WHERE cart_id = $1
 ORDER BY created_at ASC
 `
@@ -81,6 +211,9 @@ func (q *Queries) ListCartItems(ctx context.Context, cartID uuid.UUID) ([]CartIt
 			&i.Quantity,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.VariantID,
+			&i.UnitPriceAmount,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -95,36 +228,157 @@ func (q *Queries) ListCartItems(ctx context.Context, cartID uuid.UUID) ([]CartIt
 	return items, nil
 }
 
+const lockActiveCartByGuestToken = `-- name: LockActiveCartByGuestToken :one
+SELECT id, user_id, status, created_at, updated_at, guest_token, abandoned_at, version FROM carts
+WHERE guest_token = $1::text AND status = 'ACTIVE'
+    LIMIT 1
+    FOR UPDATE
+`
+
+func (q *Queries) LockActiveCartByGuestToken(ctx context.Context, guestToken string) (Cart, error) {
+	row := q.db.QueryRowContext(ctx, lockActiveCartByGuestToken, guestToken)
+	var i Cart
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GuestToken,
+		&i.AbandonedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const lockActiveCartByUserID = `-- name: LockActiveCartByUserID :one
+SELECT id, user_id, status, created_at, updated_at, guest_token, abandoned_at, version FROM carts
+WHERE user_id = $1 AND status = 'ACTIVE'
+    LIMIT 1
+    FOR UPDATE
+`
+
+func (q *Queries) LockActiveCartByUserID(ctx context.Context, userID uuid.NullUUID) (Cart, error) {
+	row := q.db.QueryRowContext(ctx, lockActiveCartByUserID, userID)
+	var i Cart
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GuestToken,
+		&i.AbandonedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const lockCartByID = `-- name: LockCartByID :one
+SELECT id, user_id, status, created_at, updated_at, guest_token, abandoned_at, version FROM carts
+WHERE id = $1
+    FOR UPDATE
+`
+
+func (q *Queries) LockCartByID(ctx context.Context, id uuid.UUID) (Cart, error) {
+	row := q.db.QueryRowContext(ctx, lockCartByID, id)
+	var i Cart
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GuestToken,
+		&i.AbandonedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const markCartCheckedOut = `-- name: MarkCartCheckedOut :one
+UPDATE carts SET status = 'CHECKED_OUT', updated_at = now(), version = version + 1
+WHERE id = $1 AND status = 'ACTIVE'
+    RETURNING id, user_id, status, created_at, updated_at, guest_token, abandoned_at, version
+`
+
+func (q *Queries) MarkCartCheckedOut(ctx context.Context, id uuid.UUID) (Cart, error) {
+	row := q.db.QueryRowContext(ctx, markCartCheckedOut, id)
+	var i Cart
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GuestToken,
+		&i.AbandonedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const markCartMerged = `-- name: MarkCartMerged :one
+UPDATE carts SET status = 'MERGED', updated_at = now(), version = version + 1
+WHERE id = $1 AND status = 'ACTIVE'
+    RETURNING id, user_id, status, created_at, updated_at, guest_token, abandoned_at, version
+`
+
+func (q *Queries) MarkCartMerged(ctx context.Context, id uuid.UUID) (Cart, error) {
+	row := q.db.QueryRowContext(ctx, markCartMerged, id)
+	var i Cart
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.GuestToken,
+		&i.AbandonedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
 const removeItem = `-- name: RemoveItem :exec
 DELETE FROM cart_items
 WHERE cart_id = $1 AND product_id = $2
+  AND variant_id IS NOT DISTINCT FROM $3::uuid
 `
 
 type RemoveItemParams struct {
-	CartID    uuid.UUID `json:"cart_id"`
-	ProductID uuid.UUID `json:"product_id"`
+	CartID    uuid.UUID     `json:"cart_id"`
+	ProductID uuid.UUID     `json:"product_id"`
+	VariantID uuid.NullUUID `json:"variant_id"`
 }
 
 func (q *Queries) RemoveItem(ctx context.Context, arg RemoveItemParams) error {
-	_, err := q.db.ExecContext(ctx, removeItem, arg.CartID, arg.ProductID)
+	_, err := q.db.ExecContext(ctx, removeItem, arg.CartID, arg.ProductID, arg.VariantID)
 	return err
 }
 
 const setItemQuantity = `-- name: SetItemQuantity :one
 UPDATE cart_items
-SET quantity = $3, updated_at = now()
-WHERE cart_id = $1 AND product_id = $2
-    RETURNING id, cart_id, product_id, quantity, created_at, updated_at
+SET quantity = $1, updated_at = now()
+WHERE cart_id = $2 AND product_id = $3
+  AND variant_id IS NOT DISTINCT FROM $4::uuid
+    RETURNING id, cart_id, product_id, quantity, created_at, updated_at, variant_id, unit_price_amount, currency
 `
 
 type SetItemQuantityParams struct {
-	CartID    uuid.UUID `json:"cart_id"`
-	ProductID uuid.UUID `json:"product_id"`
-	Quantity  int32     `json:"quantity"`
+	Quantity  int32         `json:"quantity"`
+	CartID    uuid.UUID     `json:"cart_id"`
+	ProductID uuid.UUID     `json:"product_id"`
+	VariantID uuid.NullUUID `json:"variant_id"`
 }
 
 func (q *Queries) SetItemQuantity(ctx context.Context, arg SetItemQuantityParams) (CartItem, error) {
-	row := q.db.QueryRowContext(ctx, setItemQuantity, arg.CartID, arg.ProductID, arg.Quantity)
+	row := q.db.QueryRowContext(ctx, setItemQuantity,
+		arg.Quantity,
+		arg.CartID,
+		arg.ProductID,
+		arg.VariantID,
+	)
 	var i CartItem
 	err := row.Scan(
 		&i.ID,
@@ -133,38 +387,56 @@ func (q *Queries) SetItemQuantity(ctx context.Context, arg SetItemQuantityParams
 		&i.Quantity,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.VariantID,
+		&i.UnitPriceAmount,
+		&i.Currency,
 	)
 	return i, err
 }
 
 const touchCartUpdatedAt = `-- name: TouchCartUpdatedAt :exec
-UPDATE carts SET updated_at = now()
+UPDATE carts SET updated_at = now(), version = version + 1
 WHERE id = $1
 `
 
+// Runs after every change to a cart's items and bumps the cart's version.
 func (q *Queries) TouchCartUpdatedAt(ctx context.Context, id uuid.UUID) error {
 	_, err := q.db.ExecContext(ctx, touchCartUpdatedAt, id)
 	return err
 }
 
 const upsertAddItemIncrement = `-- name: UpsertAddItemIncrement :one
-INSERT INTO cart_items (cart_id, product_id, quantity)
-VALUES ($1, $2, $3)
-    ON CONFLICT (cart_id, product_id)
+INSERT INTO cart_items (cart_id, product_id, variant_id, quantity, unit_price_amount, currency)
+VALUES ($1, $2, $3, $4, $5, $6)
+    ON CONFLICT (cart_id, product_id, variant_id)
 DO UPDATE SET
     quantity   = cart_items.quantity + EXCLUDED.quantity,
+           unit_price_amount = EXCLUDED.unit_price_amount,
+           currency   = EXCLUDED.currency,
            updated_at = now()
-           RETURNING id, cart_id, product_id, quantity, created_at, updated_at
+           RETURNING id, cart_id, product_id, quantity, created_at, updated_at, variant_id, unit_price_amount, currency
 `
 
 type UpsertAddItemIncrementParams struct {
-	CartID    uuid.UUID `json:"cart_id"`
-	ProductID uuid.UUID `json:"product_id"`
-	Quantity  int32     `json:"quantity"`
+	CartID          uuid.UUID      `json:"cart_id"`
+	ProductID       uuid.UUID      `json:"product_id"`
+	VariantID       uuid.NullUUID  `json:"variant_id"`
+	Quantity        int32          `json:"quantity"`
+	UnitPriceAmount sql.NullInt64  `json:"unit_price_amount"`
+	Currency        sql.NullString `json:"currency"`
 }
 
+// Adding to an existing line also records the price the shopper saw this
+// time.
 func (q *Queries) UpsertAddItemIncrement(ctx context.Context, arg UpsertAddItemIncrementParams) (CartItem, error) {
-	row := q.db.QueryRowContext(ctx, upsertAddItemIncrement, arg.CartID, arg.ProductID, arg.Quantity)
+	row := q.db.QueryRowContext(ctx, upsertAddItemIncrement,
+		arg.CartID,
+		arg.ProductID,
+		arg.VariantID,
+		arg.Quantity,
+		arg.UnitPriceAmount,
+		arg.Currency,
+	)
 	var i CartItem
 	err := row.Scan(
 		&i.ID,
@@ -173,6 +445,9 @@ func (q *Queries) UpsertAddItemIncrement(ctx context.Context, arg UpsertAddItemI
 		&i.Quantity,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.VariantID,
+		&i.UnitPriceAmount,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -5,24 +5,31 @@
 package cartgdb
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Cart struct {
-	ID        uuid.UUID `json:"id"`
-	UserID    uuid.UUID `json:"user_id"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uuid.UUID      `json:"id"`
+	UserID      uuid.NullUUID  `json:"user_id"`
+	Status      string         `json:"status"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	GuestToken  sql.NullString `json:"guest_token"`
+	AbandonedAt sql.NullTime   `json:"abandoned_at"`
+	Version     int64          `json:"version"`
 }
 
 type CartItem struct {
-	ID        uuid.UUID `json:"id"`
-	CartID    uuid.UUID `json:"cart_id"`
-	ProductID uuid.UUID `json:"product_id"`
-	Quantity  int32     `json:"quantity"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              uuid.UUID      `json:"id"`
+	CartID          uuid.UUID      `json:"cart_id"`
+	ProductID       uuid.UUID      `json:"product_id"`
+	Quantity        int32          `json:"quantity"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	VariantID       uuid.NullUUID  `json:"variant_id"`
+	UnitPriceAmount sql.NullInt64  `json:"unit_price_amount"`
+	Currency        sql.NullString `json:"currency"`
 }
@@ -0,0 +1,8 @@
+-- A line is a product, or one variant of it: two sizes of the same shirt are
+-- two lines. variant_id is NULL for products without variants.
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS variant_id UUID;
+
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS cart_items_cart_id_product_id_key;
+ALTER TABLE cart_items
+    ADD CONSTRAINT cart_items_cart_id_product_id_variant_id_key
+        UNIQUE NULLS NOT DISTINCT (cart_id, product_id, variant_id);
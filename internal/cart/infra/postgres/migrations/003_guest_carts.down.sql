@@ -0,0 +1,5 @@
+DELETE FROM carts WHERE user_id IS NULL;
+DROP INDEX IF EXISTS ux_carts_guest_token;
+ALTER TABLE carts DROP CONSTRAINT IF EXISTS carts_owner_check;
+ALTER TABLE carts DROP COLUMN IF EXISTS guest_token;
+ALTER TABLE carts ALTER COLUMN user_id SET NOT NULL;
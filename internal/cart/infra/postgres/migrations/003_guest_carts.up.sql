@@ -0,0 +1,12 @@
+-- Guest carts belong to an opaque session token instead of a user. A cart has
+-- exactly one owner; ux_carts_user_active still keeps one ACTIVE cart per user
+-- (NULL user IDs never collide).
+ALTER TABLE carts ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS guest_token TEXT;
+
+ALTER TABLE carts DROP CONSTRAINT IF EXISTS carts_owner_check;
+ALTER TABLE carts
+    ADD CONSTRAINT carts_owner_check
+        CHECK ((user_id IS NULL) <> (guest_token IS NULL));
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_carts_guest_token ON carts(guest_token);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS ix_carts_abandoned_at;
+DROP INDEX IF EXISTS ix_carts_active_updated_at;
+UPDATE carts SET status = 'ACTIVE' WHERE status = 'ABANDONED';
+ALTER TABLE carts DROP COLUMN IF EXISTS abandoned_at;
@@ -0,0 +1,11 @@
+-- Carts with no activity (updated_at) for a while become ABANDONED and are
+-- deleted once abandoned_at falls out of the retention period.
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS abandoned_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS ix_carts_active_updated_at
+    ON carts(updated_at)
+    WHERE status = 'ACTIVE';
+
+CREATE INDEX IF NOT EXISTS ix_carts_abandoned_at
+    ON carts(abandoned_at)
+    WHERE status = 'ABANDONED';
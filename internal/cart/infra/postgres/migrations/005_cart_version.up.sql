@@ -0,0 +1,3 @@
+-- version is bumped on every write to a cart or its items, so clients can
+-- send the version they last saw and have stale writes rejected.
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
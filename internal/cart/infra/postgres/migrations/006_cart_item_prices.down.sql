@@ -0,0 +1,3 @@
+ALTER TABLE cart_items
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS unit_price_amount;
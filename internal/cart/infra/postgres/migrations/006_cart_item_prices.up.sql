@@ -0,0 +1,6 @@
+-- The catalog price of a line when it was last added to, so the cart can
+-- tell the shopper when the price has changed since. NULL for lines added
+-- before this migration.
+ALTER TABLE cart_items
+    ADD COLUMN IF NOT EXISTS unit_price_amount BIGINT,
+    ADD COLUMN IF NOT EXISTS currency TEXT;
@@ -9,7 +9,8 @@ VALUES ($1, 'ACTIVE')
     RETURNING *;
 
 -- name: TouchCartUpdatedAt :exec
-UPDATE carts SET updated_at = now()
+-- Runs after every change to a cart's items and bumps the cart's version.
+UPDATE carts SET updated_at = now(), version = version + 1
 WHERE id = $1;
 
 -- name: ListCartItems :many
@@ -18,24 +19,92 @@ WHERE cart_id = $1
 ORDER BY created_at ASC;
 
 -- name: UpsertAddItemIncrement :one
-INSERT INTO cart_items (cart_id, product_id, quantity)
-VALUES ($1, $2, $3)
-    ON CONFLICT (cart_id, product_id)
+-- Adding to an existing line also records the price the shopper saw this
+-- time.
+INSERT INTO cart_items (cart_id, product_id, variant_id, quantity, unit_price_amount, currency)
+VALUES ($1, $2, $3, $4, $5, $6)
+    ON CONFLICT (cart_id, product_id, variant_id)
 DO UPDATE SET
     quantity   = cart_items.quantity + EXCLUDED.quantity,
+           unit_price_amount = EXCLUDED.unit_price_amount,
+           currency   = EXCLUDED.currency,
            updated_at = now()
            RETURNING *;
 
 -- name: SetItemQuantity :one
 UPDATE cart_items
-SET quantity = $3, updated_at = now()
-WHERE cart_id = $1 AND product_id = $2
+SET quantity = sqlc.arg(quantity), updated_at = now()
+WHERE cart_id = sqlc.arg(cart_id) AND product_id = sqlc.arg(product_id)
+  AND variant_id IS NOT DISTINCT FROM sqlc.narg(variant_id)::uuid
     RETURNING *;
 
 -- name: RemoveItem :exec
 DELETE FROM cart_items
-WHERE cart_id = $1 AND product_id = $2;
+WHERE cart_id = sqlc.arg(cart_id) AND product_id = sqlc.arg(product_id)
+  AND variant_id IS NOT DISTINCT FROM sqlc.narg(variant_id)::uuid;
 
 -- name: ClearCart :exec
 DELETE FROM cart_items
 WHERE cart_id = $1;
+
+-- name: LockActiveCartByUserID :one
+SELECT * FROM carts
+WHERE user_id = $1 AND status = 'ACTIVE'
+    LIMIT 1
+    FOR UPDATE;
+
+-- name: MarkCartCheckedOut :one
+UPDATE carts SET status = 'CHECKED_OUT', updated_at = now(), version = version + 1
+WHERE id = $1 AND status = 'ACTIVE'
+    RETURNING *;
+
+-- name: EnsureActiveCart :exec
+-- Creates the user's ACTIVE cart unless one exists. Unlike CreateActiveCart
+-- it never fails on ux_carts_user_active, so it is safe inside a transaction
+-- that two requests race on.
+INSERT INTO carts (user_id, status)
+VALUES ($1, 'ACTIVE')
+    ON CONFLICT (user_id) WHERE status = 'ACTIVE' DO NOTHING;
+
+-- name: CreateGuestCart :one
+INSERT INTO carts (guest_token, status)
+VALUES (sqlc.arg(guest_token)::text, 'ACTIVE')
+    RETURNING *;
+
+-- name: GetActiveCartByGuestToken :one
+SELECT * FROM carts
+WHERE guest_token = sqlc.arg(guest_token)::text AND status = 'ACTIVE'
+    LIMIT 1;
+
+-- name: LockActiveCartByGuestToken :one
+SELECT * FROM carts
+WHERE guest_token = sqlc.arg(guest_token)::text AND status = 'ACTIVE'
+    LIMIT 1
+    FOR UPDATE;
+
+-- name: MarkCartMerged :one
+UPDATE carts SET status = 'MERGED', updated_at = now(), version = version + 1
+WHERE id = $1 AND status = 'ACTIVE'
+    RETURNING *;
+
+-- name: AbandonInactiveCarts :many
+-- Oldest first; SKIP LOCKED leaves carts that are being checked out or
+-- merged right now to a later run.
+UPDATE carts SET status = 'ABANDONED', abandoned_at = now(), version = version + 1
+WHERE id IN (
+    SELECT id FROM carts
+    WHERE status = 'ACTIVE' AND updated_at < sqlc.arg(cutoff)
+    ORDER BY updated_at
+    LIMIT sqlc.arg(max_carts)
+    FOR UPDATE SKIP LOCKED
+)
+    RETURNING *;
+
+-- name: DeleteAbandonedCarts :execrows
+DELETE FROM carts
+WHERE status = 'ABANDONED' AND abandoned_at < $1;
+
+-- name: LockCartByID :one
+SELECT * FROM carts
+WHERE id = $1
+    FOR UPDATE;
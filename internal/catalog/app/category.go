@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+)
+
+var (
+	ErrSlugTaken           = errors.New("category slug is already taken")
+	ErrCategoryHasChildren = errors.New("category still has subcategories")
+	ErrCategoryCycle       = errors.New("category cannot be moved under itself or its descendants")
+)
+
+// CreateCategory adds a category under parentID, or a root category when
+// parentID is empty. The slug is derived from the name when not given.
+func (s *Service) CreateCategory(ctx context.Context, parentID, name, slug string) (domain.Category, error) {
+	c, err := newCategory(parentID, name, slug)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	return s.categories.Create(ctx, c)
+}
+
+func (s *Service) GetCategory(ctx context.Context, id string) (domain.Category, error) {
+	if strings.TrimSpace(id) == "" {
+		return domain.Category{}, ErrInvalidInput
+	}
+	return s.categories.Get(ctx, id)
+}
+
+// ListCategories returns the whole tree as a flat list; clients rebuild the
+// hierarchy from ParentID.
+func (s *Service) ListCategories(ctx context.Context) ([]domain.Category, error) {
+	return s.categories.List(ctx)
+}
+
+// UpdateCategory renames or moves a category. Moving it below one of its own
+// descendants would create a cycle and fails with ErrCategoryCycle.
+func (s *Service) UpdateCategory(ctx context.Context, id, parentID, name, slug string) (domain.Category, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return domain.Category{}, ErrInvalidInput
+	}
+	c, err := newCategory(parentID, name, slug)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	c.ID = id
+
+	if c.ParentID != "" {
+		cycle, err := s.categories.InSubtree(ctx, id, c.ParentID)
+		if err != nil {
+			return domain.Category{}, err
+		}
+		if cycle {
+			return domain.Category{}, ErrCategoryCycle
+		}
+	}
+	return s.categories.Update(ctx, c)
+}
+
+func (s *Service) DeleteCategory(ctx context.Context, id string) error {
+	if strings.TrimSpace(id) == "" {
+		return ErrInvalidInput
+	}
+	return s.categories.Delete(ctx, id)
+}
+
+// SetProductCategories replaces the product's categories; an empty list
+// removes it from all of them.
+func (s *Service) SetProductCategories(ctx context.Context, productID string, categoryIDs []string) ([]string, error) {
+	if strings.TrimSpace(productID) == "" {
+		return nil, ErrInvalidInput
+	}
+	if _, err := s.repo.Get(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(categoryIDs))
+	ids := make([]string, 0, len(categoryIDs))
+	for _, id := range categoryIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return nil, ErrInvalidInput
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if err := s.categories.SetProductCategories(ctx, productID, ids); err != nil {
+		return nil, err
+	}
+	return s.categories.ListProductCategories(ctx, productID)
+}
+
+func newCategory(parentID, name, slug string) (domain.Category, error) {
+	name = strings.TrimSpace(name)
+	slug = strings.TrimSpace(slug)
+	if slug == "" {
+		slug = domain.Slugify(name)
+	}
+	if name == "" || slug == "" || slug != domain.Slugify(slug) {
+		return domain.Category{}, ErrInvalidInput
+	}
+	return domain.Category{
+		ParentID: strings.TrimSpace(parentID),
+		Name:     name,
+		Slug:     slug,
+	}, nil
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+)
+
+// MaxImportRows bounds one ImportProducts call; split larger files.
+const MaxImportRows = 50000
+
+// exportPageSize is how many products ExportProducts reads per query.
+const exportPageSize = 500
+
+// ImportRow is one product line of an import file.
+type ImportRow struct {
+	Line        int // 1-based line in the source file, for the report
+	SKU         string
+	Name        string
+	Description string
+	Currency    string
+	Amount      int64
+	WeightGrams int32
+	// DecodeErr is set when the line couldn't be parsed; the row then fails
+	// without being validated.
+	DecodeErr string
+}
+
+type ImportAction string
+
+const (
+	ImportCreated ImportAction = "created"
+	ImportUpdated ImportAction = "updated"
+	ImportFailed  ImportAction = "failed"
+)
+
+// ImportRowResult reports what happened, or would happen, to one row.
+type ImportRowResult struct {
+	Line      int
+	SKU       string
+	Action    ImportAction
+	ProductID string // empty for failed rows and for created rows of a preview
+	Error     string // only for failed rows
+}
+
+type ImportReport struct {
+	Rows    []ImportRowResult
+	Created int
+	Updated int
+	Failed  int
+	// Applied is true when the rows were written. An import is all or
+	// nothing: a dry run, or any failed row, leaves the catalog unchanged.
+	Applied bool
+}
+
+// ImportProducts validates every row with the rules of CreateProduct and
+// upserts the products by SKU in one transaction. Rows that pass are still
+// run against the database when nothing is applied, so the report shows
+// which SKUs would be created and which updated.
+func (s *Service) ImportProducts(ctx context.Context, rows []ImportRow, dryRun bool) (ImportReport, error) {
+	if len(rows) == 0 || len(rows) > MaxImportRows {
+		return ImportReport{}, ErrInvalidInput
+	}
+
+	report := ImportReport{Rows: make([]ImportRowResult, len(rows))}
+	products := make([]domain.Product, 0, len(rows))
+	valid := make([]int, 0, len(rows)) // index into rows of each product
+	firstLine := make(map[string]int, len(rows))
+
+	for i, row := range rows {
+		sku := strings.TrimSpace(row.SKU)
+		report.Rows[i] = ImportRowResult{Line: row.Line, SKU: sku}
+
+		p, err := importProduct(row, sku, firstLine)
+		if err != nil {
+			report.Rows[i].Action = ImportFailed
+			report.Rows[i].Error = err.Error()
+			report.Failed++
+			continue
+		}
+		products = append(products, p)
+		valid = append(valid, i)
+	}
+
+	if len(products) > 0 {
+		commit := !dryRun && report.Failed == 0
+		results, err := s.repo.UpsertBySKU(ctx, products, commit)
+		if err != nil {
+			return ImportReport{}, err
+		}
+		for j, res := range results {
+			row := &report.Rows[valid[j]]
+			row.Action = ImportUpdated
+			if res.Created {
+				row.Action = ImportCreated
+				report.Created++
+			} else {
+				report.Updated++
+			}
+			// IDs of rolled-back inserts never existed.
+			if commit || !res.Created {
+				row.ProductID = res.Product.ID
+			}
+		}
+		report.Applied = commit
+	}
+	return report, nil
+}
+
+func importProduct(row ImportRow, sku string, firstLine map[string]int) (domain.Product, error) {
+	if row.DecodeErr != "" {
+		return domain.Product{}, errors.New(row.DecodeErr)
+	}
+	if sku == "" {
+		return domain.Product{}, errors.New("sku is required")
+	}
+	if line, ok := firstLine[sku]; ok {
+		return domain.Product{}, fmt.Errorf("duplicate sku, first seen on line %d", line)
+	}
+	firstLine[sku] = row.Line
+
+	p, err := newProduct(row.Name, row.Description, row.Currency, row.Amount)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	if row.WeightGrams < 0 {
+		return domain.Product{}, errors.New("weight_grams must not be negative")
+	}
+	p.ExternalSKU = sku
+	p.WeightGrams = row.WeightGrams
+	return p, nil
+}
+
+// ExportProducts calls fn for every active product, ordered by ID, and
+// stops at the first error fn returns.
+func (s *Service) ExportProducts(ctx context.Context, fn func(domain.Product) error) error {
+	after := ""
+	for {
+		page, err := s.repo.ListForExport(ctx, after, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, p := range page {
+			if err := fn(p); err != nil {
+				return err
+			}
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+		after = page[len(page)-1].ID
+	}
+}
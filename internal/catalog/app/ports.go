@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"time"
 
 	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
 )
@@ -9,5 +10,92 @@ import (
 type ProductRepo interface {
 	Create(ctx context.Context, p domain.Product) (domain.Product, error)
 	Get(ctx context.Context, id string) (domain.Product, error)
-	List(ctx context.Context, query string, limit int, cursor string) ([]domain.Product, string, error)
+	// BatchGet returns the products with the given IDs in a single query,
+	// keyed by the ID as given. IDs that don't exist are left out.
+	BatchGet(ctx context.Context, ids []string) (map[string]domain.Product, error)
+	// List returns one page in filter.Sort order. The cursor it hands back
+	// is only valid for the same filter and sort.
+	List(ctx context.Context, filter domain.ProductFilter, limit int, cursor string) ([]domain.Product, string, error)
+	// EstimateCount estimates how many products match filter without
+	// counting them.
+	EstimateCount(ctx context.Context, filter domain.ProductFilter) (int64, error)
+
+	// Update stores p if the stored version still equals p.Version, and fails
+	// with ErrVersionConflict otherwise.
+	Update(ctx context.Context, p domain.Product) (domain.Product, error)
+	Archive(ctx context.Context, id string) (domain.Product, error)
+	Delete(ctx context.Context, id string) error
+	// SetRating stores the product's review summary without bumping its
+	// version.
+	SetRating(ctx context.Context, id string, rating domain.Rating) error
+
+	// UpsertBySKU creates or updates products keyed by ExternalSKU, all in
+	// one transaction. When commit is false the transaction is rolled back,
+	// which previews the import without changing anything.
+	UpsertBySKU(ctx context.Context, products []domain.Product, commit bool) ([]UpsertResult, error)
+	// ListForExport returns active products ordered by ID, starting after
+	// afterID (empty for the first page).
+	ListForExport(ctx context.Context, afterID string, limit int) ([]domain.Product, error)
+}
+
+// UpsertResult is the outcome of one product of UpsertBySKU.
+type UpsertResult struct {
+	Product domain.Product
+	Created bool
+}
+
+type CategoryRepo interface {
+	Create(ctx context.Context, c domain.Category) (domain.Category, error)
+	Get(ctx context.Context, id string) (domain.Category, error)
+	List(ctx context.Context) ([]domain.Category, error)
+	Update(ctx context.Context, c domain.Category) (domain.Category, error)
+	// Delete fails with ErrCategoryHasChildren while subcategories exist.
+	Delete(ctx context.Context, id string) error
+	// InSubtree reports whether candidateID is rootID or one of its descendants.
+	InSubtree(ctx context.Context, rootID, candidateID string) (bool, error)
+
+	// SetProductCategories replaces the categories a product is assigned to.
+	SetProductCategories(ctx context.Context, productID string, categoryIDs []string) error
+	ListProductCategories(ctx context.Context, productID string) ([]string, error)
+	// BatchListProductCategories is ListProductCategories for many products,
+	// keyed by product ID.
+	BatchListProductCategories(ctx context.Context, productIDs []string) (map[string][]string, error)
+}
+
+type VariantRepo interface {
+	// SetOptions replaces the product's option axes.
+	SetOptions(ctx context.Context, productID string, axes []domain.OptionAxis) error
+	ListOptions(ctx context.Context, productID string) ([]domain.OptionAxis, error)
+	// BatchListOptions is ListOptions for many products, keyed by product ID.
+	BatchListOptions(ctx context.Context, productIDs []string) (map[string][]domain.OptionAxis, error)
+	// ProductsWithVariants reports which of the products have at least one
+	// variant. Products without variants are left out.
+	ProductsWithVariants(ctx context.Context, productIDs []string) (map[string]bool, error)
+
+	// Create and Update fail with ErrVariantExists when another variant has
+	// the same SKU, or the same options on the same product.
+	Create(ctx context.Context, v domain.Variant) (domain.Variant, error)
+	Get(ctx context.Context, id string) (domain.Variant, error)
+	// BatchGet is Get for many variants, keyed by ID. Variants that don't
+	// exist are left out.
+	BatchGet(ctx context.Context, ids []string) (map[string]domain.Variant, error)
+	List(ctx context.Context, productID string) ([]domain.Variant, error)
+	Update(ctx context.Context, v domain.Variant) (domain.Variant, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type PriceRepo interface {
+	// Schedule splices period into the product's price periods (see
+	// domain.Splice) and refreshes the product's current price as of now.
+	Schedule(ctx context.Context, period domain.PricePeriod, now time.Time) (domain.PricePeriod, error)
+	// At returns the period active at t, or ErrNotFound.
+	At(ctx context.Context, productID string, t time.Time) (domain.PricePeriod, error)
+	// BatchAt is At for many products, keyed by product ID. Products without
+	// an active period are left out.
+	BatchAt(ctx context.Context, productIDs []string, t time.Time) (map[string]domain.PricePeriod, error)
+	// History returns all periods of the product, oldest first.
+	History(ctx context.Context, productID string) ([]domain.PricePeriod, error)
+	// SyncCurrent copies the price active at now into every product whose
+	// stored price differs, and returns how many changed.
+	SyncCurrent(ctx context.Context, now time.Time) (int64, error)
 }
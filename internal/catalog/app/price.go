@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
+)
+
+// scheduleSkew is how far in the past a change may start and still count as
+// "now", to absorb clock differences with the caller.
+const scheduleSkew = time.Minute
+
+// SchedulePriceChange sets the product's price over [from, to). A zero from
+// means now and a zero to means until further notice. Periods it overlaps
+// are trimmed or split, so a change with an end, such as a promotion, hands
+// back to the price it interrupted. Past prices can't be changed. An empty
+// currency means the product's.
+func (s *Service) SchedulePriceChange(ctx context.Context, productID string, price domain.Money, from, to time.Time, reason string) (domain.PricePeriod, error) {
+	if strings.TrimSpace(productID) == "" || price.Amount <= 0 {
+		return domain.PricePeriod{}, ErrInvalidInput
+	}
+
+	now := s.now()
+	switch {
+	case from.IsZero():
+		from = now
+	case from.Before(now.Add(-scheduleSkew)):
+		return domain.PricePeriod{}, ErrInvalidInput
+	case from.Before(now):
+		from = now
+	}
+	if !to.IsZero() && !to.After(from) {
+		return domain.PricePeriod{}, ErrInvalidInput
+	}
+
+	p, err := s.repo.Get(ctx, productID)
+	if err != nil {
+		return domain.PricePeriod{}, err
+	}
+	if strings.TrimSpace(price.Currency) == "" {
+		price.Currency = p.Price.Currency
+	}
+	price.Currency, err = money.NormalizeCurrency(price.Currency)
+	if err != nil {
+		return domain.PricePeriod{}, ErrInvalidInput
+	}
+
+	return s.prices.Schedule(ctx, domain.PricePeriod{
+		ProductID:     p.ID,
+		Price:         price,
+		EffectiveFrom: from,
+		EffectiveTo:   to,
+		Reason:        strings.TrimSpace(reason),
+	}, now)
+}
+
+func (s *Service) GetPriceHistory(ctx context.Context, productID string) ([]domain.PricePeriod, error) {
+	if strings.TrimSpace(productID) == "" {
+		return nil, ErrInvalidInput
+	}
+	if _, err := s.repo.Get(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.prices.History(ctx, productID)
+}
+
+// SyncPrices moves products over to the price periods that have started or
+// ended. ListProducts filters and sorts by the stored price, so this should
+// run shortly after every boundary.
+func (s *Service) SyncPrices(ctx context.Context) (int64, error) {
+	return s.prices.SyncCurrent(ctx, s.now())
+}
@@ -4,63 +4,332 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
 )
 
 var (
-	ErrInvalidInput = errors.New("invalid input")
-	ErrNotFound     = errors.New("not found")
+	ErrInvalidInput    = errors.New("invalid input")
+	ErrNotFound        = errors.New("not found")
+	ErrVersionConflict = errors.New("product was modified by someone else")
 )
 
 type Service struct {
-	repo ProductRepo
+	repo       ProductRepo
+	categories CategoryRepo
+	variants   VariantRepo
+	prices     PriceRepo
+	now        func() time.Time
 }
 
-func NewService(repo ProductRepo) *Service {
+func NewService(repo ProductRepo, categories CategoryRepo, variants VariantRepo, prices PriceRepo) *Service {
 	return &Service{
-		repo: repo,
+		repo:       repo,
+		categories: categories,
+		variants:   variants,
+		prices:     prices,
+		now:        time.Now,
 	}
 }
 
-func (s *Service) CreateProduct(ctx context.Context, name, desc, currency string, amount int64) (domain.Product, error) {
+func (s *Service) CreateProduct(ctx context.Context, name, desc, currency string, amount int64, weightGrams int32) (domain.Product, error) {
+	p, err := newProduct(name, desc, currency, amount)
+	if err != nil || weightGrams < 0 {
+		return domain.Product{}, ErrInvalidInput
+	}
+	p.WeightGrams = weightGrams
+
+	product, err := s.repo.Create(ctx, p)
+	if err != nil {
+		return domain.Product{}, err
+	}
+
+	return product, nil
+}
+
+// newProduct validates the fields of a new product. CreateProduct and
+// ImportProducts share it, so an import accepts exactly what CreateProduct
+// does; the error says which field is wrong.
+func newProduct(name, desc, currency string, amount int64) (domain.Product, error) {
 	name = strings.TrimSpace(name)
 	currency = strings.TrimSpace(currency)
 
-	if name == "" || currency == "" || amount <= 0 {
-		return domain.Product{}, ErrInvalidInput
+	switch {
+	case name == "":
+		return domain.Product{}, errors.New("name is required")
+	case currency == "":
+		return domain.Product{}, errors.New("currency is required")
+	case amount <= 0:
+		return domain.Product{}, errors.New("price must be positive")
+	}
+	currency, err := money.NormalizeCurrency(currency)
+	if err != nil {
+		return domain.Product{}, err
 	}
 
-	p := domain.Product{
+	return domain.Product{
 		Name:        name,
 		Description: desc,
 		Price: domain.Money{
 			Currency: currency,
 			Amount:   amount,
 		},
+	}, nil
+}
+
+func (s *Service) GetProduct(ctx context.Context, id string) (domain.Product, error) {
+	return s.GetProductAt(ctx, id, s.now())
+}
+
+// GetProductAt returns the product with the price that is, was or will be
+// active at the given time.
+func (s *Service) GetProductAt(ctx context.Context, id string, at time.Time) (domain.Product, error) {
+	if strings.TrimSpace(id) == "" {
+		return domain.Product{}, ErrInvalidInput
 	}
 
-	product, err := s.repo.Create(ctx, p)
+	p, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	period, err := s.prices.At(ctx, p.ID, at)
+	switch {
+	case err == nil:
+		p.Price = period.Price
+	case errors.Is(err, ErrNotFound):
+		// before the product existed: keep the stored price
+	default:
+		return domain.Product{}, err
+	}
+	p.CategoryIDs, err = s.categories.ListProductCategories(ctx, p.ID)
 	if err != nil {
 		return domain.Product{}, err
 	}
+	p.Options, err = s.variants.ListOptions(ctx, p.ID)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	withVariants, err := s.variants.ProductsWithVariants(ctx, []string{p.ID})
+	if err != nil {
+		return domain.Product{}, err
+	}
+	p.HasVariants = withVariants[p.ID]
+	return p, nil
+}
 
-	return product, nil
+// MaxBatchProducts caps the IDs of one BatchGetProducts or BatchGetVariants
+// call.
+const MaxBatchProducts = 100
+
+// MissingProductsError lists the IDs BatchGetProducts couldn't find. It
+// matches ErrNotFound.
+type MissingProductsError struct {
+	IDs []string
 }
 
-func (s *Service) GetProduct(ctx context.Context, id string) (domain.Product, error) {
-	if strings.TrimSpace(id) == "" {
-		return domain.Product{}, ErrInvalidInput
+func (e *MissingProductsError) Error() string {
+	return "products not found: " + strings.Join(e.IDs, ", ")
+}
+
+func (e *MissingProductsError) Unwrap() error { return ErrNotFound }
+
+// BatchGetProducts is GetProductAt for many products, with one query per
+// table instead of one per product. Products come back in the order of ids,
+// once each, priced at at (zero means now). If any ID doesn't exist it fails
+// with a *MissingProductsError naming all of them.
+func (s *Service) BatchGetProducts(ctx context.Context, ids []string, at time.Time) ([]domain.Product, error) {
+	if len(ids) > MaxBatchProducts {
+		return nil, ErrInvalidInput
+	}
+	if at.IsZero() {
+		at = s.now()
+	}
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return nil, ErrInvalidInput
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, nil
+	}
+
+	found, err := s.repo.BatchGet(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	productIDs := make([]string, 0, len(found))
+	for _, id := range unique {
+		p, ok := found[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		productIDs = append(productIDs, p.ID)
+	}
+	if len(missing) > 0 {
+		return nil, &MissingProductsError{IDs: missing}
+	}
+
+	prices, err := s.prices.BatchAt(ctx, productIDs, at)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categories.BatchListProductCategories(ctx, productIDs)
+	if err != nil {
+		return nil, err
 	}
-	return s.repo.Get(ctx, id)
+	options, err := s.variants.BatchListOptions(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	withVariants, err := s.variants.ProductsWithVariants(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.Product, 0, len(unique))
+	for _, id := range unique {
+		p := found[id]
+		if period, ok := prices[p.ID]; ok {
+			p.Price = period.Price
+		}
+		p.CategoryIDs = categories[p.ID]
+		p.Options = options[p.ID]
+		p.HasVariants = withVariants[p.ID]
+		out = append(out, p)
+	}
+	return out, nil
 }
 
-func (s *Service) ListProducts(ctx context.Context, query string, limit int, cursor string) ([]domain.Product, string, error) {
+func (s *Service) ListProducts(ctx context.Context, filter domain.ProductFilter, limit int, cursor string) (domain.ProductPage, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 	if limit > 100 {
 		limit = 100
 	}
-	return s.repo.List(ctx, query, limit, cursor)
+
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Currency != "" {
+		code, err := money.NormalizeCurrency(filter.Currency)
+		if err != nil {
+			return domain.ProductPage{}, ErrInvalidInput
+		}
+		filter.Currency = code
+	}
+	if filter.MinPrice < 0 || filter.MaxPrice < 0 {
+		return domain.ProductPage{}, ErrInvalidInput
+	}
+	if filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice {
+		return domain.ProductPage{}, ErrInvalidInput
+	}
+	if (filter.MinPrice > 0 || filter.MaxPrice > 0) && filter.Currency == "" {
+		return domain.ProductPage{}, ErrInvalidInput
+	}
+
+	switch {
+	case filter.Sort == "":
+		filter.Sort = domain.SortNewest
+		if filter.Query != "" {
+			filter.Sort = domain.SortRelevance
+		}
+	case !filter.Sort.Valid():
+		return domain.ProductPage{}, ErrInvalidInput
+	case filter.Sort == domain.SortRelevance && filter.Query == "":
+		filter.Sort = domain.SortNewest
+	}
+
+	products, next, err := s.repo.List(ctx, filter, limit, cursor)
+	if err != nil {
+		return domain.ProductPage{}, err
+	}
+	total, err := s.repo.EstimateCount(ctx, filter)
+	if err != nil {
+		return domain.ProductPage{}, err
+	}
+	return domain.ProductPage{Products: products, NextCursor: next, Total: total}, nil
+}
+
+// UpdateProduct applies patch to the product if it is still at
+// expectedVersion, so two editors cannot silently overwrite each other.
+func (s *Service) UpdateProduct(ctx context.Context, id string, patch domain.ProductPatch, expectedVersion int64) (domain.Product, error) {
+	if strings.TrimSpace(id) == "" || expectedVersion <= 0 {
+		return domain.Product{}, ErrInvalidInput
+	}
+	if patch.Name == nil && patch.Description == nil && patch.Price == nil && patch.WeightGrams == nil {
+		return domain.Product{}, ErrInvalidInput
+	}
+
+	p, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	if p.Version != expectedVersion {
+		return domain.Product{}, ErrVersionConflict
+	}
+
+	if patch.Name != nil {
+		p.Name = strings.TrimSpace(*patch.Name)
+		if p.Name == "" {
+			return domain.Product{}, ErrInvalidInput
+		}
+	}
+	if patch.Description != nil {
+		p.Description = *patch.Description
+	}
+	if patch.Price != nil {
+		currency, err := money.NormalizeCurrency(patch.Price.Currency)
+		if err != nil || patch.Price.Amount <= 0 {
+			return domain.Product{}, ErrInvalidInput
+		}
+		p.Price = domain.Money{Currency: currency, Amount: patch.Price.Amount}
+	}
+	if patch.WeightGrams != nil {
+		if *patch.WeightGrams < 0 {
+			return domain.Product{}, ErrInvalidInput
+		}
+		p.WeightGrams = *patch.WeightGrams
+	}
+
+	return s.repo.Update(ctx, p)
+}
+
+// ArchiveProduct hides the product from listings. It stays readable by ID so
+// past orders and carts can still show it. Archiving twice is a no-op.
+func (s *Service) ArchiveProduct(ctx context.Context, id string) (domain.Product, error) {
+	if strings.TrimSpace(id) == "" {
+		return domain.Product{}, ErrInvalidInput
+	}
+	return s.repo.Archive(ctx, id)
+}
+
+// SetProductRating stores the review summary shown with the product. The
+// review module calls it whenever the approved reviews change.
+func (s *Service) SetProductRating(ctx context.Context, id string, rating domain.Rating) error {
+	if strings.TrimSpace(id) == "" || rating.Count < 0 || rating.Average < 0 || rating.Average > 5 {
+		return ErrInvalidInput
+	}
+	if rating.Count == 0 {
+		rating.Average = 0
+	}
+	return s.repo.SetRating(ctx, id, rating)
+}
+
+// DeleteProduct removes the product for good. Prefer ArchiveProduct; this is
+// meant for admins cleaning up mistakes.
+func (s *Service) DeleteProduct(ctx context.Context, id string) error {
+	if strings.TrimSpace(id) == "" {
+		return ErrInvalidInput
+	}
+	return s.repo.Delete(ctx, id)
 }
@@ -2,7 +2,11 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
 )
@@ -13,31 +17,717 @@ func (fakeRepo) Create(ctx context.Context, p domain.Product) (domain.Product, e
 func (fakeRepo) Get(ctx context.Context, id string) (domain.Product, error) {
 	return domain.Product{}, nil
 }
-func (fakeRepo) List(ctx context.Context, query string, limit int, cursor string) ([]domain.Product, string, error) {
+func (fakeRepo) BatchGet(ctx context.Context, ids []string) (map[string]domain.Product, error) {
+	return nil, nil
+}
+func (fakeRepo) List(ctx context.Context, filter domain.ProductFilter, limit int, cursor string) ([]domain.Product, string, error) {
 	return nil, "", nil
 }
+func (fakeRepo) EstimateCount(ctx context.Context, filter domain.ProductFilter) (int64, error) {
+	return 0, nil
+}
+func (fakeRepo) Update(ctx context.Context, p domain.Product) (domain.Product, error) { return p, nil }
+func (fakeRepo) Archive(ctx context.Context, id string) (domain.Product, error) {
+	return domain.Product{}, nil
+}
+func (fakeRepo) Delete(ctx context.Context, id string) error                          { return nil }
+func (fakeRepo) SetRating(ctx context.Context, id string, rating domain.Rating) error { return nil }
+func (fakeRepo) UpsertBySKU(ctx context.Context, products []domain.Product, commit bool) ([]UpsertResult, error) {
+	return nil, nil
+}
+func (fakeRepo) ListForExport(ctx context.Context, afterID string, limit int) ([]domain.Product, error) {
+	return nil, nil
+}
+
+// fakeCategories treats "root" as the parent of "child".
+type fakeCategories struct {
+	updated *domain.Category
+}
+
+func (f *fakeCategories) Create(ctx context.Context, c domain.Category) (domain.Category, error) {
+	return c, nil
+}
+func (f *fakeCategories) Get(ctx context.Context, id string) (domain.Category, error) {
+	return domain.Category{ID: id}, nil
+}
+func (f *fakeCategories) List(ctx context.Context) ([]domain.Category, error) { return nil, nil }
+func (f *fakeCategories) Update(ctx context.Context, c domain.Category) (domain.Category, error) {
+	f.updated = &c
+	return c, nil
+}
+func (f *fakeCategories) Delete(ctx context.Context, id string) error { return nil }
+func (f *fakeCategories) InSubtree(ctx context.Context, rootID, candidateID string) (bool, error) {
+	return rootID == candidateID || (rootID == "root" && candidateID == "child"), nil
+}
+func (f *fakeCategories) SetProductCategories(ctx context.Context, productID string, categoryIDs []string) error {
+	return nil
+}
+func (f *fakeCategories) ListProductCategories(ctx context.Context, productID string) ([]string, error) {
+	return nil, nil
+}
+func (f *fakeCategories) BatchListProductCategories(ctx context.Context, productIDs []string) (map[string][]string, error) {
+	return nil, nil
+}
+
+// fakeVariants stores options and variants in memory.
+type fakeVariants struct {
+	axes     []domain.OptionAxis
+	variants []domain.Variant
+	created  *domain.Variant
+}
+
+func (f *fakeVariants) SetOptions(ctx context.Context, productID string, axes []domain.OptionAxis) error {
+	f.axes = axes
+	return nil
+}
+func (f *fakeVariants) ListOptions(ctx context.Context, productID string) ([]domain.OptionAxis, error) {
+	return f.axes, nil
+}
+func (f *fakeVariants) BatchListOptions(ctx context.Context, productIDs []string) (map[string][]domain.OptionAxis, error) {
+	out := make(map[string][]domain.OptionAxis)
+	for _, id := range productIDs {
+		out[id] = f.axes
+	}
+	return out, nil
+}
+func (f *fakeVariants) ProductsWithVariants(ctx context.Context, productIDs []string) (map[string]bool, error) {
+	out := make(map[string]bool)
+	for _, v := range f.variants {
+		out[v.ProductID] = true
+	}
+	return out, nil
+}
+func (f *fakeVariants) Create(ctx context.Context, v domain.Variant) (domain.Variant, error) {
+	f.created = &v
+	return v, nil
+}
+func (f *fakeVariants) Get(ctx context.Context, id string) (domain.Variant, error) {
+	return domain.Variant{}, ErrNotFound
+}
+func (f *fakeVariants) BatchGet(ctx context.Context, ids []string) (map[string]domain.Variant, error) {
+	out := make(map[string]domain.Variant)
+	for _, v := range f.variants {
+		if slices.Contains(ids, v.ID) {
+			out[v.ID] = v
+		}
+	}
+	return out, nil
+}
+func (f *fakeVariants) List(ctx context.Context, productID string) ([]domain.Variant, error) {
+	return f.variants, nil
+}
+func (f *fakeVariants) Update(ctx context.Context, v domain.Variant) (domain.Variant, error) {
+	return v, nil
+}
+func (f *fakeVariants) Delete(ctx context.Context, id string) error { return nil }
+
+// fakePrices holds price periods in memory and records what is scheduled.
+type fakePrices struct {
+	periods   []domain.PricePeriod
+	scheduled *domain.PricePeriod
+}
+
+func (f *fakePrices) Schedule(ctx context.Context, period domain.PricePeriod, now time.Time) (domain.PricePeriod, error) {
+	f.scheduled = &period
+	return period, nil
+}
+func (f *fakePrices) At(ctx context.Context, productID string, t time.Time) (domain.PricePeriod, error) {
+	for _, p := range f.periods {
+		if p.ActiveAt(t) {
+			return p, nil
+		}
+	}
+	return domain.PricePeriod{}, ErrNotFound
+}
+func (f *fakePrices) BatchAt(ctx context.Context, productIDs []string, t time.Time) (map[string]domain.PricePeriod, error) {
+	out := make(map[string]domain.PricePeriod)
+	for _, p := range f.periods {
+		if p.ActiveAt(t) {
+			out[p.ProductID] = p
+		}
+	}
+	return out, nil
+}
+func (f *fakePrices) History(ctx context.Context, productID string) ([]domain.PricePeriod, error) {
+	return f.periods, nil
+}
+func (f *fakePrices) SyncCurrent(ctx context.Context, now time.Time) (int64, error) { return 0, nil }
+
+// storedRepo returns a fixed product from Get and records what Update stores.
+type storedRepo struct {
+	fakeRepo
+	product domain.Product
+	updated *domain.Product
+}
+
+func (r *storedRepo) Get(ctx context.Context, id string) (domain.Product, error) {
+	return r.product, nil
+}
+func (r *storedRepo) Update(ctx context.Context, p domain.Product) (domain.Product, error) {
+	r.updated = &p
+	return p, nil
+}
 
 func TestCreateProductValidation(t *testing.T) {
-	svc := NewService(fakeRepo{})
+	svc := NewService(fakeRepo{}, &fakeCategories{}, &fakeVariants{}, &fakePrices{})
 
 	t.Run("empty name -> invalid", func(t *testing.T) {
-		_, err := svc.CreateProduct(context.Background(), "   ", "x", "IDR", 100)
+		_, err := svc.CreateProduct(context.Background(), "   ", "x", "IDR", 100, 0)
 		if err != ErrInvalidInput {
 			t.Fatalf("expected ErrInvalidInput, got %v", err)
 		}
 	})
 
 	t.Run("negative amount -> invalid", func(t *testing.T) {
-		_, err := svc.CreateProduct(context.Background(), "Keyboard", "x", "IDR", -1)
+		_, err := svc.CreateProduct(context.Background(), "Keyboard", "x", "IDR", -1, 0)
 		if err != ErrInvalidInput {
 			t.Fatalf("expected ErrInvalidInput, got %v", err)
 		}
 	})
 
 	t.Run("empty currency -> invalid", func(t *testing.T) {
-		_, err := svc.CreateProduct(context.Background(), "Keyboard", "x", "   ", 100)
+		_, err := svc.CreateProduct(context.Background(), "Keyboard", "x", "   ", 100, 0)
 		if err != ErrInvalidInput {
 			t.Fatalf("expected ErrInvalidInput, got %v", err)
 		}
 	})
 }
+
+// listRepo records the filter ListProducts passes down.
+type listRepo struct {
+	fakeRepo
+	filter *domain.ProductFilter
+}
+
+func (r listRepo) List(ctx context.Context, filter domain.ProductFilter, limit int, cursor string) ([]domain.Product, string, error) {
+	*r.filter = filter
+	return []domain.Product{{ID: "p1"}}, "next", nil
+}
+func (r listRepo) EstimateCount(ctx context.Context, filter domain.ProductFilter) (int64, error) {
+	return 42, nil
+}
+
+func TestListProducts(t *testing.T) {
+	var got domain.ProductFilter
+	svc := NewService(listRepo{filter: &got}, &fakeCategories{}, &fakeVariants{}, &fakePrices{})
+	ctx := context.Background()
+
+	t.Run("default sort depends on query", func(t *testing.T) {
+		if _, err := svc.ListProducts(ctx, domain.ProductFilter{Query: " keyb "}, 10, ""); err != nil {
+			t.Fatal(err)
+		}
+		if got.Sort != domain.SortRelevance || got.Query != "keyb" {
+			t.Fatalf("expected relevance for %q, got %q", got.Query, got.Sort)
+		}
+		if _, err := svc.ListProducts(ctx, domain.ProductFilter{}, 10, ""); err != nil {
+			t.Fatal(err)
+		}
+		if got.Sort != domain.SortNewest {
+			t.Fatalf("expected newest without query, got %q", got.Sort)
+		}
+	})
+
+	t.Run("relevance without query falls back to newest", func(t *testing.T) {
+		if _, err := svc.ListProducts(ctx, domain.ProductFilter{Sort: domain.SortRelevance}, 10, ""); err != nil {
+			t.Fatal(err)
+		}
+		if got.Sort != domain.SortNewest {
+			t.Fatalf("expected newest, got %q", got.Sort)
+		}
+	})
+
+	t.Run("page carries cursor and total", func(t *testing.T) {
+		page, err := svc.ListProducts(ctx, domain.ProductFilter{Sort: domain.SortPriceAsc}, 10, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Products) != 1 || page.NextCursor != "next" || page.Total != 42 {
+			t.Fatalf("unexpected page %+v", page)
+		}
+	})
+
+	invalid := map[string]domain.ProductFilter{
+		"unknown sort":           {Sort: "cheapest"},
+		"negative price":         {Currency: "IDR", MinPrice: -1},
+		"min above max":          {Currency: "IDR", MinPrice: 500, MaxPrice: 100},
+		"price without currency": {MinPrice: 100},
+	}
+	for name, filter := range invalid {
+		t.Run(name+" -> invalid", func(t *testing.T) {
+			if _, err := svc.ListProducts(ctx, filter, 10, ""); !errors.Is(err, ErrInvalidInput) {
+				t.Fatalf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
+
+// importRepo treats SKU "old" as an existing product.
+type importRepo struct {
+	fakeRepo
+	upserted  *[]domain.Product
+	committed *bool
+}
+
+func (r importRepo) UpsertBySKU(ctx context.Context, products []domain.Product, commit bool) ([]UpsertResult, error) {
+	*r.upserted = products
+	*r.committed = commit
+	out := make([]UpsertResult, 0, len(products))
+	for i, p := range products {
+		p.ID = fmt.Sprintf("id-%d", i)
+		out = append(out, UpsertResult{Product: p, Created: p.ExternalSKU != "old"})
+	}
+	return out, nil
+}
+
+func TestImportProducts(t *testing.T) {
+	var upserted []domain.Product
+	var committed bool
+	svc := NewService(importRepo{upserted: &upserted, committed: &committed}, &fakeCategories{}, &fakeVariants{}, &fakePrices{})
+	ctx := context.Background()
+
+	good := []ImportRow{
+		{Line: 2, SKU: "new", Name: "Mouse", Currency: "IDR", Amount: 100},
+		{Line: 3, SKU: " old ", Name: "Keyboard", Currency: "IDR", Amount: 200, WeightGrams: 850},
+	}
+
+	t.Run("valid rows are upserted", func(t *testing.T) {
+		report, err := svc.ImportProducts(ctx, good, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.Applied || !committed || report.Created != 1 || report.Updated != 1 {
+			t.Fatalf("unexpected report %+v (committed=%v)", report, committed)
+		}
+		if upserted[1].ExternalSKU != "old" {
+			t.Fatalf("expected trimmed sku, got %q", upserted[1].ExternalSKU)
+		}
+		if upserted[1].WeightGrams != 850 {
+			t.Fatalf("expected the weight to be imported, got %d", upserted[1].WeightGrams)
+		}
+		if report.Rows[0].Action != ImportCreated || report.Rows[0].ProductID != "id-0" {
+			t.Fatalf("unexpected row %+v", report.Rows[0])
+		}
+	})
+
+	t.Run("dry run previews without committing", func(t *testing.T) {
+		report, err := svc.ImportProducts(ctx, good, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Applied || committed {
+			t.Fatal("dry run must not commit")
+		}
+		if report.Rows[0].ProductID != "" || report.Rows[1].ProductID != "id-1" {
+			t.Fatalf("only existing products have an id in a preview, got %+v", report.Rows)
+		}
+	})
+
+	t.Run("one bad row fails the import", func(t *testing.T) {
+		rows := append([]ImportRow{}, good...)
+		rows = append(rows,
+			ImportRow{Line: 4, SKU: "new", Name: "Dup", Currency: "IDR", Amount: 1},
+			ImportRow{Line: 5, SKU: "x", Name: "Free", Currency: "IDR", Amount: 0},
+			ImportRow{Line: 6, DecodeErr: "price_amount must be an integer"},
+			ImportRow{Line: 7, SKU: "y", Name: "Anvil", Currency: "IDR", Amount: 1, WeightGrams: -1},
+		)
+		report, err := svc.ImportProducts(ctx, rows, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Applied || committed || report.Failed != 4 || len(upserted) != 2 {
+			t.Fatalf("unexpected report %+v", report)
+		}
+		want := map[int]string{
+			4: "duplicate sku, first seen on line 2",
+			5: "price must be positive",
+			6: "price_amount must be an integer",
+			7: "weight_grams must not be negative",
+		}
+		for _, row := range report.Rows[2:] {
+			if row.Action != ImportFailed || row.Error != want[row.Line] {
+				t.Fatalf("line %d: unexpected result %+v", row.Line, row)
+			}
+		}
+	})
+
+	t.Run("empty import -> invalid", func(t *testing.T) {
+		if _, err := svc.ImportProducts(ctx, nil, false); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
+
+func TestPrices(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	stored := domain.Product{ID: "p1", Price: domain.Money{Currency: "IDR", Amount: 100}}
+	newSvc := func(prices *fakePrices) *Service {
+		svc := NewService(&storedRepo{product: stored}, &fakeCategories{}, &fakeVariants{}, prices)
+		svc.now = func() time.Time { return now }
+		return svc
+	}
+	idr := func(amount int64) domain.Money { return domain.Money{Currency: "IDR", Amount: amount} }
+
+	t.Run("product price is resolved at the requested time", func(t *testing.T) {
+		svc := newSvc(&fakePrices{periods: []domain.PricePeriod{
+			{Price: idr(100), EffectiveFrom: now.Add(-time.Hour), EffectiveTo: now.Add(time.Hour)},
+			{Price: idr(80), EffectiveFrom: now.Add(time.Hour)},
+		}})
+
+		p, err := svc.GetProduct(context.Background(), "p1")
+		if err != nil || p.Price.Amount != 100 {
+			t.Fatalf("expected 100 now, got %+v (%v)", p.Price, err)
+		}
+		p, err = svc.GetProductAt(context.Background(), "p1", now.Add(2*time.Hour))
+		if err != nil || p.Price.Amount != 80 {
+			t.Fatalf("expected 80 later, got %+v (%v)", p.Price, err)
+		}
+		p, err = svc.GetProductAt(context.Background(), "p1", now.Add(-2*time.Hour))
+		if err != nil || p.Price.Amount != 100 {
+			t.Fatalf("expected the stored price before any period, got %+v (%v)", p.Price, err)
+		}
+	})
+
+	t.Run("schedule defaults to now and the product currency", func(t *testing.T) {
+		prices := &fakePrices{}
+		_, err := newSvc(prices).SchedulePriceChange(context.Background(), "p1", domain.Money{Amount: 90}, time.Time{}, time.Time{}, " promo ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := prices.scheduled
+		if !got.EffectiveFrom.Equal(now) || got.Price != idr(90) || got.Reason != "promo" {
+			t.Fatalf("unexpected period %+v", got)
+		}
+	})
+
+	t.Run("slightly past start counts as now", func(t *testing.T) {
+		prices := &fakePrices{}
+		_, err := newSvc(prices).SchedulePriceChange(context.Background(), "p1", idr(90), now.Add(-time.Second), time.Time{}, "")
+		if err != nil || !prices.scheduled.EffectiveFrom.Equal(now) {
+			t.Fatalf("expected start clamped to now, got %+v (%v)", prices.scheduled, err)
+		}
+	})
+
+	invalid := map[string]struct {
+		price    domain.Money
+		from, to time.Time
+	}{
+		"past start":       {idr(90), now.Add(-time.Hour), time.Time{}},
+		"end before start": {idr(90), now.Add(time.Hour), now.Add(time.Minute)},
+		"zero price":       {idr(0), time.Time{}, time.Time{}},
+	}
+	for name, tc := range invalid {
+		t.Run(name+" -> invalid", func(t *testing.T) {
+			_, err := newSvc(&fakePrices{}).SchedulePriceChange(context.Background(), "p1", tc.price, tc.from, tc.to, "")
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Fatalf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
+
+// batchRepo holds products by ID and counts BatchGet calls.
+type batchRepo struct {
+	fakeRepo
+	products map[string]domain.Product
+	calls    int
+}
+
+func (r *batchRepo) BatchGet(ctx context.Context, ids []string) (map[string]domain.Product, error) {
+	r.calls++
+	out := make(map[string]domain.Product)
+	for _, id := range ids {
+		if p, ok := r.products[id]; ok {
+			out[id] = p
+		}
+	}
+	return out, nil
+}
+
+func TestBatchGetProducts(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &batchRepo{products: map[string]domain.Product{
+		"p1": {ID: "p1", Name: "Keyboard", Price: domain.Money{Currency: "IDR", Amount: 100}},
+		"p2": {ID: "p2", Name: "Mouse", Price: domain.Money{Currency: "IDR", Amount: 50}},
+	}}
+	prices := &fakePrices{periods: []domain.PricePeriod{
+		{ProductID: "p2", Price: domain.Money{Currency: "IDR", Amount: 40}, EffectiveFrom: now.Add(-time.Hour)},
+	}}
+	variants := &fakeVariants{}
+	svc := NewService(repo, &fakeCategories{}, variants, prices)
+	svc.now = func() time.Time { return now }
+
+	t.Run("returns products in request order with active prices", func(t *testing.T) {
+		got, err := svc.BatchGetProducts(context.Background(), []string{"p2", "p1", " p2 "}, time.Time{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 2 || got[0].ID != "p2" || got[1].ID != "p1" {
+			t.Fatalf("expected p2, p1, got %+v", got)
+		}
+		if got[0].Price.Amount != 40 || got[1].Price.Amount != 100 {
+			t.Fatalf("expected scheduled price for p2 only, got %v and %v", got[0].Price, got[1].Price)
+		}
+	})
+
+	t.Run("names every missing product", func(t *testing.T) {
+		_, err := svc.BatchGetProducts(context.Background(), []string{"p1", "gone", "p2", "lost"}, now)
+		var missing *MissingProductsError
+		if !errors.As(err, &missing) || !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected MissingProductsError, got %v", err)
+		}
+		if fmt.Sprint(missing.IDs) != "[gone lost]" {
+			t.Fatalf("unexpected missing IDs %v", missing.IDs)
+		}
+	})
+
+	t.Run("flags products by their variants, not their options", func(t *testing.T) {
+		variants.axes = []domain.OptionAxis{{Name: "size", Values: []string{"S", "M"}}}
+		variants.variants = []domain.Variant{{ID: "v1", ProductID: "p2"}}
+		defer func() { variants.axes, variants.variants = nil, nil }()
+
+		got, err := svc.BatchGetProducts(context.Background(), []string{"p1", "p2"}, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got[0].HasVariants || !got[1].HasVariants {
+			t.Fatalf("expected only p2 to have variants, got %v and %v", got[0].HasVariants, got[1].HasVariants)
+		}
+	})
+
+	t.Run("uses one product query", func(t *testing.T) {
+		repo.calls = 0
+		if _, err := svc.BatchGetProducts(context.Background(), []string{"p1", "p2"}, now); err != nil {
+			t.Fatal(err)
+		}
+		if repo.calls != 1 {
+			t.Fatalf("expected one BatchGet call, got %d", repo.calls)
+		}
+	})
+
+	t.Run("rejects blank and oversized batches", func(t *testing.T) {
+		if _, err := svc.BatchGetProducts(context.Background(), []string{"p1", " "}, now); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput for a blank ID, got %v", err)
+		}
+		if _, err := svc.BatchGetProducts(context.Background(), make([]string, MaxBatchProducts+1), now); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput for too many IDs, got %v", err)
+		}
+	})
+}
+
+func TestUpdateProduct(t *testing.T) {
+	stored := domain.Product{
+		ID:          "p1",
+		Name:        "Keyboard",
+		Description: "mechanical",
+		Price:       domain.Money{Currency: "IDR", Amount: 100},
+		Version:     3,
+	}
+	name := "Keyboard TKL"
+
+	t.Run("applies only the patched fields", func(t *testing.T) {
+		repo := &storedRepo{product: stored}
+		svc := NewService(repo, &fakeCategories{}, &fakeVariants{}, &fakePrices{})
+
+		_, err := svc.UpdateProduct(context.Background(), "p1", domain.ProductPatch{Name: &name}, 3)
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		got := *repo.updated
+		if got.Name != name || got.Description != "mechanical" || got.Price.Amount != 100 || got.Version != 3 {
+			t.Fatalf("unexpected update: %+v", got)
+		}
+	})
+
+	t.Run("stale version -> conflict", func(t *testing.T) {
+		repo := &storedRepo{product: stored}
+		svc := NewService(repo, &fakeCategories{}, &fakeVariants{}, &fakePrices{})
+
+		_, err := svc.UpdateProduct(context.Background(), "p1", domain.ProductPatch{Name: &name}, 2)
+		if !errors.Is(err, ErrVersionConflict) {
+			t.Fatalf("expected ErrVersionConflict, got %v", err)
+		}
+		if repo.updated != nil {
+			t.Fatalf("repo must not be updated")
+		}
+	})
+
+	t.Run("empty patch -> invalid", func(t *testing.T) {
+		svc := NewService(&storedRepo{product: stored}, &fakeCategories{}, &fakeVariants{}, &fakePrices{})
+
+		_, err := svc.UpdateProduct(context.Background(), "p1", domain.ProductPatch{}, 3)
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("non-positive price -> invalid", func(t *testing.T) {
+		svc := NewService(&storedRepo{product: stored}, &fakeCategories{}, &fakeVariants{}, &fakePrices{})
+
+		_, err := svc.UpdateProduct(context.Background(), "p1", domain.ProductPatch{Price: &domain.Money{Currency: "IDR", Amount: 0}}, 3)
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("weight", func(t *testing.T) {
+		repo := &storedRepo{product: stored}
+		svc := NewService(repo, &fakeCategories{}, &fakeVariants{}, &fakePrices{})
+
+		weight := int32(750)
+		if _, err := svc.UpdateProduct(context.Background(), "p1", domain.ProductPatch{WeightGrams: &weight}, 3); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if repo.updated.WeightGrams != 750 || repo.updated.Name != stored.Name {
+			t.Fatalf("unexpected update: %+v", *repo.updated)
+		}
+
+		negative := int32(-1)
+		if _, err := svc.UpdateProduct(context.Background(), "p1", domain.ProductPatch{WeightGrams: &negative}, 3); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
+
+func TestCategories(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("slug derived from name", func(t *testing.T) {
+		svc := NewService(fakeRepo{}, &fakeCategories{}, &fakeVariants{}, &fakePrices{})
+
+		c, err := svc.CreateCategory(ctx, "", "  Men's T-Shirts ", "")
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if c.Slug != "men-s-t-shirts" {
+			t.Fatalf("slug = %q", c.Slug)
+		}
+	})
+
+	t.Run("malformed slug -> invalid", func(t *testing.T) {
+		svc := NewService(fakeRepo{}, &fakeCategories{}, &fakeVariants{}, &fakePrices{})
+
+		_, err := svc.CreateCategory(ctx, "", "Shoes", "Shoes & Boots")
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("moving under a descendant -> cycle", func(t *testing.T) {
+		cats := &fakeCategories{}
+		svc := NewService(fakeRepo{}, cats, &fakeVariants{}, &fakePrices{})
+
+		_, err := svc.UpdateCategory(ctx, "root", "child", "Root", "")
+		if !errors.Is(err, ErrCategoryCycle) {
+			t.Fatalf("expected ErrCategoryCycle, got %v", err)
+		}
+		_, err = svc.UpdateCategory(ctx, "root", "root", "Root", "")
+		if !errors.Is(err, ErrCategoryCycle) {
+			t.Fatalf("expected ErrCategoryCycle for self-parent, got %v", err)
+		}
+		if cats.updated != nil {
+			t.Fatalf("repo must not be updated")
+		}
+	})
+
+	t.Run("moving to the root is allowed", func(t *testing.T) {
+		cats := &fakeCategories{}
+		svc := NewService(fakeRepo{}, cats, &fakeVariants{}, &fakePrices{})
+
+		if _, err := svc.UpdateCategory(ctx, "child", "", "Child", ""); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if cats.updated == nil || cats.updated.ParentID != "" {
+			t.Fatalf("unexpected update: %+v", cats.updated)
+		}
+	})
+}
+
+func TestVariants(t *testing.T) {
+	ctx := context.Background()
+	product := &storedRepo{product: domain.Product{ID: "p1", Price: domain.Money{Currency: "IDR", Amount: 100}}}
+	axes := []domain.OptionAxis{
+		{Name: "size", Values: []string{"S", "M"}},
+		{Name: "color", Values: []string{"red"}},
+	}
+
+	t.Run("price currency defaults to the product's", func(t *testing.T) {
+		variants := &fakeVariants{axes: axes}
+		svc := NewService(product, &fakeCategories{}, variants, &fakePrices{})
+
+		_, err := svc.CreateVariant(ctx, "p1", " TS-S-RED ", map[string]string{"size": "S", " color ": "red"}, domain.Money{Amount: 120})
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		got := variants.created
+		if got.SKU != "TS-S-RED" || got.Price.Currency != "IDR" || got.Options["color"] != "red" {
+			t.Fatalf("unexpected variant: %+v", got)
+		}
+	})
+
+	t.Run("options must match the axes", func(t *testing.T) {
+		svc := NewService(product, &fakeCategories{}, &fakeVariants{axes: axes}, &fakePrices{})
+
+		for _, opts := range []map[string]string{
+			{"size": "S"},
+			{"size": "XL", "color": "red"},
+			{"size": "S", "color": "red", "fit": "slim"},
+		} {
+			_, err := svc.CreateVariant(ctx, "p1", "TS", opts, domain.Money{Amount: 120})
+			if !errors.Is(err, domain.ErrInvalidOptions) {
+				t.Fatalf("options %v: expected ErrInvalidOptions, got %v", opts, err)
+			}
+		}
+	})
+
+	t.Run("other currency -> invalid", func(t *testing.T) {
+		svc := NewService(product, &fakeCategories{}, &fakeVariants{axes: axes}, &fakePrices{})
+
+		_, err := svc.CreateVariant(ctx, "p1", "TS", map[string]string{"size": "S", "color": "red"}, domain.Money{Currency: "USD", Amount: 1})
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("can't drop a value still in use", func(t *testing.T) {
+		variants := &fakeVariants{
+			axes:     axes,
+			variants: []domain.Variant{{ID: "v1", Options: map[string]string{"size": "M", "color": "red"}}},
+		}
+		svc := NewService(product, &fakeCategories{}, variants, &fakePrices{})
+
+		_, err := svc.SetProductOptions(ctx, "p1", []domain.OptionAxis{
+			{Name: "size", Values: []string{"S"}},
+			{Name: "color", Values: []string{"red"}},
+		})
+		if !errors.Is(err, ErrOptionsInUse) {
+			t.Fatalf("expected ErrOptionsInUse, got %v", err)
+		}
+	})
+
+	t.Run("duplicate axis -> invalid", func(t *testing.T) {
+		svc := NewService(product, &fakeCategories{}, &fakeVariants{}, &fakePrices{})
+
+		_, err := svc.SetProductOptions(ctx, "p1", []domain.OptionAxis{
+			{Name: "size", Values: []string{"S"}},
+			{Name: " size", Values: []string{"M"}},
+		})
+		if !errors.Is(err, domain.ErrInvalidOptions) {
+			t.Fatalf("expected ErrInvalidOptions, got %v", err)
+		}
+	})
+	t.Run("batch get leaves out missing variants", func(t *testing.T) {
+		variants := &fakeVariants{variants: []domain.Variant{{ID: "v1", ProductID: "p1"}, {ID: "v2", ProductID: "p1"}}}
+		svc := NewService(product, &fakeCategories{}, variants, &fakePrices{})
+
+		got, err := svc.BatchGetVariants(ctx, []string{" v2 ", "gone"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got["v2"].ID != "v2" {
+			t.Fatalf("expected only v2, got %+v", got)
+		}
+		if _, err := svc.BatchGetVariants(ctx, []string{"v1", ""}); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput for a blank ID, got %v", err)
+		}
+	})
+}
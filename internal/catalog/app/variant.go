@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
+)
+
+var (
+	ErrVariantExists = errors.New("a variant with this SKU or these options already exists")
+	ErrOptionsInUse  = errors.New("existing variants don't fit the new option axes")
+)
+
+// SetProductOptions replaces the axes the product's variants are picked
+// from. Every existing variant must still fit the new axes, so a value can
+// only be dropped once no variant uses it.
+func (s *Service) SetProductOptions(ctx context.Context, productID string, axes []domain.OptionAxis) ([]domain.OptionAxis, error) {
+	if strings.TrimSpace(productID) == "" {
+		return nil, ErrInvalidInput
+	}
+	axes = trimAxes(axes)
+	if err := domain.ValidateOptionAxes(axes); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.Get(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	variants, err := s.variants.List(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range variants {
+		if err := domain.MatchOptions(axes, v.Options); err != nil {
+			return nil, ErrOptionsInUse
+		}
+	}
+
+	if err := s.variants.SetOptions(ctx, productID, axes); err != nil {
+		return nil, err
+	}
+	return s.variants.ListOptions(ctx, productID)
+}
+
+// CreateVariant adds a SKU to the product. options must pick one value for
+// each of the product's axes. The price is in the product's currency; an empty
+// currency means the product's.
+func (s *Service) CreateVariant(ctx context.Context, productID, sku string, options map[string]string, price domain.Money) (domain.Variant, error) {
+	v, err := s.newVariant(ctx, productID, sku, options, price)
+	if err != nil {
+		return domain.Variant{}, err
+	}
+	return s.variants.Create(ctx, v)
+}
+
+func (s *Service) GetVariant(ctx context.Context, id string) (domain.Variant, error) {
+	if strings.TrimSpace(id) == "" {
+		return domain.Variant{}, ErrInvalidInput
+	}
+	return s.variants.Get(ctx, id)
+}
+
+// BatchGetVariants is GetVariant for many variants, with one query. It
+// returns the variants keyed by ID and leaves out those that don't exist.
+func (s *Service) BatchGetVariants(ctx context.Context, ids []string) (map[string]domain.Variant, error) {
+	if len(ids) > MaxBatchProducts {
+		return nil, ErrInvalidInput
+	}
+	trimmed := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return nil, ErrInvalidInput
+		}
+		trimmed = append(trimmed, id)
+	}
+	if len(trimmed) == 0 {
+		return map[string]domain.Variant{}, nil
+	}
+	return s.variants.BatchGet(ctx, trimmed)
+}
+
+func (s *Service) ListVariants(ctx context.Context, productID string) ([]domain.Variant, error) {
+	if strings.TrimSpace(productID) == "" {
+		return nil, ErrInvalidInput
+	}
+	if _, err := s.repo.Get(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.variants.List(ctx, productID)
+}
+
+// UpdateVariant replaces the variant's SKU, options and price.
+func (s *Service) UpdateVariant(ctx context.Context, id, sku string, options map[string]string, price domain.Money) (domain.Variant, error) {
+	current, err := s.GetVariant(ctx, id)
+	if err != nil {
+		return domain.Variant{}, err
+	}
+
+	v, err := s.newVariant(ctx, current.ProductID, sku, options, price)
+	if err != nil {
+		return domain.Variant{}, err
+	}
+	v.ID = current.ID
+	return s.variants.Update(ctx, v)
+}
+
+func (s *Service) DeleteVariant(ctx context.Context, id string) error {
+	if strings.TrimSpace(id) == "" {
+		return ErrInvalidInput
+	}
+	return s.variants.Delete(ctx, id)
+}
+
+func (s *Service) newVariant(ctx context.Context, productID, sku string, options map[string]string, price domain.Money) (domain.Variant, error) {
+	productID = strings.TrimSpace(productID)
+	sku = strings.TrimSpace(sku)
+	if productID == "" || sku == "" || price.Amount <= 0 {
+		return domain.Variant{}, ErrInvalidInput
+	}
+
+	p, err := s.repo.Get(ctx, productID)
+	if err != nil {
+		return domain.Variant{}, err
+	}
+	if strings.TrimSpace(price.Currency) == "" {
+		price.Currency = p.Price.Currency
+	}
+	price.Currency, err = money.NormalizeCurrency(price.Currency)
+	if err != nil {
+		return domain.Variant{}, ErrInvalidInput
+	}
+	// One currency per product keeps checkout from mixing currencies.
+	if price.Currency != p.Price.Currency {
+		return domain.Variant{}, ErrInvalidInput
+	}
+
+	axes, err := s.variants.ListOptions(ctx, productID)
+	if err != nil {
+		return domain.Variant{}, err
+	}
+	opts := make(map[string]string, len(options))
+	for name, value := range options {
+		opts[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	if err := domain.MatchOptions(axes, opts); err != nil {
+		return domain.Variant{}, err
+	}
+
+	return domain.Variant{
+		ProductID: productID,
+		SKU:       sku,
+		Options:   opts,
+		Price:     price,
+	}, nil
+}
+
+func trimAxes(axes []domain.OptionAxis) []domain.OptionAxis {
+	out := make([]domain.OptionAxis, 0, len(axes))
+	for _, axis := range axes {
+		values := make([]string, 0, len(axis.Values))
+		for _, v := range axis.Values {
+			values = append(values, strings.TrimSpace(v))
+		}
+		out = append(out, domain.OptionAxis{Name: strings.TrimSpace(axis.Name), Values: values})
+	}
+	return out
+}
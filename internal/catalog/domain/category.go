@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Category is a node in the browse tree. Root categories have no ParentID.
+type Category struct {
+	ID        string
+	ParentID  string
+	Name      string
+	Slug      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Slugify turns a category name into a URL-friendly slug, e.g.
+// "Men's T-Shirts" -> "men-s-t-shirts".
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,68 @@
+package domain
+
+import "time"
+
+// PricePeriod is the price of a product over [EffectiveFrom, EffectiveTo).
+// The periods of a product never overlap; the latest usually has no end.
+type PricePeriod struct {
+	ID            string
+	ProductID     string
+	Price         Money
+	EffectiveFrom time.Time
+	EffectiveTo   time.Time // zero: until further notice
+	Reason        string
+	CreatedAt     time.Time
+}
+
+func (p PricePeriod) ActiveAt(t time.Time) bool {
+	return !t.Before(p.EffectiveFrom) && (p.EffectiveTo.IsZero() || t.Before(p.EffectiveTo))
+}
+
+// overlaps reports whether p shares any instant with [from, to).
+func (p PricePeriod) overlaps(from, to time.Time) bool {
+	startsBeforeEnd := to.IsZero() || p.EffectiveFrom.Before(to)
+	endsAfterStart := p.EffectiveTo.IsZero() || p.EffectiveTo.After(from)
+	return startsBeforeEnd && endsAfterStart
+}
+
+// PriceSplice lists the edits that make room for a new period.
+type PriceSplice struct {
+	Trimmed []PricePeriod // existing periods with a new range
+	Deleted []string      // IDs of existing periods fully covered
+	Added   []PricePeriod // the new period, plus the remainders of split periods
+}
+
+// Splice fits p between existing periods of the same product. A period that
+// p cuts in two keeps its head and gets a copy for its tail, so a period with
+// an end, such as a promotion, hands back to the price it interrupted.
+func Splice(existing []PricePeriod, p PricePeriod) PriceSplice {
+	var s PriceSplice
+	for _, cur := range existing {
+		if !cur.overlaps(p.EffectiveFrom, p.EffectiveTo) {
+			continue
+		}
+
+		// outlives p: cur continues after p ends
+		outlives := !p.EffectiveTo.IsZero() &&
+			(cur.EffectiveTo.IsZero() || cur.EffectiveTo.After(p.EffectiveTo))
+
+		switch {
+		case cur.EffectiveFrom.Before(p.EffectiveFrom):
+			if outlives {
+				tail := cur
+				tail.ID = ""
+				tail.EffectiveFrom = p.EffectiveTo
+				s.Added = append(s.Added, tail)
+			}
+			cur.EffectiveTo = p.EffectiveFrom
+			s.Trimmed = append(s.Trimmed, cur)
+		case outlives:
+			cur.EffectiveFrom = p.EffectiveTo
+			s.Trimmed = append(s.Trimmed, cur)
+		default:
+			s.Deleted = append(s.Deleted, cur.ID)
+		}
+	}
+	s.Added = append(s.Added, p)
+	return s
+}
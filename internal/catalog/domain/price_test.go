@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplice(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	idr := func(amount int64) Money { return Money{Currency: "IDR", Amount: amount} }
+	base := PricePeriod{ID: "base", Price: idr(100), EffectiveFrom: day(1)}
+
+	t.Run("promotion splits the open period", func(t *testing.T) {
+		promo := PricePeriod{Price: idr(80), EffectiveFrom: day(10), EffectiveTo: day(15)}
+		s := Splice([]PricePeriod{base}, promo)
+
+		if len(s.Trimmed) != 1 || !s.Trimmed[0].EffectiveTo.Equal(day(10)) {
+			t.Fatalf("expected base to end on day 10, got %+v", s.Trimmed)
+		}
+		if len(s.Added) != 2 || len(s.Deleted) != 0 {
+			t.Fatalf("expected tail and promo, got %+v", s)
+		}
+		tail := s.Added[0]
+		if tail.ID != "" || tail.Price != base.Price || !tail.EffectiveFrom.Equal(day(15)) || !tail.EffectiveTo.IsZero() {
+			t.Fatalf("unexpected tail %+v", tail)
+		}
+		if s.Added[1] != promo {
+			t.Fatalf("expected promo last, got %+v", s.Added[1])
+		}
+	})
+
+	t.Run("open change replaces later periods", func(t *testing.T) {
+		existing := []PricePeriod{
+			{ID: "head", Price: idr(100), EffectiveFrom: day(1), EffectiveTo: day(10)},
+			{ID: "promo", Price: idr(80), EffectiveFrom: day(10), EffectiveTo: day(15)},
+			{ID: "tail", Price: idr(100), EffectiveFrom: day(15)},
+		}
+		s := Splice(existing, PricePeriod{Price: idr(120), EffectiveFrom: day(12)})
+
+		if len(s.Trimmed) != 1 || s.Trimmed[0].ID != "promo" || !s.Trimmed[0].EffectiveTo.Equal(day(12)) {
+			t.Fatalf("expected promo trimmed to day 12, got %+v", s.Trimmed)
+		}
+		if len(s.Deleted) != 1 || s.Deleted[0] != "tail" {
+			t.Fatalf("expected tail deleted, got %+v", s.Deleted)
+		}
+		if len(s.Added) != 1 {
+			t.Fatalf("expected only the new period, got %+v", s.Added)
+		}
+	})
+
+	t.Run("change starting with a period pushes it back", func(t *testing.T) {
+		future := PricePeriod{ID: "future", Price: idr(90), EffectiveFrom: day(10)}
+		s := Splice([]PricePeriod{future}, PricePeriod{Price: idr(70), EffectiveFrom: day(5), EffectiveTo: day(12)})
+
+		if len(s.Trimmed) != 1 || !s.Trimmed[0].EffectiveFrom.Equal(day(12)) || !s.Trimmed[0].EffectiveTo.IsZero() {
+			t.Fatalf("expected future to start on day 12, got %+v", s.Trimmed)
+		}
+	})
+
+	t.Run("adjacent periods are untouched", func(t *testing.T) {
+		closed := PricePeriod{ID: "closed", Price: idr(100), EffectiveFrom: day(1), EffectiveTo: day(10)}
+		s := Splice([]PricePeriod{closed}, PricePeriod{Price: idr(80), EffectiveFrom: day(10)})
+		if len(s.Trimmed)+len(s.Deleted) != 0 || len(s.Added) != 1 {
+			t.Fatalf("expected no edits, got %+v", s)
+		}
+	})
+}
@@ -1,17 +1,90 @@
 package domain
 
-import "time"
+import (
+	"time"
 
-type Money struct {
-	Currency string
-	Amount   int64
-}
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
+)
+
+// Money is an amount in the minor unit of its currency.
+type Money = money.Money
 
 type Product struct {
 	ID          string
 	Name        string
 	Price       Money
 	Description string
+	Version     int64
+	ExternalSKU string       // supplier SKU used by bulk imports; may be empty
+	WeightGrams int32        // shipping weight of one unit; 0 when unknown
+	ArchivedAt  time.Time    // zero while the product is active
+	CategoryIDs []string     // only loaded when reading a single product
+	Options     []OptionAxis // only loaded when reading a single product
+	HasVariants bool         // only loaded when reading a single product
+	Rating      Rating
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
+
+func (p Product) Archived() bool {
+	return !p.ArchivedAt.IsZero()
+}
+
+// Rating summarizes the approved reviews of a product.
+type Rating struct {
+	Average float64 // 0 without reviews
+	Count   int32
+}
+
+// ProductFilter narrows ListProducts. Zero values don't filter.
+type ProductFilter struct {
+	Query string
+	// CategoryID matches products in the category or any of its descendants.
+	CategoryID string
+	Currency   string
+	// MinPrice and MaxPrice are inclusive bounds in minor units. They need
+	// Currency, since amounts in different currencies don't compare.
+	MinPrice int64
+	MaxPrice int64
+	Sort     ProductSort
+}
+
+// ProductSort orders ListProducts. Every order breaks ties by product ID so
+// that pages never overlap or skip rows.
+type ProductSort string
+
+const (
+	// SortRelevance ranks by trigram similarity to the query; without a
+	// query it falls back to SortNewest.
+	SortRelevance ProductSort = "relevance"
+	SortPriceAsc  ProductSort = "price_asc"
+	SortPriceDesc ProductSort = "price_desc"
+	SortNewest    ProductSort = "newest"
+	// SortRating puts the best rated first; unrated products come last.
+	SortRating ProductSort = "rating"
+)
+
+func (s ProductSort) Valid() bool {
+	switch s {
+	case SortRelevance, SortPriceAsc, SortPriceDesc, SortNewest, SortRating:
+		return true
+	}
+	return false
+}
+
+// ProductPage is one page of ListProducts.
+type ProductPage struct {
+	Products   []Product
+	NextCursor string
+	// Total estimates every match, not just this page. It is the database's
+	// estimate, so it can be off in either direction.
+	Total int64
+}
+
+// ProductPatch holds the fields an update changes; nil fields are left as is.
+type ProductPatch struct {
+	Name        *string
+	Description *string
+	Price       *Money
+	WeightGrams *int32
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OptionAxis is one dimension a product varies along, e.g. "size" with the
+// values S, M and L.
+type OptionAxis struct {
+	Name   string
+	Values []string
+}
+
+// Variant is one sellable SKU of a product: a value for each of the
+// product's option axes and a price of its own.
+type Variant struct {
+	ID        string
+	ProductID string
+	SKU       string
+	Options   map[string]string // axis name -> value
+	Price     Money
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+var ErrInvalidOptions = errors.New("variant options don't match the product's option axes")
+
+// ValidateOptionAxes checks that axis names and the values within an axis are
+// non-empty and unique.
+func ValidateOptionAxes(axes []OptionAxis) error {
+	names := make(map[string]bool, len(axes))
+	for _, axis := range axes {
+		if strings.TrimSpace(axis.Name) == "" || names[axis.Name] {
+			return fmt.Errorf("%w: axis names must be non-empty and unique", ErrInvalidOptions)
+		}
+		names[axis.Name] = true
+
+		if len(axis.Values) == 0 {
+			return fmt.Errorf("%w: axis %q has no values", ErrInvalidOptions, axis.Name)
+		}
+		values := make(map[string]bool, len(axis.Values))
+		for _, v := range axis.Values {
+			if strings.TrimSpace(v) == "" || values[v] {
+				return fmt.Errorf("%w: values of axis %q must be non-empty and unique", ErrInvalidOptions, axis.Name)
+			}
+			values[v] = true
+		}
+	}
+	return nil
+}
+
+// MatchOptions checks that options picks exactly one allowed value for every
+// axis and names no other axis.
+func MatchOptions(axes []OptionAxis, options map[string]string) error {
+	if len(options) != len(axes) {
+		return fmt.Errorf("%w: want a value for each of %d axes, got %d", ErrInvalidOptions, len(axes), len(options))
+	}
+	for _, axis := range axes {
+		v, ok := options[axis.Name]
+		if !ok {
+			return fmt.Errorf("%w: missing a value for %q", ErrInvalidOptions, axis.Name)
+		}
+		allowed := false
+		for _, av := range axis.Values {
+			if av == v {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%w: %q is not a value of %q", ErrInvalidOptions, v, axis.Name)
+		}
+	}
+	return nil
+}
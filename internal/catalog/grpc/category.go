@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+
+	catalogv1 "github.com/dwikikusuma/shoping-llm/api/gen/catalog/v1"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+)
+
+func (s *Server) CreateCategory(ctx context.Context, req *catalogv1.CreateCategoryRequest) (*catalogv1.CreateCategoryResponse, error) {
+	c, err := s.svc.CreateCategory(ctx, req.GetParentId(), req.GetName(), req.GetSlug())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.CreateCategoryResponse{Category: toProtoCategory(c)}, nil
+}
+
+func (s *Server) GetCategory(ctx context.Context, req *catalogv1.GetCategoryRequest) (*catalogv1.GetCategoryResponse, error) {
+	c, err := s.svc.GetCategory(ctx, req.GetId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.GetCategoryResponse{Category: toProtoCategory(c)}, nil
+}
+
+func (s *Server) ListCategories(ctx context.Context, _ *catalogv1.ListCategoriesRequest) (*catalogv1.ListCategoriesResponse, error) {
+	categories, err := s.svc.ListCategories(ctx)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	out := make([]*catalogv1.Category, 0, len(categories))
+	for _, c := range categories {
+		out = append(out, toProtoCategory(c))
+	}
+	return &catalogv1.ListCategoriesResponse{Categories: out}, nil
+}
+
+func (s *Server) UpdateCategory(ctx context.Context, req *catalogv1.UpdateCategoryRequest) (*catalogv1.UpdateCategoryResponse, error) {
+	c, err := s.svc.UpdateCategory(ctx, req.GetId(), req.GetParentId(), req.GetName(), req.GetSlug())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.UpdateCategoryResponse{Category: toProtoCategory(c)}, nil
+}
+
+func (s *Server) DeleteCategory(ctx context.Context, req *catalogv1.DeleteCategoryRequest) (*catalogv1.DeleteCategoryResponse, error) {
+	if err := s.svc.DeleteCategory(ctx, req.GetId()); err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.DeleteCategoryResponse{}, nil
+}
+
+func (s *Server) SetProductCategories(ctx context.Context, req *catalogv1.SetProductCategoriesRequest) (*catalogv1.SetProductCategoriesResponse, error) {
+	ids, err := s.svc.SetProductCategories(ctx, req.GetProductId(), req.GetCategoryIds())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.SetProductCategoriesResponse{CategoryIds: ids}, nil
+}
+
+func toProtoCategory(c domain.Category) *catalogv1.Category {
+	return &catalogv1.Category{
+		Id:            c.ID,
+		ParentId:      c.ParentID,
+		Name:          c.Name,
+		Slug:          c.Slug,
+		CreatedAtUnix: c.CreatedAt.Unix(),
+		UpdatedAtUnix: c.UpdatedAt.Unix(),
+	}
+}
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	catalogv1 "github.com/dwikikusuma/shoping-llm/api/gen/catalog/v1"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+)
+
+func (s *Server) SchedulePriceChange(ctx context.Context, req *catalogv1.SchedulePriceChangeRequest) (*catalogv1.SchedulePriceChangeResponse, error) {
+	period, err := s.svc.SchedulePriceChange(ctx,
+		req.GetProductId(),
+		fromProtoMoney(req.GetPrice()),
+		fromUnix(req.GetEffectiveFromUnix()),
+		fromUnix(req.GetEffectiveToUnix()),
+		req.GetReason(),
+	)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.SchedulePriceChangeResponse{Period: toProtoPrice(period)}, nil
+}
+
+func (s *Server) GetPriceHistory(ctx context.Context, req *catalogv1.GetPriceHistoryRequest) (*catalogv1.GetPriceHistoryResponse, error) {
+	periods, err := s.svc.GetPriceHistory(ctx, req.GetProductId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	out := make([]*catalogv1.PricePeriod, 0, len(periods))
+	for _, p := range periods {
+		out = append(out, toProtoPrice(p))
+	}
+	return &catalogv1.GetPriceHistoryResponse{Periods: out}, nil
+}
+
+// fromUnix maps 0 to the zero time.
+func fromUnix(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+func toProtoPrice(p domain.PricePeriod) *catalogv1.PricePeriod {
+	var to int64
+	if !p.EffectiveTo.IsZero() {
+		to = p.EffectiveTo.Unix()
+	}
+	return &catalogv1.PricePeriod{
+		Id:        p.ID,
+		ProductId: p.ProductID,
+		Price: &catalogv1.Money{
+			Currency: p.Price.Currency,
+			Amount:   p.Price.Amount,
+		},
+		EffectiveFromUnix: p.EffectiveFrom.Unix(),
+		EffectiveToUnix:   to,
+		Reason:            p.Reason,
+		CreatedAtUnix:     p.CreatedAt.Unix(),
+	}
+}
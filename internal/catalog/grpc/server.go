@@ -7,24 +7,29 @@ import (
 	catalogv1 "github.com/dwikikusuma/shoping-llm/api/gen/catalog/v1"
 	"github.com/dwikikusuma/shoping-llm/internal/catalog/app"
 	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+	"github.com/dwikikusuma/shoping-llm/pkg/grpcauth"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type Server struct {
 	catalogv1.UnimplementedCatalogServiceServer
-	svc *app.Service
+	svc        *app.Service
+	adminToken string
 }
 
-func NewServer(svc *app.Service) *Server {
-	return &Server{svc: svc}
+// NewServer returns the catalog gRPC server. Admin-only RPCs require the
+// "admin-token" metadata to equal adminToken; an empty adminToken disables
+// them.
+func NewServer(svc *app.Service, adminToken string) *Server {
+	return &Server{svc: svc, adminToken: adminToken}
 }
 
 func (s *Server) CreateProduct(ctx context.Context, req *catalogv1.CreateProductRequest) (*catalogv1.CreateProductResponse, error) {
 	if req == nil || req.Price == nil {
 		return nil, status.Error(codes.InvalidArgument, "missing body/price")
 	}
-	product, err := s.svc.CreateProduct(ctx, req.Name, req.Description, req.Price.Currency, req.Price.Amount)
+	product, err := s.svc.CreateProduct(ctx, req.Name, req.Description, req.Price.Currency, req.Price.Amount, req.WeightGrams)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create product: %v", err)
 	}
@@ -34,29 +39,112 @@ func (s *Server) CreateProduct(ctx context.Context, req *catalogv1.CreateProduct
 }
 
 func (s *Server) GetProduct(ctx context.Context, req *catalogv1.GetProductRequest) (*catalogv1.GetProductResponse, error) {
-	p, err := s.svc.GetProduct(ctx, req.GetId())
+	var (
+		p   domain.Product
+		err error
+	)
+	if at := fromUnix(req.GetAtUnix()); !at.IsZero() {
+		p, err = s.svc.GetProductAt(ctx, req.GetId(), at)
+	} else {
+		p, err = s.svc.GetProduct(ctx, req.GetId())
+	}
 	if err != nil {
 		return nil, mapErr(err)
 	}
 	return &catalogv1.GetProductResponse{Product: toProto(p)}, nil
 }
 
-func (s *Server) ListProducts(ctx context.Context, req *catalogv1.ListProductsRequest) (*catalogv1.ListProductsResponse, error) {
-	products, next, err := s.svc.ListProducts(ctx, req.GetQuery(), int(req.GetLimit()), req.GetCursor())
+func (s *Server) BatchGetProducts(ctx context.Context, req *catalogv1.BatchGetProductsRequest) (*catalogv1.BatchGetProductsResponse, error) {
+	products, err := s.svc.BatchGetProducts(ctx, req.GetIds(), fromUnix(req.GetAtUnix()))
 	if err != nil {
 		return nil, mapErr(err)
 	}
 
 	out := make([]*catalogv1.Product, 0, len(products))
 	for _, p := range products {
+		out = append(out, toProto(p))
+	}
+	return &catalogv1.BatchGetProductsResponse{Products: out}, nil
+}
+
+func (s *Server) ListProducts(ctx context.Context, req *catalogv1.ListProductsRequest) (*catalogv1.ListProductsResponse, error) {
+	filter := domain.ProductFilter{
+		Query:      req.GetQuery(),
+		CategoryID: req.GetCategoryId(),
+		Currency:   req.GetCurrency(),
+		MinPrice:   req.GetMinPrice(),
+		MaxPrice:   req.GetMaxPrice(),
+		Sort:       domain.ProductSort(req.GetSort()),
+	}
+	page, err := s.svc.ListProducts(ctx, filter, int(req.GetLimit()), req.GetCursor())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	out := make([]*catalogv1.Product, 0, len(page.Products))
+	for _, p := range page.Products {
 		cp := p
 		out = append(out, toProto(cp))
 	}
 
-	return &catalogv1.ListProductsResponse{Products: out, NextCursor: next}, nil
+	return &catalogv1.ListProductsResponse{Products: out, NextCursor: page.NextCursor, TotalCount: page.Total}, nil
+}
+
+func (s *Server) UpdateProduct(ctx context.Context, req *catalogv1.UpdateProductRequest) (*catalogv1.UpdateProductResponse, error) {
+	if len(req.GetUpdateMask().GetPaths()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "update_mask is required")
+	}
+
+	in := req.GetProduct()
+	var patch domain.ProductPatch
+	for _, path := range req.GetUpdateMask().GetPaths() {
+		switch path {
+		case "name":
+			name := in.GetName()
+			patch.Name = &name
+		case "description":
+			desc := in.GetDescription()
+			patch.Description = &desc
+		case "price":
+			patch.Price = &domain.Money{Currency: in.GetPrice().GetCurrency(), Amount: in.GetPrice().GetAmount()}
+		case "weight_grams":
+			weight := in.GetWeightGrams()
+			patch.WeightGrams = &weight
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported update_mask path %q", path)
+		}
+	}
+
+	p, err := s.svc.UpdateProduct(ctx, req.GetId(), patch, req.GetExpectedVersion())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.UpdateProductResponse{Product: toProto(p)}, nil
+}
+
+func (s *Server) ArchiveProduct(ctx context.Context, req *catalogv1.ArchiveProductRequest) (*catalogv1.ArchiveProductResponse, error) {
+	p, err := s.svc.ArchiveProduct(ctx, req.GetId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.ArchiveProductResponse{Product: toProto(p)}, nil
+}
+
+func (s *Server) DeleteProduct(ctx context.Context, req *catalogv1.DeleteProductRequest) (*catalogv1.DeleteProductResponse, error) {
+	if err := grpcauth.RequireAdmin(ctx, s.adminToken); err != nil {
+		return nil, err
+	}
+	if err := s.svc.DeleteProduct(ctx, req.GetId()); err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.DeleteProductResponse{}, nil
 }
 
 func toProto(p domain.Product) *catalogv1.Product {
+	var archivedAt int64
+	if p.Archived() {
+		archivedAt = p.ArchivedAt.Unix()
+	}
 	return &catalogv1.Product{
 		Id:          p.ID,
 		Name:        p.Name,
@@ -65,8 +153,16 @@ func toProto(p domain.Product) *catalogv1.Product {
 			Currency: p.Price.Currency,
 			Amount:   p.Price.Amount,
 		},
-		CreatedAtUnix: p.CreatedAt.Unix(),
-		UpdatedAtUnix: p.UpdatedAt.Unix(),
+		CreatedAtUnix:  p.CreatedAt.Unix(),
+		UpdatedAtUnix:  p.UpdatedAt.Unix(),
+		Version:        p.Version,
+		ArchivedAtUnix: archivedAt,
+		CategoryIds:    p.CategoryIDs,
+		Options:        toProtoOptions(p.Options),
+		ExternalSku:    p.ExternalSKU,
+		RatingAvg:      p.Rating.Average,
+		RatingCount:    p.Rating.Count,
+		WeightGrams:    p.WeightGrams,
 	}
 }
 
@@ -77,5 +173,26 @@ func mapErr(err error) error {
 	if errors.Is(err, app.ErrNotFound) {
 		return status.Error(codes.NotFound, err.Error())
 	}
+	if errors.Is(err, app.ErrVersionConflict) {
+		return status.Error(codes.Aborted, err.Error())
+	}
+	if errors.Is(err, app.ErrSlugTaken) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	if errors.Is(err, app.ErrCategoryCycle) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, app.ErrCategoryHasChildren) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, domain.ErrInvalidOptions) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, app.ErrVariantExists) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	if errors.Is(err, app.ErrOptionsInUse) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
 	return status.Error(codes.Internal, "internal error")
 }
@@ -0,0 +1,281 @@
+package grpc
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	catalogv1 "github.com/dwikikusuma/shoping-llm/api/gen/catalog/v1"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/app"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	formatCSV   = "csv"
+	formatJSONL = "jsonl"
+)
+
+// transferColumns are the CSV columns of an import; exports add id first.
+var transferColumns = []string{"sku", "name", "description", "currency", "price_amount", "weight_grams"}
+
+// transferRecord is one JSONL line of an import or export.
+type transferRecord struct {
+	ID          string `json:"id,omitempty"`
+	SKU         string `json:"sku"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Currency    string `json:"currency"`
+	PriceAmount int64  `json:"price_amount"`
+	WeightGrams int32  `json:"weight_grams"`
+}
+
+func (s *Server) ImportProducts(stream catalogv1.CatalogService_ImportProductsServer) error {
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return status.Error(codes.InvalidArgument, "empty import")
+	}
+	if err != nil {
+		return err
+	}
+
+	r := &importReader{stream: stream, buf: first.GetData()}
+	rows, err := decodeImport(first.GetFormat(), r)
+	if err != nil {
+		if r.err != nil {
+			return r.err
+		}
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	report, err := s.svc.ImportProducts(stream.Context(), rows, first.GetDryRun())
+	if err != nil {
+		return mapErr(err)
+	}
+
+	resp := &catalogv1.ImportProductsResponse{
+		Rows:    make([]*catalogv1.ImportRowResult, 0, len(report.Rows)),
+		Created: int32(report.Created),
+		Updated: int32(report.Updated),
+		Failed:  int32(report.Failed),
+		Applied: report.Applied,
+	}
+	for _, row := range report.Rows {
+		resp.Rows = append(resp.Rows, &catalogv1.ImportRowResult{
+			Line:      int32(row.Line),
+			Sku:       row.SKU,
+			Action:    string(row.Action),
+			ProductId: row.ProductID,
+			Error:     row.Error,
+		})
+	}
+	return stream.SendAndClose(resp)
+}
+
+func (s *Server) ExportProducts(req *catalogv1.ExportProductsRequest, stream catalogv1.CatalogService_ExportProductsServer) error {
+	w := bufio.NewWriterSize(exportWriter{stream: stream}, 32<<10)
+
+	var encode func(p domain.Product) error
+	flush := func() error { return nil }
+	switch strings.ToLower(strings.TrimSpace(req.GetFormat())) {
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(append([]string{"id"}, transferColumns...)); err != nil {
+			return err
+		}
+		encode = func(p domain.Product) error {
+			return cw.Write([]string{
+				p.ID,
+				p.ExternalSKU,
+				p.Name,
+				p.Description,
+				p.Price.Currency,
+				strconv.FormatInt(p.Price.Amount, 10),
+				strconv.FormatInt(int64(p.WeightGrams), 10),
+			})
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	case formatJSONL:
+		enc := json.NewEncoder(w)
+		encode = func(p domain.Product) error {
+			return enc.Encode(transferRecord{
+				ID:          p.ID,
+				SKU:         p.ExternalSKU,
+				Name:        p.Name,
+				Description: p.Description,
+				Currency:    p.Price.Currency,
+				PriceAmount: p.Price.Amount,
+				WeightGrams: p.WeightGrams,
+			})
+		}
+	default:
+		return status.Error(codes.InvalidArgument, `format must be "csv" or "jsonl"`)
+	}
+
+	if err := s.svc.ExportProducts(stream.Context(), encode); err != nil {
+		return mapErr(err)
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// decodeImport parses the whole file into rows. Problems with a single row
+// are recorded on the row; only an unreadable file is an error.
+func decodeImport(format string, r io.Reader) ([]app.ImportRow, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case formatCSV:
+		return decodeCSV(r)
+	case formatJSONL:
+		return decodeJSONL(r)
+	default:
+		return nil, errors.New(`format must be "csv" or "jsonl"`)
+	}
+}
+
+func decodeCSV(r io.Reader) ([]app.ImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1 // short rows are reported per row
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv has no header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	col := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // spreadsheet BOM
+		}
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range transferColumns {
+		if _, ok := col[name]; !ok && !optionalColumn(name) {
+			return nil, fmt.Errorf("csv header is missing column %q", name)
+		}
+	}
+
+	var rows []app.ImportRow
+	for len(rows) <= app.MaxImportRows {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			i, ok := col[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		line, _ := cr.FieldPos(0)
+		row := app.ImportRow{
+			Line:        line,
+			SKU:         field("sku"),
+			Name:        field("name"),
+			Description: field("description"),
+			Currency:    field("currency"),
+		}
+		row.Amount, err = strconv.ParseInt(field("price_amount"), 10, 64)
+		if err != nil {
+			row.DecodeErr = "price_amount must be an integer"
+		}
+		if w := field("weight_grams"); w != "" && row.DecodeErr == "" {
+			weight, err := strconv.ParseInt(w, 10, 32)
+			if err != nil {
+				row.DecodeErr = "weight_grams must be an integer"
+			}
+			row.WeightGrams = int32(weight)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// optionalColumn reports whether an import may leave the column out; its
+// rows then take the zero value.
+func optionalColumn(name string) bool {
+	return name == "description" || name == "weight_grams"
+}
+
+func decodeJSONL(r io.Reader) ([]app.ImportRow, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+
+	var rows []app.ImportRow
+	for line := 1; sc.Scan() && len(rows) <= app.MaxImportRows; line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+
+		var rec transferRecord
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			rows = append(rows, app.ImportRow{Line: line, DecodeErr: "invalid json: " + err.Error()})
+			continue
+		}
+		rows = append(rows, app.ImportRow{
+			Line:        line,
+			SKU:         rec.SKU,
+			Name:        rec.Name,
+			Description: rec.Description,
+			Currency:    rec.Currency,
+			Amount:      rec.PriceAmount,
+			WeightGrams: rec.WeightGrams,
+		})
+	}
+	return rows, sc.Err()
+}
+
+// importReader turns the data chunks of an import stream into a reader.
+// A receive error is kept in err so it isn't reported as a bad file.
+type importReader struct {
+	stream catalogv1.CatalogService_ImportProductsServer
+	buf    []byte
+	err    error
+}
+
+func (r *importReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return 0, io.EOF
+		}
+		if err != nil {
+			r.err = err
+			return 0, err
+		}
+		r.buf = msg.GetData()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// exportWriter sends every write as one chunk of the export stream.
+type exportWriter struct {
+	stream catalogv1.CatalogService_ExportProductsServer
+}
+
+func (w exportWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&catalogv1.ExportProductsResponse{Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,92 @@
+package grpc
+
+import (
+	"context"
+
+	catalogv1 "github.com/dwikikusuma/shoping-llm/api/gen/catalog/v1"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+)
+
+func (s *Server) SetProductOptions(ctx context.Context, req *catalogv1.SetProductOptionsRequest) (*catalogv1.SetProductOptionsResponse, error) {
+	axes := make([]domain.OptionAxis, 0, len(req.GetOptions()))
+	for _, axis := range req.GetOptions() {
+		axes = append(axes, domain.OptionAxis{Name: axis.GetName(), Values: axis.GetValues()})
+	}
+
+	axes, err := s.svc.SetProductOptions(ctx, req.GetProductId(), axes)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.SetProductOptionsResponse{Options: toProtoOptions(axes)}, nil
+}
+
+func (s *Server) CreateVariant(ctx context.Context, req *catalogv1.CreateVariantRequest) (*catalogv1.CreateVariantResponse, error) {
+	v, err := s.svc.CreateVariant(ctx, req.GetProductId(), req.GetSku(), req.GetOptions(), fromProtoMoney(req.GetPrice()))
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.CreateVariantResponse{Variant: toProtoVariant(v)}, nil
+}
+
+func (s *Server) GetVariant(ctx context.Context, req *catalogv1.GetVariantRequest) (*catalogv1.GetVariantResponse, error) {
+	v, err := s.svc.GetVariant(ctx, req.GetId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.GetVariantResponse{Variant: toProtoVariant(v)}, nil
+}
+
+func (s *Server) ListVariants(ctx context.Context, req *catalogv1.ListVariantsRequest) (*catalogv1.ListVariantsResponse, error) {
+	variants, err := s.svc.ListVariants(ctx, req.GetProductId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	out := make([]*catalogv1.Variant, 0, len(variants))
+	for _, v := range variants {
+		out = append(out, toProtoVariant(v))
+	}
+	return &catalogv1.ListVariantsResponse{Variants: out}, nil
+}
+
+func (s *Server) UpdateVariant(ctx context.Context, req *catalogv1.UpdateVariantRequest) (*catalogv1.UpdateVariantResponse, error) {
+	v, err := s.svc.UpdateVariant(ctx, req.GetId(), req.GetSku(), req.GetOptions(), fromProtoMoney(req.GetPrice()))
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.UpdateVariantResponse{Variant: toProtoVariant(v)}, nil
+}
+
+func (s *Server) DeleteVariant(ctx context.Context, req *catalogv1.DeleteVariantRequest) (*catalogv1.DeleteVariantResponse, error) {
+	if err := s.svc.DeleteVariant(ctx, req.GetId()); err != nil {
+		return nil, mapErr(err)
+	}
+	return &catalogv1.DeleteVariantResponse{}, nil
+}
+
+func fromProtoMoney(m *catalogv1.Money) domain.Money {
+	return domain.Money{Currency: m.GetCurrency(), Amount: m.GetAmount()}
+}
+
+func toProtoOptions(axes []domain.OptionAxis) []*catalogv1.OptionAxis {
+	out := make([]*catalogv1.OptionAxis, 0, len(axes))
+	for _, axis := range axes {
+		out = append(out, &catalogv1.OptionAxis{Name: axis.Name, Values: axis.Values})
+	}
+	return out
+}
+
+func toProtoVariant(v domain.Variant) *catalogv1.Variant {
+	return &catalogv1.Variant{
+		Id:        v.ID,
+		ProductId: v.ProductID,
+		Sku:       v.SKU,
+		Options:   v.Options,
+		Price: &catalogv1.Money{
+			Currency: v.Price.Currency,
+			Amount:   v.Price.Amount,
+		},
+		CreatedAtUnix: v.CreatedAt.Unix(),
+		UpdatedAtUnix: v.UpdatedAt.Unix(),
+	}
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/app"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+)
+
+// PriceRepo passes price changes through and invalidates the cached products
+// whose stored price and version they rewrite.
+type PriceRepo struct {
+	app.PriceRepo
+	products *ProductRepo
+}
+
+func NewPriceRepo(inner app.PriceRepo, products *ProductRepo) *PriceRepo {
+	return &PriceRepo{PriceRepo: inner, products: products}
+}
+
+func (r *PriceRepo) Schedule(ctx context.Context, period domain.PricePeriod, now time.Time) (domain.PricePeriod, error) {
+	defer r.products.invalidateOnCommit(ctx, period.ProductID)
+	return r.PriceRepo.Schedule(ctx, period, now)
+}
+
+func (r *PriceRepo) SyncCurrent(ctx context.Context, now time.Time) (int64, error) {
+	n, err := r.PriceRepo.SyncCurrent(ctx, now)
+	// The sync doesn't say which products changed; it only changes any at
+	// period boundaries, so dropping everything is rare.
+	if n > 0 {
+		r.products.InvalidateAll(ctx)
+	}
+	return n, err
+}
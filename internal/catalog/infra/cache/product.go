@@ -0,0 +1,379 @@
+// Package cache puts a read-through cache in front of the catalog repos.
+//
+// Products are cached in a bounded in-process LRU and, optionally, in Redis
+// shared by every instance. Concurrent misses for the same product share one
+// database read. Writes through the wrapped repos invalidate both tiers once
+// the transaction they ran in commits, and tell the other instances over
+// Redis pub/sub to drop their local copies; entries also expire after a TTL,
+// which bounds staleness if a message is lost.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/app"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	keyPrefix = "catalog:product:v1:"
+	// invalidateChannel carries comma-separated keys, or "*" for all.
+	invalidateChannel = "catalog:product:invalidate"
+	// loadTimeout bounds a read shared by concurrent misses, which doesn't
+	// stop when the caller that started it goes away.
+	loadTimeout = 5 * time.Second
+)
+
+// ProductRepo caches Get and BatchGet of the wrapped repo. Everything else
+// goes straight through; writes also invalidate what they touch.
+type ProductRepo struct {
+	app.ProductRepo
+
+	local *lru
+	rdb   redis.UniversalClient // nil without a Redis tier
+	ttl   time.Duration
+	group singleflight.Group
+
+	// gen changes on every invalidation. A read only caches what it loaded
+	// if gen is unchanged, so it can't put back a product that was updated
+	// while it was reading.
+	gen atomic.Uint64
+
+	localHits     atomic.Int64
+	remoteHits    atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+	remoteErrors  atomic.Int64
+}
+
+// NewProductRepo caches up to size products for ttl. rdb may be nil.
+func NewProductRepo(inner app.ProductRepo, size int, ttl time.Duration, rdb redis.UniversalClient) *ProductRepo {
+	return &ProductRepo{
+		ProductRepo: inner,
+		local:       newLRU(size, ttl),
+		rdb:         rdb,
+		ttl:         ttl,
+	}
+}
+
+func (r *ProductRepo) Get(ctx context.Context, id string) (domain.Product, error) {
+	key := cacheKey(id)
+	if p, ok := r.local.get(key); ok {
+		r.localHits.Add(1)
+		return p, nil
+	}
+
+	ch := r.group.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+
+		gen := r.gen.Load()
+		if p, ok := r.getRemote(ctx, key); ok {
+			r.remoteHits.Add(1)
+			r.store(ctx, gen, map[string]domain.Product{key: p}, false)
+			return p, nil
+		}
+
+		r.misses.Add(1)
+		p, err := r.ProductRepo.Get(ctx, id)
+		if err != nil {
+			return domain.Product{}, err
+		}
+		r.store(ctx, gen, map[string]domain.Product{key: p}, true)
+		return p, nil
+	})
+	select {
+	case <-ctx.Done():
+		return domain.Product{}, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return domain.Product{}, res.Err
+		}
+		return res.Val.(domain.Product), nil
+	}
+}
+
+func (r *ProductRepo) BatchGet(ctx context.Context, ids []string) (map[string]domain.Product, error) {
+	out := make(map[string]domain.Product, len(ids))
+	var rest []string
+	for _, id := range ids {
+		if p, ok := r.local.get(cacheKey(id)); ok {
+			r.localHits.Add(1)
+			out[id] = p
+			continue
+		}
+		rest = append(rest, id)
+	}
+	if len(rest) == 0 {
+		return out, nil
+	}
+
+	gen := r.gen.Load()
+	if r.rdb != nil {
+		keys := make([]string, len(rest))
+		for i, id := range rest {
+			keys[i] = cacheKey(id)
+		}
+		vals, err := r.rdb.MGet(ctx, keys...).Result()
+		if err != nil {
+			r.remoteErrors.Add(1)
+		} else {
+			hits := make(map[string]domain.Product)
+			var missed []string
+			for i, v := range vals {
+				p, ok := decode(v)
+				if !ok {
+					missed = append(missed, rest[i])
+					continue
+				}
+				r.remoteHits.Add(1)
+				out[rest[i]] = p
+				hits[keys[i]] = p
+			}
+			r.store(ctx, gen, hits, false)
+			rest = missed
+		}
+	}
+	if len(rest) == 0 {
+		return out, nil
+	}
+
+	r.misses.Add(int64(len(rest)))
+	found, err := r.ProductRepo.BatchGet(ctx, rest)
+	if err != nil {
+		return nil, err
+	}
+	loaded := make(map[string]domain.Product, len(found))
+	for id, p := range found {
+		out[id] = p
+		loaded[cacheKey(id)] = p
+	}
+	r.store(ctx, gen, loaded, true)
+	return out, nil
+}
+
+func (r *ProductRepo) Update(ctx context.Context, p domain.Product) (domain.Product, error) {
+	// Invalidate on failure too: a version conflict means the cached copy
+	// is the stale one.
+	defer r.invalidateOnCommit(ctx, p.ID)
+	return r.ProductRepo.Update(ctx, p)
+}
+
+func (r *ProductRepo) Archive(ctx context.Context, id string) (domain.Product, error) {
+	defer r.invalidateOnCommit(ctx, id)
+	return r.ProductRepo.Archive(ctx, id)
+}
+
+func (r *ProductRepo) Delete(ctx context.Context, id string) error {
+	defer r.invalidateOnCommit(ctx, id)
+	return r.ProductRepo.Delete(ctx, id)
+}
+
+func (r *ProductRepo) SetRating(ctx context.Context, id string, rating domain.Rating) error {
+	defer r.invalidateOnCommit(ctx, id)
+	return r.ProductRepo.SetRating(ctx, id, rating)
+}
+
+func (r *ProductRepo) UpsertBySKU(ctx context.Context, products []domain.Product, commit bool) ([]app.UpsertResult, error) {
+	results, err := r.ProductRepo.UpsertBySKU(ctx, products, commit)
+	if err != nil || !commit {
+		return results, err
+	}
+	ids := make([]string, 0, len(results))
+	for _, res := range results {
+		if !res.Created {
+			ids = append(ids, res.Product.ID)
+		}
+	}
+	r.invalidateOnCommit(ctx, ids...)
+	return results, nil
+}
+
+// invalidateOnCommit drops products a write touched once the write is
+// visible. Inside a transaction that is when it commits: invalidating only
+// when the repo call returns would let a read in between load the old row
+// and cache it under the new gen. They are dropped right away too, so a
+// failed write still clears what it found stale.
+func (r *ProductRepo) invalidateOnCommit(ctx context.Context, ids ...string) {
+	if _, ok := pg.TxFromContext(ctx); ok {
+		r.Invalidate(ctx, ids...)
+	}
+	pg.AfterCommit(ctx, func() { r.Invalidate(ctx, ids...) })
+}
+
+// Invalidate drops the products from both tiers and from the local tier of
+// every other instance.
+func (r *ProductRepo) Invalidate(ctx context.Context, ids ...string) {
+	if len(ids) == 0 {
+		return
+	}
+	r.gen.Add(1)
+	r.invalidations.Add(int64(len(ids)))
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = cacheKey(id)
+		r.local.remove(keys[i])
+		r.group.Forget(keys[i])
+	}
+	if r.rdb == nil {
+		return
+	}
+	if err := r.rdb.Del(ctx, keys...).Err(); err != nil {
+		r.remoteErrors.Add(1)
+	}
+	if err := r.rdb.Publish(ctx, invalidateChannel, strings.Join(keys, ",")).Err(); err != nil {
+		r.remoteErrors.Add(1)
+	}
+}
+
+// InvalidateAll drops every cached product, for changes that touch products
+// in bulk.
+func (r *ProductRepo) InvalidateAll(ctx context.Context) {
+	r.gen.Add(1)
+	r.invalidations.Add(1)
+	r.local.purge()
+	if r.rdb == nil {
+		return
+	}
+
+	iter := r.rdb.Scan(ctx, 0, keyPrefix+"*", 500).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == 500 {
+			r.rdb.Unlink(ctx, keys...)
+			keys = keys[:0]
+		}
+	}
+	if len(keys) > 0 {
+		r.rdb.Unlink(ctx, keys...)
+	}
+	if err := iter.Err(); err != nil {
+		r.remoteErrors.Add(1)
+	}
+	if err := r.rdb.Publish(ctx, invalidateChannel, "*").Err(); err != nil {
+		r.remoteErrors.Add(1)
+	}
+}
+
+// Listen applies invalidations published by other instances until ctx is
+// cancelled. It returns at once without a Redis tier.
+func (r *ProductRepo) Listen(ctx context.Context) {
+	if r.rdb == nil {
+		return
+	}
+	sub := r.rdb.Subscribe(ctx, invalidateChannel)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			r.gen.Add(1)
+			if msg.Payload == "*" {
+				r.local.purge()
+				continue
+			}
+			for _, key := range strings.Split(msg.Payload, ",") {
+				r.local.remove(key)
+				r.group.Forget(key)
+			}
+		}
+	}
+}
+
+// WriteMetrics writes the cache counters in the Prometheus text format.
+func (r *ProductRepo) WriteMetrics(w io.Writer) {
+	fmt.Fprintln(w, "# HELP catalog_product_cache_requests_total Product cache lookups by result.")
+	fmt.Fprintln(w, "# TYPE catalog_product_cache_requests_total counter")
+	fmt.Fprintf(w, "catalog_product_cache_requests_total{result=\"local_hit\"} %d\n", r.localHits.Load())
+	fmt.Fprintf(w, "catalog_product_cache_requests_total{result=\"redis_hit\"} %d\n", r.remoteHits.Load())
+	fmt.Fprintf(w, "catalog_product_cache_requests_total{result=\"miss\"} %d\n", r.misses.Load())
+	fmt.Fprintln(w, "# HELP catalog_product_cache_invalidations_total Products dropped from the cache by writes.")
+	fmt.Fprintln(w, "# TYPE catalog_product_cache_invalidations_total counter")
+	fmt.Fprintf(w, "catalog_product_cache_invalidations_total %d\n", r.invalidations.Load())
+	fmt.Fprintln(w, "# HELP catalog_product_cache_redis_errors_total Failed Redis calls; the cache falls back to Postgres.")
+	fmt.Fprintln(w, "# TYPE catalog_product_cache_redis_errors_total counter")
+	fmt.Fprintf(w, "catalog_product_cache_redis_errors_total %d\n", r.remoteErrors.Load())
+	fmt.Fprintln(w, "# HELP catalog_product_cache_entries Products in the local cache.")
+	fmt.Fprintln(w, "# TYPE catalog_product_cache_entries gauge")
+	fmt.Fprintf(w, "catalog_product_cache_entries %d\n", r.local.len())
+}
+
+func (r *ProductRepo) getRemote(ctx context.Context, key string) (domain.Product, bool) {
+	if r.rdb == nil {
+		return domain.Product{}, false
+	}
+	b, err := r.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			r.remoteErrors.Add(1)
+		}
+		return domain.Product{}, false
+	}
+	var p domain.Product
+	if err := json.Unmarshal(b, &p); err != nil {
+		return domain.Product{}, false
+	}
+	return p, true
+}
+
+// store caches products loaded while gen was current. remote also writes
+// them to Redis, for products that came from Postgres.
+func (r *ProductRepo) store(ctx context.Context, gen uint64, products map[string]domain.Product, remote bool) {
+	if len(products) == 0 || r.gen.Load() != gen {
+		return
+	}
+	for key, p := range products {
+		r.local.add(key, p)
+	}
+	if !remote || r.rdb == nil {
+		return
+	}
+
+	pipe := r.rdb.Pipeline()
+	for key, p := range products {
+		b, err := json.Marshal(p)
+		if err != nil {
+			continue
+		}
+		pipe.Set(ctx, key, b, r.ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.remoteErrors.Add(1)
+	}
+}
+
+func decode(v any) (domain.Product, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return domain.Product{}, false
+	}
+	var p domain.Product
+	if err := json.Unmarshal([]byte(s), &p); err != nil {
+		return domain.Product{}, false
+	}
+	return p, true
+}
+
+// cacheKey normalizes id, so that differently written IDs of one product
+// share an entry and are invalidated together.
+func cacheKey(id string) string {
+	return keyPrefix + strings.ToLower(strings.TrimSpace(id))
+}
@@ -0,0 +1,292 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/app"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
+)
+
+// countingRepo serves products from a map and counts reads. When block is
+// set, Get signals entered (if set) and waits on block, so concurrent callers
+// pile up.
+type countingRepo struct {
+	app.ProductRepo
+	mu       sync.Mutex
+	products map[string]domain.Product
+	gets     atomic.Int64
+	batches  atomic.Int64
+	block    chan struct{}
+	entered  chan struct{}
+}
+
+func (r *countingRepo) Get(ctx context.Context, id string) (domain.Product, error) {
+	r.gets.Add(1)
+	if r.block != nil {
+		if r.entered != nil {
+			r.entered <- struct{}{}
+		}
+		<-r.block
+	}
+	if err := ctx.Err(); err != nil {
+		return domain.Product{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.products[id]
+	if !ok {
+		return domain.Product{}, app.ErrNotFound
+	}
+	return p, nil
+}
+
+func (r *countingRepo) BatchGet(ctx context.Context, ids []string) (map[string]domain.Product, error) {
+	r.batches.Add(1)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]domain.Product)
+	for _, id := range ids {
+		if p, ok := r.products[id]; ok {
+			out[id] = p
+		}
+	}
+	return out, nil
+}
+
+func (r *countingRepo) Update(ctx context.Context, p domain.Product) (domain.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.products[p.ID] = p
+	return p, nil
+}
+
+func newCountingRepo() *countingRepo {
+	return &countingRepo{products: map[string]domain.Product{
+		"p1": {ID: "p1", Name: "Keyboard", Version: 1},
+		"p2": {ID: "p2", Name: "Mouse", Version: 1},
+		"p3": {ID: "p3", Name: "Monitor", Version: 1},
+	}}
+}
+
+func TestProductRepoReadThrough(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepo()
+	repo := NewProductRepo(inner, 2, time.Minute, nil)
+
+	for i := 0; i < 3; i++ {
+		if _, err := repo.Get(ctx, "p1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inner.gets.Load() != 1 {
+		t.Fatalf("expected one read, got %d", inner.gets.Load())
+	}
+
+	// p1 was used last before p3 arrives, so p2 is evicted.
+	repo.Get(ctx, "p2")
+	repo.Get(ctx, "p1")
+	repo.Get(ctx, "p3")
+	repo.Get(ctx, "p1")
+	repo.Get(ctx, "p2")
+	if inner.gets.Load() != 4 {
+		t.Fatalf("expected p2 evicted and reloaded, got %d reads", inner.gets.Load())
+	}
+
+	var metrics strings.Builder
+	repo.WriteMetrics(&metrics)
+	for _, want := range []string{`result="local_hit"} 4`, `result="miss"} 4`, "catalog_product_cache_entries 2"} {
+		if !strings.Contains(metrics.String(), want) {
+			t.Fatalf("expected %q in metrics:\n%s", want, metrics.String())
+		}
+	}
+}
+
+func TestProductRepoExpiry(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepo()
+	repo := NewProductRepo(inner, 10, time.Minute, nil)
+	now := time.Now()
+	repo.local.now = func() time.Time { return now }
+
+	repo.Get(ctx, "p1")
+	now = now.Add(2 * time.Minute)
+	repo.Get(ctx, "p1")
+	if inner.gets.Load() != 2 {
+		t.Fatalf("expected expired entry to be reloaded, got %d reads", inner.gets.Load())
+	}
+}
+
+func TestProductRepoSingleflight(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepo()
+	inner.block = make(chan struct{})
+	inner.entered = make(chan struct{}, 1)
+	repo := NewProductRepo(inner, 10, time.Minute, nil)
+
+	var wg sync.WaitGroup
+	get := func(ctx context.Context) {
+		defer wg.Done()
+		if _, err := repo.Get(ctx, "p1"); err != nil {
+			t.Error(err)
+		}
+	}
+	wg.Add(1)
+	go get(ctx)
+	// Nothing is cached until the first read returns, so every later caller
+	// either joins it or, if it finished, hits the cache.
+	<-inner.entered
+	for i := 0; i < 19; i++ {
+		wg.Add(1)
+		go get(ctx)
+	}
+	close(inner.block)
+	wg.Wait()
+
+	if inner.gets.Load() != 1 {
+		t.Fatalf("expected concurrent misses to share one read, got %d", inner.gets.Load())
+	}
+
+	t.Run("a caller that goes away doesn't cancel the shared read", func(t *testing.T) {
+		inner.block = make(chan struct{})
+		repo.InvalidateAll(ctx)
+
+		first, cancel := context.WithCancel(ctx)
+		errs := make(chan error, 1)
+		go func() {
+			_, err := repo.Get(first, "p1")
+			errs <- err
+		}()
+		<-inner.entered
+
+		wg.Add(1)
+		go get(ctx)
+		cancel()
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the cancelled caller to stop waiting, got %v", err)
+		}
+		close(inner.block)
+		wg.Wait()
+	})
+}
+
+func TestProductRepoInvalidation(t *testing.T) {
+	ctx := context.Background()
+	inner := newCountingRepo()
+	repo := NewProductRepo(inner, 10, time.Minute, nil)
+
+	p, _ := repo.Get(ctx, "p1")
+	p.Name = "Mechanical keyboard"
+	if _, err := repo.Update(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := repo.Get(ctx, "p1")
+	if got.Name != "Mechanical keyboard" {
+		t.Fatalf("expected the update to be visible, got %q", got.Name)
+	}
+
+	t.Run("batch reads fill and use the cache", func(t *testing.T) {
+		got, err := repo.BatchGet(ctx, []string{"p1", "p2", "gone"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || inner.batches.Load() != 1 {
+			t.Fatalf("expected p1 from cache and p2 from one batch, got %v after %d batches", got, inner.batches.Load())
+		}
+		if _, err := repo.BatchGet(ctx, []string{"p1", "p2"}); err != nil {
+			t.Fatal(err)
+		}
+		if inner.batches.Load() != 1 {
+			t.Fatalf("expected a fully cached batch, got %d batches", inner.batches.Load())
+		}
+	})
+
+	t.Run("a read racing an update isn't cached", func(t *testing.T) {
+		repo.InvalidateAll(ctx)
+		gen := repo.gen.Load()
+		stale := domain.Product{ID: "p3", Name: "stale"}
+		repo.Invalidate(ctx, "p3")
+		repo.store(ctx, gen, map[string]domain.Product{cacheKey("p3"): stale}, true)
+
+		if _, ok := repo.local.get(cacheKey("p3")); ok {
+			t.Fatal("expected the stale read to be dropped")
+		}
+	})
+}
+
+// txRepo holds updates back until the transaction commits, like Postgres
+// hides them from other connections.
+type txRepo struct {
+	*countingRepo
+	pending []domain.Product
+}
+
+func (r *txRepo) Update(ctx context.Context, p domain.Product) (domain.Product, error) {
+	r.pending = append(r.pending, p)
+	return p, nil
+}
+
+func (r *txRepo) commit() error {
+	for _, p := range r.pending {
+		if _, err := r.countingRepo.Update(context.Background(), p); err != nil {
+			return err
+		}
+	}
+	r.pending = nil
+	return nil
+}
+
+// commitConnector's transactions call commit when they commit.
+type commitConnector struct {
+	commit func() error
+}
+
+func (c commitConnector) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c commitConnector) Driver() driver.Driver                        { return nil }
+func (c commitConnector) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (c commitConnector) Close() error              { return nil }
+func (c commitConnector) Begin() (driver.Tx, error) { return c, nil }
+func (c commitConnector) Commit() error             { return c.commit() }
+func (c commitConnector) Rollback() error           { return nil }
+
+func TestProductRepoInvalidatesAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	inner := &txRepo{countingRepo: newCountingRepo()}
+	repo := NewProductRepo(inner, 10, time.Minute, nil)
+	db := sql.OpenDB(commitConnector{commit: inner.commit})
+	defer db.Close()
+	tx := pg.NewTxManager(db)
+
+	err := tx.WithinTx(ctx, func(txCtx context.Context) error {
+		p, _ := repo.Get(txCtx, "p1")
+		p.Name = "Mechanical keyboard"
+		if _, err := repo.Update(txCtx, p); err != nil {
+			return err
+		}
+		// Another request reads before the commit and sees the old row.
+		got, _ := repo.Get(ctx, "p1")
+		if got.Name != "Keyboard" {
+			t.Fatalf("expected the uncommitted update to be hidden, got %q", got.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := repo.Get(ctx, "p1")
+	if got.Name != "Mechanical keyboard" {
+		t.Fatalf("expected the committed update to be visible, got %q", got.Name)
+	}
+}
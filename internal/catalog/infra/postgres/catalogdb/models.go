@@ -5,17 +5,68 @@
 package catalogdb
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Product struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Currency    string    `json:"currency"`
-	PriceAmount int64     `json:"price_amount"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Currency    string         `json:"currency"`
+	PriceAmount int64          `json:"price_amount"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	Version     int64          `json:"version"`
+	ArchivedAt  sql.NullTime   `json:"archived_at"`
+	ExternalSku sql.NullString `json:"external_sku"`
+	RatingAvg   float64        `json:"rating_avg"`
+	RatingCount int32          `json:"rating_count"`
+	WeightGrams int32          `json:"weight_grams"`
+}
+
+type Category struct {
+	ID        uuid.UUID     `json:"id"`
+	ParentID  uuid.NullUUID `json:"parent_id"`
+	Name      string        `json:"name"`
+	Slug      string        `json:"slug"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type ProductCategory struct {
+	ProductID  uuid.UUID `json:"product_id"`
+	CategoryID uuid.UUID `json:"category_id"`
+}
+
+type ProductOption struct {
+	ProductID    uuid.UUID       `json:"product_id"`
+	Name         string          `json:"name"`
+	Position     int32           `json:"position"`
+	OptionValues json.RawMessage `json:"option_values"`
+}
+
+type ProductVariant struct {
+	ID          uuid.UUID       `json:"id"`
+	ProductID   uuid.UUID       `json:"product_id"`
+	Sku         string          `json:"sku"`
+	Options     json.RawMessage `json:"options"`
+	Currency    string          `json:"currency"`
+	PriceAmount int64           `json:"price_amount"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type ProductPrice struct {
+	ID            uuid.UUID    `json:"id"`
+	ProductID     uuid.UUID    `json:"product_id"`
+	Currency      string       `json:"currency"`
+	Amount        int64        `json:"amount"`
+	EffectiveFrom time.Time    `json:"effective_from"`
+	EffectiveTo   sql.NullTime `json:"effective_to"`
+	Reason        string       `json:"reason"`
+	CreatedAt     time.Time    `json:"created_at"`
 }
@@ -7,15 +7,91 @@ package catalogdb
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const archiveProduct = `-- name: ArchiveProduct :one
+UPDATE products
+SET archived_at = now(),
+    version     = version + 1,
+    updated_at  = now()
+WHERE id = $1
+  AND archived_at IS NULL
+    RETURNING id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
+`
+
+func (q *Queries) ArchiveProduct(ctx context.Context, id uuid.UUID) (Product, error) {
+	row := q.db.QueryRowContext(ctx, archiveProduct, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Currency,
+		&i.PriceAmount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.ArchivedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.WeightGrams,
+	)
+	return i, err
+}
+
+const batchGetProducts = `-- name: BatchGetProducts :many
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
+FROM products
+WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) BatchGetProducts(ctx context.Context, ids []uuid.UUID) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, batchGetProducts, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Currency,
+			&i.PriceAmount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.ArchivedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.WeightGrams,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createProduct = `-- name: CreateProduct :one
 
-INSERT INTO products (name, description, currency, price_amount)
-VALUES ($1, $2, $3, $4)
-    RETURNING id, name, description, currency, price_amount, created_at, updated_at
+INSERT INTO products (name, description, currency, price_amount, weight_grams)
+VALUES ($1, $2, $3, $4, $5)
+    RETURNING id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
 `
 
 type CreateProductParams struct {
@@ -23,6 +99,7 @@ type CreateProductParams struct {
 	Description string `json:"description"`
 	Currency    string `json:"currency"`
 	PriceAmount int64  `json:"price_amount"`
+	WeightGrams int32  `json:"weight_grams"`
 }
 
 // internal/catalog/infra/postgres/queries/products.sql
@@ -32,6 +109,7 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		arg.Description,
 		arg.Currency,
 		arg.PriceAmount,
+		arg.WeightGrams,
 	)
 	var i Product
 	err := row.Scan(
@@ -42,12 +120,64 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.PriceAmount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.ArchivedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.WeightGrams,
 	)
 	return i, err
 }
 
+const deleteProduct = `-- name: DeleteProduct :execrows
+DELETE FROM products
+WHERE id = $1
+`
+
+func (q *Queries) DeleteProduct(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteProduct, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const estimateProducts = `-- name: EstimateProducts :one
+SELECT estimate_products(
+    $1::text,
+    $2::uuid,
+    $3::text,
+    $4::bigint,
+    $5::bigint
+)::bigint AS estimate
+`
+
+type EstimateProductsParams struct {
+	Query      string        `json:"query"`
+	CategoryID uuid.NullUUID `json:"category_id"`
+	Currency   string        `json:"currency"`
+	MinPrice   int64         `json:"min_price"`
+	MaxPrice   int64         `json:"max_price"`
+}
+
+// The planner's estimate of how many products match the list filters; see
+// estimate_products.
+func (q *Queries) EstimateProducts(ctx context.Context, arg EstimateProductsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, estimateProducts,
+		arg.Query,
+		arg.CategoryID,
+		arg.Currency,
+		arg.MinPrice,
+		arg.MaxPrice,
+	)
+	var estimate int64
+	err := row.Scan(&estimate)
+	return estimate, err
+}
+
 const getProduct = `-- name: GetProduct :one
-SELECT id, name, description, currency, price_amount, created_at, updated_at
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
 FROM products
 WHERE id = $1
 `
@@ -63,31 +193,320 @@ func (q *Queries) GetProduct(ctx context.Context, id uuid.UUID) (Product, error)
 		&i.PriceAmount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.ArchivedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.WeightGrams,
 	)
 	return i, err
 }
 
-const listProducts = `-- name: ListProducts :many
-SELECT id, name, description, currency, price_amount, created_at, updated_at
+const listProductsByCreatedAt = `-- name: ListProductsByCreatedAt :many
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
+FROM products
+WHERE archived_at IS NULL
+  AND ($1::text = ''
+      OR name ILIKE '%' || $1::text || '%'
+      OR name % $1::text)
+  AND ($2::uuid IS NULL OR id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT c.id FROM categories c WHERE c.id = $2::uuid
+          UNION ALL
+          SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+      )
+      SELECT pc.product_id FROM product_categories pc JOIN subtree ON pc.category_id = subtree.id
+  ))
+  AND ($3::text = '' OR currency = $3::text)
+  AND ($4::bigint = 0 OR price_amount >= $4::bigint)
+  AND ($5::bigint = 0 OR price_amount <= $5::bigint)
+  AND (NOT $6::boolean
+      OR (created_at, id) < ($7::timestamptz, $8::uuid))
+ORDER BY created_at DESC, id DESC
+    LIMIT $9
+`
+
+type ListProductsByCreatedAtParams struct {
+	Query           string        `json:"query"`
+	CategoryID      uuid.NullUUID `json:"category_id"`
+	Currency        string        `json:"currency"`
+	MinPrice        int64         `json:"min_price"`
+	MaxPrice        int64         `json:"max_price"`
+	UseCursor       bool          `json:"use_cursor"`
+	CursorCreatedAt time.Time     `json:"cursor_created_at"`
+	CursorID        uuid.UUID     `json:"cursor_id"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+// The product list queries share their filters and differ in sort order,
+// each with a query of its own so that the index for that order can serve
+// the ORDER BY. Keyset pagination: the cursor holds the sort key and id of
+// the last row of the previous page, compared in the same direction as
+// ORDER BY. This one lists the newest first, using
+// idx_products_active_created_at.
+func (q *Queries) ListProductsByCreatedAt(ctx context.Context, arg ListProductsByCreatedAtParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listProductsByCreatedAt,
+		arg.Query,
+		arg.CategoryID,
+		arg.Currency,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.UseCursor,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Currency,
+			&i.PriceAmount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.ArchivedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.WeightGrams,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsByPriceAsc = `-- name: ListProductsByPriceAsc :many
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
+FROM products
+WHERE archived_at IS NULL
+  AND ($1::text = ''
+      OR name ILIKE '%' || $1::text || '%'
+      OR name % $1::text)
+  AND ($2::uuid IS NULL OR id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT c.id FROM categories c WHERE c.id = $2::uuid
+          UNION ALL
+          SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+      )
+      SELECT pc.product_id FROM product_categories pc JOIN subtree ON pc.category_id = subtree.id
+  ))
+  AND ($3::text = '' OR currency = $3::text)
+  AND ($4::bigint = 0 OR price_amount >= $4::bigint)
+  AND ($5::bigint = 0 OR price_amount <= $5::bigint)
+  AND (NOT $6::boolean
+      OR (price_amount, id) > ($7::bigint, $8::uuid))
+ORDER BY price_amount ASC, id ASC
+    LIMIT $9
+`
+
+type ListProductsByPriceAscParams struct {
+	Query       string        `json:"query"`
+	CategoryID  uuid.NullUUID `json:"category_id"`
+	Currency    string        `json:"currency"`
+	MinPrice    int64         `json:"min_price"`
+	MaxPrice    int64         `json:"max_price"`
+	UseCursor   bool          `json:"use_cursor"`
+	CursorPrice int64         `json:"cursor_price"`
+	CursorID    uuid.UUID     `json:"cursor_id"`
+	PageLimit   int32         `json:"page_limit"`
+}
+
+// Cheapest first, using idx_products_active_price.
+func (q *Queries) ListProductsByPriceAsc(ctx context.Context, arg ListProductsByPriceAscParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listProductsByPriceAsc,
+		arg.Query,
+		arg.CategoryID,
+		arg.Currency,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.UseCursor,
+		arg.CursorPrice,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Currency,
+			&i.PriceAmount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.ArchivedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.WeightGrams,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsByPriceDesc = `-- name: ListProductsByPriceDesc :many
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
+FROM products
+WHERE archived_at IS NULL
+  AND ($1::text = ''
+      OR name ILIKE '%' || $1::text || '%'
+      OR name % $1::text)
+  AND ($2::uuid IS NULL OR id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT c.id FROM categories c WHERE c.id = $2::uuid
+          UNION ALL
+          SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+      )
+      SELECT pc.product_id FROM product_categories pc JOIN subtree ON pc.category_id = subtree.id
+  ))
+  AND ($3::text = '' OR currency = $3::text)
+  AND ($4::bigint = 0 OR price_amount >= $4::bigint)
+  AND ($5::bigint = 0 OR price_amount <= $5::bigint)
+  AND (NOT $6::boolean
+      OR (price_amount, id) < ($7::bigint, $8::uuid))
+ORDER BY price_amount DESC, id DESC
+    LIMIT $9
+`
+
+type ListProductsByPriceDescParams struct {
+	Query       string        `json:"query"`
+	CategoryID  uuid.NullUUID `json:"category_id"`
+	Currency    string        `json:"currency"`
+	MinPrice    int64         `json:"min_price"`
+	MaxPrice    int64         `json:"max_price"`
+	UseCursor   bool          `json:"use_cursor"`
+	CursorPrice int64         `json:"cursor_price"`
+	CursorID    uuid.UUID     `json:"cursor_id"`
+	PageLimit   int32         `json:"page_limit"`
+}
+
+// Most expensive first, scanning idx_products_active_price backwards.
+func (q *Queries) ListProductsByPriceDesc(ctx context.Context, arg ListProductsByPriceDescParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listProductsByPriceDesc,
+		arg.Query,
+		arg.CategoryID,
+		arg.Currency,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.UseCursor,
+		arg.CursorPrice,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Currency,
+			&i.PriceAmount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.ArchivedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.WeightGrams,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsByRating = `-- name: ListProductsByRating :many
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
 FROM products
-WHERE ($1 = '' OR name ILIKE '%' || $1 || '%')
-  AND ($2 = false OR id < $3)
-ORDER BY id DESC
-    LIMIT $4
+WHERE archived_at IS NULL
+  AND ($1::text = ''
+      OR name ILIKE '%' || $1::text || '%'
+      OR name % $1::text)
+  AND ($2::uuid IS NULL OR id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT c.id FROM categories c WHERE c.id = $2::uuid
+          UNION ALL
+          SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+      )
+      SELECT pc.product_id FROM product_categories pc JOIN subtree ON pc.category_id = subtree.id
+  ))
+  AND ($3::text = '' OR currency = $3::text)
+  AND ($4::bigint = 0 OR price_amount >= $4::bigint)
+  AND ($5::bigint = 0 OR price_amount <= $5::bigint)
+  AND (NOT $6::boolean
+      OR (rating_avg, id) < ($7::float8, $8::uuid))
+ORDER BY rating_avg DESC, id DESC
+    LIMIT $9
 `
 
-type ListProductsParams struct {
-	Query     interface{} `json:"query"`
-	UseCursor interface{} `json:"use_cursor"`
-	Cursor    uuid.UUID   `json:"cursor"`
-	PageLimit int32       `json:"page_limit"`
+type ListProductsByRatingParams struct {
+	Query        string        `json:"query"`
+	CategoryID   uuid.NullUUID `json:"category_id"`
+	Currency     string        `json:"currency"`
+	MinPrice     int64         `json:"min_price"`
+	MaxPrice     int64         `json:"max_price"`
+	UseCursor    bool          `json:"use_cursor"`
+	CursorRating float64       `json:"cursor_rating"`
+	CursorID     uuid.UUID     `json:"cursor_id"`
+	PageLimit    int32         `json:"page_limit"`
 }
 
-func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]Product, error) {
-	rows, err := q.db.QueryContext(ctx, listProducts,
+// Best rated first, using idx_products_rating.
+func (q *Queries) ListProductsByRating(ctx context.Context, arg ListProductsByRatingParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listProductsByRating,
 		arg.Query,
+		arg.CategoryID,
+		arg.Currency,
+		arg.MinPrice,
+		arg.MaxPrice,
 		arg.UseCursor,
-		arg.Cursor,
+		arg.CursorRating,
+		arg.CursorID,
 		arg.PageLimit,
 	)
 	if err != nil {
@@ -105,6 +524,167 @@ func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]P
 			&i.PriceAmount,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.ArchivedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.WeightGrams,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsByRelevance = `-- name: ListProductsByRelevance :many
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams,
+       similarity(name, $1::text)::real AS score
+FROM products
+WHERE archived_at IS NULL
+  AND ($1::text = ''
+      OR name ILIKE '%' || $1::text || '%'
+      OR name % $1::text)
+  AND ($2::uuid IS NULL OR id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT c.id FROM categories c WHERE c.id = $2::uuid
+          UNION ALL
+          SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+      )
+      SELECT pc.product_id FROM product_categories pc JOIN subtree ON pc.category_id = subtree.id
+  ))
+  AND ($3::text = '' OR currency = $3::text)
+  AND ($4::bigint = 0 OR price_amount >= $4::bigint)
+  AND ($5::bigint = 0 OR price_amount <= $5::bigint)
+  AND (NOT $6::boolean
+      OR (similarity(name, $1::text), id) < ($7::real, $8::uuid))
+ORDER BY similarity(name, $1::text) DESC, id DESC
+    LIMIT $9
+`
+
+type ListProductsByRelevanceParams struct {
+	Query       string        `json:"query"`
+	CategoryID  uuid.NullUUID `json:"category_id"`
+	Currency    string        `json:"currency"`
+	MinPrice    int64         `json:"min_price"`
+	MaxPrice    int64         `json:"max_price"`
+	UseCursor   bool          `json:"use_cursor"`
+	CursorScore float32       `json:"cursor_score"`
+	CursorID    uuid.UUID     `json:"cursor_id"`
+	PageLimit   int32         `json:"page_limit"`
+}
+
+type ListProductsByRelevanceRow struct {
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Currency    string         `json:"currency"`
+	PriceAmount int64          `json:"price_amount"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	Version     int64          `json:"version"`
+	ArchivedAt  sql.NullTime   `json:"archived_at"`
+	ExternalSku sql.NullString `json:"external_sku"`
+	RatingAvg   float64        `json:"rating_avg"`
+	RatingCount int32          `json:"rating_count"`
+	WeightGrams int32          `json:"weight_grams"`
+	Score       float32        `json:"score"`
+}
+
+// Closest name match first. No index serves this order; the trigram index
+// narrows the rows to the matches instead.
+func (q *Queries) ListProductsByRelevance(ctx context.Context, arg ListProductsByRelevanceParams) ([]ListProductsByRelevanceRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProductsByRelevance,
+		arg.Query,
+		arg.CategoryID,
+		arg.Currency,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.UseCursor,
+		arg.CursorScore,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProductsByRelevanceRow
+	for rows.Next() {
+		var i ListProductsByRelevanceRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Currency,
+			&i.PriceAmount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.ArchivedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.WeightGrams,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsForExport = `-- name: ListProductsForExport :many
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
+FROM products
+WHERE archived_at IS NULL
+  AND id > $1::uuid
+ORDER BY id
+    LIMIT $2
+`
+
+type ListProductsForExportParams struct {
+	AfterID   uuid.UUID `json:"after_id"`
+	PageLimit int32     `json:"page_limit"`
+}
+
+func (q *Queries) ListProductsForExport(ctx context.Context, arg ListProductsForExportParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listProductsForExport, arg.AfterID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Currency,
+			&i.PriceAmount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.ArchivedAt,
+			&i.ExternalSku,
+			&i.RatingAvg,
+			&i.RatingCount,
+			&i.WeightGrams,
 		); err != nil {
 			return nil, err
 		}
@@ -118,3 +698,149 @@ func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]P
 	}
 	return items, nil
 }
+
+const setProductRating = `-- name: SetProductRating :execrows
+UPDATE products
+SET rating_avg   = $1,
+    rating_count = $2
+WHERE id = $3
+`
+
+type SetProductRatingParams struct {
+	RatingAvg   float64   `json:"rating_avg"`
+	RatingCount int32     `json:"rating_count"`
+	ID          uuid.UUID `json:"id"`
+}
+
+// Ratings are derived from reviews, so this doesn't bump the version.
+func (q *Queries) SetProductRating(ctx context.Context, arg SetProductRatingParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setProductRating, arg.RatingAvg, arg.RatingCount, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateProduct = `-- name: UpdateProduct :one
+UPDATE products
+SET name         = $1,
+    description  = $2,
+    currency     = $3,
+    price_amount = $4,
+    weight_grams = $5,
+    version      = version + 1,
+    updated_at   = now()
+WHERE id = $6
+  AND version = $7
+    RETURNING id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
+`
+
+type UpdateProductParams struct {
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	Currency        string    `json:"currency"`
+	PriceAmount     int64     `json:"price_amount"`
+	WeightGrams     int32     `json:"weight_grams"`
+	ID              uuid.UUID `json:"id"`
+	ExpectedVersion int64     `json:"expected_version"`
+}
+
+func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, updateProduct,
+		arg.Name,
+		arg.Description,
+		arg.Currency,
+		arg.PriceAmount,
+		arg.WeightGrams,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Currency,
+		&i.PriceAmount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.ArchivedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.WeightGrams,
+	)
+	return i, err
+}
+
+const upsertProductBySKU = `-- name: UpsertProductBySKU :one
+INSERT INTO products (external_sku, name, description, currency, price_amount, weight_grams)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (external_sku) DO UPDATE
+SET name         = EXCLUDED.name,
+    description  = EXCLUDED.description,
+    currency     = EXCLUDED.currency,
+    price_amount = EXCLUDED.price_amount,
+    weight_grams = EXCLUDED.weight_grams,
+    version      = products.version + 1,
+    updated_at   = now()
+    RETURNING id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams,
+    (xmax = 0)::boolean AS inserted
+`
+
+type UpsertProductBySKUParams struct {
+	ExternalSku sql.NullString `json:"external_sku"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Currency    string         `json:"currency"`
+	PriceAmount int64          `json:"price_amount"`
+	WeightGrams int32          `json:"weight_grams"`
+}
+
+type UpsertProductBySKURow struct {
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Currency    string         `json:"currency"`
+	PriceAmount int64          `json:"price_amount"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	Version     int64          `json:"version"`
+	ArchivedAt  sql.NullTime   `json:"archived_at"`
+	ExternalSku sql.NullString `json:"external_sku"`
+	RatingAvg   float64        `json:"rating_avg"`
+	RatingCount int32          `json:"rating_count"`
+	WeightGrams int32          `json:"weight_grams"`
+	Inserted    bool           `json:"inserted"`
+}
+
+// inserted is false when an existing product with the SKU was updated.
+func (q *Queries) UpsertProductBySKU(ctx context.Context, arg UpsertProductBySKUParams) (UpsertProductBySKURow, error) {
+	row := q.db.QueryRowContext(ctx, upsertProductBySKU,
+		arg.ExternalSku,
+		arg.Name,
+		arg.Description,
+		arg.Currency,
+		arg.PriceAmount,
+		arg.WeightGrams,
+	)
+	var i UpsertProductBySKURow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Currency,
+		&i.PriceAmount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.ArchivedAt,
+		&i.ExternalSku,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.WeightGrams,
+		&i.Inserted,
+	)
+	return i, err
+}
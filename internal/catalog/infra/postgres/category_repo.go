@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/app"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/infra/postgres/catalogdb"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type CategoryRepo struct {
+	q  *catalogdb.Queries
+	db *sql.DB
+}
+
+func NewCategoryRepo(db *sql.DB) *CategoryRepo {
+	return &CategoryRepo{q: catalogdb.New(db), db: db}
+}
+
+// execTX runs fn in its own transaction, or in the caller's transaction when
+// ctx carries one (see pg.TxManager).
+func (r *CategoryRepo) execTX(ctx context.Context, fn func(q *catalogdb.Queries) error) error {
+	return pg.ExecTx(ctx, r.db, func(tx *sql.Tx) error {
+		return fn(r.q.WithTx(tx))
+	})
+}
+
+func (r *CategoryRepo) Create(ctx context.Context, c domain.Category) (domain.Category, error) {
+	parentID, err := parseNullUUID(c.ParentID)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	row, err := r.q.CreateCategory(ctx, catalogdb.CreateCategoryParams{
+		ParentID: parentID,
+		Name:     c.Name,
+		Slug:     c.Slug,
+	})
+	if err != nil {
+		return domain.Category{}, mapCategoryErr(err)
+	}
+	return toDomainCategory(row), nil
+}
+
+func (r *CategoryRepo) Get(ctx context.Context, id string) (domain.Category, error) {
+	catID, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return domain.Category{}, app.ErrInvalidInput
+	}
+
+	row, err := r.q.GetCategory(ctx, catID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Category{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.Category{}, err
+	}
+	return toDomainCategory(row), nil
+}
+
+func (r *CategoryRepo) List(ctx context.Context) ([]domain.Category, error) {
+	rows, err := r.q.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.Category, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toDomainCategory(row))
+	}
+	return out, nil
+}
+
+func (r *CategoryRepo) Update(ctx context.Context, c domain.Category) (domain.Category, error) {
+	catID, err := uuid.Parse(strings.TrimSpace(c.ID))
+	if err != nil {
+		return domain.Category{}, app.ErrInvalidInput
+	}
+	parentID, err := parseNullUUID(c.ParentID)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	row, err := r.q.UpdateCategory(ctx, catalogdb.UpdateCategoryParams{
+		ParentID: parentID,
+		Name:     c.Name,
+		Slug:     c.Slug,
+		ID:       catID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Category{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.Category{}, mapCategoryErr(err)
+	}
+	return toDomainCategory(row), nil
+}
+
+func (r *CategoryRepo) Delete(ctx context.Context, id string) error {
+	catID, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return app.ErrInvalidInput
+	}
+
+	n, err := r.q.DeleteCategory(ctx, catID)
+	if err != nil {
+		if pg.IsForeignKeyViolation(err) {
+			return app.ErrCategoryHasChildren
+		}
+		return err
+	}
+	if n == 0 {
+		return app.ErrNotFound
+	}
+	return nil
+}
+
+func (r *CategoryRepo) InSubtree(ctx context.Context, rootID, candidateID string) (bool, error) {
+	root, err := uuid.Parse(strings.TrimSpace(rootID))
+	if err != nil {
+		return false, app.ErrInvalidInput
+	}
+	candidate, err := uuid.Parse(strings.TrimSpace(candidateID))
+	if err != nil {
+		return false, app.ErrInvalidInput
+	}
+
+	return r.q.IsCategoryInSubtree(ctx, catalogdb.IsCategoryInSubtreeParams{
+		Root:      root,
+		Candidate: candidate,
+	})
+}
+
+func (r *CategoryRepo) SetProductCategories(ctx context.Context, productID string, categoryIDs []string) error {
+	prodID, err := uuid.Parse(strings.TrimSpace(productID))
+	if err != nil {
+		return app.ErrInvalidInput
+	}
+	catIDs := make([]uuid.UUID, 0, len(categoryIDs))
+	for _, id := range categoryIDs {
+		catID, err := uuid.Parse(strings.TrimSpace(id))
+		if err != nil {
+			return app.ErrInvalidInput
+		}
+		catIDs = append(catIDs, catID)
+	}
+
+	return r.execTX(ctx, func(q *catalogdb.Queries) error {
+		if err := q.DeleteProductCategories(ctx, prodID); err != nil {
+			return err
+		}
+		for _, catID := range catIDs {
+			err := q.AddProductCategory(ctx, catalogdb.AddProductCategoryParams{
+				ProductID:  prodID,
+				CategoryID: catID,
+			})
+			if pg.IsForeignKeyViolation(err) {
+				return app.ErrNotFound
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *CategoryRepo) ListProductCategories(ctx context.Context, productID string) ([]string, error) {
+	prodID, err := uuid.Parse(strings.TrimSpace(productID))
+	if err != nil {
+		return nil, app.ErrInvalidInput
+	}
+
+	ids, err := r.q.ListProductCategoryIDs(ctx, prodID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, id.String())
+	}
+	return out, nil
+}
+
+func (r *CategoryRepo) BatchListProductCategories(ctx context.Context, productIDs []string) (map[string][]string, error) {
+	rows, err := r.q.ListCategoryIDsForProducts(ctx, parseUUIDs(productIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]string)
+	for _, row := range rows {
+		id := row.ProductID.String()
+		out[id] = append(out[id], row.CategoryID.String())
+	}
+	return out, nil
+}
+
+func parseNullUUID(s string) (uuid.NullUUID, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return uuid.NullUUID{}, nil
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.NullUUID{}, app.ErrInvalidInput
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}, nil
+}
+
+func mapCategoryErr(err error) error {
+	if pg.IsUniqueViolation(err) {
+		return app.ErrSlugTaken
+	}
+	if pg.IsForeignKeyViolation(err) {
+		// the parent category does not exist
+		return app.ErrNotFound
+	}
+	return err
+}
+
+func toDomainCategory(row catalogdb.Category) domain.Category {
+	c := domain.Category{
+		ID:        row.ID.String(),
+		Name:      row.Name,
+		Slug:      row.Slug,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if row.ParentID.Valid {
+		c.ParentID = row.ParentID.UUID.String()
+	}
+	return c
+}
@@ -0,0 +1,6 @@
+-- version is bumped on every write and checked by UpdateProduct for
+-- optimistic concurrency. archived_at hides a product from listings while
+-- keeping it readable by ID.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS categories
+(
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- a category with children can't be deleted; move or delete them first
+    parent_id  UUID REFERENCES categories (id) ON DELETE RESTRICT,
+    name       TEXT        NOT NULL,
+    slug       TEXT        NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id
+    ON categories (parent_id);
+
+CREATE TABLE IF NOT EXISTS product_categories
+(
+    product_id  UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_categories_category_id
+    ON product_categories (category_id);
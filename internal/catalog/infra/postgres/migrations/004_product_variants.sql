@@ -0,0 +1,28 @@
+-- Option axes a product varies along, e.g. "size" with ["S","M","L"].
+-- option_values is a JSON array of the allowed values, in display order.
+CREATE TABLE IF NOT EXISTS product_options
+(
+    product_id    UUID        NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    name          TEXT        NOT NULL,
+    position      INT         NOT NULL,
+    option_values JSONB       NOT NULL,
+    PRIMARY KEY (product_id, name)
+);
+
+-- A variant is one sellable SKU of a product: one value per option axis and
+-- its own price. options is a JSON object of axis name -> value.
+CREATE TABLE IF NOT EXISTS product_variants
+(
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id   UUID        NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    sku          TEXT        NOT NULL UNIQUE,
+    options      JSONB       NOT NULL DEFAULT '{}',
+    currency     TEXT        NOT NULL,
+    price_amount BIGINT      NOT NULL CHECK (price_amount >= 0),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- two variants of a product can't share the same option values
+CREATE UNIQUE INDEX IF NOT EXISTS ux_product_variants_options
+    ON product_variants (product_id, options);
@@ -0,0 +1,7 @@
+-- Keyset indexes for the ListProducts sort orders. Relevance ranking uses
+-- idx_products_name_trgm from 001_init.sql.
+CREATE INDEX IF NOT EXISTS idx_products_active_price
+    ON products (price_amount, id) WHERE archived_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_active_created_at
+    ON products (created_at, id) WHERE archived_at IS NULL;
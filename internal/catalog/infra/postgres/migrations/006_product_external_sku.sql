@@ -0,0 +1,4 @@
+-- external_sku is the supplier's identifier for a product. Bulk imports
+-- upsert by it; products created one by one leave it NULL.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS external_sku TEXT UNIQUE;
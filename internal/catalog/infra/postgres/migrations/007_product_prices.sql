@@ -0,0 +1,28 @@
+-- product_prices keeps every price a product has had or will have.
+-- products.currency/price_amount hold a copy of the period active now, which
+-- the catalog worker refreshes when a period starts or ends.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS product_prices
+(
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id     UUID        NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    currency       TEXT        NOT NULL,
+    amount         BIGINT      NOT NULL CHECK (amount >= 0),
+    effective_from TIMESTAMPTZ NOT NULL,
+    -- NULL: until further notice
+    effective_to   TIMESTAMPTZ CHECK (effective_to > effective_from),
+    reason         TEXT        NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    -- a product has one price at any instant
+    EXCLUDE USING gist (product_id WITH =, tstzrange(effective_from, effective_to) WITH &&)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_prices_boundaries
+    ON product_prices (effective_from, effective_to);
+
+-- Existing products start with their current price.
+INSERT INTO product_prices (product_id, currency, amount, effective_from)
+SELECT p.id, p.currency, p.price_amount, p.created_at
+FROM products p
+WHERE NOT EXISTS (SELECT 1 FROM product_prices pp WHERE pp.product_id = p.id);
@@ -0,0 +1,10 @@
+-- rating_avg and rating_count summarize the approved reviews of a product.
+-- The review module recomputes them whenever a review is approved, rejected
+-- or edited; they aren't part of the product's version.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS rating_avg DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rating_count INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_products_rating
+    ON products (rating_avg DESC, id DESC)
+    WHERE archived_at IS NULL;
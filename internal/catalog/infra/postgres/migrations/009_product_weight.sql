@@ -0,0 +1,4 @@
+-- weight_grams is the shipping weight of one unit; variants share their
+-- product's weight. 0 means unknown, which shipping rates treat as weightless.
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS weight_grams INT NOT NULL DEFAULT 0 CHECK (weight_grams >= 0);
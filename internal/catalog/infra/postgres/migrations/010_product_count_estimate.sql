@@ -0,0 +1,40 @@
+-- estimate_products returns the planner's estimate of how many active
+-- products match the product list filters, without reading them. It backs
+-- the total_count of ListProducts, which only needs to be roughly right.
+-- The filters are inlined as literals so the planner estimates with the
+-- actual values.
+CREATE OR REPLACE FUNCTION estimate_products(
+    search      TEXT,
+    in_category UUID,
+    in_currency TEXT,
+    min_price   BIGINT,
+    max_price   BIGINT
+) RETURNS BIGINT
+LANGUAGE plpgsql AS $$
+DECLARE
+    plan JSON;
+BEGIN
+    EXECUTE format($q$
+        EXPLAIN (FORMAT JSON)
+        SELECT 1
+        FROM products
+        WHERE archived_at IS NULL
+          AND (%1$L = ''
+              OR name ILIKE '%%' || %1$L || '%%'
+              OR name %% %1$L)
+          AND (%2$L::uuid IS NULL OR id IN (
+              WITH RECURSIVE subtree AS (
+                  SELECT c.id FROM categories c WHERE c.id = %2$L::uuid
+                  UNION ALL
+                  SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+              )
+              SELECT pc.product_id FROM product_categories pc JOIN subtree ON pc.category_id = subtree.id
+          ))
+          AND (%3$L = '' OR currency = %3$L)
+          AND (%4$L::bigint = 0 OR price_amount >= %4$L::bigint)
+          AND (%5$L::bigint = 0 OR price_amount <= %5$L::bigint)
+    $q$, search, in_category, in_currency, min_price, max_price) INTO plan;
+
+    RETURN (plan -> 0 -> 'Plan' ->> 'Plan Rows')::BIGINT;
+END;
+$$;
@@ -0,0 +1,13 @@
+-- The catalog now stores ISO 4217 codes upper case and compares them as
+-- stored, so bring older rows saved as typed (e.g. "idr") in line.
+UPDATE products
+SET currency = upper(btrim(currency))
+WHERE currency <> upper(btrim(currency));
+
+UPDATE product_variants
+SET currency = upper(btrim(currency))
+WHERE currency <> upper(btrim(currency));
+
+UPDATE product_prices
+SET currency = upper(btrim(currency))
+WHERE currency <> upper(btrim(currency));
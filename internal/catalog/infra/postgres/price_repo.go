@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/app"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/infra/postgres/catalogdb"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type PriceRepo struct {
+	q  *catalogdb.Queries
+	db *sql.DB
+}
+
+func NewPriceRepo(db *sql.DB) *PriceRepo {
+	return &PriceRepo{q: catalogdb.New(db), db: db}
+}
+
+// execTX runs fn in its own transaction, or in the caller's transaction when
+// ctx carries one (see pg.TxManager).
+func (r *PriceRepo) execTX(ctx context.Context, fn func(q *catalogdb.Queries) error) error {
+	return pg.ExecTx(ctx, r.db, func(tx *sql.Tx) error {
+		return fn(r.q.WithTx(tx))
+	})
+}
+
+func (r *PriceRepo) Schedule(ctx context.Context, period domain.PricePeriod, now time.Time) (domain.PricePeriod, error) {
+	prodID, err := uuid.Parse(strings.TrimSpace(period.ProductID))
+	if err != nil {
+		return domain.PricePeriod{}, app.ErrInvalidInput
+	}
+
+	var row catalogdb.ProductPrice
+	err = r.execTX(ctx, func(q *catalogdb.Queries) error {
+		row, err = schedulePrice(ctx, q, prodID, period, now)
+		return err
+	})
+	if err != nil {
+		return domain.PricePeriod{}, err
+	}
+	return toDomainPrice(row), nil
+}
+
+func (r *PriceRepo) At(ctx context.Context, productID string, at time.Time) (domain.PricePeriod, error) {
+	prodID, err := uuid.Parse(strings.TrimSpace(productID))
+	if err != nil {
+		return domain.PricePeriod{}, app.ErrInvalidInput
+	}
+
+	row, err := r.q.GetProductPriceAt(ctx, catalogdb.GetProductPriceAtParams{ProductID: prodID, At: at})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PricePeriod{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.PricePeriod{}, err
+	}
+	return toDomainPrice(row), nil
+}
+
+func (r *PriceRepo) BatchAt(ctx context.Context, productIDs []string, at time.Time) (map[string]domain.PricePeriod, error) {
+	rows, err := r.q.ListProductPricesAt(ctx, catalogdb.ListProductPricesAtParams{
+		ProductIds: parseUUIDs(productIDs),
+		At:         at,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]domain.PricePeriod, len(rows))
+	for _, row := range rows {
+		out[row.ProductID.String()] = toDomainPrice(row)
+	}
+	return out, nil
+}
+
+func (r *PriceRepo) History(ctx context.Context, productID string) ([]domain.PricePeriod, error) {
+	prodID, err := uuid.Parse(strings.TrimSpace(productID))
+	if err != nil {
+		return nil, app.ErrInvalidInput
+	}
+
+	rows, err := r.q.ListProductPrices(ctx, prodID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.PricePeriod, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toDomainPrice(row))
+	}
+	return out, nil
+}
+
+func (r *PriceRepo) SyncCurrent(ctx context.Context, now time.Time) (int64, error) {
+	return r.q.SyncProductPrices(ctx, catalogdb.SyncProductPricesParams{At: now})
+}
+
+// schedulePrice splices period into the product's price periods and brings
+// the product's current price up to date. It must run in a transaction.
+func schedulePrice(ctx context.Context, q *catalogdb.Queries, productID uuid.UUID, period domain.PricePeriod, now time.Time) (catalogdb.ProductPrice, error) {
+	if _, err := q.LockProduct(ctx, productID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return catalogdb.ProductPrice{}, app.ErrNotFound
+		}
+		return catalogdb.ProductPrice{}, err
+	}
+
+	rows, err := q.ListProductPrices(ctx, productID)
+	if err != nil {
+		return catalogdb.ProductPrice{}, err
+	}
+	existing := make([]domain.PricePeriod, 0, len(rows))
+	for _, row := range rows {
+		existing = append(existing, toDomainPrice(row))
+	}
+
+	// Deletes first and inserts last: trimming only shrinks periods, so no
+	// step overlaps another period.
+	splice := domain.Splice(existing, period)
+	for _, id := range splice.Deleted {
+		if err := q.DeleteProductPrice(ctx, uuid.MustParse(id)); err != nil {
+			return catalogdb.ProductPrice{}, err
+		}
+	}
+	for _, p := range splice.Trimmed {
+		err := q.SetProductPriceRange(ctx, catalogdb.SetProductPriceRangeParams{
+			EffectiveFrom: p.EffectiveFrom,
+			EffectiveTo:   nullTime(p.EffectiveTo),
+			ID:            uuid.MustParse(p.ID),
+		})
+		if err != nil {
+			return catalogdb.ProductPrice{}, err
+		}
+	}
+
+	var inserted catalogdb.ProductPrice
+	for _, p := range splice.Added {
+		inserted, err = q.InsertProductPrice(ctx, catalogdb.InsertProductPriceParams{
+			ProductID:     productID,
+			Currency:      p.Price.Currency,
+			Amount:        p.Price.Amount,
+			EffectiveFrom: p.EffectiveFrom,
+			EffectiveTo:   nullTime(p.EffectiveTo),
+			Reason:        p.Reason,
+		})
+		if err != nil {
+			return catalogdb.ProductPrice{}, err
+		}
+	}
+
+	_, err = q.SyncProductPrices(ctx, catalogdb.SyncProductPricesParams{
+		ProductID: uuid.NullUUID{UUID: productID, Valid: true},
+		At:        now,
+	})
+	if err != nil {
+		return catalogdb.ProductPrice{}, err
+	}
+	// Splice adds the new period last.
+	return inserted, nil
+}
+
+// setCurrentPrice records a direct edit of the product's price: it replaces
+// the price from at until the active period would have ended, leaving
+// scheduled changes after that alone. It must run in a transaction.
+func setCurrentPrice(ctx context.Context, q *catalogdb.Queries, productID uuid.UUID, price domain.Money, at time.Time, reason string) error {
+	period := domain.PricePeriod{Price: price, EffectiveFrom: at, Reason: reason}
+
+	cur, err := q.GetProductPriceAt(ctx, catalogdb.GetProductPriceAtParams{ProductID: productID, At: at})
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return err
+	case cur.Currency == price.Currency && cur.Amount == price.Amount:
+		return nil
+	default:
+		if cur.EffectiveTo.Valid {
+			period.EffectiveTo = cur.EffectiveTo.Time
+		}
+	}
+
+	_, err = schedulePrice(ctx, q, productID, period, at)
+	return err
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func toDomainPrice(row catalogdb.ProductPrice) domain.PricePeriod {
+	p := domain.PricePeriod{
+		ID:        row.ID.String(),
+		ProductID: row.ProductID.String(),
+		Price: domain.Money{
+			Currency: row.Currency,
+			Amount:   row.Amount,
+		},
+		EffectiveFrom: row.EffectiveFrom,
+		Reason:        row.Reason,
+		CreatedAt:     row.CreatedAt,
+	}
+	if row.EffectiveTo.Valid {
+		p.EffectiveTo = row.EffectiveTo.Time
+	}
+	return p
+}
@@ -4,44 +4,61 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dwikikusuma/shoping-llm/internal/catalog/app"
 	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
 	"github.com/dwikikusuma/shoping-llm/internal/catalog/infra/postgres/catalogdb"
+	"github.com/dwikikusuma/shoping-llm/pkg/pagination"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
 	"github.com/google/uuid"
 )
 
 type ProductRepo struct {
-	q *catalogdb.Queries
+	q       *catalogdb.Queries
+	db      *sql.DB
+	cursors *pagination.Codec
 }
 
-func NewProductRepo(db *sql.DB) *ProductRepo {
-	return &ProductRepo{q: catalogdb.New(db)}
+func NewProductRepo(db *sql.DB, cursors *pagination.Codec) *ProductRepo {
+	return &ProductRepo{q: catalogdb.New(db), db: db, cursors: cursors}
+}
+
+// errPreview rolls back an UpsertBySKU that must not commit.
+var errPreview = errors.New("preview only")
+
+// execTX runs fn in its own transaction, or in the caller's transaction when
+// ctx carries one (see pg.TxManager).
+func (r *ProductRepo) execTX(ctx context.Context, fn func(q *catalogdb.Queries) error) error {
+	return pg.ExecTx(ctx, r.db, func(tx *sql.Tx) error {
+		return fn(r.q.WithTx(tx))
+	})
 }
 
 func (r *ProductRepo) Create(ctx context.Context, p domain.Product) (domain.Product, error) {
-	row, err := r.q.CreateProduct(ctx, catalogdb.CreateProductParams{
-		Name:        p.Name,
-		Description: p.Description,
-		PriceAmount: p.Price.Amount,
-		Currency:    p.Price.Currency,
+	var row catalogdb.Product
+	err := r.execTX(ctx, func(q *catalogdb.Queries) error {
+		var err error
+		row, err = q.CreateProduct(ctx, catalogdb.CreateProductParams{
+			Name:        p.Name,
+			Description: p.Description,
+			PriceAmount: p.Price.Amount,
+			Currency:    p.Price.Currency,
+			WeightGrams: p.WeightGrams,
+		})
+		if err != nil {
+			return err
+		}
+		return setCurrentPrice(ctx, q, row.ID, p.Price, row.CreatedAt, "initial price")
 	})
 	if err != nil {
 		return domain.Product{}, err
 	}
 
-	return domain.Product{
-		ID:          row.ID.String(),
-		Name:        row.Name,
-		Description: row.Description,
-		Price: domain.Money{
-			Amount:   row.PriceAmount,
-			Currency: row.Currency,
-		},
-		CreatedAt: row.CreatedAt,
-		UpdatedAt: row.UpdatedAt,
-	}, nil
+	return toDomainProduct(row), nil
 }
 
 func (r *ProductRepo) Get(ctx context.Context, id string) (domain.Product, error) {
@@ -58,59 +75,462 @@ func (r *ProductRepo) Get(ctx context.Context, id string) (domain.Product, error
 		return domain.Product{}, err
 	}
 
-	return domain.Product{
-		ID:          product.ID.String(),
-		Name:        product.Name,
-		Description: product.Description,
-		Price: domain.Money{
-			Amount:   product.PriceAmount,
-			Currency: product.Currency,
-		},
-		CreatedAt: product.CreatedAt,
-		UpdatedAt: product.UpdatedAt,
-	}, nil
+	return toDomainProduct(product), nil
 }
 
-func (r *ProductRepo) List(ctx context.Context, query string, limit int, cursor string) ([]domain.Product, string, error) {
-	useCursor := false
-	cursorUUID := uuid.Nil
+func (r *ProductRepo) BatchGet(ctx context.Context, ids []string) (map[string]domain.Product, error) {
+	rows, err := r.q.BatchGetProducts(ctx, parseUUIDs(ids))
+	if err != nil {
+		return nil, err
+	}
 
+	byID := make(map[uuid.UUID]domain.Product, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = toDomainProduct(row)
+	}
+	// Key by the IDs as given, which may differ from the stored ones in case.
+	out := make(map[string]domain.Product, len(rows))
+	for _, id := range ids {
+		u, err := uuid.Parse(strings.TrimSpace(id))
+		if err != nil {
+			continue
+		}
+		if p, ok := byID[u]; ok {
+			out[id] = p
+		}
+	}
+	return out, nil
+}
+
+// parseUUIDs parses ids for an ANY($1) lookup. An ID that isn't a UUID
+// can't match a row, so it is dropped rather than failing the batch.
+func parseUUIDs(ids []string) []uuid.UUID {
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if u, err := uuid.Parse(strings.TrimSpace(id)); err == nil {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+func (r *ProductRepo) List(ctx context.Context, filter domain.ProductFilter, limit int, cursor string) ([]domain.Product, string, error) {
+	categoryID, err := parseNullUUID(filter.CategoryID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	page := productPage{
+		query:      strings.TrimSpace(filter.Query),
+		categoryID: categoryID,
+		currency:   filter.Currency,
+		minPrice:   filter.MinPrice,
+		maxPrice:   filter.MaxPrice,
+		limit:      int32(limit),
+	}
+	filterHash := productFilterHash(filter)
 	if strings.TrimSpace(cursor) != "" {
-		uid, err := uuid.Parse(strings.TrimSpace(cursor))
+		c, err := r.cursors.Decode(cursor, filterHash)
 		if err != nil {
 			return nil, "", app.ErrInvalidInput
 		}
-		useCursor = true
-		cursorUUID = uid
+		if page.afterID, err = uuid.Parse(c.ID); err != nil {
+			return nil, "", app.ErrInvalidInput
+		}
+		page.after, page.afterKey = true, c.Key
 	}
 
-	rows, err := r.q.ListProducts(ctx, catalogdb.ListProductsParams{
-		Query:     strings.TrimSpace(query),
-		PageLimit: int32(limit),
-		UseCursor: useCursor,
-		Cursor:    cursorUUID,
-	})
+	rows, lastKey, err := r.listPage(ctx, filter.Sort, page)
 	if err != nil {
 		return nil, "", err
 	}
 
 	out := make([]domain.Product, 0, len(rows))
 	for _, row := range rows {
-		out = append(out, domain.Product{
-			ID:          row.ID.String(),
-			Name:        row.Name,
-			Description: row.Description,
-			Price:       domain.Money{Currency: row.Currency, Amount: row.PriceAmount},
-			CreatedAt:   row.CreatedAt,
-			UpdatedAt:   row.UpdatedAt,
-		})
+		out = append(out, toDomainProduct(row))
 	}
 
-	// next_cursor: return the last item's ID only when we returned a full page
+	// next_cursor: return the last item's sort key only when we returned a full page
 	nextCursor := ""
 	if len(rows) == limit && len(rows) > 0 {
-		nextCursor = rows[len(rows)-1].ID.String()
+		nextCursor = r.cursors.Encode(pagination.Cursor{
+			ID:     rows[len(rows)-1].ID.String(),
+			Key:    lastKey,
+			Filter: filterHash,
+		})
 	}
 
 	return out, nextCursor, nil
 }
+
+// productPage is what every product list query takes besides its sort key.
+type productPage struct {
+	query              string
+	categoryID         uuid.NullUUID
+	currency           string
+	minPrice, maxPrice int64
+	limit              int32
+
+	// after is set past the first page, which ends at the row with sort key
+	// afterKey and ID afterID.
+	after    bool
+	afterKey string
+	afterID  uuid.UUID
+}
+
+// listPage runs the list query for sort and returns its rows with the sort
+// key of the last one.
+func (r *ProductRepo) listPage(ctx context.Context, sort domain.ProductSort, p productPage) ([]catalogdb.Product, string, error) {
+	var (
+		rows []catalogdb.Product
+		err  error
+	)
+	switch sort {
+	case domain.SortPriceAsc, domain.SortPriceDesc:
+		var price int64
+		if p.after {
+			if price, err = strconv.ParseInt(p.afterKey, 10, 64); err != nil {
+				return nil, "", app.ErrInvalidInput
+			}
+		}
+		if sort == domain.SortPriceAsc {
+			rows, err = r.q.ListProductsByPriceAsc(ctx, catalogdb.ListProductsByPriceAscParams{
+				Query:       p.query,
+				CategoryID:  p.categoryID,
+				Currency:    p.currency,
+				MinPrice:    p.minPrice,
+				MaxPrice:    p.maxPrice,
+				UseCursor:   p.after,
+				CursorPrice: price,
+				CursorID:    p.afterID,
+				PageLimit:   p.limit,
+			})
+		} else {
+			rows, err = r.q.ListProductsByPriceDesc(ctx, catalogdb.ListProductsByPriceDescParams{
+				Query:       p.query,
+				CategoryID:  p.categoryID,
+				Currency:    p.currency,
+				MinPrice:    p.minPrice,
+				MaxPrice:    p.maxPrice,
+				UseCursor:   p.after,
+				CursorPrice: price,
+				CursorID:    p.afterID,
+				PageLimit:   p.limit,
+			})
+		}
+		if err != nil || len(rows) == 0 {
+			return nil, "", err
+		}
+		return rows, strconv.FormatInt(rows[len(rows)-1].PriceAmount, 10), nil
+
+	case domain.SortRating:
+		var rating float64
+		if p.after {
+			if rating, err = strconv.ParseFloat(p.afterKey, 64); err != nil {
+				return nil, "", app.ErrInvalidInput
+			}
+		}
+		rows, err = r.q.ListProductsByRating(ctx, catalogdb.ListProductsByRatingParams{
+			Query:        p.query,
+			CategoryID:   p.categoryID,
+			Currency:     p.currency,
+			MinPrice:     p.minPrice,
+			MaxPrice:     p.maxPrice,
+			UseCursor:    p.after,
+			CursorRating: rating,
+			CursorID:     p.afterID,
+			PageLimit:    p.limit,
+		})
+		if err != nil || len(rows) == 0 {
+			return nil, "", err
+		}
+		return rows, strconv.FormatFloat(rows[len(rows)-1].RatingAvg, 'g', -1, 64), nil
+
+	case domain.SortRelevance:
+		var score float64
+		if p.after {
+			if score, err = strconv.ParseFloat(p.afterKey, 32); err != nil {
+				return nil, "", app.ErrInvalidInput
+			}
+		}
+		scored, err := r.q.ListProductsByRelevance(ctx, catalogdb.ListProductsByRelevanceParams{
+			Query:       p.query,
+			CategoryID:  p.categoryID,
+			Currency:    p.currency,
+			MinPrice:    p.minPrice,
+			MaxPrice:    p.maxPrice,
+			UseCursor:   p.after,
+			CursorScore: float32(score),
+			CursorID:    p.afterID,
+			PageLimit:   p.limit,
+		})
+		if err != nil || len(scored) == 0 {
+			return nil, "", err
+		}
+		rows = make([]catalogdb.Product, 0, len(scored))
+		for _, row := range scored {
+			rows = append(rows, catalogdb.Product{
+				ID:          row.ID,
+				Name:        row.Name,
+				Description: row.Description,
+				Currency:    row.Currency,
+				PriceAmount: row.PriceAmount,
+				CreatedAt:   row.CreatedAt,
+				UpdatedAt:   row.UpdatedAt,
+				Version:     row.Version,
+				ArchivedAt:  row.ArchivedAt,
+				ExternalSku: row.ExternalSku,
+				RatingAvg:   row.RatingAvg,
+				RatingCount: row.RatingCount,
+				WeightGrams: row.WeightGrams,
+			})
+		}
+		return rows, strconv.FormatFloat(float64(scored[len(scored)-1].Score), 'g', -1, 32), nil
+
+	default:
+		var createdAt time.Time
+		if p.after {
+			micros, err := strconv.ParseInt(p.afterKey, 10, 64)
+			if err != nil {
+				return nil, "", app.ErrInvalidInput
+			}
+			createdAt = time.UnixMicro(micros)
+		}
+		rows, err = r.q.ListProductsByCreatedAt(ctx, catalogdb.ListProductsByCreatedAtParams{
+			Query:           p.query,
+			CategoryID:      p.categoryID,
+			Currency:        p.currency,
+			MinPrice:        p.minPrice,
+			MaxPrice:        p.maxPrice,
+			UseCursor:       p.after,
+			CursorCreatedAt: createdAt,
+			CursorID:        p.afterID,
+			PageLimit:       p.limit,
+		})
+		if err != nil || len(rows) == 0 {
+			return nil, "", err
+		}
+		return rows, strconv.FormatInt(rows[len(rows)-1].CreatedAt.UnixMicro(), 10), nil
+	}
+}
+
+func (r *ProductRepo) EstimateCount(ctx context.Context, filter domain.ProductFilter) (int64, error) {
+	categoryID, err := parseNullUUID(filter.CategoryID)
+	if err != nil {
+		return 0, err
+	}
+
+	return r.q.EstimateProducts(ctx, catalogdb.EstimateProductsParams{
+		Query:      strings.TrimSpace(filter.Query),
+		CategoryID: categoryID,
+		Currency:   filter.Currency,
+		MinPrice:   filter.MinPrice,
+		MaxPrice:   filter.MaxPrice,
+	})
+}
+
+// productFilterHash ties a cursor to the filters and sort it was issued for.
+func productFilterHash(f domain.ProductFilter) string {
+	return pagination.FilterHash(
+		strings.TrimSpace(f.Query),
+		strings.TrimSpace(f.CategoryID),
+		f.Currency,
+		strconv.FormatInt(f.MinPrice, 10),
+		strconv.FormatInt(f.MaxPrice, 10),
+		string(f.Sort),
+	)
+}
+
+func (r *ProductRepo) Update(ctx context.Context, p domain.Product) (domain.Product, error) {
+	prodID, err := uuid.Parse(strings.TrimSpace(p.ID))
+	if err != nil {
+		return domain.Product{}, app.ErrInvalidInput
+	}
+
+	var row catalogdb.Product
+	err = r.execTX(ctx, func(q *catalogdb.Queries) error {
+		var err error
+		row, err = q.UpdateProduct(ctx, catalogdb.UpdateProductParams{
+			Name:            p.Name,
+			Description:     p.Description,
+			Currency:        p.Price.Currency,
+			PriceAmount:     p.Price.Amount,
+			WeightGrams:     p.WeightGrams,
+			ID:              prodID,
+			ExpectedVersion: p.Version,
+		})
+		if err != nil {
+			return err
+		}
+		return setCurrentPrice(ctx, q, row.ID, p.Price, row.UpdatedAt, "price updated")
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Either the product is gone or someone else bumped the version.
+		if _, getErr := r.Get(ctx, p.ID); getErr != nil {
+			return domain.Product{}, getErr
+		}
+		return domain.Product{}, app.ErrVersionConflict
+	}
+	if err != nil {
+		return domain.Product{}, err
+	}
+	return toDomainProduct(row), nil
+}
+
+func (r *ProductRepo) Archive(ctx context.Context, id string) (domain.Product, error) {
+	prodID, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return domain.Product{}, app.ErrInvalidInput
+	}
+
+	row, err := r.q.ArchiveProduct(ctx, prodID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Missing, or already archived: Get tells which.
+		return r.Get(ctx, id)
+	}
+	if err != nil {
+		return domain.Product{}, err
+	}
+	return toDomainProduct(row), nil
+}
+
+func (r *ProductRepo) Delete(ctx context.Context, id string) error {
+	prodID, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return app.ErrInvalidInput
+	}
+
+	n, err := r.q.DeleteProduct(ctx, prodID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return app.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ProductRepo) SetRating(ctx context.Context, id string, rating domain.Rating) error {
+	prodID, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return app.ErrInvalidInput
+	}
+
+	// Join the caller's transaction: the review service writes the summary
+	// while it holds the product's rating lock.
+	var n int64
+	err = r.execTX(ctx, func(q *catalogdb.Queries) error {
+		var err error
+		n, err = q.SetProductRating(ctx, catalogdb.SetProductRatingParams{
+			RatingAvg:   rating.Average,
+			RatingCount: rating.Count,
+			ID:          prodID,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return app.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ProductRepo) UpsertBySKU(ctx context.Context, products []domain.Product, commit bool) ([]app.UpsertResult, error) {
+	out := make([]app.UpsertResult, 0, len(products))
+	err := r.execTX(ctx, func(q *catalogdb.Queries) error {
+		for _, p := range products {
+			row, err := q.UpsertProductBySKU(ctx, catalogdb.UpsertProductBySKUParams{
+				ExternalSku: sql.NullString{String: p.ExternalSKU, Valid: true},
+				Name:        p.Name,
+				Description: p.Description,
+				Currency:    p.Price.Currency,
+				PriceAmount: p.Price.Amount,
+				WeightGrams: p.WeightGrams,
+			})
+			if err != nil {
+				return fmt.Errorf("upsert sku %q: %w", p.ExternalSKU, err)
+			}
+			if err := setCurrentPrice(ctx, q, row.ID, p.Price, row.UpdatedAt, "imported"); err != nil {
+				return fmt.Errorf("record price of sku %q: %w", p.ExternalSKU, err)
+			}
+			out = append(out, app.UpsertResult{
+				Product: toDomainProduct(catalogdb.Product{
+					ID:          row.ID,
+					Name:        row.Name,
+					Description: row.Description,
+					Currency:    row.Currency,
+					PriceAmount: row.PriceAmount,
+					CreatedAt:   row.CreatedAt,
+					UpdatedAt:   row.UpdatedAt,
+					Version:     row.Version,
+					ArchivedAt:  row.ArchivedAt,
+					ExternalSku: row.ExternalSku,
+					RatingAvg:   row.RatingAvg,
+					RatingCount: row.RatingCount,
+					WeightGrams: row.WeightGrams,
+				}),
+				Created: row.Inserted,
+			})
+		}
+		if !commit {
+			return errPreview
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errPreview) {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *ProductRepo) ListForExport(ctx context.Context, afterID string, limit int) ([]domain.Product, error) {
+	after := uuid.Nil
+	if strings.TrimSpace(afterID) != "" {
+		id, err := uuid.Parse(strings.TrimSpace(afterID))
+		if err != nil {
+			return nil, app.ErrInvalidInput
+		}
+		after = id
+	}
+
+	rows, err := r.q.ListProductsForExport(ctx, catalogdb.ListProductsForExportParams{
+		AfterID:   after,
+		PageLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.Product, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toDomainProduct(row))
+	}
+	return out, nil
+}
+
+func toDomainProduct(row catalogdb.Product) domain.Product {
+	p := domain.Product{
+		ID:          row.ID.String(),
+		Name:        row.Name,
+		Description: row.Description,
+		Price: domain.Money{
+			Amount:   row.PriceAmount,
+			Currency: row.Currency,
+		},
+		Version:     row.Version,
+		ExternalSKU: row.ExternalSku.String,
+		WeightGrams: row.WeightGrams,
+		Rating: domain.Rating{
+			Average: row.RatingAvg,
+			Count:   row.RatingCount,
+		},
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if row.ArchivedAt.Valid {
+		p.ArchivedAt = row.ArchivedAt.Time
+	}
+	return p
+}
@@ -0,0 +1,53 @@
+-- name: CreateCategory :one
+INSERT INTO categories (parent_id, name, slug)
+VALUES ($1, $2, $3)
+    RETURNING *;
+
+-- name: GetCategory :one
+SELECT * FROM categories
+WHERE id = $1;
+
+-- name: ListCategories :many
+SELECT * FROM categories
+ORDER BY name ASC, id ASC;
+
+-- name: UpdateCategory :one
+UPDATE categories
+SET parent_id  = sqlc.narg(parent_id),
+    name       = sqlc.arg(name),
+    slug       = sqlc.arg(slug),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+    RETURNING *;
+
+-- name: DeleteCategory :execrows
+DELETE FROM categories
+WHERE id = $1;
+
+-- name: IsCategoryInSubtree :one
+-- Reports whether candidate is root or one of root's descendants.
+WITH RECURSIVE subtree AS (
+    SELECT c.id FROM categories c WHERE c.id = sqlc.arg(root)::uuid
+    UNION ALL
+    SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+)
+SELECT EXISTS (SELECT 1 FROM subtree WHERE subtree.id = sqlc.arg(candidate)::uuid);
+
+-- name: AddProductCategory :exec
+INSERT INTO product_categories (product_id, category_id)
+VALUES ($1, $2)
+    ON CONFLICT DO NOTHING;
+
+-- name: DeleteProductCategories :exec
+DELETE FROM product_categories
+WHERE product_id = $1;
+
+-- name: ListProductCategoryIDs :many
+SELECT category_id FROM product_categories
+WHERE product_id = $1
+ORDER BY category_id ASC;
+
+-- name: ListCategoryIDsForProducts :many
+SELECT product_id, category_id FROM product_categories
+WHERE product_id = ANY(sqlc.arg(product_ids)::uuid[])
+ORDER BY product_id, category_id ASC;
@@ -0,0 +1,55 @@
+-- name: LockProduct :one
+-- Serializes price changes of one product.
+SELECT id FROM products
+WHERE id = $1
+    FOR UPDATE;
+
+-- name: InsertProductPrice :one
+INSERT INTO product_prices (product_id, currency, amount, effective_from, effective_to, reason)
+VALUES ($1, $2, $3, $4, $5, $6)
+    RETURNING id, product_id, currency, amount, effective_from, effective_to, reason, created_at;
+
+-- name: ListProductPrices :many
+SELECT id, product_id, currency, amount, effective_from, effective_to, reason, created_at
+FROM product_prices
+WHERE product_id = $1
+ORDER BY effective_from;
+
+-- name: GetProductPriceAt :one
+SELECT id, product_id, currency, amount, effective_from, effective_to, reason, created_at
+FROM product_prices
+WHERE product_id = sqlc.arg(product_id)
+  AND effective_from <= sqlc.arg(at)
+  AND (effective_to IS NULL OR effective_to > sqlc.arg(at));
+
+-- name: ListProductPricesAt :many
+-- Batch form of GetProductPriceAt.
+SELECT id, product_id, currency, amount, effective_from, effective_to, reason, created_at
+FROM product_prices
+WHERE product_id = ANY(sqlc.arg(product_ids)::uuid[])
+  AND effective_from <= sqlc.arg(at)
+  AND (effective_to IS NULL OR effective_to > sqlc.arg(at));
+
+-- name: SetProductPriceRange :exec
+UPDATE product_prices
+SET effective_from = sqlc.arg(effective_from),
+    effective_to   = sqlc.arg(effective_to)
+WHERE id = sqlc.arg(id);
+
+-- name: DeleteProductPrice :exec
+DELETE FROM product_prices
+WHERE id = $1;
+
+-- name: SyncProductPrices :execrows
+-- Copies the price active at `at` into products, for one product or all.
+UPDATE products p
+SET currency     = pp.currency,
+    price_amount = pp.amount,
+    version      = p.version + 1,
+    updated_at   = now()
+FROM product_prices pp
+WHERE pp.product_id = p.id
+  AND (sqlc.narg(product_id)::uuid IS NULL OR p.id = sqlc.narg(product_id)::uuid)
+  AND pp.effective_from <= sqlc.arg(at)
+  AND (pp.effective_to IS NULL OR pp.effective_to > sqlc.arg(at))
+  AND (p.currency, p.price_amount) IS DISTINCT FROM (pp.currency, pp.amount);
@@ -1,19 +1,210 @@
 -- internal/catalog/infra/postgres/queries/products.sql
 
 -- name: CreateProduct :one
-INSERT INTO products (name, description, currency, price_amount)
-VALUES ($1, $2, $3, $4)
-    RETURNING id, name, description, currency, price_amount, created_at, updated_at;
+INSERT INTO products (name, description, currency, price_amount, weight_grams)
+VALUES ($1, $2, $3, $4, $5)
+    RETURNING id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams;
 
 -- name: GetProduct :one
-SELECT id, name, description, currency, price_amount, created_at, updated_at
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
 FROM products
 WHERE id = $1;
 
--- name: ListProducts :many
-SELECT id, name, description, currency, price_amount, created_at, updated_at
+-- name: BatchGetProducts :many
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
 FROM products
-WHERE (sqlc.arg(query) = '' OR name ILIKE '%' || sqlc.arg(query) || '%')
-  AND (sqlc.arg(use_cursor) = false OR id < sqlc.arg(cursor))
-ORDER BY id DESC
+WHERE id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: ListProductsByCreatedAt :many
+-- The product list queries share their filters and differ in sort order,
+-- each with a query of its own so that the index for that order can serve
+-- the ORDER BY. Keyset pagination: the cursor holds the sort key and id of
+-- the last row of the previous page, compared in the same direction as
+-- ORDER BY. This one lists the newest first, using
+-- idx_products_active_created_at.
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
+FROM products
+WHERE archived_at IS NULL
+  AND (sqlc.arg(query)::text = ''
+      OR name ILIKE '%' || sqlc.arg(query)::text || '%'
+      OR name % sqlc.arg(query)::text)
+  AND (sqlc.narg(category_id)::uuid IS NULL OR id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT c.id FROM categories c WHERE c.id = sqlc.narg(category_id)::uuid
+          UNION ALL
+          SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+      )
+      SELECT pc.product_id FROM product_categories pc JOIN subtree ON pc.category_id = subtree.id
+  ))
+  AND (sqlc.arg(currency)::text = '' OR currency = sqlc.arg(currency)::text)
+  AND (sqlc.arg(min_price)::bigint = 0 OR price_amount >= sqlc.arg(min_price)::bigint)
+  AND (sqlc.arg(max_price)::bigint = 0 OR price_amount <= sqlc.arg(max_price)::bigint)
+  AND (NOT sqlc.arg(use_cursor)::boolean
+      OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid))
+ORDER BY created_at DESC, id DESC
+    LIMIT sqlc.arg(page_limit);
+
+-- name: ListProductsByPriceAsc :many
+-- Cheapest first, using idx_products_active_price.
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
+FROM products
+WHERE archived_at IS NULL
+  AND (sqlc.arg(query)::text = ''
+      OR name ILIKE '%' || sqlc.arg(query)::text || '%'
+      OR name % sqlc.arg(query)::text)
+  AND (sqlc.narg(category_id)::uuid IS NULL OR id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT c.id FROM categories c WHERE c.id = sqlc.narg(category_id)::uuid
+          UNION ALL
+          SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+      )
+      SELECT pc.product_id FROM product_categories pc JOIN subtree ON pc.category_id = subtree.id
+  ))
+  AND (sqlc.arg(currency)::text = '' OR currency = sqlc.arg(currency)::text)
+  AND (sqlc.arg(min_price)::bigint = 0 OR price_amount >= sqlc.arg(min_price)::bigint)
+  AND (sqlc.arg(max_price)::bigint = 0 OR price_amount <= sqlc.arg(max_price)::bigint)
+  AND (NOT sqlc.arg(use_cursor)::boolean
+      OR (price_amount, id) > (sqlc.arg(cursor_price)::bigint, sqlc.arg(cursor_id)::uuid))
+ORDER BY price_amount ASC, id ASC
+    LIMIT sqlc.arg(page_limit);
+
+-- name: ListProductsByPriceDesc :many
+-- Most expensive first, scanning idx_products_active_price backwards.
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
+FROM products
+WHERE archived_at IS NULL
+  AND (sqlc.arg(query)::text = ''
+      OR name ILIKE '%' || sqlc.arg(query)::text || '%'
+      OR name % sqlc.arg(query)::text)
+  AND (sqlc.narg(category_id)::uuid IS NULL OR id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT c.id FROM categories c WHERE c.id = sqlc.narg(category_id)::uuid
+          UNION ALL
+          SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+      )
+      SELECT pc.product_id FROM product_categories pc JOIN subtree ON pc.category_id = subtree.id
+  ))
+  AND (sqlc.arg(currency)::text = '' OR currency = sqlc.arg(currency)::text)
+  AND (sqlc.arg(min_price)::bigint = 0 OR price_amount >= sqlc.arg(min_price)::bigint)
+  AND (sqlc.arg(max_price)::bigint = 0 OR price_amount <= sqlc.arg(max_price)::bigint)
+  AND (NOT sqlc.arg(use_cursor)::boolean
+      OR (price_amount, id) < (sqlc.arg(cursor_price)::bigint, sqlc.arg(cursor_id)::uuid))
+ORDER BY price_amount DESC, id DESC
+    LIMIT sqlc.arg(page_limit);
+
+-- name: ListProductsByRating :many
+-- Best rated first, using idx_products_rating.
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
+FROM products
+WHERE archived_at IS NULL
+  AND (sqlc.arg(query)::text = ''
+      OR name ILIKE '%' || sqlc.arg(query)::text || '%'
+      OR name % sqlc.arg(query)::text)
+  AND (sqlc.narg(category_id)::uuid IS NULL OR id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT c.id FROM categories c WHERE c.id = sqlc.narg(category_id)::uuid
+          UNION ALL
+          SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+      )
+      SELECT pc.product_id FROM product_categories pc JOIN subtree ON pc.category_id = subtree.id
+  ))
+  AND (sqlc.arg(currency)::text = '' OR currency = sqlc.arg(currency)::text)
+  AND (sqlc.arg(min_price)::bigint = 0 OR price_amount >= sqlc.arg(min_price)::bigint)
+  AND (sqlc.arg(max_price)::bigint = 0 OR price_amount <= sqlc.arg(max_price)::bigint)
+  AND (NOT sqlc.arg(use_cursor)::boolean
+      OR (rating_avg, id) < (sqlc.arg(cursor_rating)::float8, sqlc.arg(cursor_id)::uuid))
+ORDER BY rating_avg DESC, id DESC
+    LIMIT sqlc.arg(page_limit);
+
+-- name: ListProductsByRelevance :many
+-- Closest name match first. No index serves this order; the trigram index
+-- narrows the rows to the matches instead.
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams,
+       similarity(name, sqlc.arg(query)::text)::real AS score
+FROM products
+WHERE archived_at IS NULL
+  AND (sqlc.arg(query)::text = ''
+      OR name ILIKE '%' || sqlc.arg(query)::text || '%'
+      OR name % sqlc.arg(query)::text)
+  AND (sqlc.narg(category_id)::uuid IS NULL OR id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT c.id FROM categories c WHERE c.id = sqlc.narg(category_id)::uuid
+          UNION ALL
+          SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+      )
+      SELECT pc.product_id FROM product_categories pc JOIN subtree ON pc.category_id = subtree.id
+  ))
+  AND (sqlc.arg(currency)::text = '' OR currency = sqlc.arg(currency)::text)
+  AND (sqlc.arg(min_price)::bigint = 0 OR price_amount >= sqlc.arg(min_price)::bigint)
+  AND (sqlc.arg(max_price)::bigint = 0 OR price_amount <= sqlc.arg(max_price)::bigint)
+  AND (NOT sqlc.arg(use_cursor)::boolean
+      OR (similarity(name, sqlc.arg(query)::text), id) < (sqlc.arg(cursor_score)::real, sqlc.arg(cursor_id)::uuid))
+ORDER BY similarity(name, sqlc.arg(query)::text) DESC, id DESC
+    LIMIT sqlc.arg(page_limit);
+
+-- name: EstimateProducts :one
+-- The planner's estimate of how many products match the list filters; see
+-- estimate_products.
+SELECT estimate_products(
+    sqlc.arg(query)::text,
+    sqlc.narg(category_id)::uuid,
+    sqlc.arg(currency)::text,
+    sqlc.arg(min_price)::bigint,
+    sqlc.arg(max_price)::bigint
+)::bigint AS estimate;
+
+-- name: UpdateProduct :one
+UPDATE products
+SET name         = sqlc.arg(name),
+    description  = sqlc.arg(description),
+    currency     = sqlc.arg(currency),
+    price_amount = sqlc.arg(price_amount),
+    weight_grams = sqlc.arg(weight_grams),
+    version      = version + 1,
+    updated_at   = now()
+WHERE id = sqlc.arg(id)
+  AND version = sqlc.arg(expected_version)
+    RETURNING id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams;
+
+-- name: ArchiveProduct :one
+UPDATE products
+SET archived_at = now(),
+    version     = version + 1,
+    updated_at  = now()
+WHERE id = $1
+  AND archived_at IS NULL
+    RETURNING id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams;
+
+-- name: DeleteProduct :execrows
+DELETE FROM products
+WHERE id = $1;
+
+-- name: SetProductRating :execrows
+-- Ratings are derived from reviews, so this doesn't bump the version.
+UPDATE products
+SET rating_avg   = sqlc.arg(rating_avg),
+    rating_count = sqlc.arg(rating_count)
+WHERE id = sqlc.arg(id);
+
+-- name: UpsertProductBySKU :one
+-- inserted is false when an existing product with the SKU was updated.
+INSERT INTO products (external_sku, name, description, currency, price_amount, weight_grams)
+VALUES (sqlc.arg(external_sku), sqlc.arg(name), sqlc.arg(description), sqlc.arg(currency), sqlc.arg(price_amount), sqlc.arg(weight_grams))
+ON CONFLICT (external_sku) DO UPDATE
+SET name         = EXCLUDED.name,
+    description  = EXCLUDED.description,
+    currency     = EXCLUDED.currency,
+    price_amount = EXCLUDED.price_amount,
+    weight_grams = EXCLUDED.weight_grams,
+    version      = products.version + 1,
+    updated_at   = now()
+    RETURNING id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams,
+    (xmax = 0)::boolean AS inserted;
+
+-- name: ListProductsForExport :many
+SELECT id, name, description, currency, price_amount, created_at, updated_at, version, archived_at, external_sku, rating_avg, rating_count, weight_grams
+FROM products
+WHERE archived_at IS NULL
+  AND id > sqlc.arg(after_id)::uuid
+ORDER BY id
     LIMIT sqlc.arg(page_limit);
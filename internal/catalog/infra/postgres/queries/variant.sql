@@ -0,0 +1,53 @@
+-- name: DeleteProductOptions :exec
+DELETE FROM product_options
+WHERE product_id = $1;
+
+-- name: AddProductOption :exec
+INSERT INTO product_options (product_id, name, position, option_values)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListProductOptions :many
+SELECT * FROM product_options
+WHERE product_id = $1
+ORDER BY position ASC;
+
+-- name: ListOptionsForProducts :many
+SELECT * FROM product_options
+WHERE product_id = ANY(sqlc.arg(product_ids)::uuid[])
+ORDER BY product_id, position ASC;
+
+-- name: CreateVariant :one
+INSERT INTO product_variants (product_id, sku, options, currency, price_amount)
+VALUES ($1, $2, $3, $4, $5)
+    RETURNING *;
+
+-- name: GetVariant :one
+SELECT * FROM product_variants
+WHERE id = $1;
+
+-- name: ListProductsWithVariants :many
+SELECT DISTINCT product_id FROM product_variants
+WHERE product_id = ANY(sqlc.arg(product_ids)::uuid[]);
+
+-- name: ListVariantsByIDs :many
+SELECT * FROM product_variants
+WHERE id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: ListVariants :many
+SELECT * FROM product_variants
+WHERE product_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: UpdateVariant :one
+UPDATE product_variants
+SET sku          = sqlc.arg(sku),
+    options      = sqlc.arg(options),
+    currency     = sqlc.arg(currency),
+    price_amount = sqlc.arg(price_amount),
+    updated_at   = now()
+WHERE id = sqlc.arg(id)
+    RETURNING *;
+
+-- name: DeleteVariant :execrows
+DELETE FROM product_variants
+WHERE id = $1;
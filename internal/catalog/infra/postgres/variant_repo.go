@@ -0,0 +1,267 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/app"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+	"github.com/dwikikusuma/shoping-llm/internal/catalog/infra/postgres/catalogdb"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type VariantRepo struct {
+	q  *catalogdb.Queries
+	db *sql.DB
+}
+
+func NewVariantRepo(db *sql.DB) *VariantRepo {
+	return &VariantRepo{q: catalogdb.New(db), db: db}
+}
+
+// execTX runs fn in its own transaction, or in the caller's transaction when
+// ctx carries one (see pg.TxManager).
+func (r *VariantRepo) execTX(ctx context.Context, fn func(q *catalogdb.Queries) error) error {
+	return pg.ExecTx(ctx, r.db, func(tx *sql.Tx) error {
+		return fn(r.q.WithTx(tx))
+	})
+}
+
+func (r *VariantRepo) SetOptions(ctx context.Context, productID string, axes []domain.OptionAxis) error {
+	prodID, err := uuid.Parse(strings.TrimSpace(productID))
+	if err != nil {
+		return app.ErrInvalidInput
+	}
+
+	return r.execTX(ctx, func(q *catalogdb.Queries) error {
+		if err := q.DeleteProductOptions(ctx, prodID); err != nil {
+			return err
+		}
+		for i, axis := range axes {
+			values, err := json.Marshal(axis.Values)
+			if err != nil {
+				return err
+			}
+			err = q.AddProductOption(ctx, catalogdb.AddProductOptionParams{
+				ProductID:    prodID,
+				Name:         axis.Name,
+				Position:     int32(i),
+				OptionValues: values,
+			})
+			if pg.IsForeignKeyViolation(err) {
+				return app.ErrNotFound
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *VariantRepo) ListOptions(ctx context.Context, productID string) ([]domain.OptionAxis, error) {
+	prodID, err := uuid.Parse(strings.TrimSpace(productID))
+	if err != nil {
+		return nil, app.ErrInvalidInput
+	}
+
+	rows, err := r.q.ListProductOptions(ctx, prodID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.OptionAxis, 0, len(rows))
+	for _, row := range rows {
+		axis := domain.OptionAxis{Name: row.Name}
+		if err := json.Unmarshal(row.OptionValues, &axis.Values); err != nil {
+			return nil, fmt.Errorf("decode values of option %q: %w", row.Name, err)
+		}
+		out = append(out, axis)
+	}
+	return out, nil
+}
+
+func (r *VariantRepo) BatchListOptions(ctx context.Context, productIDs []string) (map[string][]domain.OptionAxis, error) {
+	rows, err := r.q.ListOptionsForProducts(ctx, parseUUIDs(productIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]domain.OptionAxis)
+	for _, row := range rows {
+		axis := domain.OptionAxis{Name: row.Name}
+		if err := json.Unmarshal(row.OptionValues, &axis.Values); err != nil {
+			return nil, fmt.Errorf("decode values of option %q: %w", row.Name, err)
+		}
+		id := row.ProductID.String()
+		out[id] = append(out[id], axis)
+	}
+	return out, nil
+}
+
+func (r *VariantRepo) ProductsWithVariants(ctx context.Context, productIDs []string) (map[string]bool, error) {
+	ids, err := r.q.ListProductsWithVariants(ctx, parseUUIDs(productIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		out[id.String()] = true
+	}
+	return out, nil
+}
+
+func (r *VariantRepo) Create(ctx context.Context, v domain.Variant) (domain.Variant, error) {
+	prodID, err := uuid.Parse(strings.TrimSpace(v.ProductID))
+	if err != nil {
+		return domain.Variant{}, app.ErrInvalidInput
+	}
+	options, err := json.Marshal(v.Options)
+	if err != nil {
+		return domain.Variant{}, err
+	}
+
+	row, err := r.q.CreateVariant(ctx, catalogdb.CreateVariantParams{
+		ProductID:   prodID,
+		Sku:         v.SKU,
+		Options:     options,
+		Currency:    v.Price.Currency,
+		PriceAmount: v.Price.Amount,
+	})
+	if err != nil {
+		return domain.Variant{}, mapVariantErr(err)
+	}
+	return toDomainVariant(row)
+}
+
+func (r *VariantRepo) Get(ctx context.Context, id string) (domain.Variant, error) {
+	varID, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return domain.Variant{}, app.ErrInvalidInput
+	}
+
+	row, err := r.q.GetVariant(ctx, varID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Variant{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.Variant{}, err
+	}
+	return toDomainVariant(row)
+}
+
+func (r *VariantRepo) BatchGet(ctx context.Context, ids []string) (map[string]domain.Variant, error) {
+	rows, err := r.q.ListVariantsByIDs(ctx, parseUUIDs(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]domain.Variant, len(rows))
+	for _, row := range rows {
+		v, err := toDomainVariant(row)
+		if err != nil {
+			return nil, err
+		}
+		out[v.ID] = v
+	}
+	return out, nil
+}
+
+func (r *VariantRepo) List(ctx context.Context, productID string) ([]domain.Variant, error) {
+	prodID, err := uuid.Parse(strings.TrimSpace(productID))
+	if err != nil {
+		return nil, app.ErrInvalidInput
+	}
+
+	rows, err := r.q.ListVariants(ctx, prodID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.Variant, 0, len(rows))
+	for _, row := range rows {
+		v, err := toDomainVariant(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func (r *VariantRepo) Update(ctx context.Context, v domain.Variant) (domain.Variant, error) {
+	varID, err := uuid.Parse(strings.TrimSpace(v.ID))
+	if err != nil {
+		return domain.Variant{}, app.ErrInvalidInput
+	}
+	options, err := json.Marshal(v.Options)
+	if err != nil {
+		return domain.Variant{}, err
+	}
+
+	row, err := r.q.UpdateVariant(ctx, catalogdb.UpdateVariantParams{
+		Sku:         v.SKU,
+		Options:     options,
+		Currency:    v.Price.Currency,
+		PriceAmount: v.Price.Amount,
+		ID:          varID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Variant{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.Variant{}, mapVariantErr(err)
+	}
+	return toDomainVariant(row)
+}
+
+func (r *VariantRepo) Delete(ctx context.Context, id string) error {
+	varID, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return app.ErrInvalidInput
+	}
+
+	n, err := r.q.DeleteVariant(ctx, varID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return app.ErrNotFound
+	}
+	return nil
+}
+
+func mapVariantErr(err error) error {
+	if pg.IsUniqueViolation(err) {
+		return app.ErrVariantExists
+	}
+	if pg.IsForeignKeyViolation(err) {
+		// the product does not exist
+		return app.ErrNotFound
+	}
+	return err
+}
+
+func toDomainVariant(row catalogdb.ProductVariant) (domain.Variant, error) {
+	v := domain.Variant{
+		ID:        row.ID.String(),
+		ProductID: row.ProductID.String(),
+		SKU:       row.Sku,
+		Price: domain.Money{
+			Currency: row.Currency,
+			Amount:   row.PriceAmount,
+		},
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if err := json.Unmarshal(row.Options, &v.Options); err != nil {
+		return domain.Variant{}, fmt.Errorf("decode options of variant %s: %w", v.ID, err)
+	}
+	return v, nil
+}
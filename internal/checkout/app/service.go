@@ -4,21 +4,39 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/dwikikusuma/shoping-llm/internal/checkout/domain"
-	"golang.org/x/sync/errgroup"
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
 )
 
 type CartReader interface {
 	GetCart(ctx context.Context, userID string) ([]CartItem, error)
+	// Notices returns the notices of the lines GetCart returned: lines whose
+	// price moved since they were added, or that can no longer be bought or
+	// aren't in stock.
+	Notices(ctx context.Context, items []CartItem) ([]domain.Notice, error)
 }
 
 type CartItem struct {
 	ProductID string
+	VariantID string
 	Quantity  int64
+	// UnitPrice is the price the line was added at; zero for lines added
+	// before prices were recorded.
+	UnitPrice domain.Money
 }
 type CatalogReader interface {
-	GetProduct(ctx context.Context, productID string) (Product, error)
+	// GetProducts returns the products with the given IDs, keyed by ID, with
+	// the prices active at the given time. It looks them up together rather
+	// than one by one, and fails with ErrUnknownProducts naming every ID
+	// that doesn't exist.
+	GetProducts(ctx context.Context, productIDs []string, at time.Time) (map[string]Product, error)
+	// GetVariants returns the variants with the given IDs, keyed by ID,
+	// looked up together. Variants that don't exist are left out.
+	GetVariants(ctx context.Context, variantIDs []string) (map[string]Variant, error)
 }
 
 type Product struct {
@@ -26,90 +44,408 @@ type Product struct {
 	Name     string
 	Currency string
 	Amount   int64
+	// WeightGrams is the shipping weight of one unit; 0 when unknown.
+	WeightGrams int32
+	// HasVariants is set when the product can only be bought as one of its
+	// variants.
+	HasVariants bool
+}
+
+type Variant struct {
+	ID        string
+	ProductID string
+	SKU       string
+	Currency  string
+	Amount    int64
+}
+
+// CartWriter closes the user's cart once it has been turned into an order.
+type CartWriter interface {
+	// LockActiveCart locks the user's ACTIVE cart until the surrounding
+	// transaction ends and returns its ID. It returns ErrEmptyCart when the
+	// user has no ACTIVE cart.
+	LockActiveCart(ctx context.Context, userID string) (string, error)
+	MarkCheckedOut(ctx context.Context, cartID string) error
+}
+
+type OrderCreator interface {
+	CreateOrder(ctx context.Context, req OrderRequest) (domain.PlacedOrder, error)
+}
+
+type OrderRequest struct {
+	UserID         string
+	Currency       string
+	ShippingOption string
+	Country        string
+	ShippingFee    int64
+	Discount       int64
+	Lines          []domain.QuoteLine
+}
+
+// ShippingRates prices shipping for a quote.
+type ShippingRates interface {
+	// Options returns the ways the quote's lines can ship to country,
+	// cheapest first, with fees in the currency of the quote total. An
+	// empty country means the shopper hasn't said yet.
+	Options(ctx context.Context, country string, quote domain.Quote) ([]domain.ShippingOption, error)
+}
+
+// Promotions applies automatic promotions and the codes the shopper entered.
+type Promotions interface {
+	// Apply returns the discounts the quote gets, in the catalog currency,
+	// and why entered codes don't apply. shippingFee is the fee free
+	// shipping would waive, in the currency of the lines; 0 when it isn't
+	// known yet or the lines are in more than one currency.
+	Apply(ctx context.Context, userID string, quote domain.Quote, codes []string, shippingFee int64) ([]domain.Discount, []domain.RejectedCode, error)
+	// Redeem counts the discounts against the promotions' usage limits. It
+	// fails with ErrPromotionRejected when one of them no longer applies.
+	Redeem(ctx context.Context, userID, orderID string, discounts []domain.Discount) error
+}
+
+// TxRunner runs fn inside a single database transaction carried by ctx.
+type TxRunner interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type Service struct {
-	Cart    CartReader
-	Catalog CatalogReader
+	Cart       CartReader
+	Catalog    CatalogReader
+	CartWriter CartWriter
+	Orders     OrderCreator
+	Shipping   ShippingRates
+	// Promotions discounts quotes and orders; nil disables promotions.
+	Promotions Promotions
+	// Rates converts quotes into a display currency; nil disables that.
+	Rates money.RateProvider
+	Tx    TxRunner
+}
 
-	maxConcurrent int
+func NewService(cart CartReader, catalog CatalogReader, cartWriter CartWriter, orders OrderCreator, shipping ShippingRates, promotions Promotions, rates money.RateProvider, tx TxRunner) *Service {
+	return &Service{
+		Cart:       cart,
+		Catalog:    catalog,
+		CartWriter: cartWriter,
+		Orders:     orders,
+		Shipping:   shipping,
+		Promotions: promotions,
+		Rates:      rates,
+		Tx:         tx,
+	}
 }
 
-func NewService(cart CartReader, catalog CatalogReader, maxConcurrent int) *Service {
-	if maxConcurrent <= 0 {
-		maxConcurrent = 10
+var (
+	ErrEmptyCart             = errors.New("cart is empty")
+	ErrUnknownShippingOption = errors.New("unknown shipping option")
+	ErrInvalidDestination    = errors.New("invalid shipping destination")
+	ErrMixedCurrencies       = errors.New("cart contains products priced in different currencies")
+	ErrUnsupportedCurrency   = errors.New("cart can't be quoted in this currency")
+	ErrInsufficientStock     = errors.New("insufficient stock")
+	ErrInvalidVariant        = errors.New("cart line has no valid variant for its product")
+	ErrUnknownProducts       = errors.New("cart contains products that don't exist")
+	ErrPromotionRejected     = errors.New("promotion can't be applied")
+	ErrInvalidCodes          = errors.New("invalid promotion codes") // e.g. too many
+)
+
+// Quote prices the user's cart from the catalog. Lines keep the catalog
+// currency. With a display currency every line total is converted and the
+// total is in that currency; without one the cart must be in a single
+// currency, which is also what PlaceOrder needs. The quote carries the
+// discounts of the automatic promotions and the entered codes, the ways
+// the cart can ship to country, and the cart's notices.
+func (s *Service) Quote(ctx context.Context, userID, displayCurrency, country string, codes []string) (domain.Quote, error) {
+	q, items, err := s.quote(ctx, userID, displayCurrency)
+	if err != nil {
+		return domain.Quote{}, err
 	}
 
-	return &Service{
-		Cart:          cart,
-		Catalog:       catalog,
-		maxConcurrent: maxConcurrent,
+	options, err := s.Shipping.Options(ctx, country, q)
+	if err != nil {
+		return domain.Quote{}, err
+	}
+	// The option isn't chosen yet, so free shipping is valued at the
+	// cheapest one.
+	cheapest := money.Zero(q.Total.Currency)
+	if len(options) > 0 {
+		cheapest = options[0].Fee
+	}
+	if err := s.applyPromotions(ctx, userID, &q, codes, cheapest); err != nil {
+		return domain.Quote{}, err
 	}
+	if q.FreeShipping {
+		for i := range options {
+			options[i].Fee.Amount = 0
+		}
+	}
+	q.ShippingOptions = options
+
+	q.Notices, err = s.Cart.Notices(ctx, items)
+	if err != nil {
+		return domain.Quote{}, fmt.Errorf("failed to get cart notices: %w", err)
+	}
+	return q, nil
 }
 
-var ErrEmptyCart = errors.New("cart is empty")
+// quote prices the user's cart and also returns the cart lines it priced.
+func (s *Service) quote(ctx context.Context, userID, displayCurrency string) (domain.Quote, []CartItem, error) {
+	if strings.TrimSpace(displayCurrency) != "" {
+		code, err := money.NormalizeCurrency(displayCurrency)
+		if err != nil {
+			return domain.Quote{}, nil, fmt.Errorf("%w: %v", ErrUnsupportedCurrency, err)
+		}
+		displayCurrency = code
+	}
 
-func (s *Service) Quote(ctx context.Context, userID string) (domain.Quote, error) {
 	items, err := s.Cart.GetCart(ctx, userID)
 	if err != nil {
-		return domain.Quote{}, err
+		return domain.Quote{}, nil, err
 	}
 
 	if len(items) == 0 {
-		return domain.Quote{}, ErrEmptyCart
+		return domain.Quote{}, nil, ErrEmptyCart
+	}
+
+	productIDs := make([]string, 0, len(items))
+	var variantIDs []string
+	for _, it := range items {
+		if it.Quantity <= 0 {
+			return domain.Quote{}, nil, fmt.Errorf("quantity must be greater than zero: %d", it.Quantity)
+		}
+		productIDs = append(productIDs, it.ProductID)
+		if it.VariantID != "" {
+			variantIDs = append(variantIDs, it.VariantID)
+		}
+	}
+
+	// Every line is priced at the same instant, even if a scheduled price
+	// change starts while the quote is being built.
+	products, err := s.Catalog.GetProducts(ctx, productIDs, time.Now())
+	if err != nil {
+		return domain.Quote{}, nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	variants := map[string]Variant{}
+	if len(variantIDs) > 0 {
+		variants, err = s.Catalog.GetVariants(ctx, variantIDs)
+		if err != nil {
+			return domain.Quote{}, nil, fmt.Errorf("failed to get variants: %w", err)
+		}
 	}
 
 	lines := make([]domain.QuoteLine, len(items))
-	g, ctx := errgroup.WithContext(ctx)
-	g.SetLimit(s.maxConcurrent)
-
-	for idx := range items {
-		idx := idx
-		g.Go(func() error {
-			it := items[idx]
-			if it.Quantity <= 0 {
-				return fmt.Errorf("quantity must be greater than zero: %d", it.Quantity)
-			}
+	for idx, it := range items {
+		product, ok := products[it.ProductID]
+		if !ok {
+			return domain.Quote{}, nil, fmt.Errorf("%w: %s", ErrUnknownProducts, it.ProductID)
+		}
 
-			product, err := s.Catalog.GetProduct(ctx, it.ProductID)
-			if err != nil {
-				return fmt.Errorf("failed to get product %s: %w", it.ProductID, err)
-			}
+		line := domain.QuoteLine{
+			ProductID: product.ID,
+			Name:      product.Name,
+			Quantity:  it.Quantity,
+			// Variants ship at the weight of their product.
+			WeightGrams: int64(product.WeightGrams) * it.Quantity,
+			UnitPrice: domain.Money{
+				Currency: product.Currency,
+				Amount:   product.Amount,
+			},
+		}
 
-			lineTotal := product.Amount * it.Quantity
-			lines[idx] = domain.QuoteLine{
-				ProductID: product.ID,
-				Name:      product.Name,
-				Quantity:  it.Quantity,
-				UnitPrice: domain.Money{
-					Currency: product.Currency,
-					Amount:   product.Amount,
-				},
-				LineTotal: domain.Money{
-					Currency: product.Currency,
-					Amount:   lineTotal,
-				},
+		// A variant has its own price, which replaces the product's.
+		if it.VariantID != "" || product.HasVariants {
+			if it.VariantID == "" {
+				return domain.Quote{}, nil, fmt.Errorf("%w: product %s", ErrInvalidVariant, it.ProductID)
 			}
-			return nil
-		})
+			variant, ok := variants[it.VariantID]
+			if !ok {
+				return domain.Quote{}, nil, fmt.Errorf("%w: variant %s not found", ErrInvalidVariant, it.VariantID)
+			}
+			if variant.ProductID != product.ID {
+				return domain.Quote{}, nil, fmt.Errorf("%w: variant %s is not of product %s", ErrInvalidVariant, it.VariantID, it.ProductID)
+			}
+			line.VariantID = variant.ID
+			line.SKU = variant.SKU
+			line.UnitPrice = domain.Money{
+				Currency: variant.Currency,
+				Amount:   variant.Amount,
+			}
+		}
+
+		line.LineTotal, err = line.UnitPrice.Mul(it.Quantity)
+		if err != nil {
+			return domain.Quote{}, nil, fmt.Errorf("line total of product %s: %w", it.ProductID, err)
+		}
+		lines[idx] = line
 	}
 
-	if err := g.Wait(); err != nil {
-		return domain.Quote{}, err
+	currency := displayCurrency
+	if currency == "" {
+		currency = lines[0].LineTotal.Currency
+	}
+	total := money.Zero(currency)
+	for i := range lines {
+		if displayCurrency == "" && lines[i].LineTotal.Currency != currency {
+			return domain.Quote{}, nil, ErrMixedCurrencies
+		}
+		// Converting each line rather than the sum keeps the displayed lines
+		// adding up to the displayed total.
+		converted, err := money.Convert(ctx, s.Rates, lines[i].LineTotal, currency)
+		if errors.Is(err, money.ErrNoRate) || errors.Is(err, money.ErrUnknownCurrency) {
+			return domain.Quote{}, nil, fmt.Errorf("%w: %v", ErrUnsupportedCurrency, err)
+		}
+		if err != nil {
+			return domain.Quote{}, nil, err
+		}
+		lines[i].DisplayTotal = converted
+		if total, err = total.Add(converted); err != nil {
+			return domain.Quote{}, nil, err
+		}
 	}
 
-	var totalAmount int64
-	for _, line := range lines {
-		totalAmount += line.LineTotal.Amount
+	return domain.Quote{Lines: lines, Subtotal: total, Total: total}, items, nil
+}
+
+// applyPromotions takes the discounts the quote gets off its total.
+// shipping is the fee free shipping would waive, in the currency of the
+// total; zero when unknown.
+func (s *Service) applyPromotions(ctx context.Context, userID string, q *domain.Quote, codes []string, shipping domain.Money) error {
+	if s.Promotions == nil {
+		for _, c := range codes {
+			q.RejectedCodes = append(q.RejectedCodes, domain.RejectedCode{Code: c, Reason: "is not a valid code"})
+		}
+		return nil
 	}
 
-	quote := domain.Quote{
-		Lines: lines,
-		Total: domain.Money{
-			Currency: lines[0].LineTotal.Currency,
-			Amount:   totalAmount,
-		},
+	// Promotions are priced in the currency of the lines, so a quote in a
+	// display currency picks the same promotions PlaceOrder later does.
+	var shippingFee int64
+	if currency := linesCurrency(q.Lines); currency != "" && shipping.Amount > 0 {
+		fee, err := money.Convert(ctx, s.Rates, shipping, currency)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUnsupportedCurrency, err)
+		}
+		shippingFee = fee.Amount
+	}
+
+	discounts, rejected, err := s.Promotions.Apply(ctx, userID, *q, codes, shippingFee)
+	if err != nil {
+		return err
+	}
+	for i := range discounts {
+		d := &discounts[i]
+		d.DisplayAmount, err = money.Convert(ctx, s.Rates, d.Amount, q.Total.Currency)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUnsupportedCurrency, err)
+		}
+		if q.Total, err = q.Total.Sub(d.DisplayAmount); err != nil {
+			return err
+		}
+		q.FreeShipping = q.FreeShipping || d.FreeShipping
+	}
+	// Converting each discount on its own can round past the subtotal.
+	q.Total.Amount = max(q.Total.Amount, 0)
+	q.Discounts, q.RejectedCodes = discounts, rejected
+	return nil
+}
+
+// linesCurrency returns the currency every line is priced in, or "" when
+// they are in more than one.
+func linesCurrency(lines []domain.QuoteLine) string {
+	var currency string
+	for _, ln := range lines {
+		if currency != "" && ln.UnitPrice.Currency != currency {
+			return ""
+		}
+		currency = ln.UnitPrice.Currency
+	}
+	return currency
+}
+
+// PlaceOrder turns the user's ACTIVE cart into a PENDING order. The cart is
+// re-priced from the catalog, so clients can't choose their own prices, and
+// the order is created, its promotions redeemed and the cart checked out in
+// one transaction. Every entered code must apply, otherwise the order fails
+// with ErrPromotionRejected rather than charge more than the shopper expects.
+// The shipping fee is the one the chosen option costs to ship to country,
+// which is required here, unlike in Quote; an option that isn't offered
+// there fails with ErrUnknownShippingOption.
+func (s *Service) PlaceOrder(ctx context.Context, userID, shippingOption, country string, codes []string) (domain.PlacedOrder, error) {
+	shippingOption = strings.ToUpper(strings.TrimSpace(shippingOption))
+	if shippingOption == "" {
+		shippingOption = domain.ShippingStandard
+	}
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		return domain.PlacedOrder{}, fmt.Errorf("%w: country is required", ErrInvalidDestination)
+	}
+
+	var placed domain.PlacedOrder
+	err := s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		cartID, err := s.CartWriter.LockActiveCart(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		// Orders are charged in the catalog currency, never a converted one.
+		quote, _, err := s.quote(ctx, userID, "")
+		if err != nil {
+			return err
+		}
+
+		options, err := s.Shipping.Options(ctx, country, quote)
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(options, func(o domain.ShippingOption) bool { return o.Code == shippingOption })
+		if i < 0 {
+			return fmt.Errorf("%w: %q", ErrUnknownShippingOption, shippingOption)
+		}
+		shipping := options[i]
+		fee := shipping.Fee.Amount
+
+		if err := s.applyPromotions(ctx, userID, &quote, codes, shipping.Fee); err != nil {
+			return err
+		}
+		if len(quote.RejectedCodes) > 0 {
+			r := quote.RejectedCodes[0]
+			return fmt.Errorf("%w: %s %s", ErrPromotionRejected, r.Code, r.Reason)
+		}
+		if quote.FreeShipping {
+			fee = 0
+		}
+		discount := quote.Subtotal.Amount - quote.Total.Amount
+
+		placed, err = s.Orders.CreateOrder(ctx, OrderRequest{
+			UserID:         userID,
+			Currency:       quote.Total.Currency,
+			ShippingOption: shipping.Code,
+			Country:        country,
+			ShippingFee:    fee,
+			Discount:       discount,
+			Lines:          quote.Lines,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+
+		if s.Promotions != nil && len(quote.Discounts) > 0 {
+			if err := s.Promotions.Redeem(ctx, userID, placed.OrderID, quote.Discounts); err != nil {
+				return err
+			}
+		}
+
+		if err := s.CartWriter.MarkCheckedOut(ctx, cartID); err != nil {
+			return fmt.Errorf("failed to check out cart: %w", err)
+		}
+
+		placed.Lines = quote.Lines
+		placed.Discounts = quote.Discounts
+		placed.Discount = domain.Money{Currency: quote.Total.Currency, Amount: discount}
+		shipping.Fee.Amount = fee
+		placed.Shipping = shipping
+		return nil
+	})
+	if err != nil {
+		return domain.PlacedOrder{}, err
 	}
 
-	return quote, nil
+	return placed, nil
 }
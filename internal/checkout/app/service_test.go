@@ -0,0 +1,597 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/checkout/domain"
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
+)
+
+type fakeCart struct {
+	items      []CartItem
+	notices    []domain.Notice
+	noticed    []CartItem // lines Notices was asked about
+	checkedOut bool
+}
+
+func (f *fakeCart) GetCart(ctx context.Context, userID string) ([]CartItem, error) {
+	return f.items, nil
+}
+
+func (f *fakeCart) Notices(ctx context.Context, items []CartItem) ([]domain.Notice, error) {
+	f.noticed = items
+	return f.notices, nil
+}
+
+func (f *fakeCart) LockActiveCart(ctx context.Context, userID string) (string, error) {
+	return "cart-1", nil
+}
+
+func (f *fakeCart) MarkCheckedOut(ctx context.Context, cartID string) error {
+	f.checkedOut = true
+	return nil
+}
+
+type fakeCatalog struct {
+	products map[string]Product
+	variants map[string]Variant
+	calls    *int // GetProducts calls, when set
+}
+
+func (f fakeCatalog) GetProducts(ctx context.Context, productIDs []string, at time.Time) (map[string]Product, error) {
+	if f.calls != nil {
+		*f.calls++
+	}
+	out := make(map[string]Product, len(productIDs))
+	var missing []string
+	for _, id := range productIDs {
+		p, ok := f.products[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		out[id] = p
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProducts, strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+func (f fakeCatalog) GetVariants(ctx context.Context, variantIDs []string) (map[string]Variant, error) {
+	out := make(map[string]Variant, len(variantIDs))
+	for _, id := range variantIDs {
+		if v, ok := f.variants[id]; ok {
+			out[id] = v
+		}
+	}
+	return out, nil
+}
+
+type fakeOrders struct {
+	got OrderRequest
+	err error
+}
+
+func (f *fakeOrders) CreateOrder(ctx context.Context, req OrderRequest) (domain.PlacedOrder, error) {
+	f.got = req
+	if f.err != nil {
+		return domain.PlacedOrder{}, f.err
+	}
+	var total int64
+	for _, ln := range req.Lines {
+		total += ln.LineTotal.Amount
+	}
+	return domain.PlacedOrder{
+		OrderID: "order-1",
+		Status:  "PENDING",
+		Total:   domain.Money{Currency: req.Currency, Amount: total - req.Discount + req.ShippingFee},
+	}, nil
+}
+
+// flatShipping only offers STANDARD, at a flat fee.
+type flatShipping int64
+
+func (f flatShipping) Options(ctx context.Context, country string, quote domain.Quote) ([]domain.ShippingOption, error) {
+	return []domain.ShippingOption{
+		{Code: domain.ShippingStandard, Fee: domain.Money{Currency: quote.Total.Currency, Amount: int64(f)}},
+	}, nil
+}
+
+// weightShipping charges per gram, and only ships EXPRESS within ID.
+type weightShipping struct {
+	country string
+	weight  int64
+}
+
+func (f *weightShipping) Options(ctx context.Context, country string, quote domain.Quote) ([]domain.ShippingOption, error) {
+	if country == "XX" {
+		return nil, ErrInvalidDestination
+	}
+	f.country, f.weight = country, 0
+	for _, ln := range quote.Lines {
+		f.weight += ln.WeightGrams
+	}
+	fee := func(amount int64) domain.Money { return domain.Money{Currency: quote.Total.Currency, Amount: amount} }
+	options := []domain.ShippingOption{{Code: "STANDARD", Fee: fee(f.weight), MinDays: 3, MaxDays: 5}}
+	if country == "ID" {
+		options = append(options, domain.ShippingOption{Code: "EXPRESS", Fee: fee(3 * f.weight), MinDays: 1, MaxDays: 2})
+	}
+	return options, nil
+}
+
+// fakePromotions takes off a fixed discount for every code it knows and
+// rejects the others.
+type fakePromotions struct {
+	discounts   map[string]domain.Discount
+	redeemed    []domain.Discount
+	redeemErr   error
+	shippingFee int64 // the last fee Apply got
+}
+
+func (f *fakePromotions) Apply(ctx context.Context, userID string, quote domain.Quote, codes []string, shippingFee int64) ([]domain.Discount, []domain.RejectedCode, error) {
+	f.shippingFee = shippingFee
+	var (
+		discounts []domain.Discount
+		rejected  []domain.RejectedCode
+	)
+	for _, c := range codes {
+		d, ok := f.discounts[c]
+		if !ok {
+			rejected = append(rejected, domain.RejectedCode{Code: c, Reason: "is not a valid code"})
+			continue
+		}
+		discounts = append(discounts, d)
+	}
+	return discounts, rejected, nil
+}
+
+func (f *fakePromotions) Redeem(ctx context.Context, userID, orderID string, discounts []domain.Discount) error {
+	if f.redeemErr != nil {
+		return f.redeemErr
+	}
+	f.redeemed = append(f.redeemed, discounts...)
+	return nil
+}
+
+// fakeTx only records whether fn failed, which is what the real TxRunner
+// uses to decide between commit and rollback.
+type fakeTx struct{ rolledBack bool }
+
+func (f *fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(ctx)
+	f.rolledBack = err != nil
+	return err
+}
+
+func TestPlaceOrder(t *testing.T) {
+	catalog := fakeCatalog{
+		products: map[string]Product{
+			"p1": {ID: "p1", Name: "Keyboard", Currency: "IDR", Amount: 250000},
+			"p2": {ID: "p2", Name: "Mouse", Currency: "IDR", Amount: 100000},
+			"p3": {ID: "p3", Name: "T-Shirt", Currency: "IDR", Amount: 80000, HasVariants: true},
+		},
+		variants: map[string]Variant{
+			"v-s":  {ID: "v-s", ProductID: "p3", SKU: "TS-S", Currency: "IDR", Amount: 80000},
+			"v-xl": {ID: "v-xl", ProductID: "p3", SKU: "TS-XL", Currency: "IDR", Amount: 95000},
+		},
+	}
+
+	t.Run("prices lines from catalog and checks out cart", func(t *testing.T) {
+		cart := &fakeCart{items: []CartItem{{ProductID: "p1", Quantity: 2}, {ProductID: "p2", Quantity: 1}}}
+		orders := &fakeOrders{}
+		tx := &fakeTx{}
+		svc := NewService(cart, catalog, cart, orders, flatShipping(15000), nil, nil, tx)
+
+		placed, err := svc.PlaceOrder(context.Background(), "u1", "", "ID", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if orders.got.ShippingFee != 15000 || orders.got.Currency != "IDR" || len(orders.got.Lines) != 2 {
+			t.Fatalf("unexpected order request: %+v", orders.got)
+		}
+		if orders.got.Lines[0].UnitPrice.Amount != 250000 {
+			t.Fatalf("expected catalog price, got %d", orders.got.Lines[0].UnitPrice.Amount)
+		}
+		if placed.Total.Amount != 2*250000+100000+15000 {
+			t.Fatalf("unexpected total: %d", placed.Total.Amount)
+		}
+		if !cart.checkedOut || tx.rolledBack {
+			t.Fatalf("expected cart checked out and tx committed")
+		}
+	})
+
+	t.Run("order failure leaves cart active", func(t *testing.T) {
+		cart := &fakeCart{items: []CartItem{{ProductID: "p1", Quantity: 1}}}
+		orders := &fakeOrders{err: errors.New("db down")}
+		tx := &fakeTx{}
+		svc := NewService(cart, catalog, cart, orders, flatShipping(0), nil, nil, tx)
+
+		if _, err := svc.PlaceOrder(context.Background(), "u1", "", "ID", nil); err == nil {
+			t.Fatalf("expected error")
+		}
+		if cart.checkedOut || !tx.rolledBack {
+			t.Fatalf("expected rollback without checkout")
+		}
+	})
+
+	t.Run("empty cart", func(t *testing.T) {
+		cart := &fakeCart{}
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, flatShipping(0), nil, nil, &fakeTx{})
+
+		if _, err := svc.PlaceOrder(context.Background(), "u1", "", "ID", nil); !errors.Is(err, ErrEmptyCart) {
+			t.Fatalf("expected ErrEmptyCart, got %v", err)
+		}
+	})
+
+	t.Run("prices variants separately", func(t *testing.T) {
+		cart := &fakeCart{items: []CartItem{
+			{ProductID: "p3", VariantID: "v-s", Quantity: 1},
+			{ProductID: "p3", VariantID: "v-xl", Quantity: 2},
+		}}
+		orders := &fakeOrders{}
+		svc := NewService(cart, catalog, cart, orders, flatShipping(0), nil, nil, &fakeTx{})
+
+		placed, err := svc.PlaceOrder(context.Background(), "u1", "", "ID", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		lines := orders.got.Lines
+		if len(lines) != 2 || lines[1].VariantID != "v-xl" || lines[1].SKU != "TS-XL" || lines[1].UnitPrice.Amount != 95000 {
+			t.Fatalf("unexpected lines: %+v", lines)
+		}
+		if placed.Total.Amount != 80000+2*95000 {
+			t.Fatalf("unexpected total: %d", placed.Total.Amount)
+		}
+	})
+
+	t.Run("product with variants needs a variant", func(t *testing.T) {
+		cart := &fakeCart{items: []CartItem{{ProductID: "p3", Quantity: 1}}}
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, flatShipping(0), nil, nil, &fakeTx{})
+
+		if _, err := svc.PlaceOrder(context.Background(), "u1", "", "ID", nil); !errors.Is(err, ErrInvalidVariant) {
+			t.Fatalf("expected ErrInvalidVariant, got %v", err)
+		}
+	})
+
+	t.Run("variant of another product", func(t *testing.T) {
+		cart := &fakeCart{items: []CartItem{{ProductID: "p1", VariantID: "v-s", Quantity: 1}}}
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, flatShipping(0), nil, nil, &fakeTx{})
+
+		if _, err := svc.PlaceOrder(context.Background(), "u1", "", "ID", nil); !errors.Is(err, ErrInvalidVariant) {
+			t.Fatalf("expected ErrInvalidVariant, got %v", err)
+		}
+	})
+
+	t.Run("deleted variant", func(t *testing.T) {
+		cart := &fakeCart{items: []CartItem{{ProductID: "p3", VariantID: "gone", Quantity: 1}}}
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, flatShipping(0), nil, nil, &fakeTx{})
+
+		if _, err := svc.PlaceOrder(context.Background(), "u1", "", "ID", nil); !errors.Is(err, ErrInvalidVariant) {
+			t.Fatalf("expected ErrInvalidVariant, got %v", err)
+		}
+	})
+
+	t.Run("looks up all products at once", func(t *testing.T) {
+		calls := 0
+		catalog := catalog
+		catalog.calls = &calls
+		cart := &fakeCart{items: []CartItem{
+			{ProductID: "p1", Quantity: 1},
+			{ProductID: "p2", Quantity: 1},
+			{ProductID: "p3", VariantID: "v-s", Quantity: 1},
+		}}
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, flatShipping(0), nil, nil, &fakeTx{})
+
+		if _, err := svc.Quote(context.Background(), "u1", "", "", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected one catalog lookup, got %d", calls)
+		}
+	})
+
+	t.Run("names missing products", func(t *testing.T) {
+		cart := &fakeCart{items: []CartItem{{ProductID: "p1", Quantity: 1}, {ProductID: "gone", Quantity: 1}}}
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, flatShipping(0), nil, nil, &fakeTx{})
+
+		_, err := svc.PlaceOrder(context.Background(), "u1", "", "ID", nil)
+		if !errors.Is(err, ErrUnknownProducts) || !strings.Contains(err.Error(), "gone") {
+			t.Fatalf("expected ErrUnknownProducts naming gone, got %v", err)
+		}
+	})
+
+	t.Run("unknown shipping option", func(t *testing.T) {
+		cart := &fakeCart{items: []CartItem{{ProductID: "p1", Quantity: 1}}}
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, flatShipping(0), nil, nil, &fakeTx{})
+
+		if _, err := svc.PlaceOrder(context.Background(), "u1", "TELEPORT", "ID", nil); !errors.Is(err, ErrUnknownShippingOption) {
+			t.Fatalf("expected ErrUnknownShippingOption, got %v", err)
+		}
+	})
+}
+
+func TestQuoteCurrencies(t *testing.T) {
+	catalog := fakeCatalog{
+		products: map[string]Product{
+			"idr": {ID: "idr", Name: "Batik", Currency: "IDR", Amount: 162500},
+			"usd": {ID: "usd", Name: "Mug", Currency: "USD", Amount: 1250},
+		},
+	}
+	rates, err := money.NewStaticRates("USD", map[string]string{"IDR": "16250"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mixed := &fakeCart{items: []CartItem{{ProductID: "idr", Quantity: 2}, {ProductID: "usd", Quantity: 1}}}
+
+	t.Run("mixed cart needs a display currency", func(t *testing.T) {
+		svc := NewService(mixed, catalog, mixed, &fakeOrders{}, flatShipping(0), nil, rates, &fakeTx{})
+
+		if _, err := svc.Quote(context.Background(), "u1", "", "", nil); !errors.Is(err, ErrMixedCurrencies) {
+			t.Fatalf("expected ErrMixedCurrencies, got %v", err)
+		}
+	})
+
+	t.Run("converts every line into the display currency", func(t *testing.T) {
+		svc := NewService(mixed, catalog, mixed, &fakeOrders{}, flatShipping(0), nil, rates, &fakeTx{})
+
+		q, err := svc.Quote(context.Background(), "u1", "usd", "", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if q.Lines[0].LineTotal != (domain.Money{Currency: "IDR", Amount: 325000}) {
+			t.Fatalf("expected line kept in IDR, got %v", q.Lines[0].LineTotal)
+		}
+		if q.Lines[0].DisplayTotal != (domain.Money{Currency: "USD", Amount: 2000}) {
+			t.Fatalf("expected USD 20.00, got %v", q.Lines[0].DisplayTotal)
+		}
+		if q.Total != (domain.Money{Currency: "USD", Amount: 3250}) {
+			t.Fatalf("expected USD 32.50, got %v", q.Total)
+		}
+	})
+
+	unsupported := map[string]money.RateProvider{
+		"XXX": rates,
+		"JPY": rates,
+		"IDR": nil,
+	}
+	for currency, provider := range unsupported {
+		t.Run(currency+" is not supported", func(t *testing.T) {
+			svc := NewService(mixed, catalog, mixed, &fakeOrders{}, flatShipping(0), nil, provider, &fakeTx{})
+
+			if _, err := svc.Quote(context.Background(), "u1", currency, "", nil); !errors.Is(err, ErrUnsupportedCurrency) {
+				t.Fatalf("expected ErrUnsupportedCurrency, got %v", err)
+			}
+		})
+	}
+}
+
+func TestQuoteCarriesCartNotices(t *testing.T) {
+	catalog := fakeCatalog{
+		products: map[string]Product{"p1": {ID: "p1", Name: "Mug", Currency: "USD", Amount: 1200}},
+	}
+	raised := domain.Notice{
+		ProductID: "p1",
+		Kind:      "PRICE_INCREASED",
+		OldPrice:  domain.Money{Currency: "USD", Amount: 1000},
+		NewPrice:  domain.Money{Currency: "USD", Amount: 1200},
+	}
+	line := CartItem{ProductID: "p1", Quantity: 1, UnitPrice: domain.Money{Currency: "USD", Amount: 1000}}
+	cart := &fakeCart{items: []CartItem{line}, notices: []domain.Notice{raised}}
+	svc := NewService(cart, catalog, cart, &fakeOrders{}, flatShipping(0), nil, nil, &fakeTx{})
+
+	q, err := svc.Quote(context.Background(), "u1", "", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.Notices) != 1 || q.Notices[0] != raised {
+		t.Fatalf("expected the cart's notice on the quote, got %+v", q.Notices)
+	}
+	if len(cart.noticed) != 1 || cart.noticed[0] != line {
+		t.Fatalf("expected the notices of the quoted lines, got %+v", cart.noticed)
+	}
+}
+
+func TestPromotions(t *testing.T) {
+	catalog := fakeCatalog{
+		products: map[string]Product{"p1": {ID: "p1", Name: "Mug", Currency: "USD", Amount: 2000}},
+	}
+	newPromotions := func() *fakePromotions {
+		return &fakePromotions{discounts: map[string]domain.Discount{
+			"FIVE": {PromotionID: "promo-five", Code: "FIVE", Name: "5.00 off", Amount: domain.Money{Currency: "USD", Amount: 500}},
+			"SHIP": {PromotionID: "promo-ship", Code: "SHIP", Name: "Free shipping", Amount: money.Zero("USD"), FreeShipping: true},
+		}}
+	}
+
+	t.Run("quote takes discounts off the total", func(t *testing.T) {
+		cart := &fakeCart{items: []CartItem{{ProductID: "p1", Quantity: 2}}}
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, flatShipping(700), newPromotions(), nil, &fakeTx{})
+
+		q, err := svc.Quote(context.Background(), "u1", "", "", []string{"FIVE", "NOPE"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Subtotal.Amount != 4000 || q.Total.Amount != 3500 || len(q.Discounts) != 1 {
+			t.Fatalf("expected 5.00 off 40.00, got %+v", q)
+		}
+		if len(q.RejectedCodes) != 1 || q.RejectedCodes[0].Code != "NOPE" {
+			t.Fatalf("expected NOPE to be rejected, got %+v", q.RejectedCodes)
+		}
+	})
+
+	t.Run("display currency quote values shipping in the catalog currency", func(t *testing.T) {
+		idr := fakeCatalog{products: map[string]Product{"p1": {ID: "p1", Name: "Batik", Currency: "IDR", Amount: 162500}}}
+		rates, err := money.NewStaticRates("USD", map[string]string{"IDR": "16250"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		cart := &fakeCart{items: []CartItem{{ProductID: "p1", Quantity: 1}}}
+		promotions := newPromotions()
+		svc := NewService(cart, idr, cart, &fakeOrders{}, flatShipping(700), promotions, rates, &fakeTx{})
+
+		if _, err := svc.Quote(context.Background(), "u1", "USD", "", nil); err != nil {
+			t.Fatal(err)
+		}
+		want, err := money.Convert(context.Background(), rates, domain.Money{Currency: "USD", Amount: 700}, "IDR")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if promotions.shippingFee != want.Amount {
+			t.Fatalf("expected the 7.00 USD fee as %v, got %d", want, promotions.shippingFee)
+		}
+	})
+
+	t.Run("order is charged the discounted total and redeems the promotions", func(t *testing.T) {
+		cart := &fakeCart{items: []CartItem{{ProductID: "p1", Quantity: 2}}}
+		orders := &fakeOrders{}
+		promotions := newPromotions()
+		svc := NewService(cart, catalog, cart, orders, flatShipping(700), promotions, nil, &fakeTx{})
+
+		placed, err := svc.PlaceOrder(context.Background(), "u1", "", "ID", []string{"FIVE", "SHIP"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if orders.got.Discount != 500 || orders.got.ShippingFee != 0 {
+			t.Fatalf("expected a 5.00 discount and free shipping, got %+v", orders.got)
+		}
+		if placed.Total.Amount != 3500 || placed.Discount.Amount != 500 || len(placed.Discounts) != 2 {
+			t.Fatalf("unexpected order: %+v", placed)
+		}
+		if len(promotions.redeemed) != 2 || !cart.checkedOut {
+			t.Fatalf("expected both promotions redeemed, got %+v", promotions.redeemed)
+		}
+	})
+
+	t.Run("order fails when a code doesn't apply", func(t *testing.T) {
+		cart := &fakeCart{items: []CartItem{{ProductID: "p1", Quantity: 1}}}
+		orders := &fakeOrders{}
+		tx := &fakeTx{}
+		svc := NewService(cart, catalog, cart, orders, flatShipping(700), newPromotions(), nil, tx)
+
+		_, err := svc.PlaceOrder(context.Background(), "u1", "", "ID", []string{"FIVE", "NOPE"})
+		if !errors.Is(err, ErrPromotionRejected) {
+			t.Fatalf("expected ErrPromotionRejected, got %v", err)
+		}
+		if orders.got.UserID != "" || cart.checkedOut || !tx.rolledBack {
+			t.Fatalf("expected no order and the cart left active")
+		}
+	})
+
+	t.Run("used up promotion rolls the order back", func(t *testing.T) {
+		cart := &fakeCart{items: []CartItem{{ProductID: "p1", Quantity: 1}}}
+		promotions := newPromotions()
+		promotions.redeemErr = fmt.Errorf("%w: 5.00 off has been used up", ErrPromotionRejected)
+		tx := &fakeTx{}
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, flatShipping(700), promotions, nil, tx)
+
+		if _, err := svc.PlaceOrder(context.Background(), "u1", "", "ID", []string{"FIVE"}); !errors.Is(err, ErrPromotionRejected) {
+			t.Fatalf("expected ErrPromotionRejected, got %v", err)
+		}
+		if cart.checkedOut || !tx.rolledBack {
+			t.Fatalf("expected rollback without checkout")
+		}
+	})
+}
+
+func TestShipping(t *testing.T) {
+	catalog := fakeCatalog{
+		products: map[string]Product{
+			"p1": {ID: "p1", Name: "Mug", Currency: "IDR", Amount: 50000, WeightGrams: 400},
+			"p2": {ID: "p2", Name: "Tee", Currency: "IDR", Amount: 80000, WeightGrams: 250, HasVariants: true},
+		},
+		variants: map[string]Variant{"v1": {ID: "v1", ProductID: "p2", SKU: "TEE-M", Currency: "IDR", Amount: 90000}},
+	}
+	newCart := func() *fakeCart {
+		return &fakeCart{items: []CartItem{{ProductID: "p1", Quantity: 2}, {ProductID: "p2", VariantID: "v1", Quantity: 1}}}
+	}
+
+	t.Run("quote lists the options for the cart's weight", func(t *testing.T) {
+		cart := newCart()
+		shipping := &weightShipping{}
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, shipping, nil, nil, &fakeTx{})
+
+		q, err := svc.Quote(context.Background(), "u1", "", "ID", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shipping.weight != 1050 || shipping.country != "ID" {
+			t.Fatalf("expected 1050g to ID, got %dg to %q", shipping.weight, shipping.country)
+		}
+		if len(q.ShippingOptions) != 2 || q.ShippingOptions[1].Code != "EXPRESS" || q.ShippingOptions[1].Fee.Amount != 3150 {
+			t.Fatalf("unexpected shipping options: %+v", q.ShippingOptions)
+		}
+		if q.Total.Amount != 190000 {
+			t.Fatalf("expected shipping to stay out of the quote total, got %+v", q.Total)
+		}
+	})
+
+	t.Run("free shipping zeroes the options", func(t *testing.T) {
+		cart := newCart()
+		promotions := &fakePromotions{discounts: map[string]domain.Discount{
+			"SHIP": {PromotionID: "promo-ship", Code: "SHIP", Amount: money.Zero("IDR"), FreeShipping: true},
+		}}
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, &weightShipping{}, promotions, nil, &fakeTx{})
+
+		q, err := svc.Quote(context.Background(), "u1", "", "ID", []string{"SHIP"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, o := range q.ShippingOptions {
+			if o.Fee.Amount != 0 {
+				t.Fatalf("expected free shipping on every option, got %+v", q.ShippingOptions)
+			}
+		}
+	})
+
+	t.Run("order is charged the chosen option's fee", func(t *testing.T) {
+		cart := newCart()
+		orders := &fakeOrders{}
+		svc := NewService(cart, catalog, cart, orders, &weightShipping{}, nil, nil, &fakeTx{})
+
+		placed, err := svc.PlaceOrder(context.Background(), "u1", "express", "id", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if orders.got.ShippingFee != 3150 || placed.Shipping.Code != "EXPRESS" || placed.Shipping.MaxDays != 2 {
+			t.Fatalf("expected EXPRESS for 3150, got fee %d and %+v", orders.got.ShippingFee, placed.Shipping)
+		}
+		if orders.got.ShippingOption != "EXPRESS" || orders.got.Country != "ID" {
+			t.Fatalf("expected the order to record EXPRESS to ID, got %+v", orders.got)
+		}
+	})
+
+	t.Run("order needs a destination", func(t *testing.T) {
+		cart := newCart()
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, &weightShipping{}, nil, nil, &fakeTx{})
+
+		if _, err := svc.PlaceOrder(context.Background(), "u1", "STANDARD", " ", nil); !errors.Is(err, ErrInvalidDestination) {
+			t.Fatalf("expected ErrInvalidDestination, got %v", err)
+		}
+	})
+
+	t.Run("option not offered at the destination", func(t *testing.T) {
+		cart := newCart()
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, &weightShipping{}, nil, nil, &fakeTx{})
+
+		if _, err := svc.PlaceOrder(context.Background(), "u1", "EXPRESS", "SG", nil); !errors.Is(err, ErrUnknownShippingOption) {
+			t.Fatalf("expected ErrUnknownShippingOption, got %v", err)
+		}
+		if cart.checkedOut {
+			t.Fatalf("expected the cart left active")
+		}
+	})
+
+	t.Run("invalid destination", func(t *testing.T) {
+		cart := newCart()
+		svc := NewService(cart, catalog, cart, &fakeOrders{}, &weightShipping{}, nil, nil, &fakeTx{})
+
+		if _, err := svc.Quote(context.Background(), "u1", "", "XX", nil); !errors.Is(err, ErrInvalidDestination) {
+			t.Fatalf("expected ErrInvalidDestination, got %v", err)
+		}
+	})
+}
@@ -1,19 +1,96 @@
 package domain
 
-type Money struct {
-	Currency string
-	Amount   int64
-}
+import (
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
+)
+
+// ShippingStandard is the option orders ship with when none is chosen.
+const ShippingStandard = "STANDARD"
+
+// Money is an amount in the minor unit of its currency.
+type Money = money.Money
 
 type QuoteLine struct {
 	ProductID string
+	VariantID string // empty for products without variants
+	SKU       string
 	Name      string
 	Quantity  int64
 	UnitPrice Money
 	LineTotal Money
+	// DisplayTotal is LineTotal in the currency of the quote total.
+	DisplayTotal Money
+	// WeightGrams is the weight of the whole line; 0 when unknown.
+	WeightGrams int64
+}
+
+// ShippingOption is a way the cart can ship, with its fee in the currency
+// of the quote total.
+type ShippingOption struct {
+	Code    string
+	Name    string
+	Fee     Money
+	MinDays int32 // business days until delivery
+	MaxDays int32
 }
 
 type Quote struct {
 	Lines []QuoteLine
+	// Subtotal is the sum of the lines, in the currency of the total.
+	Subtotal      Money
+	Discounts     []Discount
+	RejectedCodes []RejectedCode
+	// FreeShipping is set when a discount waives the shipping fee.
+	FreeShipping bool
+	// ShippingOptions are the ways the cart can ship to the destination,
+	// cheapest first. Their fees are zero with FreeShipping.
+	ShippingOptions []ShippingOption
+	// Total is Subtotal less the discounts.
 	Total Money
+	// Notices flag lines that changed since they were added to the cart, so
+	// the shopper can be warned before paying.
+	Notices []Notice
+}
+
+// Notice is a cart notice; Kind is one of the cart's notice kinds, such as
+// PRICE_INCREASED or OUT_OF_STOCK.
+type Notice struct {
+	ProductID string
+	VariantID string
+	Kind      string
+	OldPrice  Money // price notices only
+	NewPrice  Money // price notices only
+	Available int32 // OUT_OF_STOCK only
+}
+
+// Discount is a promotion applied to a quote.
+type Discount struct {
+	PromotionID string
+	Code        string // empty for automatic promotions
+	Name        string
+	Amount      Money // in the catalog currency; zero for free shipping
+	// DisplayAmount is Amount in the currency of the quote total.
+	DisplayAmount Money
+	FreeShipping  bool
+}
+
+// RejectedCode is a code the shopper entered that doesn't apply, with why.
+type RejectedCode struct {
+	Code   string
+	Reason string
+}
+
+type PlacedOrder struct {
+	OrderID     string
+	Status      string
+	Lines       []QuoteLine
+	Discounts   []Discount
+	Discount    Money // all discounts together
+	ShippingFee Money
+	Total       Money
+	CreatedAt   time.Time
+	// Shipping is the option the order ships with; its fee is ShippingFee.
+	Shipping ShippingOption
 }
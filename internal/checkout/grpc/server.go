@@ -25,31 +25,124 @@ func (s *Server) Quote(ctx context.Context, req *checkoutv1.QuoteRequest) (*chec
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
 
-	q, err := s.svc.Quote(ctx, req.UserId)
+	q, err := s.svc.Quote(ctx, req.UserId, req.GetDisplayCurrency(), req.GetCountry(), req.GetCouponCodes())
 	if err != nil {
 		if errors.Is(err, app.ErrEmptyCart) {
 			return nil, status.Error(codes.NotFound, "cart is empty")
 		}
+		if errors.Is(err, app.ErrUnsupportedCurrency) || errors.Is(err, app.ErrInvalidDestination) || errors.Is(err, app.ErrInvalidCodes) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, app.ErrInvalidVariant) || errors.Is(err, app.ErrMixedCurrencies) || errors.Is(err, app.ErrUnknownProducts) ||
+			errors.Is(err, app.ErrPromotionRejected) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
 		return nil, status.Errorf(codes.Internal, "quote failed: %v", err)
 	}
 
 	return toProto(q), nil
 }
 
+func (s *Server) PlaceOrder(ctx context.Context, req *checkoutv1.PlaceOrderRequest) (*checkoutv1.PlaceOrderResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	placed, err := s.svc.PlaceOrder(ctx, req.GetUserId(), req.GetShippingOption(), req.GetCountry(), req.GetCouponCodes())
+	if err != nil {
+		switch {
+		case errors.Is(err, app.ErrEmptyCart):
+			return nil, status.Error(codes.FailedPrecondition, "cart is empty")
+		case errors.Is(err, app.ErrUnknownShippingOption), errors.Is(err, app.ErrInvalidDestination), errors.Is(err, app.ErrInvalidCodes):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, app.ErrMixedCurrencies), errors.Is(err, app.ErrInsufficientStock), errors.Is(err, app.ErrInvalidVariant),
+			errors.Is(err, app.ErrUnknownProducts), errors.Is(err, app.ErrPromotionRejected):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "place order failed: %v", err)
+	}
+
+	return &checkoutv1.PlaceOrderResponse{
+		OrderId:       placed.OrderID,
+		Status:        placed.Status,
+		Lines:         toProtoLines(placed.Lines),
+		Discounts:     toProtoDiscounts(placed.Discounts),
+		Discount:      &checkoutv1.Money{Currency: placed.Discount.Currency, Amount: placed.Discount.Amount},
+		ShippingFee:   &checkoutv1.Money{Currency: placed.ShippingFee.Currency, Amount: placed.ShippingFee.Amount},
+		Total:         &checkoutv1.Money{Currency: placed.Total.Currency, Amount: placed.Total.Amount},
+		CreatedAtUnix: placed.CreatedAt.Unix(),
+		Shipping:      toProtoShippingOption(placed.Shipping),
+	}, nil
+}
+
 func toProto(q domain.Quote) *checkoutv1.QuoteResponse {
-	lines := make([]*checkoutv1.QuoteLine, 0, len(q.Lines))
-	for _, ln := range q.Lines {
-		lines = append(lines, &checkoutv1.QuoteLine{
-			ProductId: ln.ProductID,
-			Name:      ln.Name,
-			Quantity:  int32(ln.Quantity),
-			UnitPrice: &checkoutv1.Money{Currency: ln.UnitPrice.Currency, Amount: ln.UnitPrice.Amount},
-			LineTotal: &checkoutv1.Money{Currency: ln.LineTotal.Currency, Amount: ln.LineTotal.Amount},
+	resp := &checkoutv1.QuoteResponse{
+		Lines:        toProtoLines(q.Lines),
+		Subtotal:     &checkoutv1.Money{Currency: q.Subtotal.Currency, Amount: q.Subtotal.Amount},
+		Discounts:    toProtoDiscounts(q.Discounts),
+		FreeShipping: q.FreeShipping,
+		Total:        &checkoutv1.Money{Currency: q.Total.Currency, Amount: q.Total.Amount},
+	}
+	for _, o := range q.ShippingOptions {
+		resp.ShippingOptions = append(resp.ShippingOptions, toProtoShippingOption(o))
+	}
+	for _, r := range q.RejectedCodes {
+		resp.RejectedCodes = append(resp.RejectedCodes, &checkoutv1.RejectedCode{Code: r.Code, Reason: r.Reason})
+	}
+	for _, n := range q.Notices {
+		pb := &checkoutv1.Notice{
+			ProductId: n.ProductID,
+			VariantId: n.VariantID,
+			Kind:      n.Kind,
+			Available: n.Available,
+		}
+		if n.OldPrice.Currency != "" {
+			pb.OldPrice = &checkoutv1.Money{Currency: n.OldPrice.Currency, Amount: n.OldPrice.Amount}
+			pb.NewPrice = &checkoutv1.Money{Currency: n.NewPrice.Currency, Amount: n.NewPrice.Amount}
+		}
+		resp.Notices = append(resp.Notices, pb)
+	}
+	return resp
+}
+
+func toProtoShippingOption(o domain.ShippingOption) *checkoutv1.ShippingOption {
+	return &checkoutv1.ShippingOption{
+		Code:    o.Code,
+		Name:    o.Name,
+		Fee:     &checkoutv1.Money{Currency: o.Fee.Currency, Amount: o.Fee.Amount},
+		MinDays: o.MinDays,
+		MaxDays: o.MaxDays,
+	}
+}
+
+func toProtoDiscounts(in []domain.Discount) []*checkoutv1.Discount {
+	out := make([]*checkoutv1.Discount, 0, len(in))
+	for _, d := range in {
+		out = append(out, &checkoutv1.Discount{
+			PromotionId:   d.PromotionID,
+			Code:          d.Code,
+			Name:          d.Name,
+			Amount:        &checkoutv1.Money{Currency: d.Amount.Currency, Amount: d.Amount.Amount},
+			DisplayAmount: &checkoutv1.Money{Currency: d.DisplayAmount.Currency, Amount: d.DisplayAmount.Amount},
+			FreeShipping:  d.FreeShipping,
 		})
 	}
+	return out
+}
 
-	return &checkoutv1.QuoteResponse{
-		Lines: lines,
-		Total: &checkoutv1.Money{Currency: q.Total.Currency, Amount: q.Total.Amount},
+func toProtoLines(in []domain.QuoteLine) []*checkoutv1.QuoteLine {
+	lines := make([]*checkoutv1.QuoteLine, 0, len(in))
+	for _, ln := range in {
+		lines = append(lines, &checkoutv1.QuoteLine{
+			ProductId:        ln.ProductID,
+			VariantId:        ln.VariantID,
+			Sku:              ln.SKU,
+			Name:             ln.Name,
+			Quantity:         int32(ln.Quantity),
+			UnitPrice:        &checkoutv1.Money{Currency: ln.UnitPrice.Currency, Amount: ln.UnitPrice.Amount},
+			LineTotal:        &checkoutv1.Money{Currency: ln.LineTotal.Currency, Amount: ln.LineTotal.Amount},
+			DisplayLineTotal: &checkoutv1.Money{Currency: ln.DisplayTotal.Currency, Amount: ln.DisplayTotal.Amount},
+		})
 	}
+	return lines
 }
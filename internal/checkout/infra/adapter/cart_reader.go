@@ -4,7 +4,9 @@ import (
 	"context"
 
 	cartapp "github.com/dwikikusuma/shoping-llm/internal/cart/app"
+	cartdomain "github.com/dwikikusuma/shoping-llm/internal/cart/domain"
 	checkoutapp "github.com/dwikikusuma/shoping-llm/internal/checkout/app"
+	checkoutdomain "github.com/dwikikusuma/shoping-llm/internal/checkout/domain"
 )
 
 type CartServiceReader struct {
@@ -25,8 +27,39 @@ func (r *CartServiceReader) GetCart(ctx context.Context, userID string) ([]check
 	for _, it := range cart.Items {
 		items = append(items, checkoutapp.CartItem{
 			ProductID: it.ProductID,
+			VariantID: it.VariantID,
 			Quantity:  int64(it.Quantity),
+			UnitPrice: it.UnitPrice,
 		})
 	}
 	return items, nil
 }
+
+func (r *CartServiceReader) Notices(ctx context.Context, items []checkoutapp.CartItem) ([]checkoutdomain.Notice, error) {
+	cart := cartdomain.Cart{Items: make([]cartdomain.CartItem, 0, len(items))}
+	for _, it := range items {
+		cart.Items = append(cart.Items, cartdomain.CartItem{
+			ProductID: it.ProductID,
+			VariantID: it.VariantID,
+			Quantity:  int32(it.Quantity),
+			UnitPrice: it.UnitPrice,
+		})
+	}
+	notices, err := r.svc.Notices(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]checkoutdomain.Notice, 0, len(notices))
+	for _, n := range notices {
+		out = append(out, checkoutdomain.Notice{
+			ProductID: n.ProductID,
+			VariantID: n.VariantID,
+			Kind:      string(n.Kind),
+			OldPrice:  n.OldPrice,
+			NewPrice:  n.NewPrice,
+			Available: n.Available,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,33 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	cartapp "github.com/dwikikusuma/shoping-llm/internal/cart/app"
+	checkoutapp "github.com/dwikikusuma/shoping-llm/internal/checkout/app"
+)
+
+type CartServiceWriter struct {
+	svc *cartapp.Service
+}
+
+func NewCartServiceWriter(svc *cartapp.Service) *CartServiceWriter {
+	return &CartServiceWriter{svc: svc}
+}
+
+func (w *CartServiceWriter) LockActiveCart(ctx context.Context, userID string) (string, error) {
+	cart, err := w.svc.LockActiveCart(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", checkoutapp.ErrEmptyCart
+	}
+	if err != nil {
+		return "", err
+	}
+	return cart.ID, nil
+}
+
+func (w *CartServiceWriter) MarkCheckedOut(ctx context.Context, cartID string) error {
+	return w.svc.MarkCheckedOut(ctx, cartID)
+}
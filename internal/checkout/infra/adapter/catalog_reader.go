@@ -2,6 +2,10 @@ package adapter
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	catalogapp "github.com/dwikikusuma/shoping-llm/internal/catalog/app"
 	checkoutapp "github.com/dwikikusuma/shoping-llm/internal/checkout/app"
@@ -15,16 +19,68 @@ func NewCatalogServiceReader(svc *catalogapp.Service) *CatalogServiceReader {
 	return &CatalogServiceReader{svc: svc}
 }
 
-func (r *CatalogServiceReader) GetProduct(ctx context.Context, productID string) (checkoutapp.Product, error) {
-	p, err := r.svc.GetProduct(ctx, productID)
-	if err != nil {
-		return checkoutapp.Product{}, err
+func (r *CatalogServiceReader) GetProducts(ctx context.Context, productIDs []string, at time.Time) (map[string]checkoutapp.Product, error) {
+	// BatchGetProducts returns each product once, in request order, so with
+	// duplicates removed the i-th product answers the i-th ID.
+	seen := make(map[string]bool, len(productIDs))
+	unique := make([]string, 0, len(productIDs))
+	for _, id := range productIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
 	}
+	productIDs = unique
 
-	return checkoutapp.Product{
-		ID:       p.ID,
-		Name:     p.Name,
-		Currency: p.Price.Currency,
-		Amount:   p.Price.Amount,
-	}, nil
+	out := make(map[string]checkoutapp.Product, len(productIDs))
+	var missing []string
+	for start := 0; start < len(productIDs); start += catalogapp.MaxBatchProducts {
+		end := min(start+catalogapp.MaxBatchProducts, len(productIDs))
+		products, err := r.svc.BatchGetProducts(ctx, productIDs[start:end], at)
+		var notFound *catalogapp.MissingProductsError
+		if errors.As(err, &notFound) {
+			missing = append(missing, notFound.IDs...)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for i, p := range products {
+			out[productIDs[start+i]] = checkoutapp.Product{
+				ID:          p.ID,
+				Name:        p.Name,
+				Currency:    p.Price.Currency,
+				Amount:      p.Price.Amount,
+				WeightGrams: p.WeightGrams,
+				HasVariants: p.HasVariants,
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", checkoutapp.ErrUnknownProducts, strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+func (r *CatalogServiceReader) GetVariants(ctx context.Context, variantIDs []string) (map[string]checkoutapp.Variant, error) {
+	out := make(map[string]checkoutapp.Variant, len(variantIDs))
+	for start := 0; start < len(variantIDs); start += catalogapp.MaxBatchProducts {
+		end := min(start+catalogapp.MaxBatchProducts, len(variantIDs))
+		variants, err := r.svc.BatchGetVariants(ctx, variantIDs[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		for id, v := range variants {
+			out[id] = checkoutapp.Variant{
+				ID:        v.ID,
+				ProductID: v.ProductID,
+				SKU:       v.SKU,
+				Currency:  v.Price.Currency,
+				Amount:    v.Price.Amount,
+			}
+		}
+	}
+	return out, nil
 }
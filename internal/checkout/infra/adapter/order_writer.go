@@ -0,0 +1,58 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	checkoutapp "github.com/dwikikusuma/shoping-llm/internal/checkout/app"
+	"github.com/dwikikusuma/shoping-llm/internal/checkout/domain"
+	orderapp "github.com/dwikikusuma/shoping-llm/internal/order/app"
+	orderdomain "github.com/dwikikusuma/shoping-llm/internal/order/domain"
+)
+
+type OrderServiceWriter struct {
+	svc *orderapp.Service
+}
+
+func NewOrderServiceWriter(svc *orderapp.Service) *OrderServiceWriter {
+	return &OrderServiceWriter{svc: svc}
+}
+
+func (w *OrderServiceWriter) CreateOrder(ctx context.Context, req checkoutapp.OrderRequest) (domain.PlacedOrder, error) {
+	items := make([]orderdomain.OrderItemRequest, 0, len(req.Lines))
+	for _, ln := range req.Lines {
+		items = append(items, orderdomain.OrderItemRequest{
+			ProductID:  ln.ProductID,
+			VariantID:  ln.VariantID,
+			SKU:        ln.SKU,
+			Name:       ln.Name,
+			UnitAmount: ln.UnitPrice.Amount,
+			Quantity:   int32(ln.Quantity),
+		})
+	}
+
+	o, err := w.svc.CreatePricedOrder(ctx, orderdomain.CreateOrderRequest{
+		UserID:         req.UserID,
+		Currency:       req.Currency,
+		ShippingOption: req.ShippingOption,
+		Country:        req.Country,
+		ShippingAmount: req.ShippingFee,
+		DiscountAmount: req.Discount,
+		Items:          items,
+	})
+	if errors.Is(err, orderapp.ErrInsufficientStock) {
+		return domain.PlacedOrder{}, fmt.Errorf("%w: %v", checkoutapp.ErrInsufficientStock, err)
+	}
+	if err != nil {
+		return domain.PlacedOrder{}, err
+	}
+
+	return domain.PlacedOrder{
+		OrderID:     o.ID,
+		Status:      o.Status,
+		ShippingFee: domain.Money{Currency: req.Currency, Amount: req.ShippingFee},
+		Total:       domain.Money{Currency: req.Currency, Amount: o.TotalAmount},
+		CreatedAt:   o.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	checkoutapp "github.com/dwikikusuma/shoping-llm/internal/checkout/app"
+	"github.com/dwikikusuma/shoping-llm/internal/checkout/domain"
+	promotionapp "github.com/dwikikusuma/shoping-llm/internal/promotion/app"
+	promotiondomain "github.com/dwikikusuma/shoping-llm/internal/promotion/domain"
+)
+
+type PromotionServiceApplier struct {
+	svc *promotionapp.Service
+}
+
+func NewPromotionServiceApplier(svc *promotionapp.Service) *PromotionServiceApplier {
+	return &PromotionServiceApplier{svc: svc}
+}
+
+func (a *PromotionServiceApplier) Apply(ctx context.Context, userID string, quote domain.Quote, codes []string, shippingFee int64) ([]domain.Discount, []domain.RejectedCode, error) {
+	basket := promotiondomain.Basket{Lines: make([]promotiondomain.Line, 0, len(quote.Lines))}
+	for _, ln := range quote.Lines {
+		basket.Lines = append(basket.Lines, promotiondomain.Line{
+			ProductID: ln.ProductID,
+			VariantID: ln.VariantID,
+			Quantity:  ln.Quantity,
+			UnitPrice: ln.UnitPrice,
+		})
+	}
+
+	if len(quote.Lines) > 0 {
+		// The fee is in the currency of the lines, not of the quote total.
+		basket.Shipping = promotiondomain.Money{Currency: quote.Lines[0].UnitPrice.Currency, Amount: shippingFee}
+	}
+
+	result, err := a.svc.Apply(ctx, userID, basket, codes)
+	if errors.Is(err, promotionapp.ErrInvalidInput) {
+		return nil, nil, fmt.Errorf("%w: %v", checkoutapp.ErrInvalidCodes, err)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	discounts := make([]domain.Discount, 0, len(result.Discounts))
+	for _, d := range result.Discounts {
+		discounts = append(discounts, domain.Discount{
+			PromotionID:  d.PromotionID,
+			Code:         d.Code,
+			Name:         d.Name,
+			Amount:       d.Amount,
+			FreeShipping: d.FreeShipping,
+		})
+	}
+	var rejected []domain.RejectedCode
+	for _, r := range result.Rejected {
+		rejected = append(rejected, domain.RejectedCode{Code: r.Code, Reason: r.Reason})
+	}
+	return discounts, rejected, nil
+}
+
+func (a *PromotionServiceApplier) Redeem(ctx context.Context, userID, orderID string, discounts []domain.Discount) error {
+	in := make([]promotiondomain.Discount, 0, len(discounts))
+	for _, d := range discounts {
+		in = append(in, promotiondomain.Discount{
+			PromotionID:  d.PromotionID,
+			Code:         d.Code,
+			Name:         d.Name,
+			Amount:       d.Amount,
+			FreeShipping: d.FreeShipping,
+		})
+	}
+
+	err := a.svc.Redeem(ctx, userID, orderID, in)
+	if errors.Is(err, promotionapp.ErrUnavailable) {
+		return fmt.Errorf("%w: %v", checkoutapp.ErrPromotionRejected, err)
+	}
+	return err
+}
@@ -0,0 +1,49 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	checkoutapp "github.com/dwikikusuma/shoping-llm/internal/checkout/app"
+	"github.com/dwikikusuma/shoping-llm/internal/checkout/domain"
+	shippingapp "github.com/dwikikusuma/shoping-llm/internal/shipping/app"
+	shippingdomain "github.com/dwikikusuma/shoping-llm/internal/shipping/domain"
+)
+
+type ShippingServiceRates struct {
+	svc *shippingapp.Service
+}
+
+func NewShippingServiceRates(svc *shippingapp.Service) *ShippingServiceRates {
+	return &ShippingServiceRates{svc: svc}
+}
+
+func (r *ShippingServiceRates) Options(ctx context.Context, country string, quote domain.Quote) ([]domain.ShippingOption, error) {
+	parcel := shippingdomain.Parcel{Country: country, Currency: quote.Total.Currency}
+	for _, ln := range quote.Lines {
+		parcel.WeightGrams += ln.WeightGrams
+	}
+
+	options, err := r.svc.Options(ctx, parcel)
+	switch {
+	case errors.Is(err, shippingapp.ErrInvalidInput):
+		return nil, fmt.Errorf("%w: %v", checkoutapp.ErrInvalidDestination, err)
+	case errors.Is(err, shippingapp.ErrUnsupportedCurrency):
+		return nil, fmt.Errorf("%w: %v", checkoutapp.ErrUnsupportedCurrency, err)
+	case err != nil:
+		return nil, err
+	}
+
+	out := make([]domain.ShippingOption, 0, len(options))
+	for _, o := range options {
+		out = append(out, domain.ShippingOption{
+			Code:    o.Code,
+			Name:    o.Name,
+			Fee:     o.Fee,
+			MinDays: o.MinDays,
+			MaxDays: o.MaxDays,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,22 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/idempotency/domain"
+)
+
+type KeyRepo interface {
+	// Claim stores a new key, or replaces an expired one, and reports whether
+	// the caller now owns it. It returns false when a live key already exists.
+	Claim(ctx context.Context, scope, key string, requestHash []byte, expiresAt time.Time) (bool, error)
+	Get(ctx context.Context, scope, key string) (domain.Record, error)
+	SaveResponse(ctx context.Context, scope, key string, response []byte) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// TxRunner runs fn inside a single database transaction carried by ctx.
+type TxRunner interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	ErrKeyReused    = errors.New("idempotency key was already used with a different request")
+	ErrInProgress   = errors.New("a request with this idempotency key is still in progress")
+)
+
+const maxKeyLength = 255
+
+type Service struct {
+	repo KeyRepo
+	tx   TxRunner
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+func NewService(repo KeyRepo, tx TxRunner, ttl time.Duration) *Service {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return &Service{
+		repo: repo,
+		tx:   tx,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// Do runs fn at most once per (scope, key) until the key expires.
+//
+// The key is claimed, fn runs and its response is stored in one transaction,
+// so a failed fn releases the key and a concurrent duplicate waits for the
+// first request to finish. A replay with the same request hash returns the
+// stored response with replayed set; a different hash fails with ErrKeyReused.
+func (s *Service) Do(ctx context.Context, scope, key string, requestHash []byte, fn func(ctx context.Context) ([]byte, error)) (response []byte, replayed bool, err error) {
+	key = strings.TrimSpace(key)
+	if key == "" || len(key) > maxKeyLength {
+		return nil, false, ErrInvalidInput
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		claimed, err := s.repo.Claim(ctx, scope, key, requestHash, s.now().Add(s.ttl))
+		if err != nil {
+			return err
+		}
+
+		if !claimed {
+			rec, err := s.repo.Get(ctx, scope, key)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(rec.RequestHash, requestHash) {
+				return ErrKeyReused
+			}
+			if rec.Response == nil {
+				return ErrInProgress
+			}
+			response, replayed = rec.Response, true
+			return nil
+		}
+
+		response, err = fn(ctx)
+		if err != nil {
+			return err
+		}
+		if response == nil {
+			// NULL marks a key whose request has not finished yet.
+			response = []byte{}
+		}
+		return s.repo.SaveResponse(ctx, scope, key, response)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return response, replayed, nil
+}
+
+// PurgeExpired deletes keys whose TTL has passed.
+func (s *Service) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, s.now())
+}
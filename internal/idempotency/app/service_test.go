@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/idempotency/domain"
+)
+
+type fakeRepo struct {
+	records map[string]domain.Record
+}
+
+func newFakeRepo() *fakeRepo { return &fakeRepo{records: map[string]domain.Record{}} }
+
+func (f *fakeRepo) Claim(ctx context.Context, scope, key string, hash []byte, expiresAt time.Time) (bool, error) {
+	if rec, ok := f.records[scope+"/"+key]; ok && rec.ExpiresAt.After(time.Now()) {
+		return false, nil
+	}
+	f.records[scope+"/"+key] = domain.Record{Scope: scope, Key: key, RequestHash: hash, ExpiresAt: expiresAt}
+	return true, nil
+}
+
+func (f *fakeRepo) Get(ctx context.Context, scope, key string) (domain.Record, error) {
+	rec, ok := f.records[scope+"/"+key]
+	if !ok {
+		return domain.Record{}, sql.ErrNoRows
+	}
+	return rec, nil
+}
+
+func (f *fakeRepo) SaveResponse(ctx context.Context, scope, key string, response []byte) error {
+	rec := f.records[scope+"/"+key]
+	rec.Response = response
+	f.records[scope+"/"+key] = rec
+	return nil
+}
+
+func (f *fakeRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+// fakeTx emulates rollback by restoring the repo snapshot when fn fails.
+type fakeTx struct{ repo *fakeRepo }
+
+func (f fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	snapshot := make(map[string]domain.Record, len(f.repo.records))
+	for k, v := range f.repo.records {
+		snapshot[k] = v
+	}
+	if err := fn(ctx); err != nil {
+		f.repo.records = snapshot
+		return err
+	}
+	return nil
+}
+
+func TestDo(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("replay returns the first response", func(t *testing.T) {
+		repo := newFakeRepo()
+		svc := NewService(repo, fakeTx{repo}, time.Hour)
+		calls := 0
+		fn := func(ctx context.Context) ([]byte, error) {
+			calls++
+			return []byte("order-1"), nil
+		}
+
+		first, replayed, err := svc.Do(ctx, "create", "k1", []byte("h1"), fn)
+		if err != nil || replayed || string(first) != "order-1" {
+			t.Fatalf("first call: got (%q,%v,%v)", first, replayed, err)
+		}
+		second, replayed, err := svc.Do(ctx, "create", "k1", []byte("h1"), fn)
+		if err != nil || !replayed || string(second) != "order-1" {
+			t.Fatalf("replay: got (%q,%v,%v)", second, replayed, err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected handler to run once, ran %d times", calls)
+		}
+	})
+
+	t.Run("same key with different payload conflicts", func(t *testing.T) {
+		repo := newFakeRepo()
+		svc := NewService(repo, fakeTx{repo}, time.Hour)
+		fn := func(ctx context.Context) ([]byte, error) { return []byte("ok"), nil }
+
+		if _, _, err := svc.Do(ctx, "create", "k1", []byte("h1"), fn); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, _, err := svc.Do(ctx, "create", "k1", []byte("h2"), fn); !errors.Is(err, ErrKeyReused) {
+			t.Fatalf("expected ErrKeyReused, got %v", err)
+		}
+	})
+
+	t.Run("failed request releases the key", func(t *testing.T) {
+		repo := newFakeRepo()
+		svc := NewService(repo, fakeTx{repo}, time.Hour)
+
+		_, _, err := svc.Do(ctx, "create", "k1", []byte("h1"), func(ctx context.Context) ([]byte, error) {
+			return nil, errors.New("boom")
+		})
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+
+		got, replayed, err := svc.Do(ctx, "create", "k1", []byte("h1"), func(ctx context.Context) ([]byte, error) {
+			return []byte("retry"), nil
+		})
+		if err != nil || replayed || string(got) != "retry" {
+			t.Fatalf("retry: got (%q,%v,%v)", got, replayed, err)
+		}
+	})
+
+	t.Run("empty key is rejected", func(t *testing.T) {
+		repo := newFakeRepo()
+		svc := NewService(repo, fakeTx{repo}, time.Hour)
+
+		if _, _, err := svc.Do(ctx, "create", "  ", nil, nil); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Record is a stored idempotency key. Scope is the operation the key was
+// used for (the gRPC full method name), so the same key may be reused for
+// unrelated operations.
+type Record struct {
+	Scope       string
+	Key         string
+	RequestHash []byte
+	Response    []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
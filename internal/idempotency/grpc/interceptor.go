@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dwikikusuma/shoping-llm/internal/idempotency/app"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	// MetadataKey is the incoming metadata key carrying the client's idempotency key.
+	MetadataKey = "idempotency-key"
+	// ReplayedHeader is set on responses that were served from a stored key.
+	ReplayedHeader = "idempotent-replayed"
+)
+
+// UnaryServerInterceptor makes the given methods idempotent for requests that
+// carry an idempotency key. Keys are scoped to the method and the caller, so
+// two users sending the same key don't collide. Requests without a key, and
+// all other methods, pass through untouched.
+func UnaryServerInterceptor(svc *app.Service, fullMethods ...string) grpc.UnaryServerInterceptor {
+	enabled := make(map[string]bool, len(fullMethods))
+	for _, m := range fullMethods {
+		enabled[m] = true
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !enabled[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		key := keyFromContext(ctx)
+		if key == "" {
+			return handler(ctx, req)
+		}
+
+		reqMsg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		hash, err := hashRequest(reqMsg)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "hash request: %v", err)
+		}
+
+		var resp any
+		raw, replayed, err := svc.Do(ctx, callerScope(info.FullMethod, reqMsg), key, hash, func(ctx context.Context) ([]byte, error) {
+			var err error
+			resp, err = handler(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			msg, ok := resp.(proto.Message)
+			if !ok {
+				return nil, fmt.Errorf("response of %s is not a proto message", info.FullMethod)
+			}
+			return proto.Marshal(msg)
+		})
+		if err != nil {
+			return nil, mapErr(err)
+		}
+		if !replayed {
+			return resp, nil
+		}
+
+		out, err := newResponse(info.FullMethod)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "replay response: %v", err)
+		}
+		if err := proto.Unmarshal(raw, out); err != nil {
+			return nil, status.Errorf(codes.Internal, "replay response: %v", err)
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(ReplayedHeader, "true"))
+		return out, nil
+	}
+}
+
+func keyFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	vals := md.Get(MetadataKey)
+	if len(vals) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(vals[0])
+}
+
+// callerScope scopes keys of fullMethod to the request's user_id. Requests
+// without a user_id field share the method's scope.
+func callerScope(fullMethod string, req proto.Message) string {
+	m := req.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName("user_id")
+	if fd == nil || fd.Kind() != protoreflect.StringKind {
+		return fullMethod
+	}
+	return fullMethod + "|user:" + strings.TrimSpace(m.Get(fd).String())
+}
+
+func hashRequest(req proto.Message) ([]byte, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
+
+// newResponse returns an empty response message for a method such as
+// "/order.v1.OrderService/CreateOrder", looked up in the proto registry.
+func newResponse(fullMethod string) (proto.Message, error) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return nil, fmt.Errorf("malformed method %q", fullMethod)
+	}
+
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, err
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", service)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return nil, fmt.Errorf("method %s not found", fullMethod)
+	}
+
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return nil, err
+	}
+	return mt.New().Interface(), nil
+}
+
+func mapErr(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, app.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, "invalid idempotency key")
+	case errors.Is(err, app.ErrKeyReused):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, app.ErrInProgress):
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return status.Error(codes.Internal, "internal error")
+}
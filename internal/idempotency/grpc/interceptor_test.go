@@ -0,0 +1,25 @@
+package grpc
+
+import (
+	"testing"
+
+	catalogv1 "github.com/dwikikusuma/shoping-llm/api/gen/catalog/v1"
+	orderv1 "github.com/dwikikusuma/shoping-llm/api/gen/order/v1"
+)
+
+func TestCallerScope(t *testing.T) {
+	method := orderv1.OrderService_CreateOrder_FullMethodName
+
+	alice := callerScope(method, &orderv1.CreateOrderRequest{UserId: "alice"})
+	bob := callerScope(method, &orderv1.CreateOrderRequest{UserId: "bob"})
+	if alice == bob {
+		t.Fatalf("expected each user to get their own scope, both got %q", alice)
+	}
+	if again := callerScope(method, &orderv1.CreateOrderRequest{UserId: " alice ", Currency: "IDR"}); again != alice {
+		t.Fatalf("expected the scope to depend only on the user, got %q and %q", alice, again)
+	}
+
+	if got := callerScope(method, &catalogv1.GetProductRequest{Id: "p1"}); got != method {
+		t.Fatalf("expected a request without user_id to use the method's scope, got %q", got)
+	}
+}
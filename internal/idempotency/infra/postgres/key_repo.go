@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/idempotency/domain"
+	"github.com/dwikikusuma/shoping-llm/internal/idempotency/infra/postgres/idempotencydb"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
+)
+
+type KeyRepo struct {
+	q *idempotencydb.Queries
+}
+
+func NewKeyRepo(db *sql.DB) *KeyRepo {
+	return &KeyRepo{q: idempotencydb.New(db)}
+}
+
+// queries joins the caller's transaction when ctx carries one (see pg.TxManager).
+func (r *KeyRepo) queries(ctx context.Context) *idempotencydb.Queries {
+	if tx, ok := pg.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *KeyRepo) Claim(ctx context.Context, scope, key string, requestHash []byte, expiresAt time.Time) (bool, error) {
+	_, err := r.queries(ctx).ClaimIdempotencyKey(ctx, idempotencydb.ClaimIdempotencyKeyParams{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   expiresAt,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *KeyRepo) Get(ctx context.Context, scope, key string) (domain.Record, error) {
+	row, err := r.queries(ctx).GetIdempotencyKey(ctx, idempotencydb.GetIdempotencyKeyParams{
+		Scope: scope,
+		Key:   key,
+	})
+	if err != nil {
+		return domain.Record{}, err
+	}
+
+	return domain.Record{
+		Scope:       row.Scope,
+		Key:         row.Key,
+		RequestHash: row.RequestHash,
+		Response:    row.Response,
+		CreatedAt:   row.CreatedAt,
+		ExpiresAt:   row.ExpiresAt,
+	}, nil
+}
+
+func (r *KeyRepo) SaveResponse(ctx context.Context, scope, key string, response []byte) error {
+	return r.queries(ctx).SaveIdempotencyResponse(ctx, idempotencydb.SaveIdempotencyResponseParams{
+		Scope:    scope,
+		Key:      key,
+		Response: response,
+	})
+}
+
+func (r *KeyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return r.queries(ctx).DeleteExpiredIdempotencyKeys(ctx, now)
+}
@@ -0,0 +1,25 @@
+-- name: ClaimIdempotencyKey :one
+-- Inserts the key, or takes over an expired one. Returns no row when a live
+-- key already exists.
+INSERT INTO idempotency_keys (scope, key, request_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+    ON CONFLICT (scope, key)
+DO UPDATE SET
+    request_hash = EXCLUDED.request_hash,
+           response = NULL,
+           created_at = now(),
+           expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at < now()
+    RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE scope = $1 AND key = $2;
+
+-- name: SaveIdempotencyResponse :exec
+UPDATE idempotency_keys SET response = $3
+WHERE scope = $1 AND key = $2;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE expires_at < $1;
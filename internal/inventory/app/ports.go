@@ -0,0 +1,31 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/inventory/domain"
+)
+
+type StockRepo interface {
+	Get(ctx context.Context, productID string) (domain.Stock, error)
+	// BatchGet returns the stock of the products that have a stock record,
+	// keyed by product ID. Products without one are left out.
+	BatchGet(ctx context.Context, productIDs []string) (map[string]domain.Stock, error)
+	SetOnHand(ctx context.Context, productID string, onHand int32) (domain.Stock, error)
+	AdjustOnHand(ctx context.Context, productID string, delta int32) (domain.Stock, error)
+
+	// ReserveTx holds the items' stock under reference. It fails with
+	// ErrInsufficientStock when any product has too little available.
+	// Products without a stock record aren't tracked: nothing is held for
+	// them and they are left out of the reservation.
+	ReserveTx(ctx context.Context, reference string, items []domain.ReservationItem, expiresAt time.Time) (domain.Reservation, error)
+	LockReservation(ctx context.Context, reference string) (domain.Reservation, error)
+	MoveReservationTx(ctx context.Context, res domain.Reservation, to string) (domain.Reservation, error)
+	LockExpired(ctx context.Context, now time.Time, limit int) ([]domain.Reservation, error)
+}
+
+// TxRunner runs fn inside a single database transaction carried by ctx.
+type TxRunner interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,178 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/inventory/domain"
+)
+
+var (
+	ErrInvalidInput      = errors.New("invalid input")
+	ErrNotFound          = errors.New("not found")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrAlreadyReserved   = errors.New("reference already has a reservation")
+)
+
+const expireBatchSize = 100
+
+type Service struct {
+	repo StockRepo
+	tx   TxRunner
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// NewService returns an inventory service whose reservations expire after ttl
+// unless the caller asks for a different one.
+func NewService(repo StockRepo, tx TxRunner, ttl time.Duration) *Service {
+	if ttl <= 0 {
+		ttl = 30 * time.Minute
+	}
+	return &Service{
+		repo: repo,
+		tx:   tx,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+func (s *Service) GetStock(ctx context.Context, productID string) (domain.Stock, error) {
+	if strings.TrimSpace(productID) == "" {
+		return domain.Stock{}, ErrInvalidInput
+	}
+	return s.repo.Get(ctx, productID)
+}
+
+// BatchGetStock is GetStock for many products, with one query. Products
+// whose stock was never set aren't tracked by the inventory and are left
+// out, so callers can tell them apart from products that ran out.
+func (s *Service) BatchGetStock(ctx context.Context, productIDs []string) (map[string]domain.Stock, error) {
+	for _, id := range productIDs {
+		if strings.TrimSpace(id) == "" {
+			return nil, ErrInvalidInput
+		}
+	}
+	if len(productIDs) == 0 {
+		return map[string]domain.Stock{}, nil
+	}
+	return s.repo.BatchGet(ctx, productIDs)
+}
+
+// SetStock overwrites the on-hand quantity, e.g. after a stock count. It can
+// not go below what is currently reserved.
+func (s *Service) SetStock(ctx context.Context, productID string, onHand int32) (domain.Stock, error) {
+	if strings.TrimSpace(productID) == "" || onHand < 0 {
+		return domain.Stock{}, ErrInvalidInput
+	}
+	return s.repo.SetOnHand(ctx, productID, onHand)
+}
+
+// AdjustStock adds delta (which may be negative) to the on-hand quantity.
+func (s *Service) AdjustStock(ctx context.Context, productID string, delta int32) (domain.Stock, error) {
+	if strings.TrimSpace(productID) == "" || delta == 0 {
+		return domain.Stock{}, ErrInvalidInput
+	}
+	return s.repo.AdjustOnHand(ctx, productID, delta)
+}
+
+// Reserve holds stock for reference until it is committed, released or the
+// reservation expires. ttl <= 0 uses the service default. Products whose
+// stock was never set aren't tracked and are sold without a hold.
+func (s *Service) Reserve(ctx context.Context, reference string, items []domain.ReservationItem, ttl time.Duration) (domain.Reservation, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" || len(items) == 0 {
+		return domain.Reservation{}, ErrInvalidInput
+	}
+	if ttl <= 0 {
+		ttl = s.ttl
+	}
+
+	// Merge duplicate lines and sort by product so that concurrent
+	// reservations always lock inventory rows in the same order.
+	qty := make(map[string]int32, len(items))
+	for i, item := range items {
+		pid := strings.TrimSpace(item.ProductID)
+		if pid == "" || item.Quantity <= 0 {
+			return domain.Reservation{}, fmt.Errorf("%w: item %d", ErrInvalidInput, i)
+		}
+		qty[pid] += item.Quantity
+	}
+	merged := make([]domain.ReservationItem, 0, len(qty))
+	for pid, q := range qty {
+		merged = append(merged, domain.ReservationItem{ProductID: pid, Quantity: q})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].ProductID < merged[j].ProductID })
+
+	return s.repo.ReserveTx(ctx, reference, merged, s.now().Add(ttl))
+}
+
+// Commit turns the held stock into a sale. Committing twice is a no-op.
+func (s *Service) Commit(ctx context.Context, reference string) (domain.Reservation, error) {
+	return s.move(ctx, reference, domain.ReservationCommitted)
+}
+
+// Release gives the stock back. Releasing a reservation that was already
+// released or has expired is a no-op.
+func (s *Service) Release(ctx context.Context, reference string) (domain.Reservation, error) {
+	return s.move(ctx, reference, domain.ReservationReleased)
+}
+
+func (s *Service) move(ctx context.Context, reference, to string) (domain.Reservation, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return domain.Reservation{}, ErrInvalidInput
+	}
+
+	var out domain.Reservation
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		res, err := s.repo.LockReservation(ctx, reference)
+		if err != nil {
+			return err
+		}
+
+		if res.Status == to || (to == domain.ReservationReleased && res.Status == domain.ReservationExpired) {
+			out = res
+			return nil
+		}
+		if err := domain.ValidateReservationTransition(res.Status, to); err != nil {
+			return err
+		}
+
+		out, err = s.repo.MoveReservationTx(ctx, res, to)
+		return err
+	})
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return out, nil
+}
+
+// ExpireReservations releases the stock of reservations whose TTL has passed
+// and returns their references so the owners can react, e.g. by cancelling
+// the order.
+func (s *Service) ExpireReservations(ctx context.Context) ([]string, error) {
+	var refs []string
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		expired, err := s.repo.LockExpired(ctx, s.now(), expireBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, res := range expired {
+			if _, err := s.repo.MoveReservationTx(ctx, res, domain.ReservationExpired); err != nil {
+				return err
+			}
+			refs = append(refs, res.Reference)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
@@ -0,0 +1,234 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/inventory/domain"
+)
+
+// fakeRepo keeps stock and reservations in memory and applies the same stock
+// movements as the Postgres repo.
+type fakeRepo struct {
+	stock        map[string]domain.Stock
+	reservations map[string]domain.Reservation
+	reserved     [][]domain.ReservationItem
+}
+
+func newFakeRepo(onHand map[string]int32) *fakeRepo {
+	f := &fakeRepo{stock: map[string]domain.Stock{}, reservations: map[string]domain.Reservation{}}
+	for pid, n := range onHand {
+		f.stock[pid] = domain.Stock{ProductID: pid, OnHand: n}
+	}
+	return f
+}
+
+func (f *fakeRepo) Get(ctx context.Context, productID string) (domain.Stock, error) {
+	return f.stock[productID], nil
+}
+
+func (f *fakeRepo) BatchGet(ctx context.Context, productIDs []string) (map[string]domain.Stock, error) {
+	out := map[string]domain.Stock{}
+	for _, id := range productIDs {
+		if s, ok := f.stock[id]; ok {
+			out[id] = s
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) SetOnHand(ctx context.Context, productID string, onHand int32) (domain.Stock, error) {
+	s := f.stock[productID]
+	s.ProductID, s.OnHand = productID, onHand
+	f.stock[productID] = s
+	return s, nil
+}
+
+func (f *fakeRepo) AdjustOnHand(ctx context.Context, productID string, delta int32) (domain.Stock, error) {
+	return f.SetOnHand(ctx, productID, f.stock[productID].OnHand+delta)
+}
+
+func (f *fakeRepo) ReserveTx(ctx context.Context, reference string, items []domain.ReservationItem, expiresAt time.Time) (domain.Reservation, error) {
+	f.reserved = append(f.reserved, items)
+	var held []domain.ReservationItem
+	for _, it := range items {
+		s, tracked := f.stock[it.ProductID]
+		if !tracked {
+			continue
+		}
+		if s.Available() < it.Quantity {
+			return domain.Reservation{}, ErrInsufficientStock
+		}
+		held = append(held, it)
+	}
+	for _, it := range held {
+		s := f.stock[it.ProductID]
+		s.Reserved += it.Quantity
+		f.stock[it.ProductID] = s
+	}
+	res := domain.Reservation{ID: reference, Reference: reference, Status: domain.ReservationReserved, Items: held, ExpiresAt: expiresAt}
+	f.reservations[reference] = res
+	return res, nil
+}
+
+func (f *fakeRepo) LockReservation(ctx context.Context, reference string) (domain.Reservation, error) {
+	res, ok := f.reservations[reference]
+	if !ok {
+		return domain.Reservation{}, ErrNotFound
+	}
+	return res, nil
+}
+
+func (f *fakeRepo) MoveReservationTx(ctx context.Context, res domain.Reservation, to string) (domain.Reservation, error) {
+	for _, it := range res.Items {
+		s := f.stock[it.ProductID]
+		switch {
+		case res.Status == domain.ReservationReserved && to == domain.ReservationCommitted:
+			s.OnHand -= it.Quantity
+			s.Reserved -= it.Quantity
+		case res.Status == domain.ReservationReserved:
+			s.Reserved -= it.Quantity
+		case res.Status == domain.ReservationCommitted:
+			s.OnHand += it.Quantity
+		}
+		f.stock[it.ProductID] = s
+	}
+	res.Status = to
+	f.reservations[res.Reference] = res
+	return res, nil
+}
+
+func (f *fakeRepo) LockExpired(ctx context.Context, now time.Time, limit int) ([]domain.Reservation, error) {
+	var out []domain.Reservation
+	for _, res := range f.reservations {
+		if res.Status == domain.ReservationReserved && res.ExpiresAt.Before(now) {
+			out = append(out, res)
+		}
+	}
+	return out, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestReserve(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("merges duplicate products and sorts by product", func(t *testing.T) {
+		repo := newFakeRepo(map[string]int32{"a": 5, "b": 5})
+		svc := NewService(repo, fakeTx{}, time.Minute)
+
+		_, err := svc.Reserve(ctx, "order-1", []domain.ReservationItem{
+			{ProductID: "b", Quantity: 1},
+			{ProductID: "a", Quantity: 2},
+			{ProductID: "b", Quantity: 2},
+		}, 0)
+		if err != nil {
+			t.Fatalf("reserve: %v", err)
+		}
+
+		got := repo.reserved[0]
+		if len(got) != 2 || got[0] != (domain.ReservationItem{ProductID: "a", Quantity: 2}) || got[1] != (domain.ReservationItem{ProductID: "b", Quantity: 3}) {
+			t.Fatalf("items = %+v", got)
+		}
+		if repo.stock["b"].Available() != 2 {
+			t.Fatalf("b available = %d, want 2", repo.stock["b"].Available())
+		}
+	})
+
+	t.Run("fails when stock is short", func(t *testing.T) {
+		repo := newFakeRepo(map[string]int32{"a": 1})
+		svc := NewService(repo, fakeTx{}, time.Minute)
+
+		_, err := svc.Reserve(ctx, "order-1", []domain.ReservationItem{{ProductID: "a", Quantity: 2}}, 0)
+		if !errors.Is(err, ErrInsufficientStock) {
+			t.Fatalf("err = %v, want ErrInsufficientStock", err)
+		}
+	})
+
+	t.Run("holds nothing for untracked products", func(t *testing.T) {
+		repo := newFakeRepo(map[string]int32{"a": 1})
+		svc := NewService(repo, fakeTx{}, time.Minute)
+
+		res, err := svc.Reserve(ctx, "order-1", []domain.ReservationItem{{ProductID: "a", Quantity: 1}, {ProductID: "new", Quantity: 3}}, 0)
+		if err != nil {
+			t.Fatalf("reserve: %v", err)
+		}
+		if len(res.Items) != 1 || res.Items[0].ProductID != "a" {
+			t.Fatalf("items = %+v, want only a", res.Items)
+		}
+	})
+
+	t.Run("rejects non-positive quantities", func(t *testing.T) {
+		svc := NewService(newFakeRepo(nil), fakeTx{}, time.Minute)
+
+		_, err := svc.Reserve(ctx, "order-1", []domain.ReservationItem{{ProductID: "a", Quantity: 0}}, 0)
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("err = %v, want ErrInvalidInput", err)
+		}
+	})
+}
+
+func TestCommitAndRelease(t *testing.T) {
+	ctx := context.Background()
+	items := []domain.ReservationItem{{ProductID: "a", Quantity: 2}}
+
+	t.Run("commit consumes stock and release restocks it", func(t *testing.T) {
+		repo := newFakeRepo(map[string]int32{"a": 5})
+		svc := NewService(repo, fakeTx{}, time.Minute)
+		if _, err := svc.Reserve(ctx, "order-1", items, 0); err != nil {
+			t.Fatalf("reserve: %v", err)
+		}
+
+		if _, err := svc.Commit(ctx, "order-1"); err != nil {
+			t.Fatalf("commit: %v", err)
+		}
+		if s := repo.stock["a"]; s.OnHand != 3 || s.Reserved != 0 {
+			t.Fatalf("after commit: %+v", s)
+		}
+
+		if _, err := svc.Release(ctx, "order-1"); err != nil {
+			t.Fatalf("release: %v", err)
+		}
+		if s := repo.stock["a"]; s.OnHand != 5 || s.Reserved != 0 {
+			t.Fatalf("after release: %+v", s)
+		}
+
+		// A second release must not restock again.
+		if _, err := svc.Release(ctx, "order-1"); err != nil {
+			t.Fatalf("second release: %v", err)
+		}
+		if s := repo.stock["a"]; s.OnHand != 5 {
+			t.Fatalf("after second release: %+v", s)
+		}
+	})
+
+	t.Run("expired reservations cannot be committed", func(t *testing.T) {
+		repo := newFakeRepo(map[string]int32{"a": 5})
+		svc := NewService(repo, fakeTx{}, time.Minute)
+		if _, err := svc.Reserve(ctx, "order-1", items, 0); err != nil {
+			t.Fatalf("reserve: %v", err)
+		}
+
+		svc.now = func() time.Time { return time.Now().Add(time.Hour) }
+		refs, err := svc.ExpireReservations(ctx)
+		if err != nil || len(refs) != 1 || refs[0] != "order-1" {
+			t.Fatalf("expire: refs=%v err=%v", refs, err)
+		}
+		if s := repo.stock["a"]; s.Reserved != 0 {
+			t.Fatalf("after expiry: %+v", s)
+		}
+
+		if _, err := svc.Commit(ctx, "order-1"); !errors.Is(err, domain.ErrInvalidTransition) {
+			t.Fatalf("commit err = %v, want ErrInvalidTransition", err)
+		}
+		if _, err := svc.Release(ctx, "order-1"); err != nil {
+			t.Fatalf("release after expiry: %v", err)
+		}
+	})
+}
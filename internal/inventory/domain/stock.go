@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Stock struct {
+	ProductID string
+	OnHand    int32
+	Reserved  int32
+	UpdatedAt time.Time
+}
+
+// Available is what can still be reserved.
+func (s Stock) Available() int32 {
+	return s.OnHand - s.Reserved
+}
+
+const (
+	ReservationReserved  = "RESERVED"
+	ReservationCommitted = "COMMITTED"
+	ReservationReleased  = "RELEASED"
+	ReservationExpired   = "EXPIRED"
+)
+
+var ErrInvalidTransition = errors.New("invalid reservation transition")
+
+// A RESERVED reservation holds stock until it is committed (the goods are
+// sold), released (the order was cancelled) or expires. A COMMITTED
+// reservation can still be released, which puts the goods back on hand.
+var reservationTransitions = map[string][]string{
+	ReservationReserved:  {ReservationCommitted, ReservationReleased, ReservationExpired},
+	ReservationCommitted: {ReservationReleased},
+}
+
+func ValidateReservationTransition(from, to string) error {
+	for _, next := range reservationTransitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+}
+
+type ReservationItem struct {
+	ProductID string
+	Quantity  int32
+}
+
+// Reservation holds stock for a reference, usually an order ID.
+type Reservation struct {
+	ID        string
+	Reference string
+	Status    string
+	Items     []ReservationItem
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
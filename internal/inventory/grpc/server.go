@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	inventoryv1 "github.com/dwikikusuma/shoping-llm/api/gen/inventory/v1"
+	"github.com/dwikikusuma/shoping-llm/internal/inventory/app"
+	"github.com/dwikikusuma/shoping-llm/internal/inventory/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	inventoryv1.UnimplementedInventoryServiceServer
+	svc *app.Service
+}
+
+func NewServer(svc *app.Service) *Server {
+	return &Server{svc: svc}
+}
+
+func (s *Server) GetStock(ctx context.Context, req *inventoryv1.GetStockRequest) (*inventoryv1.GetStockResponse, error) {
+	stock, err := s.svc.GetStock(ctx, req.GetProductId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &inventoryv1.GetStockResponse{Stock: toProtoStock(stock)}, nil
+}
+
+func (s *Server) SetStock(ctx context.Context, req *inventoryv1.SetStockRequest) (*inventoryv1.SetStockResponse, error) {
+	stock, err := s.svc.SetStock(ctx, req.GetProductId(), req.GetOnHand())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &inventoryv1.SetStockResponse{Stock: toProtoStock(stock)}, nil
+}
+
+func (s *Server) AdjustStock(ctx context.Context, req *inventoryv1.AdjustStockRequest) (*inventoryv1.AdjustStockResponse, error) {
+	stock, err := s.svc.AdjustStock(ctx, req.GetProductId(), req.GetDelta())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &inventoryv1.AdjustStockResponse{Stock: toProtoStock(stock)}, nil
+}
+
+func (s *Server) Reserve(ctx context.Context, req *inventoryv1.ReserveRequest) (*inventoryv1.ReserveResponse, error) {
+	items := make([]domain.ReservationItem, 0, len(req.GetItems()))
+	for _, item := range req.GetItems() {
+		items = append(items, domain.ReservationItem{
+			ProductID: item.GetProductId(),
+			Quantity:  item.GetQuantity(),
+		})
+	}
+
+	res, err := s.svc.Reserve(ctx, req.GetReference(), items, time.Duration(req.GetTtlSeconds())*time.Second)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &inventoryv1.ReserveResponse{Reservation: toProtoReservation(res)}, nil
+}
+
+func (s *Server) Commit(ctx context.Context, req *inventoryv1.ReservationRequest) (*inventoryv1.ReservationResponse, error) {
+	res, err := s.svc.Commit(ctx, req.GetReference())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &inventoryv1.ReservationResponse{Reservation: toProtoReservation(res)}, nil
+}
+
+func (s *Server) Release(ctx context.Context, req *inventoryv1.ReservationRequest) (*inventoryv1.ReservationResponse, error) {
+	res, err := s.svc.Release(ctx, req.GetReference())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &inventoryv1.ReservationResponse{Reservation: toProtoReservation(res)}, nil
+}
+
+func toProtoStock(s domain.Stock) *inventoryv1.Stock {
+	var updatedAt int64
+	if !s.UpdatedAt.IsZero() {
+		updatedAt = s.UpdatedAt.Unix()
+	}
+	return &inventoryv1.Stock{
+		ProductId:     s.ProductID,
+		OnHand:        s.OnHand,
+		Reserved:      s.Reserved,
+		Available:     s.Available(),
+		UpdatedAtUnix: updatedAt,
+	}
+}
+
+func toProtoReservation(r domain.Reservation) *inventoryv1.Reservation {
+	items := make([]*inventoryv1.ReservationItem, 0, len(r.Items))
+	for _, item := range r.Items {
+		items = append(items, &inventoryv1.ReservationItem{
+			ProductId: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+	return &inventoryv1.Reservation{
+		Id:            r.ID,
+		Reference:     r.Reference,
+		Status:        r.Status,
+		Items:         items,
+		ExpiresAtUnix: r.ExpiresAt.Unix(),
+		CreatedAtUnix: r.CreatedAt.Unix(),
+	}
+}
+
+func mapErr(err error) error {
+	if errors.Is(err, app.ErrInvalidInput) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, app.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, app.ErrInsufficientStock) || errors.Is(err, domain.ErrInvalidTransition) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, app.ErrAlreadyReserved) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,324 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/inventory/app"
+	"github.com/dwikikusuma/shoping-llm/internal/inventory/domain"
+	"github.com/dwikikusuma/shoping-llm/internal/inventory/infra/postgres/inventorydb"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type InventoryRepo struct {
+	*inventorydb.Queries
+	db *sql.DB
+}
+
+func NewInventoryRepo(db *sql.DB) *InventoryRepo {
+	return &InventoryRepo{
+		Queries: inventorydb.New(db),
+		db:      db,
+	}
+}
+
+// execTX runs fn in its own transaction, or in the caller's transaction when
+// ctx carries one (see pg.TxManager).
+func (r *InventoryRepo) execTX(ctx context.Context, fn func(queries *inventorydb.Queries) error) error {
+	return pg.ExecTx(ctx, r.db, func(tx *sql.Tx) error {
+		return fn(r.Queries.WithTx(tx))
+	})
+}
+
+func (r *InventoryRepo) queries(ctx context.Context) *inventorydb.Queries {
+	if tx, ok := pg.TxFromContext(ctx); ok {
+		return r.Queries.WithTx(tx)
+	}
+	return r.Queries
+}
+
+func (r *InventoryRepo) Get(ctx context.Context, productID string) (domain.Stock, error) {
+	pid, err := parseUUID(productID)
+	if err != nil {
+		return domain.Stock{}, err
+	}
+
+	row, err := r.queries(ctx).GetInventoryItem(ctx, pid)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Products nobody has stocked yet simply have nothing on hand.
+		return domain.Stock{ProductID: pid.String()}, nil
+	}
+	if err != nil {
+		return domain.Stock{}, err
+	}
+	return toDomainStock(row), nil
+}
+
+func (r *InventoryRepo) BatchGet(ctx context.Context, productIDs []string) (map[string]domain.Stock, error) {
+	pids := make([]uuid.UUID, 0, len(productIDs))
+	for _, id := range productIDs {
+		pid, err := parseUUID(id)
+		if err != nil {
+			return nil, err
+		}
+		pids = append(pids, pid)
+	}
+
+	rows, err := r.queries(ctx).ListInventoryItems(ctx, pids)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]domain.Stock, len(rows))
+	for _, row := range rows {
+		out[row.ProductID.String()] = toDomainStock(row)
+	}
+	return out, nil
+}
+
+func (r *InventoryRepo) SetOnHand(ctx context.Context, productID string, onHand int32) (domain.Stock, error) {
+	pid, err := parseUUID(productID)
+	if err != nil {
+		return domain.Stock{}, err
+	}
+
+	row, err := r.queries(ctx).UpsertInventoryOnHand(ctx, inventorydb.UpsertInventoryOnHandParams{
+		ProductID: pid,
+		OnHand:    onHand,
+	})
+	if err != nil {
+		if pg.IsCheckViolation(err) {
+			return domain.Stock{}, app.ErrInsufficientStock
+		}
+		return domain.Stock{}, err
+	}
+	return toDomainStock(row), nil
+}
+
+// AdjustOnHand adds delta to the on-hand quantity. It fails with
+// app.ErrInsufficientStock when that would drop below what is reserved.
+func (r *InventoryRepo) AdjustOnHand(ctx context.Context, productID string, delta int32) (domain.Stock, error) {
+	pid, err := parseUUID(productID)
+	if err != nil {
+		return domain.Stock{}, err
+	}
+
+	row, err := r.queries(ctx).AdjustInventoryOnHand(ctx, inventorydb.AdjustInventoryOnHandParams{
+		Delta:     delta,
+		ProductID: pid,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		if delta < 0 {
+			return domain.Stock{}, app.ErrInsufficientStock
+		}
+		return r.SetOnHand(ctx, productID, delta)
+	}
+	if err != nil {
+		return domain.Stock{}, err
+	}
+	return toDomainStock(row), nil
+}
+
+// ReserveTx holds stock for every tracked item and records the reservation.
+// Items must be sorted by product ID so concurrent reservations lock rows in
+// the same order.
+func (r *InventoryRepo) ReserveTx(ctx context.Context, reference string, items []domain.ReservationItem, expiresAt time.Time) (domain.Reservation, error) {
+	var out domain.Reservation
+
+	err := r.execTX(ctx, func(q *inventorydb.Queries) error {
+		res, err := q.CreateReservation(ctx, inventorydb.CreateReservationParams{
+			Reference: reference,
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			if pg.IsUniqueViolation(err) {
+				return app.ErrAlreadyReserved
+			}
+			return fmt.Errorf("failed to create reservation: %w", err)
+		}
+
+		var held []domain.ReservationItem
+		for _, item := range items {
+			pid, err := parseUUID(item.ProductID)
+			if err != nil {
+				return err
+			}
+
+			_, err = q.ReserveInventory(ctx, inventorydb.ReserveInventoryParams{
+				Quantity:  item.Quantity,
+				ProductID: pid,
+			})
+			if errors.Is(err, sql.ErrNoRows) {
+				// No row means nobody tracks the product's stock, so there
+				// is nothing to hold.
+				_, err = q.GetInventoryItem(ctx, pid)
+				if errors.Is(err, sql.ErrNoRows) {
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to reserve product %s: %w", item.ProductID, err)
+				}
+				return fmt.Errorf("%w: product %s", app.ErrInsufficientStock, item.ProductID)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to reserve product %s: %w", item.ProductID, err)
+			}
+
+			err = q.AddReservationItem(ctx, inventorydb.AddReservationItemParams{
+				ReservationID: res.ID,
+				ProductID:     pid,
+				Quantity:      item.Quantity,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record reservation item: %w", err)
+			}
+			held = append(held, item)
+		}
+
+		out = toDomainReservation(res)
+		out.Items = held
+		return nil
+	})
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return out, nil
+}
+
+// LockReservation loads the reservation for reference and locks it until the
+// surrounding transaction ends.
+func (r *InventoryRepo) LockReservation(ctx context.Context, reference string) (domain.Reservation, error) {
+	q := r.queries(ctx)
+
+	res, err := q.GetReservationByReferenceForUpdate(ctx, reference)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Reservation{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return r.withItems(ctx, q, res)
+}
+
+// MoveReservationTx moves res to status `to` and applies the matching stock
+// movement: committing consumes the held stock, releasing or expiring a
+// reservation gives it back, and releasing a committed one restocks it.
+func (r *InventoryRepo) MoveReservationTx(ctx context.Context, res domain.Reservation, to string) (domain.Reservation, error) {
+	id, err := parseUUID(res.ID)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+
+	var out domain.Reservation
+	err = r.execTX(ctx, func(q *inventorydb.Queries) error {
+		for _, item := range res.Items {
+			pid, err := parseUUID(item.ProductID)
+			if err != nil {
+				return err
+			}
+			params := inventorydb.ReserveInventoryParams{Quantity: item.Quantity, ProductID: pid}
+
+			switch {
+			case res.Status == domain.ReservationReserved && to == domain.ReservationCommitted:
+				_, err = q.CommitInventory(ctx, inventorydb.CommitInventoryParams(params))
+			case res.Status == domain.ReservationReserved:
+				_, err = q.ReleaseInventory(ctx, inventorydb.ReleaseInventoryParams(params))
+			case res.Status == domain.ReservationCommitted:
+				_, err = q.RestockInventory(ctx, inventorydb.RestockInventoryParams(params))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to move stock for product %s: %w", item.ProductID, err)
+			}
+		}
+
+		row, err := q.UpdateReservationStatus(ctx, inventorydb.UpdateReservationStatusParams{
+			ID:     id,
+			Status: to,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update reservation status: %w", err)
+		}
+
+		out = toDomainReservation(row)
+		out.Items = res.Items
+		return nil
+	})
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return out, nil
+}
+
+// LockExpired returns up to limit RESERVED reservations that expired before
+// now, locked for the surrounding transaction. Rows locked by another worker
+// are skipped.
+func (r *InventoryRepo) LockExpired(ctx context.Context, now time.Time, limit int) ([]domain.Reservation, error) {
+	q := r.queries(ctx)
+
+	rows, err := q.ListExpiredReservations(ctx, inventorydb.ListExpiredReservationsParams{
+		ExpiresAt: now,
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.Reservation, 0, len(rows))
+	for _, row := range rows {
+		res, err := r.withItems(ctx, q, row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, res)
+	}
+	return out, nil
+}
+
+func (r *InventoryRepo) withItems(ctx context.Context, q *inventorydb.Queries, row inventorydb.InventoryReservation) (domain.Reservation, error) {
+	items, err := q.ListReservationItems(ctx, row.ID)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+
+	res := toDomainReservation(row)
+	res.Items = make([]domain.ReservationItem, 0, len(items))
+	for _, item := range items {
+		res.Items = append(res.Items, domain.ReservationItem{
+			ProductID: item.ProductID.String(),
+			Quantity:  item.Quantity,
+		})
+	}
+	return res, nil
+}
+
+func parseUUID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return uuid.Nil, app.ErrInvalidInput
+	}
+	return id, nil
+}
+
+func toDomainStock(row inventorydb.InventoryItem) domain.Stock {
+	return domain.Stock{
+		ProductID: row.ProductID.String(),
+		OnHand:    row.OnHand,
+		Reserved:  row.Reserved,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+func toDomainReservation(row inventorydb.InventoryReservation) domain.Reservation {
+	return domain.Reservation{
+		ID:        row.ID.String(),
+		Reference: row.Reference,
+		Status:    row.Status,
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS inventory_reservation_items;
+DROP TABLE IF EXISTS inventory_reservations;
+DROP TABLE IF EXISTS inventory_items;
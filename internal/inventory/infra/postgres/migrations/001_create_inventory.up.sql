@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS inventory_items (
+    product_id  UUID PRIMARY KEY,
+    on_hand     INT NOT NULL DEFAULT 0 CHECK (on_hand >= 0),
+    reserved    INT NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CHECK (reserved <= on_hand)
+);
+
+CREATE TABLE IF NOT EXISTS inventory_reservations (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reference   TEXT NOT NULL UNIQUE,
+    status      TEXT NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CHECK (status IN ('RESERVED','COMMITTED','RELEASED','EXPIRED'))
+);
+
+-- Only RESERVED reservations can expire
+CREATE INDEX IF NOT EXISTS ix_inventory_reservations_expires_at
+    ON inventory_reservations(expires_at)
+    WHERE status = 'RESERVED';
+
+CREATE TABLE IF NOT EXISTS inventory_reservation_items (
+    reservation_id  UUID NOT NULL REFERENCES inventory_reservations(id) ON DELETE CASCADE,
+    product_id      UUID NOT NULL,
+    quantity        INT NOT NULL CHECK (quantity > 0),
+
+    PRIMARY KEY (reservation_id, product_id)
+);
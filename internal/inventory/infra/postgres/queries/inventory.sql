@@ -0,0 +1,82 @@
+-- name: GetInventoryItem :one
+SELECT * FROM inventory_items
+WHERE product_id = $1;
+
+-- name: ListInventoryItems :many
+SELECT * FROM inventory_items
+WHERE product_id = ANY(sqlc.arg(product_ids)::uuid[]);
+
+-- name: UpsertInventoryOnHand :one
+INSERT INTO inventory_items (product_id, on_hand)
+VALUES ($1, $2)
+    ON CONFLICT (product_id)
+DO UPDATE SET
+    on_hand    = EXCLUDED.on_hand,
+           updated_at = now()
+           RETURNING *;
+
+-- name: AdjustInventoryOnHand :one
+UPDATE inventory_items
+SET on_hand = on_hand + sqlc.arg(delta)::int, updated_at = now()
+WHERE product_id = sqlc.arg(product_id)
+  AND on_hand + sqlc.arg(delta)::int >= reserved
+    RETURNING *;
+
+-- name: ReserveInventory :one
+UPDATE inventory_items
+SET reserved = reserved + sqlc.arg(quantity)::int, updated_at = now()
+WHERE product_id = sqlc.arg(product_id)
+  AND on_hand - reserved >= sqlc.arg(quantity)::int
+    RETURNING *;
+
+-- name: ReleaseInventory :one
+UPDATE inventory_items
+SET reserved = reserved - sqlc.arg(quantity)::int, updated_at = now()
+WHERE product_id = sqlc.arg(product_id)
+    RETURNING *;
+
+-- name: CommitInventory :one
+UPDATE inventory_items
+SET on_hand  = on_hand - sqlc.arg(quantity)::int,
+    reserved = reserved - sqlc.arg(quantity)::int,
+    updated_at = now()
+WHERE product_id = sqlc.arg(product_id)
+    RETURNING *;
+
+-- name: RestockInventory :one
+UPDATE inventory_items
+SET on_hand = on_hand + sqlc.arg(quantity)::int, updated_at = now()
+WHERE product_id = sqlc.arg(product_id)
+    RETURNING *;
+
+-- name: CreateReservation :one
+INSERT INTO inventory_reservations (reference, status, expires_at)
+VALUES ($1, 'RESERVED', $2)
+    RETURNING *;
+
+-- name: AddReservationItem :exec
+INSERT INTO inventory_reservation_items (reservation_id, product_id, quantity)
+VALUES ($1, $2, $3);
+
+-- name: GetReservationByReferenceForUpdate :one
+SELECT * FROM inventory_reservations
+WHERE reference = $1
+    FOR UPDATE;
+
+-- name: ListReservationItems :many
+SELECT * FROM inventory_reservation_items
+WHERE reservation_id = $1
+ORDER BY product_id ASC;
+
+-- name: UpdateReservationStatus :one
+UPDATE inventory_reservations
+SET status = $2, updated_at = now()
+WHERE id = $1
+    RETURNING *;
+
+-- name: ListExpiredReservations :many
+SELECT * FROM inventory_reservations
+WHERE status = 'RESERVED' AND expires_at < $1
+ORDER BY expires_at ASC
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED;
@@ -8,4 +8,30 @@ import (
 
 type OrderRepo interface {
 	CreateOrderTx(ctx context.Context, order domain.Order) (domain.Order, error)
+	Get(ctx context.Context, id string) (domain.Order, error)
+	UpdateStatusTx(ctx context.Context, change domain.StatusChange) (domain.Order, error)
+	ListByUser(ctx context.Context, userID, status string, limit int, cursor string) ([]domain.Order, string, error)
+	// HasOrdered reports whether the user has an order in status that
+	// contains the product.
+	HasOrdered(ctx context.Context, userID, productID, status string) (bool, error)
+}
+
+// StockReserver holds stock for an order from creation until it is paid
+// (Commit) or cancelled (Release). The order ID is the reservation reference.
+type StockReserver interface {
+	Reserve(ctx context.Context, orderID string, items []domain.OrderItem) error
+	Commit(ctx context.Context, orderID string) error
+	Release(ctx context.Context, orderID string) error
+}
+
+// ShippingPricer returns the fee, in currency, for shipping the items to
+// country with the given option. It fails with ErrInvalidInput when the
+// option isn't offered there.
+type ShippingPricer interface {
+	Fee(ctx context.Context, option, country, currency string, items []domain.OrderItemRequest) (int64, error)
+}
+
+// TxRunner runs fn inside a single database transaction carried by ctx.
+type TxRunner interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -2,27 +2,63 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/dwikikusuma/shoping-llm/internal/order/domain"
 )
 
+var (
+	ErrInvalidInput      = errors.New("invalid input")
+	ErrNotFound          = errors.New("not found")
+	ErrConflict          = errors.New("order was modified concurrently")
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// defaultShippingOption is the option orders ship with when none is chosen.
+const defaultShippingOption = "STANDARD"
+
 type Service struct {
-	repo OrderRepo
+	repo     OrderRepo
+	stock    StockReserver
+	shipping ShippingPricer
+	tx       TxRunner
 }
 
-const (
-	OrderStatusPending = "PENDING"
-)
-
-func NewService(repo OrderRepo) *Service {
-	return &Service{repo: repo}
+func NewService(repo OrderRepo, stock StockReserver, shipping ShippingPricer, tx TxRunner) *Service {
+	return &Service{repo: repo, stock: stock, shipping: shipping, tx: tx}
 }
 
+// CreateOrder creates an order shipped with req.ShippingOption to
+// req.Country. The shipping fee is priced here; req.ShippingAmount is
+// ignored.
 func (s *Service) CreateOrder(ctx context.Context, req domain.CreateOrderRequest) (domain.OrderResponse, error) {
+	req, err := normalizeShipping(req)
+	if err != nil {
+		return domain.OrderResponse{}, err
+	}
+	fee, err := s.shipping.Fee(ctx, req.ShippingOption, req.Country, req.Currency, req.Items)
+	if err != nil {
+		return domain.OrderResponse{}, err
+	}
+	req.ShippingAmount = fee
+	return s.CreatePricedOrder(ctx, req)
+}
+
+// CreatePricedOrder creates an order with the shipping fee in the request,
+// for callers that have priced shipping themselves, such as checkout.
+func (s *Service) CreatePricedOrder(ctx context.Context, req domain.CreateOrderRequest) (domain.OrderResponse, error) {
+	req, err := normalizeShipping(req)
+	if err != nil {
+		return domain.OrderResponse{}, err
+	}
 	if req.ShippingAmount < 0 {
 		return domain.OrderResponse{}, fmt.Errorf("shipping amount cannot be negative, got %d", req.ShippingAmount)
 	}
+	if req.DiscountAmount < 0 {
+		return domain.OrderResponse{}, fmt.Errorf("discount amount cannot be negative, got %d", req.DiscountAmount)
+	}
 
 	orderItem := make([]domain.OrderItem, 0, len(req.Items))
 	var subTotalAmount int64 = 0
@@ -37,6 +73,8 @@ func (s *Service) CreateOrder(ctx context.Context, req domain.CreateOrderRequest
 
 		orderItem = append(orderItem, domain.OrderItem{
 			ProductID:       item.ProductID,
+			VariantID:       item.VariantID,
+			SKU:             item.SKU,
 			Name:            item.Name,
 			UnitAmount:      item.UnitAmount,
 			Quantity:        item.Quantity,
@@ -45,18 +83,34 @@ func (s *Service) CreateOrder(ctx context.Context, req domain.CreateOrderRequest
 
 		subTotalAmount += item.UnitAmount * int64(item.Quantity)
 	}
+	if req.DiscountAmount > subTotalAmount {
+		return domain.OrderResponse{}, fmt.Errorf("discount amount %d exceeds the subtotal %d", req.DiscountAmount, subTotalAmount)
+	}
 
 	order := domain.Order{
 		UserID:         req.UserID,
-		Status:         OrderStatusPending,
+		Status:         domain.StatusPending,
 		Currency:       req.Currency,
 		ShippingAmount: req.ShippingAmount,
+		ShippingOption: req.ShippingOption,
+		Country:        req.Country,
 		SubTotalAmount: subTotalAmount,
-		TotalAmount:    subTotalAmount + req.ShippingAmount,
+		DiscountAmount: req.DiscountAmount,
+		TotalAmount:    subTotalAmount - req.DiscountAmount + req.ShippingAmount,
 		OrderItems:     orderItem,
 	}
 
-	createdOrder, err := s.repo.CreateOrderTx(ctx, order)
+	// The order and its stock reservation are created together: if the stock
+	// is not there, no order is left behind.
+	var createdOrder domain.Order
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		createdOrder, err = s.repo.CreateOrderTx(ctx, order)
+		if err != nil {
+			return err
+		}
+		return s.stock.Reserve(ctx, createdOrder.ID, createdOrder.OrderItems)
+	})
 	if err != nil {
 		return domain.OrderResponse{}, err
 	}
@@ -68,3 +122,110 @@ func (s *Service) CreateOrder(ctx context.Context, req domain.CreateOrderRequest
 		CreatedAt:   createdOrder.CreatedAt,
 	}, nil
 }
+
+// normalizeShipping upper-cases the shipping option and country, defaults
+// the option and requires the country: every order must say where it ships.
+func normalizeShipping(req domain.CreateOrderRequest) (domain.CreateOrderRequest, error) {
+	req.ShippingOption = strings.ToUpper(strings.TrimSpace(req.ShippingOption))
+	if req.ShippingOption == "" {
+		req.ShippingOption = defaultShippingOption
+	}
+	req.Country = strings.ToUpper(strings.TrimSpace(req.Country))
+	if len(req.Country) != 2 {
+		return domain.CreateOrderRequest{}, fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code, got %q", ErrInvalidInput, req.Country)
+	}
+	return req, nil
+}
+
+func (s *Service) GetOrder(ctx context.Context, id string) (domain.Order, error) {
+	if strings.TrimSpace(id) == "" {
+		return domain.Order{}, ErrInvalidInput
+	}
+	return s.repo.Get(ctx, id)
+}
+
+func (s *Service) ListOrders(ctx context.Context, userID, status string, limit int, cursor string) ([]domain.Order, string, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, "", ErrInvalidInput
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	status = strings.ToUpper(strings.TrimSpace(status))
+	if status != "" && !domain.IsValidStatus(status) {
+		return nil, "", ErrInvalidInput
+	}
+	return s.repo.ListByUser(ctx, userID, status, limit, cursor)
+}
+
+// HasReceived reports whether the user has a fulfilled order containing the
+// product.
+func (s *Service) HasReceived(ctx context.Context, userID, productID string) (bool, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" || strings.TrimSpace(productID) == "" {
+		return false, ErrInvalidInput
+	}
+	return s.repo.HasOrdered(ctx, userID, productID, domain.StatusFulfilled)
+}
+
+func (s *Service) MarkPaid(ctx context.Context, orderID, actor, reason string) (domain.Order, error) {
+	return s.changeStatus(ctx, orderID, domain.StatusPaid, actor, reason)
+}
+
+func (s *Service) CancelOrder(ctx context.Context, orderID, actor, reason string) (domain.Order, error) {
+	if strings.TrimSpace(reason) == "" {
+		return domain.Order{}, fmt.Errorf("%w: cancel reason is required", ErrInvalidInput)
+	}
+	return s.changeStatus(ctx, orderID, domain.StatusCancelled, actor, reason)
+}
+
+func (s *Service) FulfillOrder(ctx context.Context, orderID, actor, reason string) (domain.Order, error) {
+	return s.changeStatus(ctx, orderID, domain.StatusFulfilled, actor, reason)
+}
+
+func (s *Service) changeStatus(ctx context.Context, orderID, to, actor, reason string) (domain.Order, error) {
+	actor = strings.TrimSpace(actor)
+	if strings.TrimSpace(orderID) == "" || actor == "" {
+		return domain.Order{}, ErrInvalidInput
+	}
+
+	var updated domain.Order
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.repo.Get(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if err := domain.ValidateTransition(current.Status, to); err != nil {
+			return err
+		}
+
+		updated, err = s.repo.UpdateStatusTx(ctx, domain.StatusChange{
+			OrderID:    current.ID,
+			FromStatus: current.Status,
+			ToStatus:   to,
+			Actor:      actor,
+			Reason:     strings.TrimSpace(reason),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Paying turns the reservation into a sale; cancelling, even after
+		// payment, puts the stock back.
+		switch to {
+		case domain.StatusPaid:
+			return s.stock.Commit(ctx, current.ID)
+		case domain.StatusCancelled:
+			return s.stock.Release(ctx, current.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Order{}, err
+	}
+	return updated, nil
+}
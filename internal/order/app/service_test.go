@@ -0,0 +1,216 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dwikikusuma/shoping-llm/internal/order/domain"
+)
+
+// fakeRepo keeps the orders it created in memory.
+type fakeRepo struct {
+	created []domain.Order
+}
+
+func (f *fakeRepo) CreateOrderTx(ctx context.Context, order domain.Order) (domain.Order, error) {
+	order.ID = fmt.Sprintf("order-%d", len(f.created)+1)
+	f.created = append(f.created, order)
+	return order, nil
+}
+
+func (f *fakeRepo) Get(ctx context.Context, id string) (domain.Order, error) {
+	for _, o := range f.created {
+		if o.ID == id {
+			return o, nil
+		}
+	}
+	return domain.Order{}, ErrNotFound
+}
+
+func (f *fakeRepo) UpdateStatusTx(ctx context.Context, change domain.StatusChange) (domain.Order, error) {
+	return domain.Order{}, errors.New("not implemented")
+}
+
+func (f *fakeRepo) ListByUser(ctx context.Context, userID, status string, limit int, cursor string) ([]domain.Order, string, error) {
+	return nil, "", errors.New("not implemented")
+}
+
+func (f *fakeRepo) HasOrdered(ctx context.Context, userID, productID, status string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+type fakeStock struct{}
+
+func (fakeStock) Reserve(ctx context.Context, orderID string, items []domain.OrderItem) error {
+	return nil
+}
+func (fakeStock) Commit(ctx context.Context, orderID string) error  { return nil }
+func (fakeStock) Release(ctx context.Context, orderID string) error { return nil }
+
+type fakeTx struct{}
+
+func (fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeShipping charges 1 per gram at 100g per unit, and only ships EXPRESS
+// within ID. It knows the products "p1" and "p2".
+type fakeShipping struct {
+	option, country string
+}
+
+func (f *fakeShipping) Fee(ctx context.Context, option, country, currency string, items []domain.OrderItemRequest) (int64, error) {
+	f.option, f.country = option, country
+	var grams int64
+	for _, it := range items {
+		if it.ProductID != "p1" && it.ProductID != "p2" {
+			return 0, fmt.Errorf("%w: product %s not found", ErrInvalidInput, it.ProductID)
+		}
+		grams += 100 * int64(it.Quantity)
+	}
+	switch {
+	case option == "STANDARD":
+		return grams, nil
+	case option == "EXPRESS" && country == "ID":
+		return 3 * grams, nil
+	}
+	return 0, fmt.Errorf("%w: %s doesn't ship to %s", ErrInvalidInput, option, country)
+}
+
+func newTestService() (*Service, *fakeRepo, *fakeShipping) {
+	repo, shipping := &fakeRepo{}, &fakeShipping{}
+	return NewService(repo, fakeStock{}, shipping, fakeTx{}), repo, shipping
+}
+
+func orderRequest(option, country string) domain.CreateOrderRequest {
+	return domain.CreateOrderRequest{
+		UserID:         "u1",
+		Currency:       "IDR",
+		ShippingOption: option,
+		Country:        country,
+		ShippingAmount: 1, // ignored by CreateOrder
+		Items: []domain.OrderItemRequest{
+			{ProductID: "p1", Name: "Mug", UnitAmount: 5000, Quantity: 2},
+			{ProductID: "p2", Name: "Tee", UnitAmount: 8000, Quantity: 1},
+		},
+	}
+}
+
+func TestCreateOrder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("prices shipping and records the option and country", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+
+		resp, err := svc.CreateOrder(ctx, orderRequest(" express ", "id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		o := repo.created[0]
+		if o.ShippingAmount != 900 || o.ShippingOption != "EXPRESS" || o.Country != "ID" {
+			t.Fatalf("expected EXPRESS to ID for 900, got %+v", o)
+		}
+		if resp.TotalAmount != 18000+900 {
+			t.Fatalf("expected the fee in the total, got %d", resp.TotalAmount)
+		}
+	})
+
+	t.Run("ships STANDARD by default", func(t *testing.T) {
+		svc, repo, shipping := newTestService()
+
+		if _, err := svc.CreateOrder(ctx, orderRequest("", "SG")); err != nil {
+			t.Fatal(err)
+		}
+		if shipping.option != "STANDARD" || repo.created[0].ShippingOption != "STANDARD" || repo.created[0].ShippingAmount != 300 {
+			t.Fatalf("expected STANDARD for 300, got %+v", repo.created[0])
+		}
+	})
+
+	t.Run("requires a country", func(t *testing.T) {
+		svc, repo, shipping := newTestService()
+
+		for _, country := range []string{"", "IDN"} {
+			if _, err := svc.CreateOrder(ctx, orderRequest("STANDARD", country)); !errors.Is(err, ErrInvalidInput) {
+				t.Fatalf("country %q: expected ErrInvalidInput, got %v", country, err)
+			}
+		}
+		if shipping.country != "" || len(repo.created) != 0 {
+			t.Fatalf("expected no pricing and no order")
+		}
+	})
+
+	t.Run("option not offered at the destination", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+
+		if _, err := svc.CreateOrder(ctx, orderRequest("EXPRESS", "SG")); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput, got %v", err)
+		}
+		if len(repo.created) != 0 {
+			t.Fatalf("expected no order, got %+v", repo.created)
+		}
+	})
+
+	t.Run("missing product", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+		req := orderRequest("STANDARD", "ID")
+		req.Items[1].ProductID = "gone"
+
+		if _, err := svc.CreateOrder(ctx, req); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput, got %v", err)
+		}
+		if len(repo.created) != 0 {
+			t.Fatalf("expected no order, got %+v", repo.created)
+		}
+	})
+}
+
+func TestCreatePricedOrder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keeps the caller's fee", func(t *testing.T) {
+		svc, repo, shipping := newTestService()
+		req := orderRequest("express", "ID")
+		req.ShippingAmount = 12345
+		req.DiscountAmount = 1000
+
+		resp, err := svc.CreatePricedOrder(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shipping.option != "" {
+			t.Fatalf("expected shipping not to be priced again")
+		}
+		o := repo.created[0]
+		if o.ShippingAmount != 12345 || o.ShippingOption != "EXPRESS" || resp.TotalAmount != 18000-1000+12345 {
+			t.Fatalf("unexpected order: %+v", o)
+		}
+	})
+
+	t.Run("discount can't exceed the subtotal", func(t *testing.T) {
+		svc, repo, _ := newTestService()
+		req := orderRequest("STANDARD", "ID")
+		req.DiscountAmount = 18001
+
+		if _, err := svc.CreatePricedOrder(ctx, req); err == nil {
+			t.Fatalf("expected a discount above the subtotal to fail")
+		}
+		req.DiscountAmount = 18000
+		resp, err := svc.CreatePricedOrder(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(repo.created) != 1 || resp.TotalAmount != req.ShippingAmount {
+			t.Fatalf("expected a discount of the whole subtotal to leave shipping, got %+v", resp)
+		}
+	})
+
+	t.Run("requires a country", func(t *testing.T) {
+		svc, _, _ := newTestService()
+
+		if _, err := svc.CreatePricedOrder(ctx, orderRequest("STANDARD", "")); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
@@ -9,8 +9,15 @@ type Order struct {
 	Currency       string
 	SubTotalAmount int64
 	ShippingAmount int64
+	// DiscountAmount is what promotions took off; TotalAmount is net of it.
+	DiscountAmount int64
 	TotalAmount    int64
+	// ShippingOption and Country are how and where the order ships; both
+	// are empty on orders created before they were recorded.
+	ShippingOption string
+	Country        string // ISO 3166-1 alpha-2 code
 	OrderItems     []OrderItem
+	StatusHistory  []StatusChange
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
@@ -19,6 +26,8 @@ type OrderItem struct {
 	ID              string
 	OrderID         string
 	ProductID       string
+	VariantID       string // empty for products without variants
+	SKU             string
 	Name            string
 	UnitAmount      int64
 	Quantity        int32
@@ -26,14 +35,22 @@ type OrderItem struct {
 }
 
 type CreateOrderRequest struct {
-	UserID         string
-	Currency       string
+	UserID   string
+	Currency string
+	// ShippingOption and Country choose how and where the order ships;
+	// CreateOrder prices ShippingAmount from them.
+	ShippingOption string
+	Country        string
 	ShippingAmount int64
+	// DiscountAmount comes off the subtotal; it can't exceed it.
+	DiscountAmount int64
 	Items          []OrderItemRequest
 }
 
 type OrderItemRequest struct {
 	ProductID  string
+	VariantID  string
+	SKU        string
 	Name       string
 	UnitAmount int64
 	Quantity   int32
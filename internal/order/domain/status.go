@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	StatusPending   = "PENDING"
+	StatusPaid      = "PAID"
+	StatusCancelled = "CANCELLED"
+	StatusFulfilled = "FULFILLED"
+)
+
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// transitions lists the statuses each status may move to.
+// CANCELLED and FULFILLED are terminal.
+var transitions = map[string][]string{
+	StatusPending: {StatusPaid, StatusCancelled},
+	StatusPaid:    {StatusFulfilled, StatusCancelled},
+}
+
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusPaid, StatusCancelled, StatusFulfilled:
+		return true
+	}
+	return false
+}
+
+func CanTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidateTransition(from, to string) error {
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
+
+// StatusChange is one entry of an order's status history.
+// FromStatus is empty for the entry recorded when the order is created.
+type StatusChange struct {
+	ID         string
+	OrderID    string
+	FromStatus string
+	ToStatus   string
+	Actor      string
+	Reason     string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateTransition(t *testing.T) {
+	allowed := []struct{ from, to string }{
+		{StatusPending, StatusPaid},
+		{StatusPending, StatusCancelled},
+		{StatusPaid, StatusFulfilled},
+		{StatusPaid, StatusCancelled},
+	}
+	for _, tc := range allowed {
+		if err := ValidateTransition(tc.from, tc.to); err != nil {
+			t.Fatalf("%s -> %s: expected allowed, got %v", tc.from, tc.to, err)
+		}
+	}
+
+	rejected := []struct{ from, to string }{
+		{StatusPending, StatusFulfilled},
+		{StatusPending, StatusPending},
+		{StatusPaid, StatusPending},
+		{StatusCancelled, StatusPaid},
+		{StatusFulfilled, StatusCancelled},
+		{"UNKNOWN", StatusPaid},
+	}
+	for _, tc := range rejected {
+		if err := ValidateTransition(tc.from, tc.to); !errors.Is(err, ErrInvalidTransition) {
+			t.Fatalf("%s -> %s: expected ErrInvalidTransition, got %v", tc.from, tc.to, err)
+		}
+	}
+}
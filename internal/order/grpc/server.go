@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"errors"
 
 	orderv1 "github.com/dwikikusuma/shoping-llm/api/gen/order/v1"
 	"github.com/dwikikusuma/shoping-llm/internal/order/app"
@@ -26,6 +27,12 @@ func (s *Server) CreateOrder(ctx context.Context, req *orderv1.CreateOrderReques
 
 	orderRequest := s.mapProtoToCreateOrderReq(req)
 	order, err := s.svc.CreateOrder(ctx, orderRequest)
+	if errors.Is(err, app.ErrInsufficientStock) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, app.ErrInvalidInput) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create order: %v", err)
 	}
@@ -43,6 +50,8 @@ func (s *Server) mapProtoToCreateOrderReq(req *orderv1.CreateOrderRequest) domai
 	for _, item := range req.Items {
 		orderItems = append(orderItems, domain.OrderItemRequest{
 			ProductID:  item.ProductId,
+			VariantID:  item.VariantId,
+			SKU:        item.Sku,
 			Name:       item.Name,
 			UnitAmount: item.UnitAmount,
 			Quantity:   item.Quantity,
@@ -52,7 +61,114 @@ func (s *Server) mapProtoToCreateOrderReq(req *orderv1.CreateOrderRequest) domai
 	return domain.CreateOrderRequest{
 		UserID:         req.UserId,
 		Currency:       req.Currency,
-		ShippingAmount: req.ShippingFee,
+		ShippingOption: req.ShippingOption,
+		Country:        req.Country,
 		Items:          orderItems,
 	}
 }
+
+func (s *Server) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest) (*orderv1.GetOrderResponse, error) {
+	order, err := s.svc.GetOrder(ctx, req.GetOrderId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &orderv1.GetOrderResponse{Order: toProto(order)}, nil
+}
+
+func (s *Server) ListOrders(ctx context.Context, req *orderv1.ListOrdersRequest) (*orderv1.ListOrdersResponse, error) {
+	orders, next, err := s.svc.ListOrders(ctx, req.GetUserId(), req.GetStatus(), int(req.GetLimit()), req.GetCursor())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	out := make([]*orderv1.Order, 0, len(orders))
+	for _, o := range orders {
+		out = append(out, toProto(o))
+	}
+
+	return &orderv1.ListOrdersResponse{Orders: out, NextCursor: next}, nil
+}
+
+func (s *Server) MarkPaid(ctx context.Context, req *orderv1.ChangeOrderStatusRequest) (*orderv1.ChangeOrderStatusResponse, error) {
+	order, err := s.svc.MarkPaid(ctx, req.GetOrderId(), req.GetActor(), req.GetReason())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &orderv1.ChangeOrderStatusResponse{Order: toProto(order)}, nil
+}
+
+func (s *Server) CancelOrder(ctx context.Context, req *orderv1.ChangeOrderStatusRequest) (*orderv1.ChangeOrderStatusResponse, error) {
+	order, err := s.svc.CancelOrder(ctx, req.GetOrderId(), req.GetActor(), req.GetReason())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &orderv1.ChangeOrderStatusResponse{Order: toProto(order)}, nil
+}
+
+func (s *Server) FulfillOrder(ctx context.Context, req *orderv1.ChangeOrderStatusRequest) (*orderv1.ChangeOrderStatusResponse, error) {
+	order, err := s.svc.FulfillOrder(ctx, req.GetOrderId(), req.GetActor(), req.GetReason())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &orderv1.ChangeOrderStatusResponse{Order: toProto(order)}, nil
+}
+
+func toProto(o domain.Order) *orderv1.Order {
+	items := make([]*orderv1.OrderItem, 0, len(o.OrderItems))
+	for _, it := range o.OrderItems {
+		items = append(items, &orderv1.OrderItem{
+			Id:              it.ID,
+			ProductId:       it.ProductID,
+			VariantId:       it.VariantID,
+			Sku:             it.SKU,
+			Name:            it.Name,
+			UnitAmount:      it.UnitAmount,
+			Quantity:        it.Quantity,
+			LineTotalAmount: it.LineTotalAmount,
+		})
+	}
+
+	history := make([]*orderv1.StatusChange, 0, len(o.StatusHistory))
+	for _, h := range o.StatusHistory {
+		history = append(history, &orderv1.StatusChange{
+			FromStatus:    h.FromStatus,
+			ToStatus:      h.ToStatus,
+			Actor:         h.Actor,
+			Reason:        h.Reason,
+			CreatedAtUnix: h.CreatedAt.Unix(),
+		})
+	}
+
+	return &orderv1.Order{
+		Id:             o.ID,
+		UserId:         o.UserID,
+		Status:         o.Status,
+		Currency:       o.Currency,
+		SubtotalAmount: o.SubTotalAmount,
+		ShippingAmount: o.ShippingAmount,
+		DiscountAmount: o.DiscountAmount,
+		TotalAmount:    o.TotalAmount,
+		ShippingOption: o.ShippingOption,
+		Country:        o.Country,
+		Items:          items,
+		CreatedAtUnix:  o.CreatedAt.Unix(),
+		UpdatedAtUnix:  o.UpdatedAt.Unix(),
+		StatusHistory:  history,
+	}
+}
+
+func mapErr(err error) error {
+	if errors.Is(err, app.ErrInvalidInput) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, app.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, domain.ErrInvalidTransition) || errors.Is(err, app.ErrInsufficientStock) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, app.ErrConflict) {
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	orderv1 "github.com/dwikikusuma/shoping-llm/api/gen/order/v1"
+	"github.com/dwikikusuma/shoping-llm/internal/order/app"
+	"github.com/dwikikusuma/shoping-llm/internal/order/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// noShipping offers no option anywhere.
+type noShipping struct{}
+
+func (noShipping) Fee(ctx context.Context, option, country, currency string, items []domain.OrderItemRequest) (int64, error) {
+	return 0, fmt.Errorf("%w: %s doesn't ship to %s", app.ErrInvalidInput, option, country)
+}
+
+func TestCreateOrderInvalidShipping(t *testing.T) {
+	srv := NewServer(app.NewService(nil, nil, noShipping{}, nil))
+
+	for name, req := range map[string]*orderv1.CreateOrderRequest{
+		"option not offered": {UserId: "u1", Currency: "IDR", ShippingOption: "EXPRESS", Country: "US"},
+		"missing country":    {UserId: "u1", Currency: "IDR"},
+	} {
+		req.Items = []*orderv1.OrderItemInput{{ProductId: "p1", Name: "Mug", UnitAmount: 5000, Quantity: 1}}
+		if _, err := srv.CreateOrder(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument, got %v", name, err)
+		}
+	}
+}
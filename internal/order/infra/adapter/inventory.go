@@ -0,0 +1,68 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	inventoryapp "github.com/dwikikusuma/shoping-llm/internal/inventory/app"
+	inventorydomain "github.com/dwikikusuma/shoping-llm/internal/inventory/domain"
+	orderapp "github.com/dwikikusuma/shoping-llm/internal/order/app"
+	"github.com/dwikikusuma/shoping-llm/internal/order/domain"
+)
+
+type InventoryReserver struct {
+	svc *inventoryapp.Service
+}
+
+func NewInventoryReserver(svc *inventoryapp.Service) *InventoryReserver {
+	return &InventoryReserver{svc: svc}
+}
+
+func (r *InventoryReserver) Reserve(ctx context.Context, orderID string, items []domain.OrderItem) error {
+	lines := make([]inventorydomain.ReservationItem, 0, len(items))
+	for _, it := range items {
+		lines = append(lines, inventorydomain.ReservationItem{
+			ProductID: stockID(it),
+			Quantity:  it.Quantity,
+		})
+	}
+
+	_, err := r.svc.Reserve(ctx, orderID, lines, 0)
+	if errors.Is(err, inventoryapp.ErrInsufficientStock) {
+		return fmt.Errorf("%w: %v", orderapp.ErrInsufficientStock, err)
+	}
+	return err
+}
+
+func (r *InventoryReserver) Commit(ctx context.Context, orderID string) error {
+	_, err := r.svc.Commit(ctx, orderID)
+	if errors.Is(err, inventorydomain.ErrInvalidTransition) {
+		// The reservation expired (or was released) before payment came in.
+		return fmt.Errorf("%w: stock is no longer reserved", domain.ErrInvalidTransition)
+	}
+	return ignoreNotFound(err)
+}
+
+func (r *InventoryReserver) Release(ctx context.Context, orderID string) error {
+	_, err := r.svc.Release(ctx, orderID)
+	return ignoreNotFound(err)
+}
+
+// stockID is the inventory key of an order line. Each variant is stocked on
+// its own, under the variant ID.
+func stockID(it domain.OrderItem) string {
+	if it.VariantID != "" {
+		return it.VariantID
+	}
+	return it.ProductID
+}
+
+// Orders placed before stock was tracked have no reservation; there is
+// nothing to commit or release for them.
+func ignoreNotFound(err error) error {
+	if errors.Is(err, inventoryapp.ErrNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	catalogapp "github.com/dwikikusuma/shoping-llm/internal/catalog/app"
+	orderapp "github.com/dwikikusuma/shoping-llm/internal/order/app"
+	"github.com/dwikikusuma/shoping-llm/internal/order/domain"
+	shippingapp "github.com/dwikikusuma/shoping-llm/internal/shipping/app"
+	shippingdomain "github.com/dwikikusuma/shoping-llm/internal/shipping/domain"
+)
+
+// ShippingServicePricer prices shipping with the shipping module, weighing
+// the items with their catalog products.
+type ShippingServicePricer struct {
+	catalog  *catalogapp.Service
+	shipping *shippingapp.Service
+}
+
+func NewShippingServicePricer(catalog *catalogapp.Service, shipping *shippingapp.Service) *ShippingServicePricer {
+	return &ShippingServicePricer{catalog: catalog, shipping: shipping}
+}
+
+func (p *ShippingServicePricer) Fee(ctx context.Context, option, country, currency string, items []domain.OrderItemRequest) (int64, error) {
+	quantities := make(map[string]int64, len(items))
+	ids := make([]string, 0, len(items))
+	for _, it := range items {
+		if _, ok := quantities[it.ProductID]; !ok {
+			ids = append(ids, it.ProductID)
+		}
+		quantities[it.ProductID] += int64(it.Quantity)
+	}
+
+	parcel := shippingdomain.Parcel{Country: country, Currency: currency}
+	now := time.Now()
+	for start := 0; start < len(ids); start += catalogapp.MaxBatchProducts {
+		end := min(start+catalogapp.MaxBatchProducts, len(ids))
+		products, err := p.catalog.BatchGetProducts(ctx, ids[start:end], now)
+		var notFound *catalogapp.MissingProductsError
+		if errors.As(err, &notFound) {
+			return 0, fmt.Errorf("%w: %v", orderapp.ErrInvalidInput, err)
+		}
+		if err != nil {
+			return 0, err
+		}
+		for _, pr := range products {
+			parcel.WeightGrams += int64(pr.WeightGrams) * quantities[pr.ID]
+		}
+	}
+
+	o, err := p.shipping.Option(ctx, parcel, option)
+	if errors.Is(err, shippingapp.ErrInvalidInput) || errors.Is(err, shippingapp.ErrUnknownOption) ||
+		errors.Is(err, shippingapp.ErrUnsupportedCurrency) {
+		return 0, fmt.Errorf("%w: %v", orderapp.ErrInvalidInput, err)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return o.Fee.Amount, nil
+}
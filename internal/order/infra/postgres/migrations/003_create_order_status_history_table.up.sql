@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+
+    from_status TEXT NOT NULL DEFAULT '',
+    to_status TEXT NOT NULL,
+
+    actor TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_created_at
+    ON order_status_history(order_id, created_at);
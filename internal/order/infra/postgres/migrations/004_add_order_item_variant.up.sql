@@ -0,0 +1,4 @@
+-- The variant bought, if the product has variants. sku is copied so the line
+-- still reads right after the variant is changed or deleted.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id UUID;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS sku TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- What promotions took off the order; total_amount already has it deducted.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount_amount BIGINT NOT NULL DEFAULT 0;
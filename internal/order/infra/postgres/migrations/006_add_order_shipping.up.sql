@@ -0,0 +1,4 @@
+-- How and where the order ships; shipping_amount was priced from them.
+-- Both are empty for orders created before they were recorded.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_option TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS country TEXT NOT NULL DEFAULT '';
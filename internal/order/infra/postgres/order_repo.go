@@ -3,41 +3,47 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/dwikikusuma/shoping-llm/internal/order/app"
 	"github.com/dwikikusuma/shoping-llm/internal/order/domain"
 	"github.com/dwikikusuma/shoping-llm/internal/order/infra/postgres/orderdb"
+	"github.com/dwikikusuma/shoping-llm/pkg/pagination"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
 	"github.com/google/uuid"
 )
 
 type OrderRepo struct {
 	*orderdb.Queries
-	db *sql.DB
+	db      *sql.DB
+	cursors *pagination.Codec
 }
 
-func NewOrderRepo(db *sql.DB) *OrderRepo {
+func NewOrderRepo(db *sql.DB, cursors *pagination.Codec) *OrderRepo {
 	return &OrderRepo{
 		Queries: orderdb.New(db),
 		db:      db,
+		cursors: cursors,
 	}
 }
 
+// execTX runs fn in its own transaction, or in the caller's transaction when
+// ctx carries one (see pg.TxManager).
 func (r *OrderRepo) execTX(ctx context.Context, fn func(queries *orderdb.Queries) error) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
+	return pg.ExecTx(ctx, r.db, func(tx *sql.Tx) error {
+		return fn(r.Queries.WithTx(tx))
+	})
+}
 
-	q := orderdb.New(tx)
-	err = fn(q)
-	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return fmt.Errorf("tx err: %w; rollback err: %v", err, rbErr)
-		}
-		return err
+func (r *OrderRepo) queries(ctx context.Context) *orderdb.Queries {
+	if tx, ok := pg.TxFromContext(ctx); ok {
+		return r.Queries.WithTx(tx)
 	}
-
-	return tx.Commit()
+	return r.Queries
 }
 
 func (r *OrderRepo) CreateOrderTx(ctx context.Context, order domain.Order) (domain.Order, error) {
@@ -45,12 +51,16 @@ func (r *OrderRepo) CreateOrderTx(ctx context.Context, order domain.Order) (doma
 
 	err := r.execTX(ctx, func(q *orderdb.Queries) error {
 		o, err := q.CreateOrder(ctx, orderdb.CreateOrderParams{
+			ID:             uuid.New(),
 			UserID:         order.UserID,
 			Status:         order.Status,
 			Currency:       order.Currency,
 			SubtotalAmount: order.SubTotalAmount,
 			ShippingAmount: order.ShippingAmount,
 			TotalAmount:    order.TotalAmount,
+			DiscountAmount: order.DiscountAmount,
+			ShippingOption: order.ShippingOption,
+			Country:        order.Country,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create order: %w", err)
@@ -69,43 +79,49 @@ func (r *OrderRepo) CreateOrderTx(ctx context.Context, order domain.Order) (doma
 				return fmt.Errorf("item %d: invalid product UUID: %w", i, err)
 			}
 
+			var vUUID uuid.NullUUID
+			if item.VariantID != "" {
+				id, err := uuid.Parse(item.VariantID)
+				if err != nil {
+					return fmt.Errorf("item %d: invalid variant UUID: %w", i, err)
+				}
+				vUUID = uuid.NullUUID{UUID: id, Valid: true}
+			}
+
 			row, err := q.AddOrderItem(ctx, orderdb.AddOrderItemParams{
+				ID:              uuid.New(),
 				OrderID:         o.ID,
 				ProductID:       pUUID,
 				Name:            item.Name,
 				UnitAmount:      item.UnitAmount,
 				Quantity:        item.Quantity,
 				LineTotalAmount: item.LineTotalAmount, // Already calculated from service
+				VariantID:       vUUID,
+				Sku:             item.SKU,
 			})
 
 			if err != nil {
 				return fmt.Errorf("failed to insert item %d: %w", i, err)
 			}
 
-			orderItems = append(orderItems, domain.OrderItem{
-				ID:              row.ID.String(),
-				OrderID:         row.OrderID.String(),
-				ProductID:       row.ProductID.String(),
-				Name:            row.Name,
-				UnitAmount:      row.UnitAmount,
-				Quantity:        row.Quantity,
-				LineTotalAmount: row.LineTotalAmount,
-			})
+			orderItems = append(orderItems, toDomainOrderItem(row))
 		}
 
-		createdOrder = domain.Order{
-			ID:             o.ID.String(),
-			UserID:         o.UserID,
-			Status:         o.Status,
-			Currency:       o.Currency,
-			SubTotalAmount: o.SubtotalAmount,
-			ShippingAmount: o.ShippingAmount,
-			TotalAmount:    o.TotalAmount,
-			OrderItems:     orderItems,
-			CreatedAt:      o.CreatedAt,
-			UpdatedAt:      o.UpdatedAt,
+		h, err := q.AddOrderStatusHistory(ctx, orderdb.AddOrderStatusHistoryParams{
+			ID:       uuid.New(),
+			OrderID:  o.ID,
+			ToStatus: o.Status,
+			Actor:    o.UserID,
+			Reason:   "order created",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record status history: %w", err)
 		}
 
+		createdOrder = toDomainOrder(o)
+		createdOrder.OrderItems = orderItems
+		createdOrder.StatusHistory = []domain.StatusChange{toDomainStatusChange(h)}
+
 		return nil
 	})
 	if err != nil {
@@ -113,3 +129,189 @@ func (r *OrderRepo) CreateOrderTx(ctx context.Context, order domain.Order) (doma
 	}
 	return createdOrder, nil
 }
+
+func (r *OrderRepo) Get(ctx context.Context, id string) (domain.Order, error) {
+	orderID, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return domain.Order{}, app.ErrInvalidInput
+	}
+
+	o, err := r.queries(ctx).GetOrderById(ctx, orderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Order{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	rows, err := r.queries(ctx).ListOrderItem(ctx, o.ID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	history, err := r.queries(ctx).ListOrderStatusHistory(ctx, o.ID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	order := toDomainOrder(o)
+	order.OrderItems = make([]domain.OrderItem, 0, len(rows))
+	for _, row := range rows {
+		order.OrderItems = append(order.OrderItems, toDomainOrderItem(row))
+	}
+	order.StatusHistory = make([]domain.StatusChange, 0, len(history))
+	for _, h := range history {
+		order.StatusHistory = append(order.StatusHistory, toDomainStatusChange(h))
+	}
+	return order, nil
+}
+
+// UpdateStatusTx moves the order from change.FromStatus to change.ToStatus and
+// records the change. It fails with app.ErrConflict when the order is no longer
+// in change.FromStatus, e.g. because a concurrent request moved it first.
+func (r *OrderRepo) UpdateStatusTx(ctx context.Context, change domain.StatusChange) (domain.Order, error) {
+	orderID, err := uuid.Parse(strings.TrimSpace(change.OrderID))
+	if err != nil {
+		return domain.Order{}, app.ErrInvalidInput
+	}
+
+	err = r.execTX(ctx, func(q *orderdb.Queries) error {
+		_, err := q.UpdateOrderStatus(ctx, orderdb.UpdateOrderStatusParams{
+			ToStatus:   change.ToStatus,
+			ID:         orderID,
+			FromStatus: change.FromStatus,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return app.ErrConflict
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+
+		_, err = q.AddOrderStatusHistory(ctx, orderdb.AddOrderStatusHistoryParams{
+			ID:         uuid.New(),
+			OrderID:    orderID,
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			Actor:      change.Actor,
+			Reason:     change.Reason,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record status history: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	return r.Get(ctx, change.OrderID)
+}
+
+func (r *OrderRepo) ListByUser(ctx context.Context, userID, status string, limit int, cursor string) ([]domain.Order, string, error) {
+	arg := orderdb.ListOrderByUserIdParams{
+		UserID:    userID,
+		Status:    status,
+		PageLimit: int32(limit),
+	}
+
+	filterHash := pagination.FilterHash(userID, status)
+	if strings.TrimSpace(cursor) != "" {
+		c, err := r.cursors.Decode(cursor, filterHash)
+		if err != nil {
+			return nil, "", app.ErrInvalidInput
+		}
+		id, err := uuid.Parse(c.ID)
+		if err != nil {
+			return nil, "", app.ErrInvalidInput
+		}
+		micros, err := strconv.ParseInt(c.Key, 10, 64)
+		if err != nil {
+			return nil, "", app.ErrInvalidInput
+		}
+		arg.UseCursor = true
+		arg.CursorID = id
+		arg.CursorCreatedAt = time.UnixMicro(micros)
+	}
+
+	rows, err := r.queries(ctx).ListOrderByUserId(ctx, arg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	out := make([]domain.Order, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toDomainOrder(row))
+	}
+
+	// next_cursor: point after the last order only when we returned a full page
+	nextCursor := ""
+	if len(rows) == limit && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = r.cursors.Encode(pagination.Cursor{
+			Key:    strconv.FormatInt(last.CreatedAt.UnixMicro(), 10),
+			ID:     last.ID.String(),
+			Filter: filterHash,
+		})
+	}
+
+	return out, nextCursor, nil
+}
+
+func (r *OrderRepo) HasOrdered(ctx context.Context, userID, productID, status string) (bool, error) {
+	pid, err := uuid.Parse(strings.TrimSpace(productID))
+	if err != nil {
+		return false, app.ErrInvalidInput
+	}
+	return r.queries(ctx).HasOrderedProduct(ctx, orderdb.HasOrderedProductParams{
+		UserID:    userID,
+		Status:    status,
+		ProductID: pid,
+	})
+}
+
+func toDomainOrder(o orderdb.Order) domain.Order {
+	return domain.Order{
+		ID:             o.ID.String(),
+		UserID:         o.UserID,
+		Status:         o.Status,
+		Currency:       o.Currency,
+		SubTotalAmount: o.SubtotalAmount,
+		ShippingAmount: o.ShippingAmount,
+		TotalAmount:    o.TotalAmount,
+		DiscountAmount: o.DiscountAmount,
+		ShippingOption: o.ShippingOption,
+		Country:        o.Country,
+		CreatedAt:      o.CreatedAt,
+		UpdatedAt:      o.UpdatedAt,
+	}
+}
+
+func toDomainOrderItem(row orderdb.OrderItem) domain.OrderItem {
+	item := domain.OrderItem{
+		ID:              row.ID.String(),
+		OrderID:         row.OrderID.String(),
+		ProductID:       row.ProductID.String(),
+		SKU:             row.Sku,
+		Name:            row.Name,
+		UnitAmount:      row.UnitAmount,
+		Quantity:        row.Quantity,
+		LineTotalAmount: row.LineTotalAmount,
+	}
+	if row.VariantID.Valid {
+		item.VariantID = row.VariantID.UUID.String()
+	}
+	return item
+}
+
+func toDomainStatusChange(h orderdb.OrderStatusHistory) domain.StatusChange {
+	return domain.StatusChange{
+		ID:         h.ID.String(),
+		OrderID:    h.OrderID.String(),
+		FromStatus: h.FromStatus,
+		ToStatus:   h.ToStatus,
+		Actor:      h.Actor,
+		Reason:     h.Reason,
+		CreatedAt:  h.CreatedAt,
+	}
+}
@@ -20,14 +20,29 @@ type Order struct {
 	TotalAmount    int64     `json:"total_amount"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+	DiscountAmount int64     `json:"discount_amount"`
+	ShippingOption string    `json:"shipping_option"`
+	Country        string    `json:"country"`
 }
 
 type OrderItem struct {
-	ID              uuid.UUID `json:"id"`
-	OrderID         uuid.UUID `json:"order_id"`
-	ProductID       uuid.UUID `json:"product_id"`
-	Name            string    `json:"name"`
-	UnitAmount      int64     `json:"unit_amount"`
-	Quantity        int32     `json:"quantity"`
-	LineTotalAmount int64     `json:"line_total_amount"`
+	ID              uuid.UUID     `json:"id"`
+	OrderID         uuid.UUID     `json:"order_id"`
+	ProductID       uuid.UUID     `json:"product_id"`
+	Name            string        `json:"name"`
+	UnitAmount      int64         `json:"unit_amount"`
+	Quantity        int32         `json:"quantity"`
+	LineTotalAmount int64         `json:"line_total_amount"`
+	VariantID       uuid.NullUUID `json:"variant_id"`
+	Sku             string        `json:"sku"`
+}
+
+type OrderStatusHistory struct {
+	ID         uuid.UUID `json:"id"`
+	OrderID    uuid.UUID `json:"order_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
 }
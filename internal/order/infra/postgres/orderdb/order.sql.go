@@ -7,6 +7,7 @@ package orderdb
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -19,21 +20,25 @@ INSERT INTO order_items (
     name,
     unit_amount,
     quantity,
-    line_total_amount
+    line_total_amount,
+    variant_id,
+    sku
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
          )
-RETURNING id, order_id, product_id, name, unit_amount, quantity, line_total_amount
+RETURNING id, order_id, product_id, name, unit_amount, quantity, line_total_amount, variant_id, sku
 `
 
 type AddOrderItemParams struct {
-	ID              uuid.UUID `json:"id"`
-	OrderID         uuid.UUID `json:"order_id"`
-	ProductID       uuid.UUID `json:"product_id"`
-	Name            string    `json:"name"`
-	UnitAmount      int64     `json:"unit_amount"`
-	Quantity        int32     `json:"quantity"`
-	LineTotalAmount int64     `json:"line_total_amount"`
+	ID              uuid.UUID     `json:"id"`
+	OrderID         uuid.UUID     `json:"order_id"`
+	ProductID       uuid.UUID     `json:"product_id"`
+	Name            string        `json:"name"`
+	UnitAmount      int64         `json:"unit_amount"`
+	Quantity        int32         `json:"quantity"`
+	LineTotalAmount int64         `json:"line_total_amount"`
+	VariantID       uuid.NullUUID `json:"variant_id"`
+	Sku             string        `json:"sku"`
 }
 
 func (q *Queries) AddOrderItem(ctx context.Context, arg AddOrderItemParams) (OrderItem, error) {
@@ -45,6 +50,8 @@ func (q *Queries) AddOrderItem(ctx context.Context, arg AddOrderItemParams) (Ord
 		arg.UnitAmount,
 		arg.Quantity,
 		arg.LineTotalAmount,
+		arg.VariantID,
+		arg.Sku,
 	)
 	var i OrderItem
 	err := row.Scan(
@@ -55,6 +62,53 @@ func (q *Queries) AddOrderItem(ctx context.Context, arg AddOrderItemParams) (Ord
 		&i.UnitAmount,
 		&i.Quantity,
 		&i.LineTotalAmount,
+		&i.VariantID,
+		&i.Sku,
+	)
+	return i, err
+}
+
+const addOrderStatusHistory = `-- name: AddOrderStatusHistory :one
+INSERT INTO order_status_history (
+    id,
+    order_id,
+    from_status,
+    to_status,
+    actor,
+    reason
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, order_id, from_status, to_status, actor, reason, created_at
+`
+
+type AddOrderStatusHistoryParams struct {
+	ID         uuid.UUID `json:"id"`
+	OrderID    uuid.UUID `json:"order_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason"`
+}
+
+func (q *Queries) AddOrderStatusHistory(ctx context.Context, arg AddOrderStatusHistoryParams) (OrderStatusHistory, error) {
+	row := q.db.QueryRowContext(ctx, addOrderStatusHistory,
+		arg.ID,
+		arg.OrderID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.Actor,
+		arg.Reason,
+	)
+	var i OrderStatusHistory
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.Actor,
+		&i.Reason,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -67,11 +121,14 @@ INSERT INTO orders (
     currency,
     subtotal_amount,
     shipping_amount,
-    total_amount
+    total_amount,
+    discount_amount,
+    shipping_option,
+    country
 ) VALUES (
      $1, $2, $3, $4,
-$5, $6, $7
- ) RETURNING id, user_id, status, currency, subtotal_amount, shipping_amount, total_amount, created_at, updated_at
+$5, $6, $7, $8, $9, $10
+ ) RETURNING id, user_id, status, currency, subtotal_amount, shipping_amount, total_amount, created_at, updated_at, discount_amount, shipping_option, country
 `
 
 type CreateOrderParams struct {
@@ -82,6 +139,9 @@ type CreateOrderParams struct {
 	SubtotalAmount int64     `json:"subtotal_amount"`
 	ShippingAmount int64     `json:"shipping_amount"`
 	TotalAmount    int64     `json:"total_amount"`
+	DiscountAmount int64     `json:"discount_amount"`
+	ShippingOption string    `json:"shipping_option"`
+	Country        string    `json:"country"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error) {
@@ -93,6 +153,9 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		arg.SubtotalAmount,
 		arg.ShippingAmount,
 		arg.TotalAmount,
+		arg.DiscountAmount,
+		arg.ShippingOption,
+		arg.Country,
 	)
 	var i Order
 	err := row.Scan(
@@ -105,12 +168,15 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		&i.TotalAmount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DiscountAmount,
+		&i.ShippingOption,
+		&i.Country,
 	)
 	return i, err
 }
 
 const getOrderById = `-- name: GetOrderById :one
-SELECT id, user_id, status, currency, subtotal_amount, shipping_amount, total_amount, created_at, updated_at FROM orders WHERE id = $1
+SELECT id, user_id, status, currency, subtotal_amount, shipping_amount, total_amount, created_at, updated_at, discount_amount, shipping_option, country FROM orders WHERE id = $1
 `
 
 func (q *Queries) GetOrderById(ctx context.Context, id uuid.UUID) (Order, error) {
@@ -126,22 +192,67 @@ func (q *Queries) GetOrderById(ctx context.Context, id uuid.UUID) (Order, error)
 		&i.TotalAmount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DiscountAmount,
+		&i.ShippingOption,
+		&i.Country,
 	)
 	return i, err
 }
 
+const hasOrderedProduct = `-- name: HasOrderedProduct :one
+SELECT EXISTS (
+    SELECT 1
+    FROM orders o
+    JOIN order_items i ON i.order_id = o.id
+    WHERE o.user_id = $1
+      AND o.status = $2
+      AND i.product_id = $3
+)::boolean AS ordered
+`
+
+type HasOrderedProductParams struct {
+	UserID    string    `json:"user_id"`
+	Status    string    `json:"status"`
+	ProductID uuid.UUID `json:"product_id"`
+}
+
+// Reports whether the user has an order in the given status containing the
+// product, under any of its variants.
+func (q *Queries) HasOrderedProduct(ctx context.Context, arg HasOrderedProductParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, hasOrderedProduct, arg.UserID, arg.Status, arg.ProductID)
+	var ordered bool
+	err := row.Scan(&ordered)
+	return ordered, err
+}
+
 const listOrderByUserId = `-- name: ListOrderByUserId :many
-SELECT id, user_id, status, currency, subtotal_amount, shipping_amount, total_amount, created_at, updated_at FROM orders WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3
+SELECT id, user_id, status, currency, subtotal_amount, shipping_amount, total_amount, created_at, updated_at, discount_amount, shipping_option, country FROM orders
+WHERE user_id = $1
+  AND ($2::text = '' OR status = $2::text)
+  AND ($3::boolean = false
+      OR (created_at, id) < ($4::timestamptz, $5::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT $6
 `
 
 type ListOrderByUserIdParams struct {
-	UserID string `json:"user_id"`
-	Limit  int32  `json:"limit"`
-	Offset int32  `json:"offset"`
+	UserID          string    `json:"user_id"`
+	Status          string    `json:"status"`
+	UseCursor       bool      `json:"use_cursor"`
+	CursorCreatedAt time.Time `json:"cursor_created_at"`
+	CursorID        uuid.UUID `json:"cursor_id"`
+	PageLimit       int32     `json:"page_limit"`
 }
 
 func (q *Queries) ListOrderByUserId(ctx context.Context, arg ListOrderByUserIdParams) ([]Order, error) {
-	rows, err := q.db.QueryContext(ctx, listOrderByUserId, arg.UserID, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listOrderByUserId,
+		arg.UserID,
+		arg.Status,
+		arg.UseCursor,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -159,6 +270,9 @@ func (q *Queries) ListOrderByUserId(ctx context.Context, arg ListOrderByUserIdPa
 			&i.TotalAmount,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DiscountAmount,
+			&i.ShippingOption,
+			&i.Country,
 		); err != nil {
 			return nil, err
 		}
@@ -174,7 +288,7 @@ func (q *Queries) ListOrderByUserId(ctx context.Context, arg ListOrderByUserIdPa
 }
 
 const listOrderItem = `-- name: ListOrderItem :many
-SELECT id, order_id, product_id, name, unit_amount, quantity, line_total_amount FROM order_items WHERE order_id = $1
+SELECT id, order_id, product_id, name, unit_amount, quantity, line_total_amount, variant_id, sku FROM order_items WHERE order_id = $1
 `
 
 func (q *Queries) ListOrderItem(ctx context.Context, orderID uuid.UUID) ([]OrderItem, error) {
@@ -194,6 +308,43 @@ func (q *Queries) ListOrderItem(ctx context.Context, orderID uuid.UUID) ([]Order
 			&i.UnitAmount,
 			&i.Quantity,
 			&i.LineTotalAmount,
+			&i.VariantID,
+			&i.Sku,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderStatusHistory = `-- name: ListOrderStatusHistory :many
+SELECT id, order_id, from_status, to_status, actor, reason, created_at FROM order_status_history WHERE order_id = $1 ORDER BY created_at ASC
+`
+
+func (q *Queries) ListOrderStatusHistory(ctx context.Context, orderID uuid.UUID) ([]OrderStatusHistory, error) {
+	rows, err := q.db.QueryContext(ctx, listOrderStatusHistory, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrderStatusHistory
+	for rows.Next() {
+		var i OrderStatusHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.Actor,
+			&i.Reason,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -207,3 +358,36 @@ func (q *Queries) ListOrderItem(ctx context.Context, orderID uuid.UUID) ([]Order
 	}
 	return items, nil
 }
+
+const updateOrderStatus = `-- name: UpdateOrderStatus :one
+UPDATE orders
+SET status = $1, updated_at = NOW()
+WHERE id = $2 AND status = $3
+RETURNING id, user_id, status, currency, subtotal_amount, shipping_amount, total_amount, created_at, updated_at, discount_amount, shipping_option, country
+`
+
+type UpdateOrderStatusParams struct {
+	ToStatus   string    `json:"to_status"`
+	ID         uuid.UUID `json:"id"`
+	FromStatus string    `json:"from_status"`
+}
+
+func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, updateOrderStatus, arg.ToStatus, arg.ID, arg.FromStatus)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Status,
+		&i.Currency,
+		&i.SubtotalAmount,
+		&i.ShippingAmount,
+		&i.TotalAmount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DiscountAmount,
+		&i.ShippingOption,
+		&i.Country,
+	)
+	return i, err
+}
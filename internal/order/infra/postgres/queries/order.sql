@@ -6,10 +6,13 @@ INSERT INTO orders (
     currency,
     subtotal_amount,
     shipping_amount,
-    total_amount
+    total_amount,
+    discount_amount,
+    shipping_option,
+    country
 ) VALUES (
      $1, $2, $3, $4,
-$5, $6, $7
+$5, $6, $7, $8, $9, $10
  ) RETURNING *;
 
 -- name: AddOrderItem :one
@@ -20,9 +23,11 @@ INSERT INTO order_items (
     name,
     unit_amount,
     quantity,
-    line_total_amount
+    line_total_amount,
+    variant_id,
+    sku
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
          )
 RETURNING *;
 
@@ -33,4 +38,44 @@ SELECT * FROM orders WHERE id = $1;
 SELECT * FROM order_items WHERE order_id = $1;
 
 -- name: ListOrderByUserId :many
-SELECT * FROM orders WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3;
\ No newline at end of file
+SELECT * FROM orders
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.arg(status)::text = '' OR status = sqlc.arg(status)::text)
+  AND (sqlc.arg(use_cursor)::boolean = false
+      OR (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateOrderStatus :one
+UPDATE orders
+SET status = sqlc.arg(to_status), updated_at = NOW()
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+RETURNING *;
+
+-- name: AddOrderStatusHistory :one
+INSERT INTO order_status_history (
+    id,
+    order_id,
+    from_status,
+    to_status,
+    actor,
+    reason
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: ListOrderStatusHistory :many
+SELECT * FROM order_status_history WHERE order_id = $1 ORDER BY created_at ASC;
+
+-- name: HasOrderedProduct :one
+-- Reports whether the user has an order in the given status containing the
+-- product, under any of its variants.
+SELECT EXISTS (
+    SELECT 1
+    FROM orders o
+    JOIN order_items i ON i.order_id = o.id
+    WHERE o.user_id = sqlc.arg(user_id)
+      AND o.status = sqlc.arg(status)
+      AND i.product_id = sqlc.arg(product_id)
+)::boolean AS ordered;
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"context"
+
+	"github.com/dwikikusuma/shoping-llm/internal/promotion/domain"
+)
+
+type PromotionRepo interface {
+	// Create fails with ErrCodeTaken when another promotion has the code.
+	Create(ctx context.Context, p domain.Promotion) (domain.Promotion, error)
+	// List returns every promotion, newest first.
+	List(ctx context.Context) ([]domain.Promotion, error)
+	Deactivate(ctx context.Context, id string) (domain.Promotion, error)
+	// ListAutomatic returns the active promotions without a code, oldest
+	// first.
+	ListAutomatic(ctx context.Context) ([]domain.Promotion, error)
+	// GetByCodes returns the promotions with the given codes; codes nobody
+	// has are left out.
+	GetByCodes(ctx context.Context, codes []string) ([]domain.Promotion, error)
+	// LockByID locks the promotion until the surrounding transaction ends.
+	LockByID(ctx context.Context, id string) (domain.Promotion, error)
+	// Usage counts the redemptions of each promotion, overall and by the
+	// user. Promotions never redeemed are left out.
+	Usage(ctx context.Context, promotionIDs []string, userID string) (map[string]domain.Usage, error)
+	AddRedemption(ctx context.Context, promotionID, userID, orderID string) error
+}
+
+// TxRunner runs fn inside a single database transaction carried by ctx.
+type TxRunner interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/promotion/domain"
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
+)
+
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	ErrNotFound     = errors.New("not found")
+	ErrCodeTaken    = errors.New("promotion code already exists")
+	// ErrUnavailable is returned by Redeem when a promotion stopped applying
+	// between the quote and the order, e.g. because it was used up.
+	ErrUnavailable = errors.New("promotion can no longer be applied")
+)
+
+// MaxCodes caps the codes a shopper can enter on one cart.
+const MaxCodes = 5
+
+type Service struct {
+	repo PromotionRepo
+	tx   TxRunner
+	now  func() time.Time
+}
+
+func NewService(repo PromotionRepo, tx TxRunner) *Service {
+	return &Service{repo: repo, tx: tx, now: time.Now}
+}
+
+func (s *Service) CreatePromotion(ctx context.Context, p domain.Promotion) (domain.Promotion, error) {
+	p.Code = normalizeCode(p.Code)
+	p.Name = strings.TrimSpace(p.Name)
+	p.ProductID = strings.TrimSpace(p.ProductID)
+	p.Kind = domain.Kind(strings.ToUpper(strings.TrimSpace(string(p.Kind))))
+	for _, m := range []*domain.Money{&p.AmountOff, &p.MinSpend} {
+		if m.Amount == 0 {
+			continue
+		}
+		code, err := money.NormalizeCurrency(m.Currency)
+		if err != nil {
+			return domain.Promotion{}, fmt.Errorf("%w: %v", domain.ErrInvalidPromotion, err)
+		}
+		m.Currency = code
+	}
+	if err := p.Validate(); err != nil {
+		return domain.Promotion{}, err
+	}
+	p.Active = true
+	return s.repo.Create(ctx, p)
+}
+
+func (s *Service) ListPromotions(ctx context.Context) ([]domain.Promotion, error) {
+	return s.repo.List(ctx)
+}
+
+// DeactivatePromotion stops the promotion from applying to new quotes and
+// orders. Orders already placed keep their discount.
+func (s *Service) DeactivatePromotion(ctx context.Context, id string) (domain.Promotion, error) {
+	if strings.TrimSpace(id) == "" {
+		return domain.Promotion{}, ErrInvalidInput
+	}
+	return s.repo.Deactivate(ctx, id)
+}
+
+// Apply works out the discounts the user's basket gets from the automatic
+// promotions and the codes they entered (see domain.Apply). Unknown codes are
+// rejected like codes that don't apply.
+func (s *Service) Apply(ctx context.Context, userID string, basket domain.Basket, codes []string) (domain.Result, error) {
+	if len(codes) > MaxCodes {
+		return domain.Result{}, fmt.Errorf("%w: at most %d codes", ErrInvalidInput, MaxCodes)
+	}
+
+	var entered []string
+	for _, c := range codes {
+		if c = normalizeCode(c); c != "" && !slices.Contains(entered, c) {
+			entered = append(entered, c)
+		}
+	}
+
+	promos, err := s.repo.ListAutomatic(ctx)
+	if err != nil {
+		return domain.Result{}, err
+	}
+	var unknown []domain.Rejection
+	if len(entered) > 0 {
+		byCode, err := s.repo.GetByCodes(ctx, entered)
+		if err != nil {
+			return domain.Result{}, err
+		}
+		// Entered codes are tried in the order the shopper typed them.
+		for _, c := range entered {
+			i := slices.IndexFunc(byCode, func(p domain.Promotion) bool { return p.Code == c })
+			if i < 0 {
+				unknown = append(unknown, domain.Rejection{Code: c, Reason: "is not a valid code"})
+				continue
+			}
+			promos = append(promos, byCode[i])
+		}
+	}
+
+	ids := make([]string, 0, len(promos))
+	for _, p := range promos {
+		ids = append(ids, p.ID)
+	}
+	usage := map[string]domain.Usage{}
+	if len(ids) > 0 {
+		if usage, err = s.repo.Usage(ctx, ids, userID); err != nil {
+			return domain.Result{}, err
+		}
+	}
+
+	candidates := make([]domain.Candidate, 0, len(promos))
+	for _, p := range promos {
+		candidates = append(candidates, domain.Candidate{Promotion: p, Usage: usage[p.ID]})
+	}
+	result := domain.Apply(basket, candidates, s.now())
+	result.Rejected = append(unknown, result.Rejected...)
+	return result, nil
+}
+
+// Redeem records that the order used the discounts. Each promotion is locked
+// and its limits checked again, so concurrent orders can't use a promotion
+// past its limits; that fails with ErrUnavailable. Call it in the
+// transaction that creates the order.
+func (s *Service) Redeem(ctx context.Context, userID, orderID string, discounts []domain.Discount) error {
+	if strings.TrimSpace(userID) == "" || strings.TrimSpace(orderID) == "" {
+		return ErrInvalidInput
+	}
+
+	// Lock in ID order so that concurrent orders using the same promotions
+	// can't deadlock on each other.
+	sorted := slices.Clone(discounts)
+	slices.SortFunc(sorted, func(a, b domain.Discount) int { return strings.Compare(a.PromotionID, b.PromotionID) })
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for _, d := range sorted {
+			p, err := s.repo.LockByID(ctx, d.PromotionID)
+			if err != nil {
+				return err
+			}
+			usage, err := s.repo.Usage(ctx, []string{p.ID}, userID)
+			if err != nil {
+				return err
+			}
+
+			if reason := p.Unavailable(usage[p.ID], s.now()); reason != "" {
+				return fmt.Errorf("%w: %s %s", ErrUnavailable, p.Name, reason)
+			}
+
+			if err := s.repo.AddRedemption(ctx, p.ID, userID, orderID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
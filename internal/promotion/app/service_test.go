@@ -0,0 +1,240 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/promotion/domain"
+)
+
+// fakeRepo keeps promotions and redemptions in memory.
+type fakeRepo struct {
+	promos      []domain.Promotion
+	redemptions []redemption
+	locked      []string
+}
+
+type redemption struct{ promotionID, userID, orderID string }
+
+func (f *fakeRepo) Create(ctx context.Context, p domain.Promotion) (domain.Promotion, error) {
+	if p.Code != "" && slices.ContainsFunc(f.promos, func(q domain.Promotion) bool { return q.Code == p.Code }) {
+		return domain.Promotion{}, ErrCodeTaken
+	}
+	p.ID = p.Name
+	f.promos = append(f.promos, p)
+	return p, nil
+}
+
+func (f *fakeRepo) List(ctx context.Context) ([]domain.Promotion, error) {
+	return f.promos, nil
+}
+
+func (f *fakeRepo) Deactivate(ctx context.Context, id string) (domain.Promotion, error) {
+	for i := range f.promos {
+		if f.promos[i].ID == id {
+			f.promos[i].Active = false
+			return f.promos[i], nil
+		}
+	}
+	return domain.Promotion{}, ErrNotFound
+}
+
+func (f *fakeRepo) ListAutomatic(ctx context.Context) ([]domain.Promotion, error) {
+	var out []domain.Promotion
+	for _, p := range f.promos {
+		if p.Code == "" && p.Active {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) GetByCodes(ctx context.Context, codes []string) ([]domain.Promotion, error) {
+	var out []domain.Promotion
+	for _, p := range f.promos {
+		if p.Code != "" && slices.Contains(codes, p.Code) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) LockByID(ctx context.Context, id string) (domain.Promotion, error) {
+	f.locked = append(f.locked, id)
+	for _, p := range f.promos {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return domain.Promotion{}, ErrNotFound
+}
+
+func (f *fakeRepo) Usage(ctx context.Context, promotionIDs []string, userID string) (map[string]domain.Usage, error) {
+	out := map[string]domain.Usage{}
+	for _, r := range f.redemptions {
+		if !slices.Contains(promotionIDs, r.promotionID) {
+			continue
+		}
+		u := out[r.promotionID]
+		u.Total++
+		if r.userID == userID {
+			u.ByUser++
+		}
+		out[r.promotionID] = u
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) AddRedemption(ctx context.Context, promotionID, userID, orderID string) error {
+	f.redemptions = append(f.redemptions, redemption{promotionID, userID, orderID})
+	return nil
+}
+
+// fakeTx drops the redemptions fn added when it fails, like a rolled back
+// transaction.
+type fakeTx struct{ repo *fakeRepo }
+
+func (f fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	before := len(f.repo.redemptions)
+	if err := fn(ctx); err != nil {
+		f.repo.redemptions = f.repo.redemptions[:before]
+		return err
+	}
+	return nil
+}
+
+func usd(amount int64) domain.Money { return domain.Money{Currency: "USD", Amount: amount} }
+
+var basket = domain.Basket{Lines: []domain.Line{{ProductID: "mug", Quantity: 2, UnitPrice: usd(1000)}}}
+
+func newTestService(t *testing.T, promos ...domain.Promotion) (*Service, *fakeRepo) {
+	t.Helper()
+	repo := &fakeRepo{}
+	svc := NewService(repo, fakeTx{repo: repo})
+	for _, p := range promos {
+		if _, err := svc.CreatePromotion(context.Background(), p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return svc, repo
+}
+
+func TestCreatePromotion(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+
+	p, err := svc.CreatePromotion(ctx, domain.Promotion{
+		Code:      " welcome ",
+		Name:      "Welcome",
+		Kind:      "fixed_off",
+		AmountOff: domain.Money{Currency: "usd", Amount: 500},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Code != "WELCOME" || p.Kind != domain.KindFixedOff || p.AmountOff.Currency != "USD" || !p.Active {
+		t.Fatalf("expected the promotion to be normalized and active, got %+v", p)
+	}
+
+	if _, err := svc.CreatePromotion(ctx, domain.Promotion{Code: "welcome", Name: "Again", Kind: domain.KindFreeShipping}); !errors.Is(err, ErrCodeTaken) {
+		t.Fatalf("expected ErrCodeTaken, got %v", err)
+	}
+	if _, err := svc.CreatePromotion(ctx, domain.Promotion{Name: "Bad", Kind: domain.KindPercentOff}); !errors.Is(err, domain.ErrInvalidPromotion) {
+		t.Fatalf("expected ErrInvalidPromotion, got %v", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	svc, _ := newTestService(t,
+		domain.Promotion{Name: "auto", Kind: domain.KindPercentOff, PercentOff: 10, Stackable: true},
+		domain.Promotion{Name: "five", Code: "FIVE", Kind: domain.KindFixedOff, AmountOff: usd(500), Stackable: true},
+	)
+
+	r, err := svc.Apply(context.Background(), "u1", basket, []string{"five", "FIVE", "nope"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Discounts) != 2 || r.Total != usd(700) {
+		t.Fatalf("expected the automatic promotion and FIVE once, got %+v", r)
+	}
+	if len(r.Rejected) != 1 || r.Rejected[0] != (domain.Rejection{Code: "NOPE", Reason: "is not a valid code"}) {
+		t.Fatalf("expected NOPE to be rejected, got %+v", r.Rejected)
+	}
+
+	if _, err := svc.Apply(context.Background(), "u1", basket, []string{"A", "B", "C", "D", "E", "F"}); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for too many codes, got %v", err)
+	}
+}
+
+func TestRedeem(t *testing.T) {
+	ctx := context.Background()
+	once := domain.Promotion{Name: "once", Code: "ONCE", Kind: domain.KindFreeShipping, MaxUsesPerUser: 1, Stackable: true}
+	two := domain.Promotion{Name: "two", Code: "TWO", Kind: domain.KindFreeShipping, MaxUses: 2, Stackable: true}
+	svc, repo := newTestService(t, once, two)
+
+	discounts := func(codes ...string) []domain.Discount {
+		var out []domain.Discount
+		for _, c := range codes {
+			p, _ := repo.GetByCodes(ctx, []string{c})
+			out = append(out, domain.Discount{PromotionID: p[0].ID, Code: c, Name: p[0].Name, FreeShipping: true})
+		}
+		return out
+	}
+
+	if err := svc.Redeem(ctx, "u1", "order-1", discounts("ONCE", "TWO")); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("per-user limit", func(t *testing.T) {
+		if err := svc.Redeem(ctx, "u1", "order-2", discounts("TWO", "ONCE")); !errors.Is(err, ErrUnavailable) {
+			t.Fatalf("expected ErrUnavailable, got %v", err)
+		}
+		if len(repo.redemptions) != 2 {
+			t.Fatalf("expected the failed redemption to roll back, got %+v", repo.redemptions)
+		}
+		if err := svc.Redeem(ctx, "u2", "order-3", discounts("ONCE")); err != nil {
+			t.Fatalf("expected another user to redeem ONCE, got %v", err)
+		}
+	})
+
+	t.Run("global limit", func(t *testing.T) {
+		if err := svc.Redeem(ctx, "u2", "order-4", discounts("TWO")); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.Redeem(ctx, "u3", "order-5", discounts("TWO")); !errors.Is(err, ErrUnavailable) {
+			t.Fatalf("expected TWO to be used up, got %v", err)
+		}
+	})
+
+	t.Run("window closed since the quote", func(t *testing.T) {
+		svc.now = func() time.Time { return time.Now().Add(time.Hour) }
+		defer func() { svc.now = time.Now }()
+		repo.promos[0].EndsAt = time.Now()
+
+		if err := svc.Redeem(ctx, "u4", "order-6", discounts("ONCE")); !errors.Is(err, ErrUnavailable) {
+			t.Fatalf("expected ErrUnavailable for an expired promotion, got %v", err)
+		}
+	})
+}
+
+func TestRedeemLocksInIDOrder(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestService(t,
+		domain.Promotion{Name: "b", Code: "B", Kind: domain.KindFreeShipping, Stackable: true},
+		domain.Promotion{Name: "a", Code: "A", Kind: domain.KindFreeShipping, Stackable: true},
+	)
+
+	discounts := []domain.Discount{{PromotionID: "b", FreeShipping: true}, {PromotionID: "a", FreeShipping: true}}
+	if err := svc.Redeem(ctx, "u1", "order-1", discounts); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(repo.locked, []string{"a", "b"}) {
+		t.Fatalf("expected promotions locked in ID order, got %v", repo.locked)
+	}
+	if discounts[0].PromotionID != "b" {
+		t.Fatalf("expected the caller's discounts left as they were")
+	}
+}
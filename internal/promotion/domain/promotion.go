@@ -0,0 +1,359 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
+)
+
+type Kind string
+
+const (
+	// KindPercentOff takes PercentOff percent off the cart, or off the lines
+	// of ProductID when it is set.
+	KindPercentOff Kind = "PERCENT_OFF"
+	// KindFixedOff takes AmountOff off the cart.
+	KindFixedOff Kind = "FIXED_OFF"
+	// KindBuyXGetY makes GetQuantity of every BuyQuantity+GetQuantity units
+	// of ProductID free, the cheapest units first.
+	KindBuyXGetY Kind = "BUY_X_GET_Y"
+	// KindFreeShipping waives the shipping fee.
+	KindFreeShipping Kind = "FREE_SHIPPING"
+)
+
+var ErrInvalidPromotion = errors.New("invalid promotion")
+
+// Money is an amount in the minor unit of its currency.
+type Money = money.Money
+
+// Promotion is a discount the cart gets either automatically, when Code is
+// empty, or when the shopper enters Code. MinSpend and the validity window
+// apply to every kind.
+type Promotion struct {
+	ID          string
+	Code        string // upper case; empty for automatic promotions
+	Name        string
+	Kind        Kind
+	PercentOff  int32  // PERCENT_OFF: 1 to 100
+	AmountOff   Money  // FIXED_OFF
+	ProductID   string // PERCENT_OFF (optional) and BUY_X_GET_Y
+	BuyQuantity int32  // BUY_X_GET_Y
+	GetQuantity int32  // BUY_X_GET_Y
+	// MinSpend is the cart subtotal needed to qualify; zero for none.
+	MinSpend Money
+	StartsAt time.Time // zero: already started
+	EndsAt   time.Time // zero: never ends
+	// MaxUses and MaxUsesPerUser count placed orders; 0 is unlimited.
+	MaxUses        int32
+	MaxUsesPerUser int32
+	// Stackable promotions combine with each other; any other promotion
+	// only applies on its own.
+	Stackable bool
+	Active    bool
+	CreatedAt time.Time
+}
+
+// Validate checks that p describes a discount that can be applied.
+func (p Promotion) Validate() error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidPromotion, fmt.Sprintf(format, args...))
+	}
+
+	if strings.TrimSpace(p.Name) == "" {
+		return invalid("name is required")
+	}
+	if p.Code != strings.ToUpper(strings.TrimSpace(p.Code)) || strings.ContainsAny(p.Code, " \t") {
+		return invalid("code must be upper case without spaces")
+	}
+	switch p.Kind {
+	case KindPercentOff:
+		if p.PercentOff < 1 || p.PercentOff > 100 {
+			return invalid("percent_off must be between 1 and 100")
+		}
+	case KindFixedOff:
+		if p.AmountOff.Currency == "" || p.AmountOff.Amount <= 0 {
+			return invalid("amount_off must be positive")
+		}
+	case KindBuyXGetY:
+		if p.ProductID == "" {
+			return invalid("product_id is required")
+		}
+		if p.BuyQuantity < 1 || p.GetQuantity < 1 {
+			return invalid("buy_quantity and get_quantity must be positive")
+		}
+	case KindFreeShipping:
+	default:
+		return invalid("unknown kind %q", p.Kind)
+	}
+	if p.MinSpend.Amount < 0 || (p.MinSpend.Amount > 0 && p.MinSpend.Currency == "") {
+		return invalid("min_spend must be a positive amount with a currency")
+	}
+	if p.MinSpend.Amount > 0 && p.AmountOff.Currency != "" && p.MinSpend.Currency != p.AmountOff.Currency {
+		return invalid("min_spend and amount_off must be in the same currency")
+	}
+	if !p.StartsAt.IsZero() && !p.EndsAt.IsZero() && !p.EndsAt.After(p.StartsAt) {
+		return invalid("ends_at must be after starts_at")
+	}
+	if p.MaxUses < 0 || p.MaxUsesPerUser < 0 {
+		return invalid("usage limits cannot be negative")
+	}
+	return nil
+}
+
+// Unavailable tells why p can't be used at now with the given usage, or
+// returns "" when it can.
+func (p Promotion) Unavailable(usage Usage, now time.Time) string {
+	switch {
+	case !p.Active:
+		return "is no longer available"
+	case !p.StartsAt.IsZero() && now.Before(p.StartsAt):
+		return "is not valid yet"
+	case !p.EndsAt.IsZero() && !now.Before(p.EndsAt):
+		return "has expired"
+	case p.MaxUses > 0 && usage.Total >= int64(p.MaxUses):
+		return "has been used up"
+	case p.MaxUsesPerUser > 0 && usage.ByUser >= int64(p.MaxUsesPerUser):
+		return "has already been used"
+	}
+	return ""
+}
+
+// Usage counts the orders a promotion was redeemed on.
+type Usage struct {
+	Total  int64
+	ByUser int64 // by the user the cart belongs to
+}
+
+// Line is a cart line as checkout priced it.
+type Line struct {
+	ProductID string
+	VariantID string
+	Quantity  int64
+	UnitPrice Money
+}
+
+// Basket is what promotions are applied to.
+type Basket struct {
+	Lines []Line
+	// Shipping is the fee free shipping would waive, when it is known. It
+	// only matters when choosing between promotions that don't stack.
+	Shipping Money
+}
+
+// Discount is what one promotion takes off a basket, in the basket currency.
+type Discount struct {
+	PromotionID  string
+	Code         string // empty for automatic promotions
+	Name         string
+	Amount       Money // zero for free shipping
+	FreeShipping bool
+}
+
+// Rejection explains why an entered code doesn't apply.
+type Rejection struct {
+	Code   string
+	Reason string
+}
+
+// Candidate is a promotion that may apply, with how often it has been used.
+type Candidate struct {
+	Promotion Promotion
+	Usage     Usage
+}
+
+type Result struct {
+	Discounts []Discount
+	Rejected  []Rejection
+	// Total is the sum of the discount amounts; never more than the basket
+	// subtotal.
+	Total        Money
+	FreeShipping bool
+}
+
+// Apply works out which candidates the basket gets. Candidates with a code
+// that don't apply are rejected with a reason; automatic ones are silently
+// left out. Stackable promotions all apply together, while a promotion that
+// doesn't stack applies alone; whichever of those choices saves the shopper
+// most wins.
+func Apply(basket Basket, candidates []Candidate, now time.Time) Result {
+	var (
+		result    Result
+		stackable []Discount
+		exclusive []Discount
+	)
+	subtotal, currency, mixed := basketSubtotal(basket)
+	result.Total = money.Zero(currency)
+
+	reject := func(code, reason string) {
+		if code != "" {
+			result.Rejected = append(result.Rejected, Rejection{Code: code, Reason: reason})
+		}
+	}
+
+	for _, c := range candidates {
+		if mixed {
+			reject(c.Promotion.Code, "can't be applied to a cart in several currencies")
+			continue
+		}
+		d, reason := evaluate(c.Promotion, c.Usage, basket, subtotal, now)
+		if reason != "" {
+			reject(c.Promotion.Code, reason)
+			continue
+		}
+		if c.Promotion.Stackable {
+			stackable = append(stackable, d)
+		} else {
+			exclusive = append(exclusive, d)
+		}
+	}
+
+	// The stackable set is capped at the subtotal and holds free shipping
+	// at most once.
+	var stacked []Discount
+	left := subtotal.Amount
+	free := false
+	for _, d := range stackable {
+		if d.FreeShipping {
+			if free {
+				reject(d.Code, "free shipping already applies")
+				continue
+			}
+			free = true
+			stacked = append(stacked, d)
+			continue
+		}
+		if left == 0 {
+			reject(d.Code, "nothing left to discount")
+			continue
+		}
+		d.Amount.Amount = min(d.Amount.Amount, left)
+		left -= d.Amount.Amount
+		stacked = append(stacked, d)
+	}
+
+	best, bestValue := stacked, value(stacked, basket.Shipping)
+	for _, d := range exclusive {
+		if v := value([]Discount{d}, basket.Shipping); v > bestValue || len(best) == 0 {
+			best, bestValue = []Discount{d}, v
+		}
+	}
+
+	applied := map[string]bool{}
+	for _, d := range best {
+		applied[d.PromotionID] = true
+		result.Discounts = append(result.Discounts, d)
+		result.Total.Amount += d.Amount.Amount
+		result.FreeShipping = result.FreeShipping || d.FreeShipping
+	}
+	for _, d := range append(stacked, exclusive...) {
+		if !applied[d.PromotionID] {
+			reject(d.Code, "can't be combined with the other promotions on this cart")
+		}
+	}
+	return result
+}
+
+// value is what the discounts save the shopper, free shipping included.
+func value(discounts []Discount, shipping Money) int64 {
+	var v int64
+	for _, d := range discounts {
+		v += d.Amount.Amount
+		if d.FreeShipping {
+			v += shipping.Amount
+		}
+	}
+	return v
+}
+
+// evaluate returns the promotion's discount on the basket, or why it
+// doesn't apply.
+func evaluate(p Promotion, usage Usage, basket Basket, subtotal Money, now time.Time) (Discount, string) {
+	if reason := p.Unavailable(usage, now); reason != "" {
+		return Discount{}, reason
+	}
+
+	if p.MinSpend.Amount > 0 {
+		if p.MinSpend.Currency != subtotal.Currency {
+			return Discount{}, fmt.Sprintf("is not available in %s", subtotal.Currency)
+		}
+		if subtotal.Amount < p.MinSpend.Amount {
+			return Discount{}, fmt.Sprintf("needs a minimum spend of %s", p.MinSpend)
+		}
+	}
+
+	d := Discount{PromotionID: p.ID, Code: p.Code, Name: p.Name, Amount: money.Zero(subtotal.Currency)}
+	switch p.Kind {
+	case KindPercentOff:
+		base := subtotal.Amount
+		if p.ProductID != "" {
+			base = 0
+			for _, ln := range basket.Lines {
+				if ln.ProductID == p.ProductID {
+					base += ln.UnitPrice.Amount * ln.Quantity
+				}
+			}
+		}
+		d.Amount.Amount = base * int64(p.PercentOff) / 100
+	case KindFixedOff:
+		if p.AmountOff.Currency != subtotal.Currency {
+			return Discount{}, fmt.Sprintf("is not available in %s", subtotal.Currency)
+		}
+		d.Amount.Amount = min(p.AmountOff.Amount, subtotal.Amount)
+	case KindBuyXGetY:
+		d.Amount.Amount = freeUnits(p, basket.Lines)
+	case KindFreeShipping:
+		d.FreeShipping = true
+		return d, ""
+	}
+
+	if d.Amount.Amount <= 0 {
+		if p.Kind == KindBuyXGetY {
+			return Discount{}, fmt.Sprintf("needs %d of the product in the cart", p.BuyQuantity+p.GetQuantity)
+		}
+		return Discount{}, "doesn't apply to anything in the cart"
+	}
+	return d, ""
+}
+
+// freeUnits prices the units a buy-X-get-Y promotion gives away: of every
+// BuyQuantity+GetQuantity units of the product, the GetQuantity cheapest
+// ones, so a variant line only counts at its own price.
+func freeUnits(p Promotion, lines []Line) int64 {
+	var prices []int64 // one per unit
+	for _, ln := range lines {
+		if ln.ProductID != p.ProductID {
+			continue
+		}
+		for i := int64(0); i < ln.Quantity; i++ {
+			prices = append(prices, ln.UnitPrice.Amount)
+		}
+	}
+
+	free := int64(len(prices)) / int64(p.BuyQuantity+p.GetQuantity) * int64(p.GetQuantity)
+	slices.Sort(prices)
+	var amount int64
+	for _, price := range prices[:free] {
+		amount += price
+	}
+	return amount
+}
+
+// basketSubtotal sums the lines. mixed reports lines in more than one
+// currency, which promotions don't apply to.
+func basketSubtotal(b Basket) (subtotal Money, currency string, mixed bool) {
+	for _, ln := range b.Lines {
+		if currency == "" {
+			currency = ln.UnitPrice.Currency
+		}
+		if ln.UnitPrice.Currency != currency {
+			return Money{}, "", true
+		}
+		subtotal.Amount += ln.UnitPrice.Amount * ln.Quantity
+	}
+	subtotal.Currency = currency
+	return subtotal, currency, false
+}
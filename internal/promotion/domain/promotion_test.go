@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func usd(amount int64) Money { return Money{Currency: "USD", Amount: amount} }
+
+// basket holds 3 mugs at 10.00 and 1 lamp at 50.00: 80.00 in total.
+var basket = Basket{
+	Lines: []Line{
+		{ProductID: "mug", Quantity: 3, UnitPrice: usd(1000)},
+		{ProductID: "lamp", Quantity: 1, UnitPrice: usd(5000)},
+	},
+	Shipping: usd(1500),
+}
+
+func TestApplyKinds(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		promo  Promotion
+		amount int64
+		free   bool
+		reason string
+	}{
+		{name: "percent off the cart", promo: Promotion{Kind: KindPercentOff, PercentOff: 10}, amount: 800},
+		{name: "percent off one product", promo: Promotion{Kind: KindPercentOff, PercentOff: 50, ProductID: "lamp"}, amount: 2500},
+		{name: "fixed off", promo: Promotion{Kind: KindFixedOff, AmountOff: usd(1500)}, amount: 1500},
+		{name: "fixed off never exceeds the cart", promo: Promotion{Kind: KindFixedOff, AmountOff: usd(100000)}, amount: 8000},
+		{name: "fixed off in another currency", promo: Promotion{Kind: KindFixedOff, AmountOff: Money{Currency: "EUR", Amount: 500}}, reason: "is not available in USD"},
+		{name: "buy 2 get 1", promo: Promotion{Kind: KindBuyXGetY, ProductID: "mug", BuyQuantity: 2, GetQuantity: 1}, amount: 1000},
+		{name: "buy 3 get 1 needs 4", promo: Promotion{Kind: KindBuyXGetY, ProductID: "mug", BuyQuantity: 3, GetQuantity: 1}, reason: "needs 4 of the product in the cart"},
+		{name: "free shipping", promo: Promotion{Kind: KindFreeShipping}, free: true},
+		{name: "minimum spend met", promo: Promotion{Kind: KindPercentOff, PercentOff: 10, MinSpend: usd(8000)}, amount: 800},
+		{name: "minimum spend missed", promo: Promotion{Kind: KindPercentOff, PercentOff: 10, MinSpend: usd(8001)}, reason: "needs a minimum spend of USD 80.01"},
+		{name: "not started", promo: Promotion{Kind: KindFreeShipping, StartsAt: now.Add(time.Hour)}, reason: "is not valid yet"},
+		{name: "expired", promo: Promotion{Kind: KindFreeShipping, EndsAt: now}, reason: "has expired"},
+		{name: "deactivated", promo: Promotion{Kind: KindFreeShipping, Active: false}, reason: "is no longer available"},
+		{name: "product not in the cart", promo: Promotion{Kind: KindPercentOff, PercentOff: 10, ProductID: "desk"}, reason: "doesn't apply to anything in the cart"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := tc.promo
+			p.ID, p.Code, p.Name = "promo", "CODE", tc.name
+			p.Active = p.Active || tc.name != "deactivated"
+
+			r := Apply(basket, []Candidate{{Promotion: p}}, now)
+			if tc.reason != "" {
+				if len(r.Rejected) != 1 || r.Rejected[0].Reason != tc.reason || len(r.Discounts) != 0 {
+					t.Fatalf("expected the code to be rejected with %q, got %+v", tc.reason, r)
+				}
+				return
+			}
+			if len(r.Discounts) != 1 || r.Total != usd(tc.amount) || r.FreeShipping != tc.free {
+				t.Fatalf("expected a discount of %d (free shipping %v), got %+v", tc.amount, tc.free, r)
+			}
+		})
+	}
+}
+
+func TestApplyUsageLimits(t *testing.T) {
+	now := time.Now()
+	p := Promotion{ID: "p", Code: "ONCE", Kind: KindFreeShipping, Active: true, MaxUses: 100, MaxUsesPerUser: 1}
+
+	if r := Apply(basket, []Candidate{{Promotion: p, Usage: Usage{Total: 99}}}, now); len(r.Discounts) != 1 {
+		t.Fatalf("expected the promotion to apply below its limits, got %+v", r)
+	}
+	if r := Apply(basket, []Candidate{{Promotion: p, Usage: Usage{Total: 5, ByUser: 1}}}, now); len(r.Rejected) != 1 || r.Rejected[0].Reason != "has already been used" {
+		t.Fatalf("expected the per-user limit to reject the code, got %+v", r)
+	}
+	if r := Apply(basket, []Candidate{{Promotion: p, Usage: Usage{Total: 100}}}, now); len(r.Rejected) != 1 || r.Rejected[0].Reason != "has been used up" {
+		t.Fatalf("expected the global limit to reject the code, got %+v", r)
+	}
+}
+
+func TestApplyStacking(t *testing.T) {
+	now := time.Now()
+	tenOff := Promotion{ID: "auto10", Name: "10% off", Kind: KindPercentOff, PercentOff: 10, Stackable: true, Active: true}
+	fiveOff := Promotion{ID: "five", Code: "FIVE", Name: "5.00 off", Kind: KindFixedOff, AmountOff: usd(500), Stackable: true, Active: true}
+	ship := Promotion{ID: "ship", Code: "SHIP", Name: "Free shipping", Kind: KindFreeShipping, Active: true}
+	half := Promotion{ID: "half", Code: "HALF", Name: "Half off", Kind: KindPercentOff, PercentOff: 50, Active: true}
+
+	t.Run("stackable promotions add up", func(t *testing.T) {
+		r := Apply(basket, []Candidate{{Promotion: tenOff}, {Promotion: fiveOff}}, now)
+		if len(r.Discounts) != 2 || r.Total != usd(1300) {
+			t.Fatalf("expected 8.00 + 5.00 off, got %+v", r)
+		}
+	})
+
+	t.Run("the better choice wins", func(t *testing.T) {
+		r := Apply(basket, []Candidate{{Promotion: tenOff}, {Promotion: fiveOff}, {Promotion: half}}, now)
+		if len(r.Discounts) != 1 || r.Discounts[0].PromotionID != "half" || r.Total != usd(4000) {
+			t.Fatalf("expected half off on its own, got %+v", r)
+		}
+		if len(r.Rejected) != 1 || r.Rejected[0].Code != "FIVE" {
+			t.Fatalf("expected FIVE to be rejected, and the automatic promotion left out quietly, got %+v", r.Rejected)
+		}
+	})
+
+	t.Run("free shipping counts at the shipping fee", func(t *testing.T) {
+		r := Apply(basket, []Candidate{{Promotion: fiveOff}, {Promotion: ship}}, now)
+		if len(r.Discounts) != 1 || !r.FreeShipping {
+			t.Fatalf("expected free shipping (15.00) to beat 5.00 off, got %+v", r)
+		}
+
+		unknownFee := basket
+		unknownFee.Shipping = Money{}
+		r = Apply(unknownFee, []Candidate{{Promotion: ship}}, now)
+		if len(r.Discounts) != 1 || !r.FreeShipping {
+			t.Fatalf("expected free shipping to apply on its own, got %+v", r)
+		}
+	})
+
+	t.Run("stacked discounts never exceed the subtotal", func(t *testing.T) {
+		all := fiveOff
+		all.ID, all.Code, all.AmountOff = "all", "ALL", usd(7900)
+		r := Apply(basket, []Candidate{{Promotion: all}, {Promotion: fiveOff}, {Promotion: tenOff}}, now)
+		if r.Total != usd(8000) {
+			t.Fatalf("expected the discounts to stop at 80.00, got %+v", r)
+		}
+		if len(r.Rejected) != 0 || len(r.Discounts) != 2 {
+			t.Fatalf("expected ALL and FIVE to apply and nothing to be left for the automatic one, got %+v", r)
+		}
+	})
+
+	t.Run("carts in several currencies get no promotions", func(t *testing.T) {
+		mixed := Basket{Lines: append([]Line{{ProductID: "tea", Quantity: 1, UnitPrice: Money{Currency: "IDR", Amount: 50000}}}, basket.Lines...)}
+		r := Apply(mixed, []Candidate{{Promotion: fiveOff}, {Promotion: tenOff}}, now)
+		if len(r.Discounts) != 0 || len(r.Rejected) != 1 {
+			t.Fatalf("expected FIVE to be rejected and nothing applied, got %+v", r)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	valid := Promotion{Name: "Summer", Code: "SUMMER", Kind: KindPercentOff, PercentOff: 20}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected a valid promotion, got %v", err)
+	}
+
+	invalid := map[string]func(p *Promotion){
+		"lower case code":     func(p *Promotion) { p.Code = "summer" },
+		"no name":             func(p *Promotion) { p.Name = " " },
+		"percent over 100":    func(p *Promotion) { p.PercentOff = 101 },
+		"unknown kind":        func(p *Promotion) { p.Kind = "BOGO" },
+		"buy x get y product": func(p *Promotion) { p.Kind, p.BuyQuantity, p.GetQuantity = KindBuyXGetY, 1, 1 },
+		"ends before start":   func(p *Promotion) { p.StartsAt, p.EndsAt = time.Unix(10, 0), time.Unix(5, 0) },
+		"min spend currency":  func(p *Promotion) { p.MinSpend = Money{Amount: 100} },
+		"negative limit":      func(p *Promotion) { p.MaxUsesPerUser = -1 },
+	}
+	for name, mutate := range invalid {
+		p := valid
+		mutate(&p)
+		if err := p.Validate(); !errors.Is(err, ErrInvalidPromotion) {
+			t.Errorf("%s: expected ErrInvalidPromotion, got %v", name, err)
+		}
+	}
+}
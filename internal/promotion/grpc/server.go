@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	promotionv1 "github.com/dwikikusuma/shoping-llm/api/gen/promotion/v1"
+	"github.com/dwikikusuma/shoping-llm/internal/promotion/app"
+	"github.com/dwikikusuma/shoping-llm/internal/promotion/domain"
+	"github.com/dwikikusuma/shoping-llm/pkg/grpcauth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	promotionv1.UnimplementedPromotionServiceServer
+	svc        *app.Service
+	adminToken string
+}
+
+// NewServer returns the promotion gRPC server. Every RPC requires the
+// "admin-token" metadata to equal adminToken; an empty adminToken disables
+// them. Shoppers use promotions through checkout.
+func NewServer(svc *app.Service, adminToken string) *Server {
+	return &Server{svc: svc, adminToken: adminToken}
+}
+
+func (s *Server) CreatePromotion(ctx context.Context, req *promotionv1.CreatePromotionRequest) (*promotionv1.Promotion, error) {
+	if err := grpcauth.RequireAdmin(ctx, s.adminToken); err != nil {
+		return nil, err
+	}
+
+	in := req.GetPromotion()
+	p, err := s.svc.CreatePromotion(ctx, domain.Promotion{
+		Code:           in.GetCode(),
+		Name:           in.GetName(),
+		Kind:           domain.Kind(in.GetKind()),
+		PercentOff:     in.GetPercentOff(),
+		AmountOff:      fromProtoMoney(in.GetAmountOff()),
+		ProductID:      in.GetProductId(),
+		BuyQuantity:    in.GetBuyQuantity(),
+		GetQuantity:    in.GetGetQuantity(),
+		MinSpend:       fromProtoMoney(in.GetMinSpend()),
+		StartsAt:       fromUnix(in.GetStartsAtUnix()),
+		EndsAt:         fromUnix(in.GetEndsAtUnix()),
+		MaxUses:        in.GetMaxUses(),
+		MaxUsesPerUser: in.GetMaxUsesPerUser(),
+		Stackable:      in.GetStackable(),
+	})
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return toProto(p), nil
+}
+
+func (s *Server) ListPromotions(ctx context.Context, req *promotionv1.ListPromotionsRequest) (*promotionv1.ListPromotionsResponse, error) {
+	if err := grpcauth.RequireAdmin(ctx, s.adminToken); err != nil {
+		return nil, err
+	}
+
+	promos, err := s.svc.ListPromotions(ctx)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	out := make([]*promotionv1.Promotion, 0, len(promos))
+	for _, p := range promos {
+		out = append(out, toProto(p))
+	}
+	return &promotionv1.ListPromotionsResponse{Promotions: out}, nil
+}
+
+func (s *Server) DeactivatePromotion(ctx context.Context, req *promotionv1.DeactivatePromotionRequest) (*promotionv1.Promotion, error) {
+	if err := grpcauth.RequireAdmin(ctx, s.adminToken); err != nil {
+		return nil, err
+	}
+
+	p, err := s.svc.DeactivatePromotion(ctx, req.GetId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return toProto(p), nil
+}
+
+func toProto(p domain.Promotion) *promotionv1.Promotion {
+	return &promotionv1.Promotion{
+		Id:             p.ID,
+		Code:           p.Code,
+		Name:           p.Name,
+		Kind:           string(p.Kind),
+		PercentOff:     p.PercentOff,
+		AmountOff:      toProtoMoney(p.AmountOff),
+		ProductId:      p.ProductID,
+		BuyQuantity:    p.BuyQuantity,
+		GetQuantity:    p.GetQuantity,
+		MinSpend:       toProtoMoney(p.MinSpend),
+		StartsAtUnix:   toUnix(p.StartsAt),
+		EndsAtUnix:     toUnix(p.EndsAt),
+		MaxUses:        p.MaxUses,
+		MaxUsesPerUser: p.MaxUsesPerUser,
+		Stackable:      p.Stackable,
+		Active:         p.Active,
+		CreatedAtUnix:  p.CreatedAt.Unix(),
+	}
+}
+
+func toProtoMoney(m domain.Money) *promotionv1.Money {
+	if m.Currency == "" {
+		return nil
+	}
+	return &promotionv1.Money{Currency: m.Currency, Amount: m.Amount}
+}
+
+func fromProtoMoney(m *promotionv1.Money) domain.Money {
+	return domain.Money{Currency: m.GetCurrency(), Amount: m.GetAmount()}
+}
+
+func toUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func fromUnix(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+func mapErr(err error) error {
+	if errors.Is(err, app.ErrInvalidInput) || errors.Is(err, domain.ErrInvalidPromotion) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, app.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, app.ErrCodeTaken) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	return status.Error(codes.Internal, "internal error")
+}
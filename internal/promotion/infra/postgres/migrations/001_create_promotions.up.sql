@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS promotions (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code              TEXT UNIQUE, -- NULL for automatic promotions
+    name              TEXT NOT NULL,
+    kind              TEXT NOT NULL,
+    percent_off       INT NOT NULL DEFAULT 0 CHECK (percent_off BETWEEN 0 AND 100),
+    currency          TEXT NOT NULL DEFAULT '', -- of amount_off and min_spend
+    amount_off        BIGINT NOT NULL DEFAULT 0 CHECK (amount_off >= 0),
+    min_spend         BIGINT NOT NULL DEFAULT 0 CHECK (min_spend >= 0),
+    product_id        UUID,
+    buy_quantity      INT NOT NULL DEFAULT 0,
+    get_quantity      INT NOT NULL DEFAULT 0,
+    starts_at         TIMESTAMPTZ,
+    ends_at           TIMESTAMPTZ,
+    max_uses          INT NOT NULL DEFAULT 0, -- 0 is unlimited
+    max_uses_per_user INT NOT NULL DEFAULT 0, -- 0 is unlimited
+    stackable         BOOLEAN NOT NULL DEFAULT false,
+    active            BOOLEAN NOT NULL DEFAULT true,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CHECK (kind IN ('PERCENT_OFF','FIXED_OFF','BUY_X_GET_Y','FREE_SHIPPING'))
+);
+
+-- one row per promotion used on an order; usage limits count these
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    promotion_id UUID NOT NULL REFERENCES promotions(id),
+    user_id      TEXT NOT NULL,
+    order_id     UUID NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (promotion_id, order_id)
+);
+
+CREATE INDEX IF NOT EXISTS ix_promotion_redemptions_user
+    ON promotion_redemptions(promotion_id, user_id);
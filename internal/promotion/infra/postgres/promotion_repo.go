@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/dwikikusuma/shoping-llm/internal/promotion/app"
+	"github.com/dwikikusuma/shoping-llm/internal/promotion/domain"
+	"github.com/dwikikusuma/shoping-llm/internal/promotion/infra/postgres/promotiondb"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type PromotionRepo struct {
+	q *promotiondb.Queries
+}
+
+func NewPromotionRepo(db *sql.DB) *PromotionRepo {
+	return &PromotionRepo{q: promotiondb.New(db)}
+}
+
+// queries joins the caller's transaction when ctx carries one (see pg.TxManager).
+func (r *PromotionRepo) queries(ctx context.Context) *promotiondb.Queries {
+	if tx, ok := pg.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *PromotionRepo) Create(ctx context.Context, p domain.Promotion) (domain.Promotion, error) {
+	productID, err := parseNullUUID(p.ProductID)
+	if err != nil {
+		return domain.Promotion{}, err
+	}
+
+	// amount_off and min_spend share a currency; Validate made sure they agree.
+	currency := p.AmountOff.Currency
+	if currency == "" {
+		currency = p.MinSpend.Currency
+	}
+
+	row, err := r.queries(ctx).CreatePromotion(ctx, promotiondb.CreatePromotionParams{
+		Code:           sql.NullString{String: p.Code, Valid: p.Code != ""},
+		Name:           p.Name,
+		Kind:           string(p.Kind),
+		PercentOff:     p.PercentOff,
+		Currency:       currency,
+		AmountOff:      p.AmountOff.Amount,
+		MinSpend:       p.MinSpend.Amount,
+		ProductID:      productID,
+		BuyQuantity:    p.BuyQuantity,
+		GetQuantity:    p.GetQuantity,
+		StartsAt:       sql.NullTime{Time: p.StartsAt, Valid: !p.StartsAt.IsZero()},
+		EndsAt:         sql.NullTime{Time: p.EndsAt, Valid: !p.EndsAt.IsZero()},
+		MaxUses:        p.MaxUses,
+		MaxUsesPerUser: p.MaxUsesPerUser,
+		Stackable:      p.Stackable,
+	})
+	if pg.IsUniqueViolation(err) {
+		return domain.Promotion{}, app.ErrCodeTaken
+	}
+	if err != nil {
+		return domain.Promotion{}, err
+	}
+	return toDomain(row), nil
+}
+
+func (r *PromotionRepo) List(ctx context.Context) ([]domain.Promotion, error) {
+	rows, err := r.queries(ctx).ListPromotions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toDomainList(rows), nil
+}
+
+func (r *PromotionRepo) Deactivate(ctx context.Context, id string) (domain.Promotion, error) {
+	pid, err := parseUUID(id)
+	if err != nil {
+		return domain.Promotion{}, err
+	}
+
+	row, err := r.queries(ctx).DeactivatePromotion(ctx, pid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Promotion{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.Promotion{}, err
+	}
+	return toDomain(row), nil
+}
+
+func (r *PromotionRepo) ListAutomatic(ctx context.Context) ([]domain.Promotion, error) {
+	rows, err := r.queries(ctx).ListAutomaticPromotions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toDomainList(rows), nil
+}
+
+func (r *PromotionRepo) GetByCodes(ctx context.Context, codes []string) ([]domain.Promotion, error) {
+	rows, err := r.queries(ctx).GetPromotionsByCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	return toDomainList(rows), nil
+}
+
+func (r *PromotionRepo) LockByID(ctx context.Context, id string) (domain.Promotion, error) {
+	pid, err := parseUUID(id)
+	if err != nil {
+		return domain.Promotion{}, err
+	}
+
+	row, err := r.queries(ctx).LockPromotionByID(ctx, pid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Promotion{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.Promotion{}, err
+	}
+	return toDomain(row), nil
+}
+
+func (r *PromotionRepo) Usage(ctx context.Context, promotionIDs []string, userID string) (map[string]domain.Usage, error) {
+	ids := make([]uuid.UUID, 0, len(promotionIDs))
+	for _, id := range promotionIDs {
+		pid, err := parseUUID(id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, pid)
+	}
+
+	rows, err := r.queries(ctx).PromotionUsage(ctx, promotiondb.PromotionUsageParams{
+		UserID:       userID,
+		PromotionIds: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]domain.Usage, len(rows))
+	for _, row := range rows {
+		usage[row.PromotionID.String()] = domain.Usage{Total: row.Total, ByUser: row.ByUser}
+	}
+	return usage, nil
+}
+
+func (r *PromotionRepo) AddRedemption(ctx context.Context, promotionID, userID, orderID string) error {
+	pid, err := parseUUID(promotionID)
+	if err != nil {
+		return err
+	}
+	oid, err := parseUUID(orderID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries(ctx).AddPromotionRedemption(ctx, promotiondb.AddPromotionRedemptionParams{
+		PromotionID: pid,
+		UserID:      userID,
+		OrderID:     oid,
+	})
+}
+
+func toDomain(row promotiondb.Promotion) domain.Promotion {
+	p := domain.Promotion{
+		ID:             row.ID.String(),
+		Code:           row.Code.String,
+		Name:           row.Name,
+		Kind:           domain.Kind(row.Kind),
+		PercentOff:     row.PercentOff,
+		BuyQuantity:    row.BuyQuantity,
+		GetQuantity:    row.GetQuantity,
+		StartsAt:       row.StartsAt.Time,
+		EndsAt:         row.EndsAt.Time,
+		MaxUses:        row.MaxUses,
+		MaxUsesPerUser: row.MaxUsesPerUser,
+		Stackable:      row.Stackable,
+		Active:         row.Active,
+		CreatedAt:      row.CreatedAt,
+	}
+	if row.ProductID.Valid {
+		p.ProductID = row.ProductID.UUID.String()
+	}
+	if row.AmountOff > 0 {
+		p.AmountOff = domain.Money{Currency: row.Currency, Amount: row.AmountOff}
+	}
+	if row.MinSpend > 0 {
+		p.MinSpend = domain.Money{Currency: row.Currency, Amount: row.MinSpend}
+	}
+	return p
+}
+
+func toDomainList(rows []promotiondb.Promotion) []domain.Promotion {
+	out := make([]domain.Promotion, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toDomain(row))
+	}
+	return out
+}
+
+func parseUUID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return uuid.Nil, app.ErrInvalidInput
+	}
+	return id, nil
+}
+
+// parseNullUUID maps an empty ID to NULL.
+func parseNullUUID(s string) (uuid.NullUUID, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return uuid.NullUUID{}, nil
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.NullUUID{}, app.ErrInvalidInput
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}, nil
+}
@@ -0,0 +1,43 @@
+-- name: CreatePromotion :one
+INSERT INTO promotions (
+    code, name, kind, percent_off, currency, amount_off, min_spend, product_id,
+    buy_quantity, get_quantity, starts_at, ends_at, max_uses, max_uses_per_user, stackable
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+RETURNING *;
+
+-- name: ListPromotions :many
+SELECT * FROM promotions
+ORDER BY created_at DESC, id DESC;
+
+-- name: DeactivatePromotion :one
+UPDATE promotions
+SET active = false
+WHERE id = $1
+RETURNING *;
+
+-- name: ListAutomaticPromotions :many
+SELECT * FROM promotions
+WHERE code IS NULL AND active
+ORDER BY created_at, id;
+
+-- name: GetPromotionsByCodes :many
+SELECT * FROM promotions
+WHERE code = ANY(sqlc.arg(codes)::text[]);
+
+-- name: LockPromotionByID :one
+SELECT * FROM promotions
+WHERE id = $1
+FOR UPDATE;
+
+-- name: PromotionUsage :many
+SELECT promotion_id,
+       count(*)::bigint AS total,
+       (count(*) FILTER (WHERE user_id = sqlc.arg(user_id)))::bigint AS by_user
+FROM promotion_redemptions
+WHERE promotion_id = ANY(sqlc.arg(promotion_ids)::uuid[])
+GROUP BY promotion_id;
+
+-- name: AddPromotionRedemption :exec
+INSERT INTO promotion_redemptions (promotion_id, user_id, order_id)
+VALUES ($1, $2, $3);
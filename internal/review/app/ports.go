@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+
+	"github.com/dwikikusuma/shoping-llm/internal/review/domain"
+)
+
+type ReviewRepo interface {
+	// Create fails with ErrAlreadyReviewed when the user already reviewed
+	// the product.
+	Create(ctx context.Context, r domain.Review) (domain.Review, error)
+	Get(ctx context.Context, id string) (domain.Review, error)
+	// UpdateContent stores the rating, title and body of r and sends the
+	// review back to PENDING.
+	UpdateContent(ctx context.Context, r domain.Review) (domain.Review, error)
+	SetStatus(ctx context.Context, id, status, note string) (domain.Review, error)
+	// List returns one page in filter.Sort order. The cursor it hands back
+	// is only valid for the same filter.
+	List(ctx context.Context, filter domain.ReviewFilter, limit int, cursor string) ([]domain.Review, string, error)
+	// Vote adds (helpful) or withdraws the user's helpful vote. Voting twice
+	// the same way changes nothing.
+	Vote(ctx context.Context, reviewID, userID string, helpful bool) (domain.Review, error)
+	// Summary aggregates the product's approved reviews.
+	Summary(ctx context.Context, productID string) (domain.Summary, error)
+	// LockProduct keeps other rating refreshes of the product waiting until
+	// the transaction in ctx ends.
+	LockProduct(ctx context.Context, productID string) error
+}
+
+// PurchaseChecker tells whether a user received the product, i.e. has a
+// fulfilled order containing it.
+type PurchaseChecker interface {
+	HasReceived(ctx context.Context, userID, productID string) (bool, error)
+}
+
+// RatingWriter stores a product's review summary where shoppers read the
+// product.
+type RatingWriter interface {
+	SetRating(ctx context.Context, productID string, summary domain.Summary) error
+}
+
+// TxRunner runs fn inside a single database transaction carried by ctx.
+type TxRunner interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dwikikusuma/shoping-llm/internal/review/domain"
+)
+
+var (
+	ErrInvalidInput    = errors.New("invalid input")
+	ErrNotFound        = errors.New("not found")
+	ErrNotPurchased    = errors.New("only customers who received the product can review it")
+	ErrAlreadyReviewed = errors.New("product already reviewed by this user")
+	ErrNotAuthor       = errors.New("review belongs to another user")
+	ErrOwnReview       = errors.New("cannot vote on own review")
+)
+
+type Service struct {
+	repo      ReviewRepo
+	purchases PurchaseChecker
+	ratings   RatingWriter
+	tx        TxRunner
+}
+
+func NewService(repo ReviewRepo, purchases PurchaseChecker, ratings RatingWriter, tx TxRunner) *Service {
+	return &Service{repo: repo, purchases: purchases, ratings: ratings, tx: tx}
+}
+
+// CreateReview stores a PENDING review. The user must have a fulfilled order
+// containing the product, and can review each product once.
+func (s *Service) CreateReview(ctx context.Context, r domain.Review) (domain.Review, error) {
+	r.UserID = strings.TrimSpace(r.UserID)
+	r.ProductID = strings.TrimSpace(r.ProductID)
+	if r.UserID == "" || r.ProductID == "" {
+		return domain.Review{}, ErrInvalidInput
+	}
+	if err := r.Normalize(); err != nil {
+		return domain.Review{}, err
+	}
+
+	ok, err := s.purchases.HasReceived(ctx, r.UserID, r.ProductID)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	if !ok {
+		return domain.Review{}, ErrNotPurchased
+	}
+
+	r.Status = domain.StatusPending
+	return s.repo.Create(ctx, r)
+}
+
+// UpdateReview lets the author change the rating and text. The review goes
+// back to moderation, so an approved one stops counting until re-approved.
+func (s *Service) UpdateReview(ctx context.Context, r domain.Review) (domain.Review, error) {
+	if strings.TrimSpace(r.ID) == "" || strings.TrimSpace(r.UserID) == "" {
+		return domain.Review{}, ErrInvalidInput
+	}
+	if err := r.Normalize(); err != nil {
+		return domain.Review{}, err
+	}
+
+	current, err := s.repo.Get(ctx, r.ID)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	if current.UserID != strings.TrimSpace(r.UserID) {
+		return domain.Review{}, ErrNotAuthor
+	}
+
+	updated, err := s.repo.UpdateContent(ctx, r)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	if current.Status == domain.StatusApproved {
+		if err := s.refreshRating(ctx, updated.ProductID); err != nil {
+			return domain.Review{}, err
+		}
+	}
+	return updated, nil
+}
+
+// ListReviews pages through reviews. The status defaults to APPROVED; other
+// statuses are for moderators, which the caller must check.
+func (s *Service) ListReviews(ctx context.Context, filter domain.ReviewFilter, limit int, cursor string) ([]domain.Review, string, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	filter.ProductID = strings.TrimSpace(filter.ProductID)
+	filter.Status = strings.ToUpper(strings.TrimSpace(filter.Status))
+	if filter.Status == "" {
+		filter.Status = domain.StatusApproved
+	}
+	if !domain.IsValidStatus(filter.Status) {
+		return nil, "", ErrInvalidInput
+	}
+	if filter.Sort == "" {
+		filter.Sort = domain.SortNewest
+	}
+	if !filter.Sort.Valid() {
+		return nil, "", ErrInvalidInput
+	}
+	return s.repo.List(ctx, filter, limit, cursor)
+}
+
+// ModerateReview approves or rejects a review and recomputes the product's
+// rating. Moderating again with the same status is allowed, which also
+// retries a rating update that failed.
+func (s *Service) ModerateReview(ctx context.Context, id, status, note string) (domain.Review, error) {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	if strings.TrimSpace(id) == "" || (status != domain.StatusApproved && status != domain.StatusRejected) {
+		return domain.Review{}, ErrInvalidInput
+	}
+
+	r, err := s.repo.SetStatus(ctx, id, status, strings.TrimSpace(note))
+	if err != nil {
+		return domain.Review{}, err
+	}
+	if err := s.refreshRating(ctx, r.ProductID); err != nil {
+		return domain.Review{}, err
+	}
+	return r, nil
+}
+
+// VoteHelpful records (helpful) or withdraws the user's vote that an approved
+// review was helpful. Authors can't vote on their own reviews.
+func (s *Service) VoteHelpful(ctx context.Context, reviewID, userID string, helpful bool) (domain.Review, error) {
+	userID = strings.TrimSpace(userID)
+	if strings.TrimSpace(reviewID) == "" || userID == "" {
+		return domain.Review{}, ErrInvalidInput
+	}
+
+	r, err := s.repo.Get(ctx, reviewID)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	if r.Status != domain.StatusApproved {
+		// Reviews awaiting moderation aren't visible to shoppers.
+		return domain.Review{}, ErrNotFound
+	}
+	if r.UserID == userID {
+		return domain.Review{}, ErrOwnReview
+	}
+	return s.repo.Vote(ctx, r.ID, userID, helpful)
+}
+
+// refreshRating recomputes the product's summary from scratch. Refreshes of
+// the same product take turns, so a summary read before a concurrent
+// moderation can't be written after the one that includes it.
+func (s *Service) refreshRating(ctx context.Context, productID string) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.LockProduct(ctx, productID); err != nil {
+			return fmt.Errorf("lock rating of %s: %w", productID, err)
+		}
+		summary, err := s.repo.Summary(ctx, productID)
+		if err != nil {
+			return fmt.Errorf("summarize reviews of %s: %w", productID, err)
+		}
+		if err := s.ratings.SetRating(ctx, productID, summary); err != nil {
+			return fmt.Errorf("update rating of %s: %w", productID, err)
+		}
+		return nil
+	})
+}
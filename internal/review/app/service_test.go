@@ -0,0 +1,289 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dwikikusuma/shoping-llm/internal/review/domain"
+)
+
+// fakeRepo keeps reviews and votes in memory.
+type fakeRepo struct {
+	reviews map[string]domain.Review
+	votes   map[string]bool // review ID + "/" + user ID
+	nextID  int
+	locked  []string // products whose rating was locked, in order
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{reviews: map[string]domain.Review{}, votes: map[string]bool{}}
+}
+
+func (f *fakeRepo) Create(ctx context.Context, r domain.Review) (domain.Review, error) {
+	for _, existing := range f.reviews {
+		if existing.ProductID == r.ProductID && existing.UserID == r.UserID {
+			return domain.Review{}, ErrAlreadyReviewed
+		}
+	}
+	f.nextID++
+	r.ID = fmt.Sprintf("r%d", f.nextID)
+	f.reviews[r.ID] = r
+	return r, nil
+}
+
+func (f *fakeRepo) Get(ctx context.Context, id string) (domain.Review, error) {
+	r, ok := f.reviews[id]
+	if !ok {
+		return domain.Review{}, ErrNotFound
+	}
+	return r, nil
+}
+
+func (f *fakeRepo) UpdateContent(ctx context.Context, r domain.Review) (domain.Review, error) {
+	current := f.reviews[r.ID]
+	current.Rating, current.Title, current.Body = r.Rating, r.Title, r.Body
+	current.Status, current.ModerationNote = domain.StatusPending, ""
+	f.reviews[r.ID] = current
+	return current, nil
+}
+
+func (f *fakeRepo) SetStatus(ctx context.Context, id, status, note string) (domain.Review, error) {
+	r, ok := f.reviews[id]
+	if !ok {
+		return domain.Review{}, ErrNotFound
+	}
+	r.Status, r.ModerationNote = status, note
+	f.reviews[id] = r
+	return r, nil
+}
+
+func (f *fakeRepo) List(ctx context.Context, filter domain.ReviewFilter, limit int, cursor string) ([]domain.Review, string, error) {
+	var out []domain.Review
+	for _, r := range f.reviews {
+		if r.Status == filter.Status && (filter.ProductID == "" || r.ProductID == filter.ProductID) {
+			out = append(out, r)
+		}
+	}
+	return out, "", nil
+}
+
+func (f *fakeRepo) Vote(ctx context.Context, reviewID, userID string, helpful bool) (domain.Review, error) {
+	key := reviewID + "/" + userID
+	r := f.reviews[reviewID]
+	if helpful && !f.votes[key] {
+		r.HelpfulCount++
+	}
+	if !helpful && f.votes[key] {
+		r.HelpfulCount--
+	}
+	f.votes[key] = helpful
+	f.reviews[reviewID] = r
+	return r, nil
+}
+
+func (f *fakeRepo) Summary(ctx context.Context, productID string) (domain.Summary, error) {
+	var sum, n int32
+	for _, r := range f.reviews {
+		if r.ProductID == productID && r.Status == domain.StatusApproved {
+			sum += r.Rating
+			n++
+		}
+	}
+	if n == 0 {
+		return domain.Summary{}, nil
+	}
+	return domain.Summary{Average: float64(sum) / float64(n), Count: n}, nil
+}
+
+func (f *fakeRepo) LockProduct(ctx context.Context, productID string) error {
+	if ctx.Value(inTx{}) == nil {
+		return errors.New("locked outside a transaction")
+	}
+	f.locked = append(f.locked, productID)
+	return nil
+}
+
+// inTx marks the contexts fakeTx hands to fn.
+type inTx struct{}
+
+type fakeTx struct{}
+
+func (fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, inTx{}, true))
+}
+
+// fakePurchases lets the users in received review "p1".
+type fakePurchases struct {
+	received map[string]bool
+}
+
+func (f fakePurchases) HasReceived(ctx context.Context, userID, productID string) (bool, error) {
+	return productID == "p1" && f.received[userID], nil
+}
+
+type fakeRatings struct {
+	ratings map[string]domain.Summary
+	err     error
+}
+
+func (f *fakeRatings) SetRating(ctx context.Context, productID string, summary domain.Summary) error {
+	if f.err != nil {
+		return f.err
+	}
+	if ctx.Value(inTx{}) == nil {
+		return errors.New("rating written outside the lock's transaction")
+	}
+	f.ratings[productID] = summary
+	return nil
+}
+
+func newTestService() (*Service, *fakeRepo, *fakeRatings) {
+	repo := newFakeRepo()
+	ratings := &fakeRatings{ratings: map[string]domain.Summary{}}
+	purchases := fakePurchases{received: map[string]bool{"alice": true, "bob": true}}
+	return NewService(repo, purchases, ratings, fakeTx{}), repo, ratings
+}
+
+func TestCreateReview(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	r, err := svc.CreateReview(ctx, domain.Review{UserID: "alice", ProductID: "p1", Rating: 5, Title: "  Great  "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != domain.StatusPending || r.Title != "Great" {
+		t.Fatalf("expected a trimmed PENDING review, got %+v", r)
+	}
+
+	cases := []struct {
+		name string
+		in   domain.Review
+		want error
+	}{
+		{"second review of the same product", domain.Review{UserID: "alice", ProductID: "p1", Rating: 4}, ErrAlreadyReviewed},
+		{"never received the product", domain.Review{UserID: "carol", ProductID: "p1", Rating: 4}, ErrNotPurchased},
+		{"received a different product", domain.Review{UserID: "bob", ProductID: "p2", Rating: 4}, ErrNotPurchased},
+		{"rating out of range", domain.Review{UserID: "bob", ProductID: "p1", Rating: 6}, domain.ErrInvalidReview},
+		{"no rating", domain.Review{UserID: "bob", ProductID: "p1"}, domain.ErrInvalidReview},
+		{"no user", domain.Review{ProductID: "p1", Rating: 3}, ErrInvalidInput},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := svc.CreateReview(ctx, tc.in); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestModerationUpdatesRating(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, ratings := newTestService()
+
+	a, _ := svc.CreateReview(ctx, domain.Review{UserID: "alice", ProductID: "p1", Rating: 5})
+	b, _ := svc.CreateReview(ctx, domain.Review{UserID: "bob", ProductID: "p1", Rating: 2})
+
+	if _, err := svc.ModerateReview(ctx, a.ID, "approved", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ModerateReview(ctx, b.ID, domain.StatusApproved, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := ratings.ratings["p1"]; got != (domain.Summary{Average: 3.5, Count: 2}) {
+		t.Fatalf("expected 3.5 from 2 reviews, got %+v", got)
+	}
+	if len(repo.locked) != 2 || repo.locked[0] != "p1" || repo.locked[1] != "p1" {
+		t.Fatalf("expected each refresh to lock p1, got %v", repo.locked)
+	}
+
+	if _, err := svc.ModerateReview(ctx, b.ID, domain.StatusRejected, "off topic"); err != nil {
+		t.Fatal(err)
+	}
+	if got := ratings.ratings["p1"]; got != (domain.Summary{Average: 5, Count: 1}) {
+		t.Fatalf("expected the rejected review to drop out, got %+v", got)
+	}
+
+	// Editing an approved review takes it out of the rating until it is
+	// approved again.
+	updated, err := svc.UpdateReview(ctx, domain.Review{ID: a.ID, UserID: "alice", Rating: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status != domain.StatusPending {
+		t.Fatalf("expected the edit to go back to moderation, got %s", updated.Status)
+	}
+	if got := ratings.ratings["p1"]; got != (domain.Summary{}) {
+		t.Fatalf("expected no approved reviews left, got %+v", got)
+	}
+
+	if _, err := svc.UpdateReview(ctx, domain.Review{ID: a.ID, UserID: "bob", Rating: 1}); !errors.Is(err, ErrNotAuthor) {
+		t.Fatalf("expected ErrNotAuthor, got %v", err)
+	}
+	if _, err := svc.ModerateReview(ctx, a.ID, domain.StatusPending, ""); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected moderating back to PENDING to fail, got %v", err)
+	}
+
+	t.Run("a failed rating update surfaces and can be retried", func(t *testing.T) {
+		ratings.err = errors.New("catalog down")
+		if _, err := svc.ModerateReview(ctx, a.ID, domain.StatusApproved, ""); err == nil {
+			t.Fatal("expected the rating error")
+		}
+		ratings.err = nil
+		if _, err := svc.ModerateReview(ctx, a.ID, domain.StatusApproved, ""); err != nil {
+			t.Fatal(err)
+		}
+		if got := ratings.ratings["p1"]; got != (domain.Summary{Average: 4, Count: 1}) {
+			t.Fatalf("expected the retry to store the rating, got %+v", got)
+		}
+	})
+}
+
+func TestVoteHelpful(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	r, _ := svc.CreateReview(ctx, domain.Review{UserID: "alice", ProductID: "p1", Rating: 5})
+	if _, err := svc.VoteHelpful(ctx, r.ID, "bob", true); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected pending reviews to be hidden from votes, got %v", err)
+	}
+	svc.ModerateReview(ctx, r.ID, domain.StatusApproved, "")
+
+	for i := 0; i < 2; i++ {
+		got, err := svc.VoteHelpful(ctx, r.ID, "bob", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.HelpfulCount != 1 {
+			t.Fatalf("expected repeated votes to count once, got %d", got.HelpfulCount)
+		}
+	}
+	if got, _ := svc.VoteHelpful(ctx, r.ID, "bob", false); got.HelpfulCount != 0 {
+		t.Fatalf("expected the vote to be withdrawn, got %d", got.HelpfulCount)
+	}
+	if _, err := svc.VoteHelpful(ctx, r.ID, "alice", true); !errors.Is(err, ErrOwnReview) {
+		t.Fatalf("expected ErrOwnReview, got %v", err)
+	}
+}
+
+func TestListReviewsDefaults(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestService()
+
+	a, _ := svc.CreateReview(ctx, domain.Review{UserID: "alice", ProductID: "p1", Rating: 5})
+	svc.CreateReview(ctx, domain.Review{UserID: "bob", ProductID: "p1", Rating: 1})
+	svc.ModerateReview(ctx, a.ID, domain.StatusApproved, "")
+
+	got, _, err := svc.ListReviews(ctx, domain.ReviewFilter{ProductID: "p1"}, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != a.ID {
+		t.Fatalf("expected only the approved review, got %+v", got)
+	}
+	if _, _, err := svc.ListReviews(ctx, domain.ReviewFilter{Sort: "stars"}, 0, ""); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected an unknown sort to fail, got %v", err)
+	}
+}
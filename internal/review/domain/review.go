@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// A review starts PENDING and only counts towards the product's rating, or
+// shows up in public listings, once a moderator approves it. Editing a
+// review sends it back to PENDING.
+const (
+	StatusPending  = "PENDING"
+	StatusApproved = "APPROVED"
+	StatusRejected = "REJECTED"
+)
+
+const (
+	MinRating   = 1
+	MaxRating   = 5
+	MaxTitleLen = 120
+	MaxBodyLen  = 5000
+)
+
+var ErrInvalidReview = errors.New("invalid review")
+
+type Review struct {
+	ID        string
+	ProductID string
+	UserID    string
+	Rating    int32
+	Title     string
+	Body      string
+	Status    string
+	// ModerationNote is the moderator's reason, e.g. for a rejection.
+	ModerationNote string
+	HelpfulCount   int32
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Normalize trims the text fields and checks the rating and their lengths.
+func (r *Review) Normalize() error {
+	r.Title = strings.TrimSpace(r.Title)
+	r.Body = strings.TrimSpace(r.Body)
+	if r.Rating < MinRating || r.Rating > MaxRating {
+		return fmt.Errorf("%w: rating must be between %d and %d", ErrInvalidReview, MinRating, MaxRating)
+	}
+	if utf8.RuneCountInString(r.Title) > MaxTitleLen {
+		return fmt.Errorf("%w: title is longer than %d characters", ErrInvalidReview, MaxTitleLen)
+	}
+	if utf8.RuneCountInString(r.Body) > MaxBodyLen {
+		return fmt.Errorf("%w: body is longer than %d characters", ErrInvalidReview, MaxBodyLen)
+	}
+	return nil
+}
+
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusApproved, StatusRejected:
+		return true
+	}
+	return false
+}
+
+// ReviewSort orders ListReviews. Both break ties by review ID.
+type ReviewSort string
+
+const (
+	SortNewest  ReviewSort = "newest"
+	SortHelpful ReviewSort = "helpful"
+)
+
+func (s ReviewSort) Valid() bool {
+	return s == SortNewest || s == SortHelpful
+}
+
+// ReviewFilter narrows ListReviews. An empty ProductID lists reviews of all
+// products, which moderators use to work through the PENDING queue.
+type ReviewFilter struct {
+	ProductID string
+	Status    string
+	Sort      ReviewSort
+}
+
+// Summary aggregates the approved reviews of a product.
+type Summary struct {
+	Average float64 // 0 without reviews
+	Count   int32
+}
@@ -0,0 +1,133 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	reviewv1 "github.com/dwikikusuma/shoping-llm/api/gen/review/v1"
+	"github.com/dwikikusuma/shoping-llm/internal/review/app"
+	"github.com/dwikikusuma/shoping-llm/internal/review/domain"
+	"github.com/dwikikusuma/shoping-llm/pkg/grpcauth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	reviewv1.UnimplementedReviewServiceServer
+	svc        *app.Service
+	adminToken string
+}
+
+// NewServer returns the review gRPC server. Moderation requires the
+// "admin-token" metadata to equal adminToken; an empty adminToken disables
+// it.
+func NewServer(svc *app.Service, adminToken string) *Server {
+	return &Server{svc: svc, adminToken: adminToken}
+}
+
+func (s *Server) CreateReview(ctx context.Context, req *reviewv1.CreateReviewRequest) (*reviewv1.CreateReviewResponse, error) {
+	r, err := s.svc.CreateReview(ctx, domain.Review{
+		UserID:    req.GetUserId(),
+		ProductID: req.GetProductId(),
+		Rating:    req.GetRating(),
+		Title:     req.GetTitle(),
+		Body:      req.GetBody(),
+	})
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &reviewv1.CreateReviewResponse{Review: toProto(r)}, nil
+}
+
+func (s *Server) UpdateReview(ctx context.Context, req *reviewv1.UpdateReviewRequest) (*reviewv1.UpdateReviewResponse, error) {
+	r, err := s.svc.UpdateReview(ctx, domain.Review{
+		ID:     req.GetId(),
+		UserID: req.GetUserId(),
+		Rating: req.GetRating(),
+		Title:  req.GetTitle(),
+		Body:   req.GetBody(),
+	})
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &reviewv1.UpdateReviewResponse{Review: toProto(r)}, nil
+}
+
+func (s *Server) ListReviews(ctx context.Context, req *reviewv1.ListReviewsRequest) (*reviewv1.ListReviewsResponse, error) {
+	st := strings.ToUpper(strings.TrimSpace(req.GetStatus()))
+	if st != "" && st != domain.StatusApproved {
+		if err := grpcauth.RequireAdmin(ctx, s.adminToken); err != nil {
+			return nil, err
+		}
+	}
+
+	reviews, next, err := s.svc.ListReviews(ctx, domain.ReviewFilter{
+		ProductID: req.GetProductId(),
+		Status:    st,
+		Sort:      domain.ReviewSort(strings.ToLower(strings.TrimSpace(req.GetSort()))),
+	}, int(req.GetLimit()), req.GetCursor())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	out := make([]*reviewv1.Review, 0, len(reviews))
+	for _, r := range reviews {
+		out = append(out, toProto(r))
+	}
+	return &reviewv1.ListReviewsResponse{Reviews: out, NextCursor: next}, nil
+}
+
+func (s *Server) ModerateReview(ctx context.Context, req *reviewv1.ModerateReviewRequest) (*reviewv1.ModerateReviewResponse, error) {
+	if err := grpcauth.RequireAdmin(ctx, s.adminToken); err != nil {
+		return nil, err
+	}
+	r, err := s.svc.ModerateReview(ctx, req.GetId(), req.GetStatus(), req.GetNote())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &reviewv1.ModerateReviewResponse{Review: toProto(r)}, nil
+}
+
+func (s *Server) VoteHelpful(ctx context.Context, req *reviewv1.VoteHelpfulRequest) (*reviewv1.VoteHelpfulResponse, error) {
+	r, err := s.svc.VoteHelpful(ctx, req.GetReviewId(), req.GetUserId(), req.GetHelpful())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &reviewv1.VoteHelpfulResponse{Review: toProto(r)}, nil
+}
+
+func toProto(r domain.Review) *reviewv1.Review {
+	return &reviewv1.Review{
+		Id:             r.ID,
+		ProductId:      r.ProductID,
+		UserId:         r.UserID,
+		Rating:         r.Rating,
+		Title:          r.Title,
+		Body:           r.Body,
+		Status:         r.Status,
+		ModerationNote: r.ModerationNote,
+		HelpfulCount:   r.HelpfulCount,
+		CreatedAtUnix:  r.CreatedAt.Unix(),
+		UpdatedAtUnix:  r.UpdatedAt.Unix(),
+	}
+}
+
+func mapErr(err error) error {
+	if errors.Is(err, app.ErrInvalidInput) || errors.Is(err, domain.ErrInvalidReview) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, app.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, app.ErrNotPurchased) || errors.Is(err, app.ErrOwnReview) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, app.ErrAlreadyReviewed) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	if errors.Is(err, app.ErrNotAuthor) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Internal, "internal error")
+}
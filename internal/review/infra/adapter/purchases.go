@@ -0,0 +1,25 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+
+	orderapp "github.com/dwikikusuma/shoping-llm/internal/order/app"
+	reviewapp "github.com/dwikikusuma/shoping-llm/internal/review/app"
+)
+
+type OrderPurchaseChecker struct {
+	svc *orderapp.Service
+}
+
+func NewOrderPurchaseChecker(svc *orderapp.Service) *OrderPurchaseChecker {
+	return &OrderPurchaseChecker{svc: svc}
+}
+
+func (c *OrderPurchaseChecker) HasReceived(ctx context.Context, userID, productID string) (bool, error) {
+	ok, err := c.svc.HasReceived(ctx, userID, productID)
+	if errors.Is(err, orderapp.ErrInvalidInput) {
+		return false, reviewapp.ErrInvalidInput
+	}
+	return ok, err
+}
@@ -0,0 +1,30 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+
+	catalogapp "github.com/dwikikusuma/shoping-llm/internal/catalog/app"
+	catalogdomain "github.com/dwikikusuma/shoping-llm/internal/catalog/domain"
+	"github.com/dwikikusuma/shoping-llm/internal/review/domain"
+)
+
+type CatalogRatingWriter struct {
+	svc *catalogapp.Service
+}
+
+func NewCatalogRatingWriter(svc *catalogapp.Service) *CatalogRatingWriter {
+	return &CatalogRatingWriter{svc: svc}
+}
+
+func (w *CatalogRatingWriter) SetRating(ctx context.Context, productID string, summary domain.Summary) error {
+	err := w.svc.SetProductRating(ctx, productID, catalogdomain.Rating{
+		Average: summary.Average,
+		Count:   summary.Count,
+	})
+	if errors.Is(err, catalogapp.ErrNotFound) {
+		// The product was deleted; there is nothing left to show the rating on.
+		return nil
+	}
+	return err
+}
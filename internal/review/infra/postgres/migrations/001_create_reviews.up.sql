@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS reviews (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id      UUID NOT NULL,
+    user_id         TEXT NOT NULL,
+    rating          INT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    title           TEXT NOT NULL DEFAULT '',
+    body            TEXT NOT NULL DEFAULT '',
+    status          TEXT NOT NULL DEFAULT 'PENDING',
+    moderation_note TEXT NOT NULL DEFAULT '',
+    helpful_count   INT NOT NULL DEFAULT 0 CHECK (helpful_count >= 0),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    -- one review per user and product; editing replaces it
+    UNIQUE (product_id, user_id),
+    CHECK (status IN ('PENDING','APPROVED','REJECTED'))
+);
+
+-- Public listings of a product and the moderation queue across products
+CREATE INDEX IF NOT EXISTS ix_reviews_product_status_created
+    ON reviews(product_id, status, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS ix_reviews_status_created
+    ON reviews(status, created_at DESC, id DESC);
+
+-- helpful_count on reviews is kept in step with this table
+CREATE TABLE IF NOT EXISTS review_votes (
+    review_id   UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    user_id     TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (review_id, user_id)
+);
@@ -0,0 +1,66 @@
+-- name: CreateReview :one
+INSERT INTO reviews (product_id, user_id, rating, title, body, status)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetReview :one
+SELECT * FROM reviews WHERE id = $1;
+
+-- name: UpdateReviewContent :one
+UPDATE reviews
+SET rating          = sqlc.arg(rating),
+    title           = sqlc.arg(title),
+    body            = sqlc.arg(body),
+    status          = 'PENDING',
+    moderation_note = '',
+    updated_at      = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SetReviewStatus :one
+UPDATE reviews
+SET status          = sqlc.arg(status),
+    moderation_note = sqlc.arg(moderation_note),
+    updated_at      = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListReviews :many
+-- Keyset pagination like ListProducts: the cursor holds the sort key and id
+-- of the last row of the previous page.
+SELECT * FROM reviews
+WHERE status = sqlc.arg(status)
+  AND (sqlc.narg(product_id)::uuid IS NULL OR product_id = sqlc.narg(product_id)::uuid)
+  AND (NOT sqlc.arg(use_cursor)::boolean OR CASE sqlc.arg(sort_by)::text
+      WHEN 'helpful' THEN (helpful_count, id) < (sqlc.arg(cursor_helpful)::int, sqlc.arg(cursor_id)::uuid)
+      ELSE (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+  END)
+ORDER BY CASE WHEN sqlc.arg(sort_by)::text = 'helpful' THEN helpful_count END DESC,
+         CASE WHEN sqlc.arg(sort_by)::text = 'newest' THEN created_at END DESC,
+         id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: AddReviewVote :execrows
+INSERT INTO review_votes (review_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteReviewVote :execrows
+DELETE FROM review_votes
+WHERE review_id = $1 AND user_id = $2;
+
+-- name: AdjustHelpfulCount :one
+UPDATE reviews
+SET helpful_count = helpful_count + sqlc.arg(delta)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetRatingSummary :one
+SELECT COALESCE(AVG(rating), 0)::float8 AS rating_avg,
+       count(*)::int AS rating_count
+FROM reviews
+WHERE product_id = $1 AND status = 'APPROVED';
+
+-- name: LockProductRating :exec
+-- Serializes the rating refreshes of one product until the transaction ends.
+SELECT pg_advisory_xact_lock(hashtextextended('review_rating:' || sqlc.arg(product_id)::uuid::text, 0));
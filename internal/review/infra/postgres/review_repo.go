@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dwikikusuma/shoping-llm/internal/review/app"
+	"github.com/dwikikusuma/shoping-llm/internal/review/domain"
+	"github.com/dwikikusuma/shoping-llm/internal/review/infra/postgres/reviewdb"
+	"github.com/dwikikusuma/shoping-llm/pkg/pagination"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type ReviewRepo struct {
+	*reviewdb.Queries
+	db      *sql.DB
+	cursors *pagination.Codec
+}
+
+func NewReviewRepo(db *sql.DB, cursors *pagination.Codec) *ReviewRepo {
+	return &ReviewRepo{
+		Queries: reviewdb.New(db),
+		db:      db,
+		cursors: cursors,
+	}
+}
+
+// execTX runs fn in its own transaction, or in the caller's transaction when
+// ctx carries one (see pg.TxManager).
+func (r *ReviewRepo) execTX(ctx context.Context, fn func(queries *reviewdb.Queries) error) error {
+	return pg.ExecTx(ctx, r.db, func(tx *sql.Tx) error {
+		return fn(r.Queries.WithTx(tx))
+	})
+}
+
+func (r *ReviewRepo) queries(ctx context.Context) *reviewdb.Queries {
+	if tx, ok := pg.TxFromContext(ctx); ok {
+		return r.Queries.WithTx(tx)
+	}
+	return r.Queries
+}
+
+func (r *ReviewRepo) Create(ctx context.Context, review domain.Review) (domain.Review, error) {
+	pid, err := parseUUID(review.ProductID)
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	row, err := r.queries(ctx).CreateReview(ctx, reviewdb.CreateReviewParams{
+		ProductID: pid,
+		UserID:    review.UserID,
+		Rating:    review.Rating,
+		Title:     review.Title,
+		Body:      review.Body,
+		Status:    review.Status,
+	})
+	if pg.IsUniqueViolation(err) {
+		return domain.Review{}, app.ErrAlreadyReviewed
+	}
+	if err != nil {
+		return domain.Review{}, err
+	}
+	return toDomainReview(row), nil
+}
+
+func (r *ReviewRepo) Get(ctx context.Context, id string) (domain.Review, error) {
+	rid, err := parseUUID(id)
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	row, err := r.queries(ctx).GetReview(ctx, rid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Review{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.Review{}, err
+	}
+	return toDomainReview(row), nil
+}
+
+func (r *ReviewRepo) UpdateContent(ctx context.Context, review domain.Review) (domain.Review, error) {
+	rid, err := parseUUID(review.ID)
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	row, err := r.queries(ctx).UpdateReviewContent(ctx, reviewdb.UpdateReviewContentParams{
+		Rating: review.Rating,
+		Title:  review.Title,
+		Body:   review.Body,
+		ID:     rid,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Review{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.Review{}, err
+	}
+	return toDomainReview(row), nil
+}
+
+func (r *ReviewRepo) SetStatus(ctx context.Context, id, status, note string) (domain.Review, error) {
+	rid, err := parseUUID(id)
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	row, err := r.queries(ctx).SetReviewStatus(ctx, reviewdb.SetReviewStatusParams{
+		Status:         status,
+		ModerationNote: note,
+		ID:             rid,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Review{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.Review{}, err
+	}
+	return toDomainReview(row), nil
+}
+
+func (r *ReviewRepo) List(ctx context.Context, filter domain.ReviewFilter, limit int, cursor string) ([]domain.Review, string, error) {
+	arg := reviewdb.ListReviewsParams{
+		Status:    filter.Status,
+		SortBy:    string(filter.Sort),
+		PageLimit: int32(limit),
+	}
+	if filter.ProductID != "" {
+		pid, err := parseUUID(filter.ProductID)
+		if err != nil {
+			return nil, "", err
+		}
+		arg.ProductID = uuid.NullUUID{UUID: pid, Valid: true}
+	}
+
+	filterHash := pagination.FilterHash(filter.ProductID, filter.Status, string(filter.Sort))
+	if strings.TrimSpace(cursor) != "" {
+		c, err := r.cursors.Decode(cursor, filterHash)
+		if err != nil {
+			return nil, "", app.ErrInvalidInput
+		}
+		if err := setReviewCursor(&arg, filter.Sort, c); err != nil {
+			return nil, "", app.ErrInvalidInput
+		}
+		arg.UseCursor = true
+	}
+
+	rows, err := r.queries(ctx).ListReviews(ctx, arg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	out := make([]domain.Review, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toDomainReview(row))
+	}
+
+	// next_cursor: return the last review's sort key only when we returned a full page
+	nextCursor := ""
+	if len(rows) == limit && len(rows) > 0 {
+		nextCursor = r.cursors.Encode(reviewCursor(filter.Sort, rows[len(rows)-1], filterHash))
+	}
+	return out, nextCursor, nil
+}
+
+// reviewCursor stores the sort key of row that matches sort.
+func reviewCursor(sort domain.ReviewSort, row reviewdb.Review, filterHash string) pagination.Cursor {
+	c := pagination.Cursor{ID: row.ID.String(), Filter: filterHash}
+	if sort == domain.SortHelpful {
+		c.Key = strconv.FormatInt(int64(row.HelpfulCount), 10)
+	} else {
+		c.Key = strconv.FormatInt(row.CreatedAt.UnixMicro(), 10)
+	}
+	return c
+}
+
+// setReviewCursor is the inverse of reviewCursor.
+func setReviewCursor(arg *reviewdb.ListReviewsParams, sort domain.ReviewSort, c pagination.Cursor) error {
+	id, err := uuid.Parse(c.ID)
+	if err != nil {
+		return err
+	}
+	arg.CursorID = id
+
+	if sort == domain.SortHelpful {
+		var helpful int64
+		helpful, err = strconv.ParseInt(c.Key, 10, 32)
+		arg.CursorHelpful = int32(helpful)
+		return err
+	}
+	var micros int64
+	micros, err = strconv.ParseInt(c.Key, 10, 64)
+	arg.CursorCreatedAt = time.UnixMicro(micros)
+	return err
+}
+
+func (r *ReviewRepo) Vote(ctx context.Context, reviewID, userID string, helpful bool) (domain.Review, error) {
+	rid, err := parseUUID(reviewID)
+	if err != nil {
+		return domain.Review{}, err
+	}
+
+	var row reviewdb.Review
+	err = r.execTX(ctx, func(q *reviewdb.Queries) error {
+		var (
+			changed int64
+			delta   int32 = 1
+			err     error
+		)
+		if helpful {
+			changed, err = q.AddReviewVote(ctx, reviewdb.AddReviewVoteParams{ReviewID: rid, UserID: userID})
+		} else {
+			changed, err = q.DeleteReviewVote(ctx, reviewdb.DeleteReviewVoteParams{ReviewID: rid, UserID: userID})
+			delta = -1
+		}
+		if err != nil {
+			return err
+		}
+
+		// A repeated vote changes nothing, so the count stays put.
+		if changed == 0 {
+			row, err = q.GetReview(ctx, rid)
+			return err
+		}
+		row, err = q.AdjustHelpfulCount(ctx, reviewdb.AdjustHelpfulCountParams{Delta: delta, ID: rid})
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) || pg.IsForeignKeyViolation(err) {
+		return domain.Review{}, app.ErrNotFound
+	}
+	if err != nil {
+		return domain.Review{}, err
+	}
+	return toDomainReview(row), nil
+}
+
+func (r *ReviewRepo) Summary(ctx context.Context, productID string) (domain.Summary, error) {
+	pid, err := parseUUID(productID)
+	if err != nil {
+		return domain.Summary{}, err
+	}
+
+	row, err := r.queries(ctx).GetRatingSummary(ctx, pid)
+	if err != nil {
+		return domain.Summary{}, err
+	}
+	return domain.Summary{Average: row.RatingAvg, Count: row.RatingCount}, nil
+}
+
+func (r *ReviewRepo) LockProduct(ctx context.Context, productID string) error {
+	pid, err := parseUUID(productID)
+	if err != nil {
+		return err
+	}
+	return r.queries(ctx).LockProductRating(ctx, pid)
+}
+
+func parseUUID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return uuid.Nil, app.ErrInvalidInput
+	}
+	return id, nil
+}
+
+func toDomainReview(row reviewdb.Review) domain.Review {
+	return domain.Review{
+		ID:             row.ID.String(),
+		ProductID:      row.ProductID.String(),
+		UserID:         row.UserID,
+		Rating:         row.Rating,
+		Title:          row.Title,
+		Body:           row.Body,
+		Status:         row.Status,
+		ModerationNote: row.ModerationNote,
+		HelpfulCount:   row.HelpfulCount,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+}
@@ -0,0 +1,14 @@
+package app
+
+import (
+	"context"
+
+	"github.com/dwikikusuma/shoping-llm/internal/shipping/domain"
+)
+
+// RateProvider prices the ways a parcel can be shipped.
+type RateProvider interface {
+	// Options returns the options for the parcel, cheapest first. Fees may
+	// be in any currency; Service converts them.
+	Options(ctx context.Context, parcel domain.Parcel) ([]domain.Option, error)
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dwikikusuma/shoping-llm/internal/shipping/domain"
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
+)
+
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrUnknownOption means the option isn't offered for the parcel, either
+	// because no such option exists or because it doesn't ship there.
+	ErrUnknownOption = errors.New("unknown shipping option")
+	// ErrUnsupportedCurrency means a fee can't be converted to the parcel
+	// currency.
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+)
+
+type Service struct {
+	rates RateProvider
+	fx    money.RateProvider // nil: fees are only offered in their own currency
+}
+
+func NewService(rates RateProvider, fx money.RateProvider) *Service {
+	return &Service{rates: rates, fx: fx}
+}
+
+// Options returns the ways the parcel can be shipped, cheapest first, with
+// fees in the parcel currency.
+func (s *Service) Options(ctx context.Context, parcel domain.Parcel) ([]domain.Option, error) {
+	parcel, err := normalizeParcel(parcel)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := s.rates.Options(ctx, parcel)
+	if err != nil {
+		return nil, err
+	}
+	for i := range options {
+		fee, err := money.Convert(ctx, s.fx, options[i].Fee, parcel.Currency)
+		if errors.Is(err, money.ErrNoRate) || errors.Is(err, money.ErrUnknownCurrency) {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedCurrency, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		options[i].Fee = fee
+	}
+	// Converted fees can round into a different order.
+	slices.SortStableFunc(options, func(a, b domain.Option) int { return cmp.Compare(a.Fee.Amount, b.Fee.Amount) })
+	return options, nil
+}
+
+// Option returns the option with the given code, or ErrUnknownOption when
+// the parcel can't be shipped that way.
+func (s *Service) Option(ctx context.Context, parcel domain.Parcel, code string) (domain.Option, error) {
+	options, err := s.Options(ctx, parcel)
+	if err != nil {
+		return domain.Option{}, err
+	}
+	code = strings.ToUpper(strings.TrimSpace(code))
+	i := slices.IndexFunc(options, func(o domain.Option) bool { return o.Code == code })
+	if i < 0 {
+		return domain.Option{}, fmt.Errorf("%w: %q", ErrUnknownOption, code)
+	}
+	return options[i], nil
+}
+
+func normalizeParcel(p domain.Parcel) (domain.Parcel, error) {
+	p.Country = strings.ToUpper(strings.TrimSpace(p.Country))
+	if p.Country != "" && len(p.Country) != 2 {
+		return domain.Parcel{}, fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code, got %q", ErrInvalidInput, p.Country)
+	}
+	if p.WeightGrams < 0 {
+		return domain.Parcel{}, fmt.Errorf("%w: weight cannot be negative", ErrInvalidInput)
+	}
+	currency, err := money.NormalizeCurrency(p.Currency)
+	if err != nil {
+		return domain.Parcel{}, fmt.Errorf("%w: %v", ErrUnsupportedCurrency, err)
+	}
+	p.Currency = currency
+	return p, nil
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dwikikusuma/shoping-llm/internal/shipping/domain"
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
+)
+
+// fakeRates offers the same options for every parcel and records the last
+// parcel it priced.
+type fakeRates struct {
+	options []domain.Option
+	got     domain.Parcel
+}
+
+func (f *fakeRates) Options(ctx context.Context, parcel domain.Parcel) ([]domain.Option, error) {
+	f.got = parcel
+	return append([]domain.Option(nil), f.options...), nil
+}
+
+func TestOptions(t *testing.T) {
+	ctx := context.Background()
+	rates := &fakeRates{options: []domain.Option{
+		{Code: "STANDARD", Fee: domain.Money{Currency: "IDR", Amount: 16250}},
+		{Code: "EXPRESS", Fee: domain.Money{Currency: "IDR", Amount: 32500}},
+	}}
+	fx, err := money.NewStaticRates("USD", map[string]string{"IDR": "16250"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(rates, fx)
+
+	t.Run("fees are converted to the parcel currency", func(t *testing.T) {
+		options, err := svc.Options(ctx, domain.Parcel{Country: " id ", WeightGrams: 500, Currency: "usd"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rates.got.Country != "ID" || rates.got.Currency != "USD" {
+			t.Fatalf("expected the parcel to be normalized, got %+v", rates.got)
+		}
+		if options[0].Fee != (domain.Money{Currency: "USD", Amount: 100}) || options[1].Fee.Amount != 200 {
+			t.Fatalf("unexpected fees: %+v", options)
+		}
+	})
+
+	t.Run("option by code", func(t *testing.T) {
+		o, err := svc.Option(ctx, domain.Parcel{Country: "ID", Currency: "IDR"}, "express")
+		if err != nil || o.Fee.Amount != 32500 {
+			t.Fatalf("expected EXPRESS at IDR 32500, got %+v, %v", o, err)
+		}
+		if _, err := svc.Option(ctx, domain.Parcel{Country: "ID", Currency: "IDR"}, "DRONE"); !errors.Is(err, ErrUnknownOption) {
+			t.Fatalf("expected ErrUnknownOption, got %v", err)
+		}
+	})
+
+	t.Run("invalid parcels", func(t *testing.T) {
+		if _, err := svc.Options(ctx, domain.Parcel{Country: "IDN", Currency: "IDR"}); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput for a 3-letter country, got %v", err)
+		}
+		if _, err := svc.Options(ctx, domain.Parcel{WeightGrams: -1, Currency: "IDR"}); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput for a negative weight, got %v", err)
+		}
+		if _, err := NewService(rates, nil).Options(ctx, domain.Parcel{Currency: "EUR"}); !errors.Is(err, ErrUnsupportedCurrency) {
+			t.Fatalf("expected ErrUnsupportedCurrency without exchange rates, got %v", err)
+		}
+	})
+}
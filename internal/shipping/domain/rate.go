@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dwikikusuma/shoping-llm/pkg/money"
+)
+
+var ErrInvalidTable = errors.New("invalid shipping rate table")
+
+// Money is an amount in the minor unit of its currency.
+type Money = money.Money
+
+// Parcel is what a shopper wants shipped.
+type Parcel struct {
+	// Country is the ISO 3166-1 alpha-2 code of the destination; empty when
+	// the shopper hasn't said yet.
+	Country     string
+	WeightGrams int64
+	// Currency is the currency the fees are wanted in.
+	Currency string
+}
+
+// Option is one way to ship a parcel, with its fee and delivery estimate.
+type Option struct {
+	Code    string // e.g. STANDARD or EXPRESS
+	Name    string
+	Fee     Money
+	MinDays int32 // business days until delivery
+	MaxDays int32
+}
+
+// Table prices parcels from rules. Each method is offered when one of its
+// rules matches the parcel, and the first rule that matches sets the fee.
+type Table struct {
+	// Currency is the currency of every fee in the table.
+	Currency string
+	// Zones group destination countries, e.g. {"DOMESTIC": ["ID"]}. The
+	// country "*" puts every country that is in no other zone in the zone.
+	Zones map[string][]string
+	// DefaultZone is the zone of parcels without a destination.
+	DefaultZone string
+	Methods     []Method
+}
+
+type Method struct {
+	Code    string
+	Name    string
+	MinDays int32
+	MaxDays int32
+	Rules   []Rule
+}
+
+// Rule charges Fee plus PerKg for every started kilogram. A rule with
+// neither Zone nor MaxWeightGrams is a flat rate.
+type Rule struct {
+	Zone           string // empty: every zone
+	MaxWeightGrams int64  // 0: any weight
+	Fee            int64
+	PerKg          int64
+}
+
+func (r Rule) matches(zone string, weight int64) bool {
+	return (r.Zone == "" || r.Zone == zone) && (r.MaxWeightGrams == 0 || weight <= r.MaxWeightGrams)
+}
+
+func (r Rule) fee(weight int64) int64 {
+	kg := (weight + 999) / 1000
+	return r.Fee + r.PerKg*kg
+}
+
+// Validate checks that every method can be offered and that rules only name
+// zones the table has.
+func (t Table) Validate() error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidTable, fmt.Sprintf(format, args...))
+	}
+
+	if _, err := money.LookupCurrency(t.Currency); err != nil {
+		return invalid("%v", err)
+	}
+	seen := map[string]string{}
+	for zone, countries := range t.Zones {
+		for _, c := range countries {
+			if c != "*" && (len(c) != 2 || c != strings.ToUpper(c)) {
+				return invalid("zone %s: country %q must be an upper case ISO 3166-1 alpha-2 code", zone, c)
+			}
+			if other, ok := seen[c]; ok {
+				return invalid("country %s is in zones %s and %s", c, other, zone)
+			}
+			seen[c] = zone
+		}
+	}
+	if _, ok := t.Zones[t.DefaultZone]; t.DefaultZone != "" && !ok {
+		return invalid("unknown default zone %q", t.DefaultZone)
+	}
+
+	if len(t.Methods) == 0 {
+		return invalid("no shipping methods")
+	}
+	codes := map[string]bool{}
+	for _, m := range t.Methods {
+		switch {
+		case m.Code == "" || m.Code != strings.ToUpper(m.Code):
+			return invalid("method code %q must be upper case", m.Code)
+		case codes[m.Code]:
+			return invalid("duplicate method %s", m.Code)
+		case m.MinDays < 0 || m.MaxDays < m.MinDays:
+			return invalid("method %s: days must satisfy 0 <= min_days <= max_days", m.Code)
+		case len(m.Rules) == 0:
+			return invalid("method %s has no rules", m.Code)
+		}
+		codes[m.Code] = true
+		for _, r := range m.Rules {
+			if _, ok := t.Zones[r.Zone]; r.Zone != "" && !ok {
+				return invalid("method %s: unknown zone %q", m.Code, r.Zone)
+			}
+			if r.MaxWeightGrams < 0 || r.Fee < 0 || r.PerKg < 0 {
+				return invalid("method %s: weights and fees cannot be negative", m.Code)
+			}
+		}
+	}
+	return nil
+}
+
+// Zone returns the zone a country ships in, or "" when it is in none; only
+// rules for every zone match then.
+func (t Table) Zone(country string) string {
+	if country == "" {
+		return t.DefaultZone
+	}
+	rest := ""
+	for zone, countries := range t.Zones {
+		if slices.Contains(countries, country) {
+			return zone
+		}
+		if slices.Contains(countries, "*") {
+			rest = zone
+		}
+	}
+	return rest
+}
+
+// Options prices the parcel with every method that has a matching rule,
+// cheapest first. Fees are in the table currency.
+func (t Table) Options(p Parcel) []Option {
+	zone := t.Zone(p.Country)
+	var out []Option
+	for _, m := range t.Methods {
+		i := slices.IndexFunc(m.Rules, func(r Rule) bool { return r.matches(zone, p.WeightGrams) })
+		if i < 0 {
+			continue
+		}
+		out = append(out, Option{
+			Code:    m.Code,
+			Name:    m.Name,
+			Fee:     Money{Currency: t.Currency, Amount: m.Rules[i].fee(p.WeightGrams)},
+			MinDays: m.MinDays,
+			MaxDays: m.MaxDays,
+		})
+	}
+	slices.SortStableFunc(out, func(a, b Option) int { return cmp.Compare(a.Fee.Amount, b.Fee.Amount) })
+	return out
+}
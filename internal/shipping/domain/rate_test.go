@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+var table = Table{
+	Currency: "IDR",
+	Zones: map[string][]string{
+		"DOMESTIC": {"ID"},
+		"ASEAN":    {"SG", "MY"},
+		"WORLD":    {"*"},
+	},
+	DefaultZone: "DOMESTIC",
+	Methods: []Method{
+		{Code: "EXPRESS", Name: "Express", MinDays: 1, MaxDays: 2, Rules: []Rule{
+			{Zone: "DOMESTIC", MaxWeightGrams: 5000, Fee: 25000, PerKg: 8000},
+		}},
+		{Code: "STANDARD", Name: "Standard", MinDays: 3, MaxDays: 5, Rules: []Rule{
+			{Zone: "DOMESTIC", MaxWeightGrams: 1000, Fee: 10000},
+			{Zone: "DOMESTIC", Fee: 10000, PerKg: 4000},
+			{Fee: 150000},
+		}},
+	},
+}
+
+func TestOptions(t *testing.T) {
+	cases := []struct {
+		name    string
+		parcel  Parcel
+		options map[string]int64 // code: fee
+	}{
+		{name: "light domestic parcel", parcel: Parcel{Country: "ID", WeightGrams: 800}, options: map[string]int64{"STANDARD": 10000, "EXPRESS": 25000 + 8000}},
+		{name: "heavier parcel pays per started kg", parcel: Parcel{Country: "ID", WeightGrams: 2100}, options: map[string]int64{"STANDARD": 10000 + 3*4000, "EXPRESS": 25000 + 3*8000}},
+		{name: "too heavy for express", parcel: Parcel{Country: "ID", WeightGrams: 5001}, options: map[string]int64{"STANDARD": 10000 + 6*4000}},
+		{name: "no destination uses the default zone", parcel: Parcel{WeightGrams: 0}, options: map[string]int64{"STANDARD": 10000, "EXPRESS": 25000}},
+		{name: "other zones get the flat rate", parcel: Parcel{Country: "SG", WeightGrams: 300}, options: map[string]int64{"STANDARD": 150000}},
+		{name: "countries in no zone fall in the catch-all", parcel: Parcel{Country: "US", WeightGrams: 300}, options: map[string]int64{"STANDARD": 150000}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := table.Options(tc.parcel)
+			if len(got) != len(tc.options) {
+				t.Fatalf("expected %d options, got %+v", len(tc.options), got)
+			}
+			for i, o := range got {
+				if fee, ok := tc.options[o.Code]; !ok || o.Fee != (Money{Currency: "IDR", Amount: fee}) {
+					t.Fatalf("unexpected option %+v", o)
+				}
+				if i > 0 && got[i-1].Fee.Amount > o.Fee.Amount {
+					t.Fatalf("expected the cheapest option first, got %+v", got)
+				}
+			}
+		})
+	}
+
+	if got := table.Options(Parcel{Country: "ID"}); got[0].MinDays != 3 || got[0].MaxDays != 5 || got[0].Name != "Standard" {
+		t.Fatalf("expected the method's name and delivery estimate, got %+v", got[0])
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := table.Validate(); err != nil {
+		t.Fatalf("expected a valid table, got %v", err)
+	}
+
+	invalid := map[string]func(t *Table){
+		"unknown currency":      func(t *Table) { t.Currency = "XXX" },
+		"lower case country":    func(t *Table) { t.Zones = map[string][]string{"DOMESTIC": {"id"}} },
+		"country in two zones":  func(t *Table) { t.Zones = map[string][]string{"DOMESTIC": {"ID"}, "ASEAN": {"ID"}} },
+		"unknown default zone":  func(t *Table) { t.DefaultZone = "MARS" },
+		"no methods":            func(t *Table) { t.Methods = nil },
+		"method without rules":  func(t *Table) { t.Methods = []Method{{Code: "STANDARD"}} },
+		"rule for unknown zone": func(t *Table) { t.Methods = []Method{{Code: "STANDARD", Rules: []Rule{{Zone: "MARS"}}}} },
+		"negative fee":          func(t *Table) { t.Methods = []Method{{Code: "STANDARD", Rules: []Rule{{Fee: -1}}}} },
+		"max days before min":   func(t *Table) { t.Methods = []Method{{Code: "STANDARD", MinDays: 3, MaxDays: 1, Rules: []Rule{{}}}} },
+		"duplicate method codes": func(t *Table) {
+			t.Methods = []Method{{Code: "STANDARD", Rules: []Rule{{}}}, {Code: "STANDARD", Rules: []Rule{{}}}}
+		},
+	}
+	for name, mutate := range invalid {
+		tt := table
+		mutate(&tt)
+		if err := tt.Validate(); !errors.Is(err, ErrInvalidTable) {
+			t.Errorf("%s: expected ErrInvalidTable, got %v", name, err)
+		}
+	}
+}
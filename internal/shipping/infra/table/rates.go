@@ -0,0 +1,103 @@
+// Package table prices shipping from a rate table loaded from config.
+package table
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dwikikusuma/shoping-llm/internal/shipping/domain"
+)
+
+// Rates is a shipping.RateProvider backed by a fixed table, typically
+// loaded from a file that is refreshed by deploying a new one.
+type Rates struct {
+	table domain.Table
+}
+
+func New(t domain.Table) (*Rates, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return &Rates{table: t}, nil
+}
+
+// Default ships anywhere in IDR: STANDARD for free and EXPRESS for a flat
+// 20000. It is used when no table is configured.
+func Default() *Rates {
+	return &Rates{table: domain.Table{
+		Currency: "IDR",
+		Methods: []domain.Method{
+			{Code: "STANDARD", Name: "Standard", MinDays: 3, MaxDays: 5, Rules: []domain.Rule{{}}},
+			{Code: "EXPRESS", Name: "Express", MinDays: 1, MaxDays: 2, Rules: []domain.Rule{{Fee: 20000}}},
+		},
+	}}
+}
+
+// Load reads a JSON file of the form
+//
+//	{
+//	  "currency": "IDR",
+//	  "zones": {"DOMESTIC": ["ID"], "ASEAN": ["SG", "MY"], "WORLD": ["*"]},
+//	  "default_zone": "DOMESTIC",
+//	  "methods": [
+//	    {"code": "STANDARD", "name": "Standard", "min_days": 3, "max_days": 5, "rules": [
+//	      {"zone": "DOMESTIC", "max_weight_grams": 1000, "fee": 10000},
+//	      {"zone": "DOMESTIC", "fee": 10000, "per_kg": 5000}
+//	    ]}
+//	  ]
+//	}
+//
+// where a method's first matching rule sets its fee; see domain.Rule.
+func Load(path string) (*Rates, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Currency    string              `json:"currency"`
+		Zones       map[string][]string `json:"zones"`
+		DefaultZone string              `json:"default_zone"`
+		Methods     []struct {
+			Code    string `json:"code"`
+			Name    string `json:"name"`
+			MinDays int32  `json:"min_days"`
+			MaxDays int32  `json:"max_days"`
+			Rules   []struct {
+				Zone           string `json:"zone"`
+				MaxWeightGrams int64  `json:"max_weight_grams"`
+				Fee            int64  `json:"fee"`
+				PerKg          int64  `json:"per_kg"`
+			} `json:"rules"`
+		} `json:"methods"`
+	}
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	t := domain.Table{Currency: file.Currency, Zones: file.Zones, DefaultZone: file.DefaultZone}
+	for _, m := range file.Methods {
+		method := domain.Method{Code: m.Code, Name: m.Name, MinDays: m.MinDays, MaxDays: m.MaxDays}
+		for _, r := range m.Rules {
+			method.Rules = append(method.Rules, domain.Rule{
+				Zone:           r.Zone,
+				MaxWeightGrams: r.MaxWeightGrams,
+				Fee:            r.Fee,
+				PerKg:          r.PerKg,
+			})
+		}
+		t.Methods = append(t.Methods, method)
+	}
+
+	rates, err := New(t)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rates, nil
+}
+
+func (r *Rates) Options(ctx context.Context, parcel domain.Parcel) ([]domain.Option, error) {
+	return r.table.Options(parcel), nil
+}
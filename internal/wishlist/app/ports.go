@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+
+	"github.com/dwikikusuma/shoping-llm/internal/wishlist/domain"
+)
+
+type WishlistRepo interface {
+	Get(ctx context.Context, userID string) (domain.Wishlist, error)
+	// Add saves the item; saving it again keeps the original AddedAt.
+	Add(ctx context.Context, userID string, item domain.Item) error
+	// Remove reports whether the item was on the wishlist.
+	Remove(ctx context.Context, userID, productID, variantID string) (bool, error)
+	Count(ctx context.Context, userID string) (int, error)
+	// Lock keeps other additions to the user's wishlist waiting until the
+	// transaction in ctx ends.
+	Lock(ctx context.Context, userID string) error
+}
+
+// Catalog tells the wishlist which products and variants it may hold. Both
+// lookups fail with ErrNotFound for an ID that doesn't exist.
+type Catalog interface {
+	GetProduct(ctx context.Context, productID string) (Product, error)
+	GetVariant(ctx context.Context, variantID string) (Variant, error)
+}
+
+type Product struct {
+	ID       string
+	Archived bool
+	// HasVariants is set when the product can only be bought as one of its
+	// variants.
+	HasVariants bool
+}
+
+type Variant struct {
+	ID        string
+	ProductID string
+}
+
+// CartAdder puts an item into the user's active cart, creating the cart if
+// needed. It must join the transaction carried by ctx, and fail with
+// ErrCartRejected when the cart won't take the item.
+type CartAdder interface {
+	AddToCart(ctx context.Context, userID, productID, variantID string, quantity int32) error
+}
+
+// TxRunner runs fn inside a single database transaction carried by ctx.
+type TxRunner interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dwikikusuma/shoping-llm/internal/wishlist/domain"
+)
+
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	ErrNotFound     = errors.New("not found")
+	ErrWishlistFull = errors.New("wishlist is full")
+	ErrCartRejected = errors.New("cart refused the item")
+
+	ErrUnknownProduct  = errors.New("product does not exist")
+	ErrProductArchived = errors.New("product is no longer sold")
+	ErrInvalidVariant  = errors.New("item has no valid variant for its product")
+)
+
+// MaxItems caps a wishlist so that Get stays a single small read.
+const MaxItems = 200
+
+type Service struct {
+	repo    WishlistRepo
+	catalog Catalog
+	cart    CartAdder
+	tx      TxRunner
+}
+
+func NewService(repo WishlistRepo, catalog Catalog, cart CartAdder, tx TxRunner) *Service {
+	return &Service{repo: repo, catalog: catalog, cart: cart, tx: tx}
+}
+
+func (s *Service) GetWishlist(ctx context.Context, userID string) (domain.Wishlist, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return domain.Wishlist{}, ErrInvalidInput
+	}
+	return s.repo.Get(ctx, userID)
+}
+
+// AddItem saves the item for later. The item must be one the cart would
+// take: a product still on sale, as one of its variants if it has any.
+// Adding an item that is already saved is a no-op.
+func (s *Service) AddItem(ctx context.Context, userID, productID, variantID string) (domain.Wishlist, error) {
+	userID, productID, variantID = strings.TrimSpace(userID), strings.TrimSpace(productID), strings.TrimSpace(variantID)
+	if userID == "" || productID == "" {
+		return domain.Wishlist{}, ErrInvalidInput
+	}
+	if err := s.checkItem(ctx, productID, variantID); err != nil {
+		return domain.Wishlist{}, err
+	}
+
+	// Additions to the same wishlist take turns, so two of them can't both
+	// see room for one more item.
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Lock(ctx, userID); err != nil {
+			return err
+		}
+		n, err := s.repo.Count(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if n >= MaxItems {
+			return ErrWishlistFull
+		}
+		return s.repo.Add(ctx, userID, domain.Item{ProductID: productID, VariantID: variantID})
+	})
+	if err != nil {
+		return domain.Wishlist{}, err
+	}
+	return s.repo.Get(ctx, userID)
+}
+
+// checkItem asks the catalog whether the product, and the variant if given,
+// can be saved.
+func (s *Service) checkItem(ctx context.Context, productID, variantID string) error {
+	product, err := s.catalog.GetProduct(ctx, productID)
+	if errors.Is(err, ErrNotFound) {
+		return ErrUnknownProduct
+	}
+	if err != nil {
+		return err
+	}
+	if product.Archived {
+		return ErrProductArchived
+	}
+	if variantID == "" {
+		if product.HasVariants {
+			return ErrInvalidVariant
+		}
+		return nil
+	}
+
+	variant, err := s.catalog.GetVariant(ctx, variantID)
+	if errors.Is(err, ErrNotFound) {
+		return ErrInvalidVariant
+	}
+	if err != nil {
+		return err
+	}
+	if variant.ProductID != product.ID {
+		return ErrInvalidVariant
+	}
+	return nil
+}
+
+// RemoveItem drops the item. Removing an item that isn't saved is a no-op.
+func (s *Service) RemoveItem(ctx context.Context, userID, productID, variantID string) (domain.Wishlist, error) {
+	userID, productID, variantID = strings.TrimSpace(userID), strings.TrimSpace(productID), strings.TrimSpace(variantID)
+	if userID == "" || productID == "" {
+		return domain.Wishlist{}, ErrInvalidInput
+	}
+
+	if _, err := s.repo.Remove(ctx, userID, productID, variantID); err != nil {
+		return domain.Wishlist{}, err
+	}
+	return s.repo.Get(ctx, userID)
+}
+
+// MoveToCart takes the item off the wishlist and adds quantity of it to the
+// user's cart in one transaction, so it ends up in exactly one of the two.
+// Moving an item that isn't saved fails with ErrNotFound, which also stops a
+// retried or concurrent move from adding it twice.
+func (s *Service) MoveToCart(ctx context.Context, userID, productID, variantID string, quantity int32) (domain.Wishlist, error) {
+	userID, productID, variantID = strings.TrimSpace(userID), strings.TrimSpace(productID), strings.TrimSpace(variantID)
+	if userID == "" || productID == "" {
+		return domain.Wishlist{}, ErrInvalidInput
+	}
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 {
+		return domain.Wishlist{}, ErrInvalidInput
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		removed, err := s.repo.Remove(ctx, userID, productID, variantID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return ErrNotFound
+		}
+		return s.cart.AddToCart(ctx, userID, productID, variantID, quantity)
+	})
+	if err != nil {
+		return domain.Wishlist{}, err
+	}
+	return s.repo.Get(ctx, userID)
+}
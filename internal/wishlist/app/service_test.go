@@ -0,0 +1,227 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"testing"
+
+	"github.com/dwikikusuma/shoping-llm/internal/wishlist/domain"
+)
+
+// fakeRepo keeps one wishlist per user in memory.
+type fakeRepo struct {
+	items  map[string]domain.Item // user ID + "/" + product ID + "/" + variant ID
+	locked []string               // users whose wishlist was locked, in order
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{items: map[string]domain.Item{}}
+}
+
+func key(userID, productID, variantID string) string {
+	return userID + "/" + productID + "/" + variantID
+}
+
+func (f *fakeRepo) Get(ctx context.Context, userID string) (domain.Wishlist, error) {
+	w := domain.Wishlist{UserID: userID}
+	for k, it := range f.items {
+		if k == key(userID, it.ProductID, it.VariantID) {
+			w.Items = append(w.Items, it)
+		}
+	}
+	return w, nil
+}
+
+func (f *fakeRepo) Add(ctx context.Context, userID string, item domain.Item) error {
+	k := key(userID, item.ProductID, item.VariantID)
+	if _, ok := f.items[k]; !ok {
+		f.items[k] = item
+	}
+	return nil
+}
+
+func (f *fakeRepo) Remove(ctx context.Context, userID, productID, variantID string) (bool, error) {
+	k := key(userID, productID, variantID)
+	_, ok := f.items[k]
+	delete(f.items, k)
+	return ok, nil
+}
+
+func (f *fakeRepo) Count(ctx context.Context, userID string) (int, error) {
+	w, _ := f.Get(ctx, userID)
+	return len(w.Items), nil
+}
+
+func (f *fakeRepo) Lock(ctx context.Context, userID string) error {
+	if ctx.Value(inTx{}) == nil {
+		return errors.New("locked outside a transaction")
+	}
+	f.locked = append(f.locked, userID)
+	return nil
+}
+
+// inTx marks the contexts fakeTx hands to fn.
+type inTx struct{}
+
+// fakeTx restores the wishlist when fn fails, like a rolled back transaction.
+type fakeTx struct {
+	repo *fakeRepo
+}
+
+func (t fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	snapshot := maps.Clone(t.repo.items)
+	if err := fn(context.WithValue(ctx, inTx{}, true)); err != nil {
+		t.repo.items = snapshot
+		return err
+	}
+	return nil
+}
+
+// fakeCatalog sells p1 on its own and p2 as v1 or v2; p3 is archived.
+type fakeCatalog struct{}
+
+func (fakeCatalog) GetProduct(ctx context.Context, productID string) (Product, error) {
+	switch productID {
+	case "p1":
+		return Product{ID: "p1"}, nil
+	case "p2":
+		return Product{ID: "p2", HasVariants: true}, nil
+	case "p3":
+		return Product{ID: "p3", Archived: true}, nil
+	}
+	return Product{}, ErrNotFound
+}
+
+func (fakeCatalog) GetVariant(ctx context.Context, variantID string) (Variant, error) {
+	if variantID == "v1" || variantID == "v2" {
+		return Variant{ID: variantID, ProductID: "p2"}, nil
+	}
+	return Variant{}, ErrNotFound
+}
+
+type fakeCart struct {
+	lines map[string]int32 // product ID + "/" + variant ID
+	err   error
+}
+
+func (f *fakeCart) AddToCart(ctx context.Context, userID, productID, variantID string, quantity int32) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.lines[productID+"/"+variantID] += quantity
+	return nil
+}
+
+func newTestService() (*Service, *fakeRepo, *fakeCart) {
+	repo := newFakeRepo()
+	cart := &fakeCart{lines: map[string]int32{}}
+	return NewService(repo, fakeCatalog{}, cart, fakeTx{repo: repo}), repo, cart
+}
+
+func TestAddItem(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newTestService()
+
+	for i := 0; i < 2; i++ {
+		w, err := svc.AddItem(ctx, "alice", "p1", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(w.Items) != 1 {
+			t.Fatalf("expected saving twice to keep one item, got %+v", w.Items)
+		}
+	}
+	svc.AddItem(ctx, "alice", "p2", "v1")
+	if w, _ := svc.AddItem(ctx, "alice", "p2", "v2"); len(w.Items) != 3 {
+		t.Fatalf("expected each variant to be its own item, got %+v", w.Items)
+	}
+	if len(repo.locked) != 4 || repo.locked[0] != "alice" {
+		t.Fatalf("expected every addition to lock alice's wishlist, got %v", repo.locked)
+	}
+
+	if _, err := svc.AddItem(ctx, "alice", " ", ""); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+
+	for i := 0; i < MaxItems; i++ {
+		repo.Add(ctx, "bob", domain.Item{ProductID: fmt.Sprintf("x%d", i)})
+	}
+	if _, err := svc.AddItem(ctx, "bob", "p1", ""); !errors.Is(err, ErrWishlistFull) {
+		t.Fatalf("expected ErrWishlistFull, got %v", err)
+	}
+}
+
+func TestAddItemChecksCatalog(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newTestService()
+
+	tests := []struct {
+		name                 string
+		productID, variantID string
+		want                 error
+	}{
+		{"unknown product", "nope", "", ErrUnknownProduct},
+		{"archived product", "p3", "", ErrProductArchived},
+		{"variant product without a variant", "p2", "", ErrInvalidVariant},
+		{"unknown variant", "p2", "v9", ErrInvalidVariant},
+		{"another product's variant", "p1", "v1", ErrInvalidVariant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.AddItem(ctx, "alice", tt.productID, tt.variantID); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+	if len(repo.items) != 0 {
+		t.Fatalf("expected nothing saved, got %+v", repo.items)
+	}
+}
+
+func TestMoveToCart(t *testing.T) {
+	ctx := context.Background()
+	svc, _, cart := newTestService()
+
+	svc.AddItem(ctx, "alice", "p2", "v1")
+	svc.AddItem(ctx, "alice", "p1", "")
+
+	w, err := svc.MoveToCart(ctx, "alice", "p2", "v1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.Items) != 1 || w.Items[0].ProductID != "p1" {
+		t.Fatalf("expected only p1 left on the wishlist, got %+v", w.Items)
+	}
+	if cart.lines["p2/v1"] != 1 {
+		t.Fatalf("expected one p2/v1 in the cart, got %+v", cart.lines)
+	}
+
+	if _, err := svc.MoveToCart(ctx, "alice", "p2", "v1", 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected a second move to fail with ErrNotFound, got %v", err)
+	}
+	if cart.lines["p2/v1"] != 1 {
+		t.Fatalf("expected the second move to leave the cart alone, got %+v", cart.lines)
+	}
+
+	t.Run("a failed cart add keeps the item on the wishlist", func(t *testing.T) {
+		cart.err = errors.New("cart down")
+		if _, err := svc.MoveToCart(ctx, "alice", "p1", "", 3); err == nil {
+			t.Fatal("expected the cart error")
+		}
+		cart.err = nil
+
+		w, _ := svc.GetWishlist(ctx, "alice")
+		if len(w.Items) != 1 || w.Items[0].ProductID != "p1" {
+			t.Fatalf("expected p1 to stay on the wishlist, got %+v", w.Items)
+		}
+		if _, ok := cart.lines["p1/"]; ok {
+			t.Fatalf("expected p1 to stay out of the cart, got %+v", cart.lines)
+		}
+	})
+
+	if _, err := svc.MoveToCart(ctx, "alice", "p1", "", -1); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected a negative quantity to fail, got %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Item is a product, or one variant of it, saved for later. Like cart lines,
+// two sizes of the same shirt are two items.
+type Item struct {
+	ProductID string
+	VariantID string // empty for products without variants
+	AddedAt   time.Time
+}
+
+// Wishlist is every item the user saved, most recently added first.
+type Wishlist struct {
+	UserID string
+	Items  []Item
+}
@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	wishlistv1 "github.com/dwikikusuma/shoping-llm/api/gen/wishlist/v1"
+	"github.com/dwikikusuma/shoping-llm/internal/wishlist/app"
+	"github.com/dwikikusuma/shoping-llm/internal/wishlist/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	wishlistv1.UnimplementedWishlistServiceServer
+	svc *app.Service
+}
+
+func NewServer(svc *app.Service) *Server {
+	return &Server{svc: svc}
+}
+
+func (s *Server) GetWishlist(ctx context.Context, req *wishlistv1.GetWishlistRequest) (*wishlistv1.Wishlist, error) {
+	w, err := s.svc.GetWishlist(ctx, req.GetUserId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return toProto(w), nil
+}
+
+func (s *Server) AddItem(ctx context.Context, req *wishlistv1.AddWishlistItemRequest) (*wishlistv1.Wishlist, error) {
+	w, err := s.svc.AddItem(ctx, req.GetUserId(), req.GetProductId(), req.GetVariantId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return toProto(w), nil
+}
+
+func (s *Server) RemoveItem(ctx context.Context, req *wishlistv1.RemoveWishlistItemRequest) (*wishlistv1.Wishlist, error) {
+	w, err := s.svc.RemoveItem(ctx, req.GetUserId(), req.GetProductId(), req.GetVariantId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return toProto(w), nil
+}
+
+func (s *Server) MoveToCart(ctx context.Context, req *wishlistv1.MoveToCartRequest) (*wishlistv1.Wishlist, error) {
+	w, err := s.svc.MoveToCart(ctx, req.GetUserId(), req.GetProductId(), req.GetVariantId(), req.GetQuantity())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return toProto(w), nil
+}
+
+func toProto(w domain.Wishlist) *wishlistv1.Wishlist {
+	items := make([]*wishlistv1.WishlistItem, 0, len(w.Items))
+	for _, it := range w.Items {
+		items = append(items, &wishlistv1.WishlistItem{
+			ProductId:   it.ProductID,
+			VariantId:   it.VariantID,
+			AddedAtUnix: it.AddedAt.Unix(),
+		})
+	}
+	return &wishlistv1.Wishlist{UserId: w.UserID, Items: items}
+}
+
+func mapErr(err error) error {
+	if errors.Is(err, app.ErrInvalidInput) || errors.Is(err, app.ErrUnknownProduct) || errors.Is(err, app.ErrInvalidVariant) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, app.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, app.ErrWishlistFull) || errors.Is(err, app.ErrCartRejected) || errors.Is(err, app.ErrProductArchived) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, "internal error")
+}
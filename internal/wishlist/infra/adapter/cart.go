@@ -0,0 +1,56 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cartapp "github.com/dwikikusuma/shoping-llm/internal/cart/app"
+	cartdomain "github.com/dwikikusuma/shoping-llm/internal/cart/domain"
+	wishlistapp "github.com/dwikikusuma/shoping-llm/internal/wishlist/app"
+)
+
+// CartServiceAdder adds wishlist items to the cart through the cart service,
+// whose repo joins the transaction carried by ctx.
+type CartServiceAdder struct {
+	svc *cartapp.Service
+}
+
+func NewCartServiceAdder(svc *cartapp.Service) *CartServiceAdder {
+	return &CartServiceAdder{svc: svc}
+}
+
+func (a *CartServiceAdder) AddToCart(ctx context.Context, userID, productID, variantID string, quantity int32) error {
+	cart, err := a.svc.GetOrCreate(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = a.svc.AddItemToCart(ctx, cartdomain.CartItem{
+		ProductID: productID,
+		VariantID: variantID,
+		Quantity:  quantity,
+	}, cart.ID, 0)
+	if isRejected(err) {
+		return fmt.Errorf("%w: %v", wishlistapp.ErrCartRejected, err)
+	}
+	return err
+}
+
+// isRejected reports whether the cart refused the item, as opposed to failing
+// to answer.
+func isRejected(err error) bool {
+	for _, target := range []error{
+		cartapp.ErrUnknownProduct,
+		cartapp.ErrProductArchived,
+		cartapp.ErrInvalidVariant,
+		cartapp.ErrQuantityLimit,
+		cartapp.ErrCartFull,
+		cartapp.ErrOutOfStock,
+		cartapp.ErrCartNotActive,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	cartapp "github.com/dwikikusuma/shoping-llm/internal/cart/app"
+)
+
+func TestIsRejected(t *testing.T) {
+	for _, err := range []error{
+		cartapp.ErrUnknownProduct,
+		cartapp.ErrProductArchived,
+		cartapp.ErrInvalidVariant,
+		cartapp.ErrQuantityLimit,
+		cartapp.ErrCartFull,
+		cartapp.ErrOutOfStock,
+		cartapp.ErrCartNotActive,
+		fmt.Errorf("add item: %w", cartapp.ErrOutOfStock),
+	} {
+		if !isRejected(err) {
+			t.Errorf("expected %v to be a rejection", err)
+		}
+	}
+	for _, err := range []error{nil, context.DeadlineExceeded, errors.New("connection reset")} {
+		if isRejected(err) {
+			t.Errorf("expected %v not to be a rejection", err)
+		}
+	}
+}
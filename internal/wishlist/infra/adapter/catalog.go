@@ -0,0 +1,48 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+
+	catalogapp "github.com/dwikikusuma/shoping-llm/internal/catalog/app"
+	wishlistapp "github.com/dwikikusuma/shoping-llm/internal/wishlist/app"
+)
+
+// CatalogServiceReader looks wishlist items up in the catalog service.
+type CatalogServiceReader struct {
+	svc *catalogapp.Service
+}
+
+func NewCatalogServiceReader(svc *catalogapp.Service) *CatalogServiceReader {
+	return &CatalogServiceReader{svc: svc}
+}
+
+func (r *CatalogServiceReader) GetProduct(ctx context.Context, productID string) (wishlistapp.Product, error) {
+	p, err := r.svc.GetProduct(ctx, productID)
+	if err != nil {
+		return wishlistapp.Product{}, mapCatalogErr(err)
+	}
+	return wishlistapp.Product{
+		ID:          p.ID,
+		Archived:    p.Archived(),
+		HasVariants: p.HasVariants,
+	}, nil
+}
+
+func (r *CatalogServiceReader) GetVariant(ctx context.Context, variantID string) (wishlistapp.Variant, error) {
+	v, err := r.svc.GetVariant(ctx, variantID)
+	if err != nil {
+		return wishlistapp.Variant{}, mapCatalogErr(err)
+	}
+	return wishlistapp.Variant{ID: v.ID, ProductID: v.ProductID}, nil
+}
+
+func mapCatalogErr(err error) error {
+	switch {
+	case errors.Is(err, catalogapp.ErrNotFound):
+		return wishlistapp.ErrNotFound
+	case errors.Is(err, catalogapp.ErrInvalidInput):
+		return wishlistapp.ErrInvalidInput
+	}
+	return err
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID NOT NULL,
+    product_id  UUID NOT NULL,
+    variant_id  UUID, -- NULL for products without variants
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT wishlist_items_user_product_variant_key
+        UNIQUE NULLS NOT DISTINCT (user_id, product_id, variant_id)
+);
+
+CREATE INDEX IF NOT EXISTS ix_wishlist_items_user_created
+    ON wishlist_items(user_id, created_at DESC);
@@ -0,0 +1,22 @@
+-- name: ListWishlistItems :many
+SELECT * FROM wishlist_items
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: CountWishlistItems :one
+SELECT count(*) FROM wishlist_items
+WHERE user_id = $1;
+
+-- name: AddWishlistItem :exec
+INSERT INTO wishlist_items (user_id, product_id, variant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, product_id, variant_id) DO NOTHING;
+
+-- name: RemoveWishlistItem :execrows
+DELETE FROM wishlist_items
+WHERE user_id = sqlc.arg(user_id) AND product_id = sqlc.arg(product_id)
+  AND variant_id IS NOT DISTINCT FROM sqlc.narg(variant_id)::uuid;
+
+-- name: LockWishlist :exec
+-- Serializes additions to one user's wishlist until the transaction ends.
+SELECT pg_advisory_xact_lock(hashtextextended('wishlist:' || sqlc.arg(user_id)::uuid::text, 0));
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/dwikikusuma/shoping-llm/internal/wishlist/app"
+	"github.com/dwikikusuma/shoping-llm/internal/wishlist/domain"
+	"github.com/dwikikusuma/shoping-llm/internal/wishlist/infra/postgres/wishlistdb"
+	pg "github.com/dwikikusuma/shoping-llm/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type WishlistRepo struct {
+	q *wishlistdb.Queries
+}
+
+func NewWishlistRepo(db *sql.DB) *WishlistRepo {
+	return &WishlistRepo{q: wishlistdb.New(db)}
+}
+
+// queries joins the caller's transaction when ctx carries one (see pg.TxManager).
+func (r *WishlistRepo) queries(ctx context.Context) *wishlistdb.Queries {
+	if tx, ok := pg.TxFromContext(ctx); ok {
+		return r.q.WithTx(tx)
+	}
+	return r.q
+}
+
+func (r *WishlistRepo) Get(ctx context.Context, userID string) (domain.Wishlist, error) {
+	uid, err := parseUUID(userID)
+	if err != nil {
+		return domain.Wishlist{}, err
+	}
+
+	rows, err := r.queries(ctx).ListWishlistItems(ctx, uid)
+	if err != nil {
+		return domain.Wishlist{}, err
+	}
+
+	items := make([]domain.Item, 0, len(rows))
+	for _, row := range rows {
+		item := domain.Item{
+			ProductID: row.ProductID.String(),
+			AddedAt:   row.CreatedAt,
+		}
+		if row.VariantID.Valid {
+			item.VariantID = row.VariantID.UUID.String()
+		}
+		items = append(items, item)
+	}
+	return domain.Wishlist{UserID: uid.String(), Items: items}, nil
+}
+
+func (r *WishlistRepo) Add(ctx context.Context, userID string, item domain.Item) error {
+	uid, err := parseUUID(userID)
+	if err != nil {
+		return err
+	}
+	pid, err := parseUUID(item.ProductID)
+	if err != nil {
+		return err
+	}
+	vid, err := parseNullUUID(item.VariantID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries(ctx).AddWishlistItem(ctx, wishlistdb.AddWishlistItemParams{
+		UserID:    uid,
+		ProductID: pid,
+		VariantID: vid,
+	})
+}
+
+func (r *WishlistRepo) Remove(ctx context.Context, userID, productID, variantID string) (bool, error) {
+	uid, err := parseUUID(userID)
+	if err != nil {
+		return false, err
+	}
+	pid, err := parseUUID(productID)
+	if err != nil {
+		return false, err
+	}
+	vid, err := parseNullUUID(variantID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.queries(ctx).RemoveWishlistItem(ctx, wishlistdb.RemoveWishlistItemParams{
+		UserID:    uid,
+		ProductID: pid,
+		VariantID: vid,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *WishlistRepo) Count(ctx context.Context, userID string) (int, error) {
+	uid, err := parseUUID(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := r.queries(ctx).CountWishlistItems(ctx, uid)
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+func (r *WishlistRepo) Lock(ctx context.Context, userID string) error {
+	uid, err := parseUUID(userID)
+	if err != nil {
+		return err
+	}
+	return r.queries(ctx).LockWishlist(ctx, uid)
+}
+
+func parseUUID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return uuid.Nil, app.ErrInvalidInput
+	}
+	return id, nil
+}
+
+// parseNullUUID maps an empty ID to NULL.
+func parseNullUUID(s string) (uuid.NullUUID, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return uuid.NullUUID{}, nil
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.NullUUID{}, app.ErrInvalidInput
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}, nil
+}
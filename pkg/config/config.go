@@ -13,15 +13,35 @@ type Config struct {
 	GRPCPort int
 
 	CatalogGRPCAddr string
+
+	// AdminToken guards admin-only operations such as DeleteProduct. When it
+	// is empty those operations are disabled.
+	AdminToken string
+
+	// CursorSecret signs pagination cursors. When it is empty a random key is
+	// used and cursors stop working after a restart.
+	CursorSecret string
+
+	// FXRatesFile is a JSON file of exchange rates used to quote carts in a
+	// display currency. When it is empty quotes can't be converted.
+	FXRatesFile string
+
+	// ShippingRatesFile is a JSON table of shipping rates. When it is empty
+	// every order ships for a flat fee.
+	ShippingRatesFile string
 }
 
 func Load() Config {
 	return Config{
-		AppEnv:          getEnv("APP_ENV", "dev"),
-		LogLevel:        getEnv("LOG_LEVEL", "info"),
-		HTTPPort:        getEnvInt("HTTP_PORT", 8080),
-		GRPCPort:        getEnvInt("GRPC_PORT", 8081),
-		CatalogGRPCAddr: getEnv("CATALOG_GRPC_ADDR", "localhost:8081"),
+		AppEnv:            getEnv("APP_ENV", "dev"),
+		LogLevel:          getEnv("LOG_LEVEL", "info"),
+		HTTPPort:          getEnvInt("HTTP_PORT", 8080),
+		GRPCPort:          getEnvInt("GRPC_PORT", 8081),
+		CatalogGRPCAddr:   getEnv("CATALOG_GRPC_ADDR", "localhost:8081"),
+		AdminToken:        getEnv("ADMIN_TOKEN", ""),
+		CursorSecret:      getEnv("CURSOR_SECRET", ""),
+		FXRatesFile:       getEnv("FX_RATES_FILE", ""),
+		ShippingRatesFile: getEnv("SHIPPING_RATES_FILE", ""),
 	}
 }
 
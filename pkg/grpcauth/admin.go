@@ -0,0 +1,28 @@
+// Package grpcauth checks credentials carried in gRPC metadata.
+package grpcauth
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequireAdmin checks that the "admin-token" metadata equals token. An empty
+// token disables admin operations altogether.
+func RequireAdmin(ctx context.Context, token string) error {
+	if token == "" {
+		return status.Error(codes.PermissionDenied, "admin operations are disabled")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get("admin-token")
+	if len(tokens) == 0 {
+		return status.Error(codes.Unauthenticated, "missing admin token")
+	}
+	if subtle.ConstantTimeCompare([]byte(tokens[0]), []byte(token)) != 1 {
+		return status.Error(codes.PermissionDenied, "invalid admin token")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Currency is ISO-4217 metadata for a currency code.
+type Currency struct {
+	Code string
+	// Exponent is the number of minor units in one major unit, as a power of
+	// ten: amounts in USD are cents (2), amounts in IDR are whole rupiah (0).
+	Exponent int
+	Name     string
+}
+
+// currencies are the codes the shop accepts. IDR is kept at exponent 0
+// because sen are not used in practice, although ISO lists 2.
+var currencies = map[string]Currency{
+	"AUD": {Code: "AUD", Exponent: 2, Name: "Australian Dollar"},
+	"BHD": {Code: "BHD", Exponent: 3, Name: "Bahraini Dinar"},
+	"CNY": {Code: "CNY", Exponent: 2, Name: "Yuan Renminbi"},
+	"EUR": {Code: "EUR", Exponent: 2, Name: "Euro"},
+	"GBP": {Code: "GBP", Exponent: 2, Name: "Pound Sterling"},
+	"HKD": {Code: "HKD", Exponent: 2, Name: "Hong Kong Dollar"},
+	"IDR": {Code: "IDR", Exponent: 0, Name: "Rupiah"},
+	"INR": {Code: "INR", Exponent: 2, Name: "Indian Rupee"},
+	"JPY": {Code: "JPY", Exponent: 0, Name: "Yen"},
+	"KRW": {Code: "KRW", Exponent: 0, Name: "Won"},
+	"KWD": {Code: "KWD", Exponent: 3, Name: "Kuwaiti Dinar"},
+	"MYR": {Code: "MYR", Exponent: 2, Name: "Malaysian Ringgit"},
+	"PHP": {Code: "PHP", Exponent: 2, Name: "Philippine Peso"},
+	"SGD": {Code: "SGD", Exponent: 2, Name: "Singapore Dollar"},
+	"THB": {Code: "THB", Exponent: 2, Name: "Baht"},
+	"USD": {Code: "USD", Exponent: 2, Name: "US Dollar"},
+	"VND": {Code: "VND", Exponent: 0, Name: "Dong"},
+}
+
+// LookupCurrency returns the metadata for code, which must already be
+// normalized.
+func LookupCurrency(code string) (Currency, error) {
+	c, ok := currencies[code]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return c, nil
+}
+
+// NormalizeCurrency trims and upper-cases code and checks that it is a known
+// currency.
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if _, err := LookupCurrency(code); err != nil {
+		return "", err
+	}
+	return code, nil
+}
@@ -0,0 +1,98 @@
+// Package money holds amounts of money in the minor unit of their currency,
+// together with the ISO-4217 metadata and exchange rates needed to combine
+// and convert them. Arithmetic never mixes currencies silently: it returns
+// ErrCurrencyMismatch instead.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrOverflow         = errors.New("amount out of range")
+)
+
+// Money is an amount in the minor unit of Currency, such as cents for USD.
+type Money struct {
+	Currency string
+	Amount   int64
+}
+
+// New returns amount minor units of currency, normalizing the code.
+func New(currency string, amount int64) (Money, error) {
+	code, err := NormalizeCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Currency: code, Amount: amount}, nil
+}
+
+// Zero returns no money in currency. It is the starting point of a sum.
+func Zero(currency string) Money {
+	return Money{Currency: currency}
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	if (o.Amount > 0 && m.Amount > math.MaxInt64-o.Amount) || (o.Amount < 0 && m.Amount < math.MinInt64-o.Amount) {
+		return Money{}, ErrOverflow
+	}
+	return Money{Currency: m.Currency, Amount: m.Amount + o.Amount}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if o.Amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(Money{Currency: o.Currency, Amount: -o.Amount})
+}
+
+// Mul multiplies m by a quantity.
+func (m Money) Mul(n int64) (Money, error) {
+	if m.Amount != 0 && n != 0 {
+		p := m.Amount * n
+		if p/n != m.Amount || (m.Amount == -1 && n == math.MinInt64) || (n == -1 && m.Amount == math.MinInt64) {
+			return Money{}, ErrOverflow
+		}
+		return Money{Currency: m.Currency, Amount: p}, nil
+	}
+	return Money{Currency: m.Currency}, nil
+}
+
+// String formats m in major units, such as "USD 12.50" or "IDR 250000".
+func (m Money) String() string {
+	c, err := LookupCurrency(m.Currency)
+	if err != nil || c.Exponent == 0 {
+		return m.Currency + " " + strconv.FormatInt(m.Amount, 10)
+	}
+
+	sign := ""
+	digits := strconv.FormatUint(absUint(m.Amount), 10)
+	if m.Amount < 0 {
+		sign = "-"
+	}
+	if len(digits) <= c.Exponent {
+		digits = strings.Repeat("0", c.Exponent-len(digits)+1) + digits
+	}
+	cut := len(digits) - c.Exponent
+	return m.Currency + " " + sign + digits[:cut] + "." + digits[cut:]
+}
+
+func absUint(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
@@ -0,0 +1,100 @@
+package money
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArithmetic(t *testing.T) {
+	usd := func(n int64) Money { return Money{Currency: "USD", Amount: n} }
+
+	sum, err := usd(150).Add(usd(275))
+	if err != nil || sum != usd(425) {
+		t.Fatalf("expected USD 4.25, got %v (%v)", sum, err)
+	}
+	if _, err := usd(1).Add(Money{Currency: "IDR", Amount: 1}); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := usd(math.MaxInt64).Add(usd(1)); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expected ErrOverflow on add, got %v", err)
+	}
+	if _, err := usd(math.MaxInt64 / 2).Mul(3); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expected ErrOverflow on mul, got %v", err)
+	}
+	if got, _ := usd(250).Mul(3); got != usd(750) {
+		t.Fatalf("expected USD 7.50, got %v", got)
+	}
+}
+
+func TestString(t *testing.T) {
+	cases := map[Money]string{
+		{Currency: "USD", Amount: 1250}:   "USD 12.50",
+		{Currency: "USD", Amount: 5}:      "USD 0.05",
+		{Currency: "USD", Amount: -5}:     "USD -0.05",
+		{Currency: "KWD", Amount: 1}:      "KWD 0.001",
+		{Currency: "IDR", Amount: 250000}: "IDR 250000",
+	}
+	for m, want := range cases {
+		if got := m.String(); got != want {
+			t.Errorf("%#v: expected %q, got %q", m, want, got)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	m, err := New(" usd ", 100)
+	if err != nil || m.Currency != "USD" {
+		t.Fatalf("expected normalized USD, got %+v (%v)", m, err)
+	}
+	if _, err := New("XXX", 1); !errors.Is(err, ErrUnknownCurrency) {
+		t.Fatalf("expected ErrUnknownCurrency, got %v", err)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	data := `{"base": "USD", "rates": {"IDR": 16250, "EUR": "0.92"}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rates, err := LoadStaticRates(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	cases := []struct {
+		in   Money
+		to   string
+		want Money
+	}{
+		// exponent 2 -> 0: USD 12.34 * 16250 = IDR 200525
+		{Money{Currency: "USD", Amount: 1234}, "IDR", Money{Currency: "IDR", Amount: 200525}},
+		// exponent 0 -> 2: IDR 250000 / 16250 = USD 15.3846... -> 15.38
+		{Money{Currency: "IDR", Amount: 250000}, "USD", Money{Currency: "USD", Amount: 1538}},
+		// cross rate through the base: EUR 10 = USD 10.8695... -> IDR 176630.43 -> 176630
+		{Money{Currency: "EUR", Amount: 1000}, "IDR", Money{Currency: "IDR", Amount: 176630}},
+		// IDR 81 is just under half a cent and IDR 82 just over
+		{Money{Currency: "IDR", Amount: 81}, "USD", Money{Currency: "USD", Amount: 0}},
+		{Money{Currency: "IDR", Amount: 82}, "USD", Money{Currency: "USD", Amount: 1}},
+		{Money{Currency: "IDR", Amount: -82}, "USD", Money{Currency: "USD", Amount: -1}},
+		{Money{Currency: "USD", Amount: 7}, "USD", Money{Currency: "USD", Amount: 7}},
+	}
+	for _, tc := range cases {
+		got, err := Convert(ctx, rates, tc.in, tc.to)
+		if err != nil || got != tc.want {
+			t.Errorf("%v -> %s: expected %v, got %v (%v)", tc.in, tc.to, tc.want, got, err)
+		}
+	}
+
+	if _, err := Convert(ctx, rates, Money{Currency: "USD", Amount: 1}, "JPY"); !errors.Is(err, ErrNoRate) {
+		t.Fatalf("expected ErrNoRate, got %v", err)
+	}
+	if _, err := Convert(ctx, nil, Money{Currency: "USD", Amount: 1}, "IDR"); !errors.Is(err, ErrNoRate) {
+		t.Fatalf("expected ErrNoRate without a provider, got %v", err)
+	}
+}
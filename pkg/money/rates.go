@@ -0,0 +1,143 @@
+package money
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// ErrNoRate means the provider can't convert between the two currencies.
+var ErrNoRate = errors.New("no exchange rate")
+
+// RateProvider returns exchange rates between currency codes.
+type RateProvider interface {
+	// Rate returns how many major units of to one major unit of from buys.
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+}
+
+// Convert converts m into the currency to, rounding half away from zero to
+// the minor unit of to.
+func Convert(ctx context.Context, rates RateProvider, m Money, to string) (Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+	src, err := LookupCurrency(m.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+	dst, err := LookupCurrency(to)
+	if err != nil {
+		return Money{}, err
+	}
+	if rates == nil {
+		return Money{}, fmt.Errorf("%w: %s to %s", ErrNoRate, m.Currency, to)
+	}
+	rate, err := rates.Rate(ctx, m.Currency, to)
+	if err != nil {
+		return Money{}, err
+	}
+
+	// minor(to) = minor(from) * rate * 10^(exp(to) - exp(from))
+	v := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), rate)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(dst.Exponent-src.Exponent))), nil)
+	if dst.Exponent > src.Exponent {
+		v.Mul(v, new(big.Rat).SetInt(scale))
+	} else {
+		v.Quo(v, new(big.Rat).SetInt(scale))
+	}
+
+	amount, ok := round(v)
+	if !ok {
+		return Money{}, ErrOverflow
+	}
+	return Money{Currency: to, Amount: amount}, nil
+}
+
+// round rounds v half away from zero and reports whether it fits an int64.
+func round(v *big.Rat) (int64, bool) {
+	num := new(big.Int).Abs(v.Num())
+	q, r := new(big.Int).QuoRem(num, v.Denom(), new(big.Int))
+	if r.Lsh(r, 1).Cmp(v.Denom()) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if v.Sign() < 0 {
+		q.Neg(q)
+	}
+	if !q.IsInt64() {
+		return 0, false
+	}
+	return q.Int64(), true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// StaticRates is a fixed table of rates against a base currency, typically
+// loaded from a file that is refreshed by deploying a new one.
+type StaticRates struct {
+	base  string
+	rates map[string]*big.Rat // units of the currency per unit of base
+}
+
+// NewStaticRates builds a table from decimal rates such as "16250.5", each
+// the price of one base unit in that currency.
+func NewStaticRates(base string, rates map[string]string) (*StaticRates, error) {
+	base, err := NormalizeCurrency(base)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &StaticRates{base: base, rates: map[string]*big.Rat{base: big.NewRat(1, 1)}}
+	for code, value := range rates {
+		code, err := NormalizeCurrency(code)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := new(big.Rat).SetString(value)
+		if !ok || r.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid rate for %s: %q", code, value)
+		}
+		s.rates[code] = r
+	}
+	return s, nil
+}
+
+// LoadStaticRates reads a JSON file of the form
+//
+//	{"base": "USD", "rates": {"IDR": 16250, "EUR": 0.92}}
+func LoadStaticRates(path string) (*StaticRates, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Base  string                 `json:"base"`
+		Rates map[string]json.Number `json:"rates"`
+	}
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	rates := make(map[string]string, len(file.Rates))
+	for code, n := range file.Rates {
+		rates[code] = n.String()
+	}
+	return NewStaticRates(file.Base, rates)
+}
+
+func (s *StaticRates) Rate(ctx context.Context, from, to string) (*big.Rat, error) {
+	f, okFrom := s.rates[from]
+	t, okTo := s.rates[to]
+	if !okFrom || !okTo {
+		return nil, fmt.Errorf("%w: %s to %s", ErrNoRate, from, to)
+	}
+	return new(big.Rat).Quo(t, f), nil
+}
@@ -0,0 +1,55 @@
+package pagination
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCodec(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	filter := FilterHash("keyboard", "price_asc")
+	cur := Cursor{Key: "150000", ID: "0b4a7c1e-1111-4c1c-9a7e-2f6b2d1c0a01", Filter: filter}
+	token := codec.Encode(cur)
+
+	t.Run("round trip", func(t *testing.T) {
+		got, err := codec.Decode(token, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != cur {
+			t.Fatalf("expected %+v, got %+v", cur, got)
+		}
+	})
+
+	t.Run("other filters -> mismatch", func(t *testing.T) {
+		_, err := codec.Decode(token, FilterHash("keyboard", "price_desc"))
+		if !errors.Is(err, ErrFilterMismatch) {
+			t.Fatalf("expected ErrFilterMismatch, got %v", err)
+		}
+	})
+
+	t.Run("tampered payload -> invalid", func(t *testing.T) {
+		forged, _, _ := strings.Cut(NewCodec([]byte("other")).Encode(Cursor{Key: "1", ID: cur.ID, Filter: filter}), ".")
+		_, sig, _ := strings.Cut(token, ".")
+		_, err := codec.Decode(forged+"."+sig, filter)
+		if !errors.Is(err, ErrInvalidCursor) {
+			t.Fatalf("expected ErrInvalidCursor, got %v", err)
+		}
+	})
+
+	t.Run("other key -> invalid", func(t *testing.T) {
+		_, err := NewCodec([]byte("other")).Decode(token, filter)
+		if !errors.Is(err, ErrInvalidCursor) {
+			t.Fatalf("expected ErrInvalidCursor, got %v", err)
+		}
+	})
+
+	t.Run("garbage -> invalid", func(t *testing.T) {
+		for _, s := range []string{"", "abc", "0b4a7c1e-1111-4c1c-9a7e-2f6b2d1c0a01", "e30.e30"} {
+			if _, err := codec.Decode(s, filter); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("%q: expected ErrInvalidCursor, got %v", s, err)
+			}
+		}
+	})
+}
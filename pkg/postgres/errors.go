@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE codes of the constraint violations repositories translate into
+// domain errors.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+	checkViolation      = "23514"
+)
+
+func IsUniqueViolation(err error) bool     { return hasCode(err, uniqueViolation) }
+func IsForeignKeyViolation(err error) bool { return hasCode(err, foreignKeyViolation) }
+func IsCheckViolation(err error) bool      { return hasCode(err, checkViolation) }
+
+func hasCode(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
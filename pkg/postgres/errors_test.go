@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestConstraintViolations(t *testing.T) {
+	unique := fmt.Errorf("create: %w", &pgconn.PgError{Code: "23505"})
+	if !IsUniqueViolation(unique) || IsForeignKeyViolation(unique) || IsCheckViolation(unique) {
+		t.Fatalf("expected only a unique violation")
+	}
+	if !IsForeignKeyViolation(&pgconn.PgError{Code: "23503"}) || !IsCheckViolation(&pgconn.PgError{Code: "23514"}) {
+		t.Fatalf("expected foreign key and check violations")
+	}
+	if IsUniqueViolation(errors.New("duplicate key value violates unique constraint")) || IsUniqueViolation(nil) {
+		t.Fatalf("expected only Postgres errors to match")
+	}
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type txKey struct{}
+
+// txState is what WithinTx puts in the context: the transaction and what to
+// run once it commits.
+type txState struct {
+	tx          *sql.Tx
+	afterCommit []func()
+}
+
+// TxManager runs functions inside a database transaction that is carried by
+// the context, so repositories of different modules can join the same
+// transaction without knowing about each other.
+type TxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx runs fn in a transaction. If ctx already carries a transaction, fn
+// joins it and the outermost call decides whether to commit or roll back.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	st := &txState{}
+	err := ExecTx(ctx, m.db, func(tx *sql.Tx) error {
+		st.tx = tx
+		return fn(context.WithValue(ctx, txKey{}, st))
+	})
+	if err != nil {
+		return err
+	}
+	for _, f := range st.afterCommit {
+		f()
+	}
+	return nil
+}
+
+// AfterCommit runs fn once the transaction ctx carries has committed, or right
+// away if ctx carries none. fn is dropped if the transaction rolls back. It is
+// for side effects other readers must not see before the data, such as cache
+// invalidation.
+func AfterCommit(ctx context.Context, fn func()) {
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		st.afterCommit = append(st.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+// ExecTx runs fn in a transaction of db, or in the transaction ctx carries
+// (see WithinTx). It is what repositories build their sqlc WithTx calls on.
+func ExecTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		return fn(tx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %w; rollback err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// TxFromContext returns the transaction started by WithinTx, if any.
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	st, ok := ctx.Value(txKey{}).(*txState)
+	if !ok {
+		return nil, false
+	}
+	return st.tx, true
+}
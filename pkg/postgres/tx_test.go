@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+// fakeConnector hands out connections whose transactions only record that
+// they committed.
+type fakeConnector struct {
+	commits *int
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn(c), nil }
+func (c fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct {
+	commits *int
+}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return c, nil }
+func (c fakeConn) Commit() error                       { *c.commits++; return nil }
+func (c fakeConn) Rollback() error                     { return nil }
+
+func TestAfterCommit(t *testing.T) {
+	var commits int
+	db := sql.OpenDB(fakeConnector{commits: &commits})
+	defer db.Close()
+	m := NewTxManager(db)
+	ctx := context.Background()
+
+	var ran []int
+	err := m.WithinTx(ctx, func(ctx context.Context) error {
+		AfterCommit(ctx, func() { ran = append(ran, commits) })
+		// A nested call joins the transaction, so its hook waits too.
+		return m.WithinTx(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { ran = append(ran, commits) })
+			if len(ran) != 0 {
+				t.Fatalf("expected hooks to wait for the commit")
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("within tx: %v", err)
+	}
+	if commits != 1 || len(ran) != 2 || ran[0] != 1 || ran[1] != 1 {
+		t.Fatalf("expected both hooks after the one commit, got commits=%d ran=%v", commits, ran)
+	}
+
+	ran = nil
+	err = m.WithinTx(ctx, func(ctx context.Context) error {
+		AfterCommit(ctx, func() { ran = append(ran, commits) })
+		return errors.New("boom")
+	})
+	if err == nil || len(ran) != 0 {
+		t.Fatalf("expected a rolled back transaction to drop its hooks, got err=%v ran=%v", err, ran)
+	}
+
+	AfterCommit(ctx, func() { ran = append(ran, commits) })
+	if len(ran) != 1 {
+		t.Fatalf("expected a hook outside a transaction to run right away")
+	}
+}